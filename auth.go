@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Principal is an authenticated caller. Guests are anonymous callers without a user ID, they
+// are identified by the guest token issued along with their first cart. Users who shopped as
+// guests before signing in hold a guest token too.
+type Principal struct {
+	UserID     int64
+	Scopes     []string
+	GuestToken string
+}
+
+// HasScope reports whether the principal has been granted the scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeAdmin grants access to carts of every user.
+const ScopeAdmin = "admin"
+
+// PrincipalFromContext returns the principal placed in the context by APIv1AuthMiddleware.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(ctxAuth).(*Principal)
+	return p, ok
+}
+
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxAuth, p)
+}
+
+// Authenticator verifies a bearer token and returns the principal it was issued to.
+// Authenticate returns ErrUnauthenticated if the token is not valid.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authenticators tries its authenticators in order until one accepts the token.
+type Authenticators []Authenticator
+
+func (aa Authenticators) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	for _, a := range aa {
+		p, err := a.Authenticate(ctx, token)
+		if errors.Is(err, ErrUnauthenticated) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrUnauthenticated
+}
+
+// JWTAuthenticator verifies HS256 tokens signed with a shared secret and RS256 tokens signed
+// with a key from a JWKS. The user ID is taken from the sub claim, scopes from the
+// space-delimited scope claim.
+type JWTAuthenticator struct {
+	secret []byte
+	keys   map[string]*rsa.PublicKey
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope"`
+}
+
+// NewJWTAuthenticator instantiates JWTAuthenticator. HS256 is disabled when secret is empty,
+// RS256 is disabled when there are no keys, keys are looked up by the kid header.
+func NewJWTAuthenticator(secret []byte, keys map[string]*rsa.PublicKey) *JWTAuthenticator {
+	return &JWTAuthenticator{secret: secret, keys: keys}
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(token, &claims, a.key, jwt.WithValidMethods([]string{"HS256", "RS256"}))
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %v: %w", err, ErrUnauthenticated)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, fmt.Errorf("jwt: sub %q: %w", claims.Subject, ErrUnauthenticated)
+	}
+
+	return &Principal{UserID: userID, Scopes: strings.Fields(claims.Scope)}, nil
+}
+
+func (a *JWTAuthenticator) key(t *jwt.Token) (interface{}, error) {
+	switch t.Method.Alg() {
+	case "HS256":
+		if len(a.secret) == 0 {
+			return nil, errors.New("HS256 is not enabled")
+		}
+		return a.secret, nil
+	case "RS256":
+		kid, _ := t.Header["kid"].(string)
+		if k, ok := a.keys[kid]; ok {
+			return k, nil
+		}
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+}
+
+// LoadJWKS reads RSA public keys from a JWKS file, returns them by key ID.
+func LoadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &jwks); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q n: %w", k.Kid, err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q e: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+// APIKeyAuthenticator authenticates static API keys.
+type APIKeyAuthenticator struct {
+	keys map[[sha256.Size]byte]*Principal
+}
+
+// NewAPIKeyAuthenticator instantiates APIKeyAuthenticator with principals by API key.
+func NewAPIKeyAuthenticator(keys map[string]*Principal) *APIKeyAuthenticator {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]*Principal, len(keys))}
+	for k, p := range keys {
+		// Keys are looked up by their hashes so the lookup time does not depend on the key.
+		a.keys[sha256.Sum256([]byte(k))] = p
+	}
+	return a
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	p, ok := a.keys[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, fmt.Errorf("api key: %w", ErrUnauthenticated)
+	}
+	return p, nil
+}
+
+// LoadAPIKeys reads principals by API key from a JSON file of the form
+// {"<key>": {"user_id": 1, "scopes": ["admin"]}}.
+func LoadAPIKeys(path string) (map[string]*Principal, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var kk map[string]struct {
+		UserID int64    `json:"user_id"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.Unmarshal(b, &kk); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	keys := make(map[string]*Principal, len(kk))
+	for k, p := range kk {
+		keys[k] = &Principal{UserID: p.UserID, Scopes: p.Scopes}
+	}
+	return keys, nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	secret := []byte("secret")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := NewJWTAuthenticator(secret, map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey})
+
+	sign := func(method jwt.SigningMethod, key interface{}, kid string, claims jwt.Claims) string {
+		t.Helper()
+
+		tok := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			tok.Header["kid"] = kid
+		}
+
+		s, err := tok.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	claims := func(sub string, exp time.Duration) jwtClaims {
+		return jwtClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   sub,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(exp)),
+			},
+			Scope: "admin carts:read",
+		}
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		exp   *Principal
+	}{
+		{"hs256", sign(jwt.SigningMethodHS256, secret, "", claims("15", time.Hour)), &Principal{UserID: 15, Scopes: []string{"admin", "carts:read"}}},
+		{"rs256", sign(jwt.SigningMethodRS256, rsaKey, "k1", claims("16", time.Hour)), &Principal{UserID: 16, Scopes: []string{"admin", "carts:read"}}},
+		{"expired", sign(jwt.SigningMethodHS256, secret, "", claims("15", -time.Hour)), nil},
+		{"wrong secret", sign(jwt.SigningMethodHS256, []byte("wrong"), "", claims("15", time.Hour)), nil},
+		{"unknown kid", sign(jwt.SigningMethodRS256, rsaKey, "k2", claims("16", time.Hour)), nil},
+		{"unexpected alg", sign(jwt.SigningMethodHS384, secret, "", claims("15", time.Hour)), nil},
+		{"invalid sub", sign(jwt.SigningMethodHS256, secret, "", claims("user", time.Hour)), nil},
+		{"garbage", "garbage", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := auth.Authenticate(context.Background(), tt.token)
+			if tt.exp == nil {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("err exp: %v, got: %v", ErrUnauthenticated, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tt.exp, p) {
+				t.Errorf("principals do not match\nexp: %+v\ngot: %+v", tt.exp, p)
+			}
+		})
+	}
+
+	t.Run("hs256 disabled", func(t *testing.T) {
+		auth := NewJWTAuthenticator(nil, nil)
+
+		_, err := auth.Authenticate(context.Background(), sign(jwt.SigningMethodHS256, []byte{}, "", claims("15", time.Hour)))
+		if !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("err exp: %v, got: %v", ErrUnauthenticated, err)
+		}
+	})
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := writeTestFile(t, "jwks.json", fmt.Sprintf(
+		`{"keys":[{"kty":"RSA","kid":"k1","use":"sig","alg":"RS256","n":%q,"e":%q},{"kty":"EC","kid":"k2"}]}`,
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	))
+
+	keys, err := LoadJWKS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := len(keys); l != 1 {
+		t.Fatalf("keys num exp: %d, got: %d", 1, l)
+	}
+
+	if !rsaKey.PublicKey.Equal(keys["k1"]) {
+		t.Errorf("keys do not match\nexp: %+v\ngot: %+v", rsaKey.PublicKey, keys["k1"])
+	}
+}
+
+func TestAPIKeyAuthenticator_Authenticate(t *testing.T) {
+	path := writeTestFile(t, "api_keys.json", `{"key1":{"user_id":15},"key2":{"user_id":1,"scopes":["admin"]}}`)
+
+	keys, err := LoadAPIKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := NewAPIKeyAuthenticator(keys)
+
+	p, err := auth.Authenticate(context.Background(), "key2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := (&Principal{UserID: 1, Scopes: []string{"admin"}}); !reflect.DeepEqual(exp, p) {
+		t.Errorf("principals do not match\nexp: %+v\ngot: %+v", exp, p)
+	}
+
+	if _, err := auth.Authenticate(context.Background(), "key3"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("err exp: %v, got: %v", ErrUnauthenticated, err)
+	}
+}
+
+func TestAuthenticators_Authenticate(t *testing.T) {
+	auth := Authenticators{
+		NewAPIKeyAuthenticator(map[string]*Principal{"key1": {UserID: 1}}),
+		NewAPIKeyAuthenticator(map[string]*Principal{"key2": {UserID: 2}}),
+	}
+
+	p, err := auth.Authenticate(context.Background(), "key2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.UserID != 2 {
+		t.Errorf("user id exp: %d, got: %d", 2, p.UserID)
+	}
+
+	if _, err := auth.Authenticate(context.Background(), "key3"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("err exp: %v, got: %v", ErrUnauthenticated, err)
+	}
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	p := &Principal{Scopes: []string{"admin"}}
+
+	if !p.HasScope("admin") {
+		t.Error("admin scope exp")
+	}
+	if p.HasScope("root") {
+		t.Error("root scope not exp")
+	}
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "shoppingcart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -3,17 +3,78 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 )
 
-// Cart is a shopping cart of a user, holds theirs line items.
+// Cart is a shopping cart of a user or of a guest, holds theirs line items.
 type Cart struct {
-	ID        int64
-	UserID    int64
-	LineItems []*LineItem
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID         int64
+	UserID     int64      // zero for guest carts
+	GuestToken string     // SHA-256 of the token of the guest owning the cart, empty for carts of users
+	Version    int64      // bumped on every mutation of the cart or its items
+	Status     CartStatus // carts other than active ones are frozen
+	OrderID    int64      // set once the cart has been checked out
+	LineItems  []*LineItem
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	ShippingAddress *Address // where the items are shipped to, nil if unknown
+	ShippingMethod  string   // code of the chosen shipping method, empty if none
+
+	Coupons     []string     // codes of the applied coupons in the order of application
+	FiredRules  []FiredRule  // rules applied to the cart when it has been priced
+	Adjustments []Adjustment // discounts, taxes and shipping charges
+	Totals      *Totals      // nil until the cart is priced
+}
+
+// CartStatus is the lifecycle state of a cart.
+type CartStatus string
+
+// Cart statuses, a user has at most one active cart.
+const (
+	CartActive     CartStatus = "active"
+	CartCheckedOut CartStatus = "checked_out"
+	CartAbandoned  CartStatus = "abandoned" // superseded by a newer cart of the user
+	CartMerged     CartStatus = "merged"    // guest cart merged into a cart of a user
+)
+
+// Address is a postal address, its country and region define the tax jurisdiction.
+type Address struct {
+	Name       string
+	Line1      string
+	Line2      string
+	City       string
+	PostalCode string
+	Region     string // ISO 3166-2 subdivision code without the country prefix, e.g. CA
+	Country    string // ISO 3166-1 alpha-2 code, e.g. US
+}
+
+// validate checks and normalizes a shipping address.
+func (a *Address) validate() error {
+	a.Country, a.Region = strings.ToUpper(strings.TrimSpace(a.Country)), strings.ToUpper(strings.TrimSpace(a.Region))
+
+	for _, f := range []struct {
+		name  string
+		value string
+	}{
+		{"line1", a.Line1},
+		{"city", a.City},
+		{"country", a.Country},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			return &InvalidParamError{Name: f.name, Err: fmt.Errorf("required: %w", ErrInvalidArgument)}
+		}
+	}
+
+	if len(a.Country) != 2 {
+		return &InvalidParamError{Name: "country", Err: fmt.Errorf("%q is not an ISO 3166-1 alpha-2 code: %w", a.Country, ErrInvalidArgument)}
+	}
+
+	return nil
 }
 
 // LineItem is an SKU item of a cart with a quantity multiplier.
@@ -24,111 +85,1581 @@ type LineItem struct {
 	Quantity  int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Unit price the item has been added at, unknown without a price source.
+	UnitPrice Money
+
+	// Product details, filled in from the catalog and the price source.
+	SKU              string
+	Name             string
+	Category         string
+	TaxClass         string
+	PriceChanged     bool  // the current unit price differs from the one the item has been added at
+	CurrentUnitPrice Money // set if the price has changed
+
+	Adjustments []Adjustment // discounts of the item
+}
+
+// CartsQuery filters and paginates carts of a user, carts are ordered by ID.
+// Time ranges include the From bound and exclude the To bound, zero bounds are ignored.
+type CartsQuery struct {
+	AfterID     int64 // ID of the last cart of the previous page
+	Limit       int
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	WithItems   bool
 }
 
+// Carts page size limits.
+const (
+	cartsDefaultLimit = 20
+	cartsMaxLimit     = 100
+)
+
+// UpsertMode defines how LineItemsUpsert treats the quantity of an item already in a cart.
+type UpsertMode uint8
+
+const (
+	UpsertSet       UpsertMode = iota // replace the quantity
+	UpsertIncrement                   // add to the quantity atomically
+)
+
 // storer describes Shopping Cart storage functions.
 type storer interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (storer, error) // NOTE: an interesting point to discuss
 	Commit() error
+	Rollback() error
 
-	CartCreate(ctx context.Context, cart *Cart) error
+	CartCreate(ctx context.Context, cart *Cart) error // an active cart, a new cart of a user abandons their active cart
 	CartWithItemsByCartID(ctx context.Context, cartID int64) (*Cart, error)
+	ActiveCartByUserID(ctx context.Context, userID int64) (*Cart, error)
+	CartsByUserID(ctx context.Context, userID int64, q CartsQuery) ([]*Cart, error)
 	CartEmpty(ctx context.Context, cartID int64) error
+	CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) error // a nil address removes it
+	CartShippingMethodSet(ctx context.Context, cartID int64, code string) error // an empty code removes it
+	CartStatusSet(ctx context.Context, cartID int64, status CartStatus) error
 
-	LineItemsUpsert(ctx context.Context, cartID int64, items ...*LineItem) error
+	OrderCreate(ctx context.Context, o *Order) error // checks the cart out, fails with ErrConflict if it has been already
+
+	PaymentCreate(ctx context.Context, p *Payment) error
+	PaymentByCartID(ctx context.Context, cartID int64) (*Payment, error) // the latest payment of the cart
+	PaymentStatusSet(ctx context.Context, paymentID int64, status PaymentStatus) error
+
+	LineItemsUpsert(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) error
 	LineItemRemove(ctx context.Context, cartID, itemID int64) error
+
+	CartCouponAdd(ctx context.Context, cartID int64, code string) error
+	CartCouponRemove(ctx context.Context, cartID int64, code string) error
+	CouponUses(ctx context.Context, userID int64, code string) (int, error) // checked out carts of the user the coupon is applied to
+
+	IdempotencyKeyCreate(ctx context.Context, key *IdempotencyKey) error
+	IdempotencyKeyByKey(ctx context.Context, userID int64, key string) (*IdempotencyKey, error)
+	IdempotencyKeyComplete(ctx context.Context, key *IdempotencyKey) error // stores the response and the new expiry
+	IdempotencyKeyDelete(ctx context.Context, userID int64, key string) error
+	IdempotencyKeysPurge(ctx context.Context, before time.Time) error
 }
 
 // ShoppingCart holds business logic.
 type ShoppingCart struct {
 	storage storer
+	catalog Catalog     // products are neither validated nor enriched without a catalog
+	prices  PriceSource // items are not priced without a price source
+
+	promotions Promotions // coupons can not be applied without promotions
+	rules      Rules      // no automatic promotions without rules
+
+	taxes    TaxCalculator // carts are not taxed without a tax calculator
+	shipping ShippingRater // no shipping methods without a shipping rater
+
+	inventory      Inventory     // stock is not checked without an inventory
+	reservationTTL time.Duration // stock is checked but not reserved if 0
+
+	payments PaymentGateway // carts can not be paid without a payment gateway
+}
+
+// WithTx runs fn within a transaction. The transaction is committed if fn succeeds
+// and rolled back if fn returns an error or panics.
+func (sc *ShoppingCart) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx storer) error) (err error) {
+	tx, err := sc.storage.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("tx: %w", err)
+	}
+
+	done := false
+	defer func() {
+		if done {
+			return
+		}
+
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w (rollback: %v)", err, rerr)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	done = true
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// cartTx runs a mutation of the cart within a transaction like WithTx. The authorized payment of
+// the cart does not cover the cart once it has been modified, so it is voided after the commit.
+func (sc *ShoppingCart) cartTx(ctx context.Context, cartID int64, fn func(tx storer) error) error {
+	if err := sc.WithTx(ctx, nil, fn); err != nil {
+		return err
+	}
+
+	sc.voidStalePayment(ctx, cartID)
+	return nil
 }
 
 // CartCreate creates and persists a shopping cart, returns created cart with items if were any.
+// The new cart of a user becomes their active cart, the previous one is abandoned.
 func (sc *ShoppingCart) CartCreate(ctx context.Context, userID int64, items []*LineItem) (*Cart, error) {
 	cart := &Cart{
 		UserID:    userID,
 		LineItems: items,
 	}
 
+	// Carts without a user belong to the guest.
+	if userID == 0 {
+		p, ok := PrincipalFromContext(ctx)
+		if !ok || p.GuestToken == "" {
+			return nil, ErrUnauthenticated
+		}
+		cart.GuestToken = hashGuestToken(p.GuestToken)
+	} else if err := sc.authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := sc.validateLineItems(items); err != nil {
+		return nil, err
+	}
+
+	if err := sc.checkProducts(ctx, items); err != nil {
+		return nil, err
+	}
+
+	if err := sc.snapshotPrices(ctx, items); err != nil {
+		return nil, err
+	}
+
+	// The previous active cart of the user is abandoned, its payment would be left authorized and
+	// its stock reserved. The gateway is not called within the transaction.
+	var prev *Cart
+	if userID != 0 && (sc.inventory != nil || sc.payments != nil) {
+		c, err := sc.storage.ActiveCartByUserID(ctx, userID)
+		switch {
+		case errors.Is(err, ErrCartNotFound):
+		case err != nil:
+			return nil, fmt.Errorf("active cart: %w", err)
+		default:
+			if err := sc.voidCartPayment(ctx, c.ID); err != nil {
+				return nil, err
+			}
+			prev = c
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	var (
-		tx  storer
-		err error
-	)
-	tx, err = sc.storage.BeginTx(ctx, nil)
+	err := sc.WithTx(ctx, nil, func(tx storer) error {
+		if err := tx.CartCreate(ctx, cart); err != nil {
+			return fmt.Errorf("cart: %w", err)
+		}
+
+		if err := tx.LineItemsUpsert(ctx, cart.ID, UpsertSet, items...); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+
+		// The reservations are not restored if the creation fails, they are made again once the
+		// previous cart is modified.
+		if prev != nil {
+			if err := sc.releaseStock(ctx, prev.ID); err != nil {
+				return err
+			}
+		}
+
+		return sc.reserveStock(ctx, cart.ID, items)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("tx: %w", err)
+		return nil, err
 	}
 
-	if err := tx.CartCreate(ctx, cart); err != nil {
-		return nil, fmt.Errorf("cart: %w", err)
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return nil, err
 	}
 
-	if err := tx.LineItemsUpsert(ctx, cart.ID, items...); err != nil {
-		return nil, fmt.Errorf("items: %w", err)
+	return cart, nil
+}
+
+// CartShow returns the details of a cart.
+func (sc *ShoppingCart) CartShow(ctx context.Context, cartID int64) (*Cart, error) {
+	cart, err := sc.authorizedCart(ctx, sc.storage, cartID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit: %w", err)
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return nil, err
 	}
 
 	return cart, nil
 }
 
-// CartShow returns the details of a cart.
-func (sc *ShoppingCart) CartShow(ctx context.Context, cartID int64) (*Cart, error) {
-	return sc.storage.CartWithItemsByCartID(ctx, cartID)
+// CartReprice updates unit prices of the cart items to the current prices, which acknowledges
+// price changes. Returns the repriced cart.
+func (sc *ShoppingCart) CartReprice(ctx context.Context, cartID int64) (*Cart, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cart *Cart
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		c, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		stored := make(map[int64]Money, len(c.LineItems))
+		for _, i := range c.LineItems {
+			stored[i.ID] = i.UnitPrice
+		}
+
+		if err := sc.enrichLineItems(ctx, c.LineItems); err != nil {
+			return err
+		}
+
+		var changed []*LineItem
+		for _, i := range c.LineItems {
+			if i.PriceChanged {
+				i.UnitPrice, i.CurrentUnitPrice, i.PriceChanged = i.CurrentUnitPrice, Money{}, false
+			}
+			if i.UnitPrice != stored[i.ID] {
+				changed = append(changed, i)
+			}
+		}
+
+		if len(changed) == 0 {
+			cart = c
+			return nil
+		}
+
+		if err := tx.LineItemsUpsert(ctx, cartID, UpsertSet, changed...); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+
+		// Re-read for the bumped version.
+		cart, err = tx.CartWithItemsByCartID(ctx, cartID)
+		if err != nil {
+			return fmt.Errorf("cart: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// CartsByUser returns a page of carts of the user and the ID to pass as CartsQuery.AfterID
+// to get the next page, the ID is 0 on the last page.
+func (sc *ShoppingCart) CartsByUser(ctx context.Context, userID int64, q CartsQuery) ([]*Cart, int64, error) {
+	if err := sc.authorize(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case q.Limit == 0:
+		q.Limit = cartsDefaultLimit
+	case q.Limit < 0, q.Limit > cartsMaxLimit:
+		return nil, 0, &InvalidParamError{Name: "limit", Err: fmt.Errorf("%d is out of 1..%d: %w", q.Limit, cartsMaxLimit, ErrInvalidArgument)}
+	}
+
+	limit := q.Limit
+	q.Limit++ // to know whether there is a next page
+
+	carts, err := sc.storage.CartsByUserID(ctx, userID, q)
+	if err != nil {
+		return nil, 0, fmt.Errorf("carts: %w", err)
+	}
+
+	var nextID int64
+	if len(carts) > limit {
+		carts = carts[:limit]
+		nextID = carts[limit-1].ID
+	}
+
+	var items []*LineItem
+	for _, c := range carts {
+		items = append(items, c.LineItems...)
+	}
+
+	if err := sc.enrichLineItems(ctx, items); err != nil {
+		return nil, 0, err
+	}
+
+	// Totals of carts listed without items would be misleading.
+	if q.WithItems {
+		for _, c := range carts {
+			if err := sc.totalCart(ctx, c); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	return carts, nextID, nil
+}
+
+// ActiveCart returns the active cart of the user, an empty cart is created if the user has none.
+func (sc *ShoppingCart) ActiveCart(ctx context.Context, userID int64) (*Cart, error) {
+	if err := sc.authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cart *Cart
+	err := sc.WithTx(ctx, nil, func(tx storer) error {
+		var err error
+		cart, err = tx.ActiveCartByUserID(ctx, userID)
+		if !errors.Is(err, ErrCartNotFound) {
+			return err
+		}
+
+		cart = &Cart{UserID: userID}
+		return tx.CartCreate(ctx, cart)
+	})
+	// A concurrent request has created the active cart in the meantime.
+	if errors.Is(err, ErrConflict) {
+		cart, err = sc.storage.ActiveCartByUserID(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("active cart: %w", err)
+	}
+
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// CartEmpty empties a shopping cart, returns the new version of the cart.
+func (sc *ShoppingCart) CartEmpty(ctx context.Context, cartID int64) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var version int64
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		if _, err := sc.mutableCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		if err := tx.CartEmpty(ctx, cartID); err != nil {
+			return err
+		}
+
+		if err := sc.releaseStock(ctx, cartID); err != nil {
+			return err
+		}
+
+		if err := sc.revalidateShippingMethod(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		var err error
+		version, err = cartVersion(ctx, tx, cartID)
+		return err
+	})
+	return version, err
 }
 
-// CartEmpty empties a shopping cart.
-func (sc *ShoppingCart) CartEmpty(ctx context.Context, cartID int64) error {
-	return sc.storage.CartEmpty(ctx, cartID)
+// LineItemAdd adds products to a shopping cart, returns items added and the new version of
+// the cart.
+func (sc *ShoppingCart) LineItemAdd(ctx context.Context, cartID int64, items []*LineItem) ([]*LineItem, int64, error) {
+	if err := sc.validateLineItems(items); err != nil {
+		return nil, 0, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cart *Cart
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		if _, err := sc.mutableCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		// The catalog is looked up for authorized callers only, others could probe products.
+		if err := sc.checkProducts(ctx, items); err != nil {
+			return err
+		}
+
+		if err := sc.snapshotPrices(ctx, items); err != nil {
+			return err
+		}
+
+		// Quantities of existing products are summed up by the storer, their prices are kept.
+		if err := tx.LineItemsUpsert(ctx, cartID, UpsertIncrement, items...); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+
+		if err := sc.revalidateShippingMethod(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		// Discounts of the items depend on the whole cart.
+		var err error
+		if cart, err = tx.CartWithItemsByCartID(ctx, cartID); err != nil {
+			return fmt.Errorf("cart: %w", err)
+		}
+
+		// Stock is reserved for the summed up quantities of the products added.
+		added := make(map[int64]bool, len(items))
+		for _, i := range items {
+			added[i.ProductID] = true
+		}
+
+		var reserved []*LineItem
+		for _, i := range cart.LineItems {
+			if added[i.ProductID] {
+				reserved = append(reserved, i)
+			}
+		}
+
+		return sc.reserveStock(ctx, cartID, reserved)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := sc.priceLineItems(ctx, cart, items); err != nil {
+		return nil, 0, err
+	}
+
+	return items, cart.Version, nil
 }
 
-// LineItemAdd adds products to a shopping cart, returns items added.
-func (sc *ShoppingCart) LineItemAdd(ctx context.Context, cartID int64, items []*LineItem) ([]*LineItem, error) {
+// LineItemSetQuantity sets the quantity of a cart item, zero quantity removes the item.
+// The updated item is returned, nil if the item has been removed, along with the new version of
+// the cart.
+func (sc *ShoppingCart) LineItemSetQuantity(ctx context.Context, cartID, itemID, quantity int64) (*LineItem, int64, error) {
+	if quantity < 0 {
+		return nil, 0, &InvalidParamError{Name: "quantity", Err: ErrInvalidQuantity}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	var (
-		tx  storer
-		err error
+		cart    *Cart
+		item    *LineItem
+		version int64
 	)
-	tx, err = sc.storage.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		var err error
+		if cart, err = sc.mutableCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		for _, i := range cart.LineItems {
+			if i.ID == itemID {
+				item = i
+			}
+		}
+		if item == nil {
+			return fmt.Errorf("item %d: %w", itemID, ErrLineItemNotFound)
+		}
+
+		if quantity == 0 {
+			productID := item.ProductID
+			item = nil
+			if err := tx.LineItemRemove(ctx, cartID, itemID); err != nil {
+				return err
+			}
+
+			if err := sc.releaseStock(ctx, cartID, productID); err != nil {
+				return err
+			}
+
+			if err := sc.revalidateShippingMethod(ctx, tx, cartID); err != nil {
+				return err
+			}
+
+			version, err = cartVersion(ctx, tx, cartID)
+			return err
+		}
+
+		item.Quantity = quantity
+		if err := tx.LineItemsUpsert(ctx, cartID, UpsertSet, item); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+
+		if err := sc.reserveStock(ctx, cartID, []*LineItem{item}); err != nil {
+			return err
+		}
+
+		if err := sc.revalidateShippingMethod(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		version, err = cartVersion(ctx, tx, cartID)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("tx: %w", err)
+		return nil, 0, err
 	}
 
-	cart, err := tx.CartWithItemsByCartID(ctx, cartID)
-	if err != nil {
-		return nil, fmt.Errorf("cart: %w", err)
+	// The item has been updated in place, discounts of the item depend on the whole cart.
+	if item != nil {
+		if err := sc.priceLineItems(ctx, cart, []*LineItem{item}); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// Sum quantity of existing products.
-	for _, item := range items {
+	return item, version, nil
+}
+
+// LineItemRemove removes products from a shopping cart, returns the new version of the cart.
+func (sc *ShoppingCart) LineItemRemove(ctx context.Context, cartID, itemID int64) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var version int64
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		cart, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.LineItemRemove(ctx, cartID, itemID); err != nil {
+			return err
+		}
+
 		for _, i := range cart.LineItems {
-			if i.ProductID == item.ProductID {
-				item.ID = i.ID
-				item.Quantity += i.Quantity
+			if i.ID == itemID {
+				if err := sc.releaseStock(ctx, cartID, i.ProductID); err != nil {
+					return err
+				}
 			}
 		}
+
+		if err := sc.revalidateShippingMethod(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		version, err = cartVersion(ctx, tx, cartID)
+		return err
+	})
+	return version, err
+}
+
+// CouponApply applies a coupon to a cart, returns the repriced cart. Applying a coupon twice
+// has no effect.
+func (sc *ShoppingCart) CouponApply(ctx context.Context, cartID int64, code string) (*Cart, error) {
+	code = normalizeCouponCode(code)
+	if code == "" {
+		return nil, &InvalidParamError{Name: "code", Err: ErrInvalidArgument}
 	}
 
-	if err := tx.LineItemsUpsert(ctx, cartID, items...); err != nil {
-		return nil, fmt.Errorf("items: %w", err)
+	if sc.promotions == nil {
+		return nil, fmt.Errorf("coupon %q: %w", code, ErrCouponNotFound)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit: %w", err)
+	p, err := sc.promotions.PromotionByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("promotions: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err = sc.cartTx(ctx, cartID, func(tx storer) error {
+		cart, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range cart.Coupons {
+			if c == code {
+				return nil
+			}
+		}
+
+		if p.MaxUsesPerUser > 0 && cart.UserID == 0 {
+			return fmt.Errorf("coupon %q is limited per user: %w", code, ErrCouponNotApplicable)
+		}
+		if p.MaxUsesPerUser > 0 {
+			uses, err := tx.CouponUses(ctx, cart.UserID, code)
+			if err != nil {
+				return fmt.Errorf("coupon uses: %w", err)
+			}
+			if uses >= p.MaxUsesPerUser {
+				return fmt.Errorf("coupon %q has been used %d times: %w", code, uses, ErrCouponNotApplicable)
+			}
+		}
+
+		if err := sc.enrichLineItems(ctx, cart.LineItems); err != nil {
+			return err
+		}
+
+		if err := p.applicable(cart, time.Now()); err != nil {
+			return err
+		}
+
+		return tx.CartCouponAdd(ctx, cartID, code)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return items, nil
+	return sc.CartShow(ctx, cartID)
 }
 
-// LineItemRemove removes products from a shopping cart.
-func (sc *ShoppingCart) LineItemRemove(ctx context.Context, cartID, itemID int64) error {
-	return sc.storage.LineItemRemove(ctx, cartID, itemID)
+// CouponRemove removes a coupon from a cart, returns the new version of the cart.
+func (sc *ShoppingCart) CouponRemove(ctx context.Context, cartID int64, code string) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var version int64
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		if _, err := sc.mutableCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		if err := tx.CartCouponRemove(ctx, cartID, normalizeCouponCode(code)); err != nil {
+			return err
+		}
+
+		var err error
+		version, err = cartVersion(ctx, tx, cartID)
+		return err
+	})
+	return version, err
+}
+
+// CartShippingAddressSet sets the shipping address of a cart, returns the repriced cart. The
+// shipping method is removed if it is not available for the address.
+func (sc *ShoppingCart) CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) (*Cart, error) {
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		if _, err := sc.mutableCart(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		if err := tx.CartShippingAddressSet(ctx, cartID, a); err != nil {
+			return fmt.Errorf("shipping address: %w", err)
+		}
+
+		return sc.revalidateShippingMethod(ctx, tx, cartID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sc.CartShow(ctx, cartID)
+}
+
+// CartShippingMethodSet sets the shipping method of a cart, returns the repriced cart. The
+// method must be available for the items and the shipping address of the cart.
+func (sc *ShoppingCart) CartShippingMethodSet(ctx context.Context, cartID int64, code string) (*Cart, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, &InvalidParamError{Name: "code", Err: ErrInvalidArgument}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	err := sc.cartTx(ctx, cartID, func(tx storer) error {
+		cart, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		if cart.ShippingMethod == code {
+			return nil
+		}
+
+		if err := sc.enrichLineItems(ctx, cart.LineItems); err != nil {
+			return err
+		}
+
+		cart.ShippingMethod = code
+		if _, err := sc.shippingRate(ctx, cart); err != nil {
+			return err
+		}
+
+		return tx.CartShippingMethodSet(ctx, cartID, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sc.CartShow(ctx, cartID)
+}
+
+// ShippingMethods returns the shipping methods available for the items and the shipping
+// address of a cart, with their costs.
+func (sc *ShoppingCart) ShippingMethods(ctx context.Context, cartID int64) ([]ShippingRate, error) {
+	cart, err := sc.authorizedCart(ctx, sc.storage, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sc.shipping == nil {
+		return nil, nil
+	}
+
+	if err := sc.enrichLineItems(ctx, cart.LineItems); err != nil {
+		return nil, err
+	}
+
+	rates, err := sc.shipping.ShippingRates(ctx, cart)
+	if err != nil {
+		return nil, fmt.Errorf("shipping rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Checkout converts a cart into an order and freezes the cart, returns the order ID. Items of
+// the cart must be of active products, priced at their current prices and in stock.
+func (sc *ShoppingCart) Checkout(ctx context.Context, cartID int64) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		order     *Order
+		withdrawn map[int64]int64
+	)
+	err := sc.WithTx(ctx, nil, func(tx storer) error {
+		cart, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		if len(cart.LineItems) == 0 {
+			return fmt.Errorf("cart %d: %w", cartID, ErrCartEmpty)
+		}
+
+		if err := sc.checkProducts(ctx, cart.LineItems); err != nil {
+			return err
+		}
+
+		if err := sc.priceCart(ctx, cart); err != nil {
+			return err
+		}
+
+		for _, i := range cart.LineItems {
+			switch {
+			case i.PriceChanged:
+				return fmt.Errorf("item %d: %w", i.ID, ErrPriceChanged)
+			case i.UnitPrice.Currency == "":
+				return fmt.Errorf("item %d: product %d has no price: %w", i.ID, i.ProductID, ErrInvalidProduct)
+			}
+		}
+
+		if order, err = newOrder(cart); err != nil {
+			return err
+		}
+
+		if err := tx.OrderCreate(ctx, order); err != nil {
+			return fmt.Errorf("order: %w", err)
+		}
+
+		// Stock is withdrawn last, it is given back if the order fails to commit.
+		withdrawn, err = sc.withdrawStock(ctx, cartID, cart.LineItems)
+		return err
+	})
+	if err != nil {
+		if withdrawn != nil {
+			if rerr := sc.restock(withdrawn); rerr != nil {
+				err = fmt.Errorf("%w (restock: %v)", err, rerr)
+			}
+		}
+		return 0, err
+	}
+
+	return order.ID, nil
+}
+
+// CartPay authorizes payment of the grand total of the cart with the payment method of the
+// source token and records the payment, returns the payment and whether it has been created. A
+// cart paid for already returns its payment unless the cart has been modified since, which voids
+// the payment.
+func (sc *ShoppingCart) CartPay(ctx context.Context, cartID int64, source string) (*Payment, bool, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, false, &InvalidParamError{Name: "source", Err: fmt.Errorf("required: %w", ErrInvalidArgument)}
+	}
+
+	if sc.payments == nil {
+		return nil, false, errors.New("payments: no payment gateway")
+	}
+
+	cart, err := sc.mutableCart(ctx, sc.storage, cartID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	p, err := sc.storage.PaymentByCartID(ctx, cartID)
+	switch {
+	case errors.Is(err, ErrPaymentNotFound):
+	case err != nil:
+		return nil, false, fmt.Errorf("payment: %w", err)
+	case p.Status == PaymentAuthorized && p.CartVersion == cart.Version:
+		return p, false, nil
+	case p.Status == PaymentAuthorized:
+		if err := sc.voidPayment(ctx, p); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return nil, false, err
+	}
+
+	amount := cart.Totals.GrandTotal
+	if amount.Amount <= 0 {
+		return nil, false, fmt.Errorf("cart %d: nothing to pay: %w", cartID, ErrCartEmpty)
+	}
+
+	// The gateway is not called within the transaction, retries of the same cart version and
+	// payment method are authorized once.
+	authID, err := sc.payments.Authorize(ctx, amount, source, fmt.Sprintf("cart-%d-%d-%s", cartID, cart.Version, source))
+	if err != nil {
+		return nil, false, fmt.Errorf("payments: %w", err)
+	}
+
+	p = &Payment{
+		CartID:          cartID,
+		CartVersion:     cart.Version,
+		Amount:          amount,
+		Status:          PaymentAuthorized,
+		AuthorizationID: authID,
+	}
+	err = sc.WithTx(ctx, nil, func(tx storer) error {
+		c, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		if c.Version != cart.Version {
+			return fmt.Errorf("cart %d modified while paying: %w", cartID, ErrConflict)
+		}
+
+		return tx.PaymentCreate(ctx, p)
+	})
+	if err != nil {
+		if verr := sc.payments.Void(ctx, authID); verr != nil {
+			err = fmt.Errorf("%w (void: %v)", err, verr)
+		}
+		return nil, false, err
+	}
+
+	return p, true, nil
+}
+
+// CartMerge merges the items of a guest cart into a cart of a user and marks the guest cart
+// merged, returns the merged cart. Quantities of products in both carts are merged by the strategy.
+// The caller has to hold the token of the guest.
+func (sc *ShoppingCart) CartMerge(ctx context.Context, cartID, guestCartID int64, strategy MergeStrategy) (*Cart, error) {
+	if err := strategy.validate(); err != nil {
+		return nil, err
+	}
+
+	if guestCartID == cartID {
+		return nil, &InvalidParamError{Name: "guest_cart_id", Err: fmt.Errorf("merging cart %d into itself: %w", cartID, ErrInvalidArgument)}
+	}
+
+	guest, err := sc.guestCart(ctx, sc.storage, guestCartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payment of the guest cart would be left authorized, the gateway is not called within
+	// the transaction.
+	if err := sc.voidCartPayment(ctx, guest.ID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cart *Cart
+	err = sc.cartTx(ctx, cartID, func(tx storer) error {
+		c, err := sc.mutableCart(ctx, tx, cartID)
+		if err != nil {
+			return err
+		}
+
+		if c.GuestToken != "" {
+			return &InvalidParamError{Name: "cartID", Err: fmt.Errorf("cart %d is a guest cart: %w", cartID, ErrInvalidArgument)}
+		}
+
+		if guest, err = sc.guestCart(ctx, tx, guestCartID); err != nil {
+			return err
+		}
+
+		merged := mergeLineItems(c.LineItems, guest.LineItems, strategy)
+		if err := tx.LineItemsUpsert(ctx, cartID, UpsertSet, merged...); err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+
+		if err := tx.CartStatusSet(ctx, guestCartID, CartMerged); err != nil {
+			return fmt.Errorf("guest cart: %w", err)
+		}
+
+		if err := sc.revalidateShippingMethod(ctx, tx, cartID); err != nil {
+			return err
+		}
+
+		// Stock held by the guest cart is moved to the cart. The guest reservations are not
+		// restored if the merge fails, they are made again once the guest cart is modified.
+		if err := sc.releaseStock(ctx, guestCartID); err != nil {
+			return err
+		}
+
+		if err := sc.reserveStock(ctx, cartID, merged); err != nil {
+			return err
+		}
+
+		// Re-read for the merged items and the bumped version.
+		cart, err = tx.CartWithItemsByCartID(ctx, cartID)
+		if err != nil {
+			return fmt.Errorf("cart: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// guestCart returns the guest cart to be merged if the principal of the context holds its token.
+func (sc *ShoppingCart) guestCart(ctx context.Context, st storer, cartID int64) (*Cart, error) {
+	cart, err := sc.authorizedCart(ctx, st, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cart.GuestToken == "":
+		return nil, &InvalidParamError{Name: "guest_cart_id", Err: fmt.Errorf("cart %d is not a guest cart: %w", cartID, ErrInvalidArgument)}
+	case cart.OrderID != 0:
+		return nil, fmt.Errorf("cart %d order %d: %w", cartID, cart.OrderID, ErrCartCheckedOut)
+	case cart.Status != CartActive:
+		return nil, fmt.Errorf("cart %d is %s: %w", cartID, cart.Status, ErrCartInactive)
+	}
+
+	return cart, nil
+}
+
+// authorize checks that the principal of the context may access carts of the user.
+// Admins may access carts of every user.
+func (sc *ShoppingCart) authorize(ctx context.Context, userID int64) error {
+	p, ok := PrincipalFromContext(ctx)
+	switch {
+	case !ok:
+		return ErrUnauthenticated
+	case p.HasScope(ScopeAdmin):
+		return nil
+	case p.UserID == 0 && p.GuestToken == "":
+		return ErrUnauthenticated
+	case p.UserID == 0:
+		return fmt.Errorf("guest accessing user %d: %w", userID, ErrForbidden)
+	case p.UserID != userID:
+		return fmt.Errorf("user %d accessing user %d: %w", p.UserID, userID, ErrForbidden)
+	}
+	return nil
+}
+
+// authorizeGuest checks that the principal of the context holds the guest token of the given
+// hash. Admins may access carts of every guest.
+func (sc *ShoppingCart) authorizeGuest(ctx context.Context, tokenHash string) error {
+	p, ok := PrincipalFromContext(ctx)
+	switch {
+	case !ok:
+		return ErrUnauthenticated
+	case p.HasScope(ScopeAdmin):
+		return nil
+	case p.GuestToken == "" && p.UserID == 0:
+		return ErrUnauthenticated
+	case p.GuestToken == "" || hashGuestToken(p.GuestToken) != tokenHash:
+		return fmt.Errorf("accessing a cart of another guest: %w", ErrForbidden)
+	}
+	return nil
+}
+
+// authorizedCart returns the cart if the principal of the context may access it.
+func (sc *ShoppingCart) authorizedCart(ctx context.Context, st storer, cartID int64) (*Cart, error) {
+	cart, err := st.CartWithItemsByCartID(ctx, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("cart: %w", err)
+	}
+
+	if cart.GuestToken != "" {
+		err = sc.authorizeGuest(ctx, cart.GuestToken)
+	} else {
+		err = sc.authorize(ctx, cart.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// mutableCart returns the cart to be mutated if the principal of the context may access it
+// and the cart version satisfies the precondition of the context.
+func (sc *ShoppingCart) mutableCart(ctx context.Context, st storer, cartID int64) (*Cart, error) {
+	cart, err := sc.authorizedCart(ctx, st, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cart.OrderID != 0:
+		return nil, fmt.Errorf("cart %d order %d: %w", cartID, cart.OrderID, ErrCartCheckedOut)
+	case cart.Status != CartActive:
+		return nil, fmt.Errorf("cart %d is %s: %w", cartID, cart.Status, ErrCartInactive)
+	}
+
+	if versions, ok := cartVersionsFromContext(ctx); ok {
+		for _, v := range versions {
+			if v == cart.Version {
+				return cart, nil
+			}
+		}
+		return nil, fmt.Errorf("cart %d version %d: %w", cartID, cart.Version, ErrPreconditionFailed)
+	}
+
+	return cart, nil
+}
+
+// WithCartVersions returns a context which restricts cart mutations to the given cart versions,
+// mutations of other versions fail with ErrPreconditionFailed.
+func WithCartVersions(ctx context.Context, versions ...int64) context.Context {
+	return context.WithValue(ctx, ctxCartVersions, versions)
+}
+
+func cartVersionsFromContext(ctx context.Context) ([]int64, bool) {
+	vv, ok := ctx.Value(ctxCartVersions).([]int64)
+	return vv, ok
+}
+
+// Context cart constants.
+type ctxCartKey uint8
+
+const ctxCartVersions ctxCartKey = 0
+
+// checkProducts checks that products of the items are known to the catalog and active.
+func (sc *ShoppingCart) checkProducts(ctx context.Context, items []*LineItem) error {
+	if sc.catalog == nil || len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(items))
+	for j, item := range items {
+		ids[j] = item.ProductID
+	}
+
+	products, err := sc.catalog.Products(ctx, ids...)
+	if err != nil {
+		return fmt.Errorf("catalog: %w", err)
+	}
+
+	for j, item := range items {
+		if p, ok := products[item.ProductID]; !ok || !p.Active {
+			return &InvalidParamError{
+				Name: fmt.Sprintf("line_items[%d].product_id", j),
+				Err:  fmt.Errorf("product %d is not available: %w", item.ProductID, ErrInvalidProduct),
+			}
+		}
+	}
+
+	return nil
+}
+
+// snapshotPrices sets unit prices of the items to be added to a cart to the current prices.
+func (sc *ShoppingCart) snapshotPrices(ctx context.Context, items []*LineItem) error {
+	if sc.prices == nil || len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(items))
+	for j, item := range items {
+		ids[j] = item.ProductID
+	}
+
+	prices, err := sc.prices.UnitPrices(ctx, ids...)
+	if err != nil {
+		return fmt.Errorf("prices: %w", err)
+	}
+
+	for _, item := range items {
+		item.UnitPrice = prices[item.ProductID]
+	}
+
+	return nil
+}
+
+// enrichLineItems fills in product details of the items and flags items which prices have
+// changed since they have been added. Items added without a price get the current price.
+// Items of products gone from the catalog or without a price are left as they are.
+func (sc *ShoppingCart) enrichLineItems(ctx context.Context, items []*LineItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(items))
+	for j, item := range items {
+		ids[j] = item.ProductID
+	}
+
+	if sc.catalog != nil {
+		products, err := sc.catalog.Products(ctx, ids...)
+		if err != nil {
+			return fmt.Errorf("catalog: %w", err)
+		}
+
+		for _, item := range items {
+			if p, ok := products[item.ProductID]; ok {
+				item.SKU, item.Name, item.Category, item.TaxClass = p.SKU, p.Name, p.Category, p.TaxClass
+			}
+		}
+	}
+
+	if sc.prices != nil {
+		prices, err := sc.prices.UnitPrices(ctx, ids...)
+		if err != nil {
+			return fmt.Errorf("prices: %w", err)
+		}
+
+		for _, item := range items {
+			p, ok := prices[item.ProductID]
+			switch {
+			case !ok:
+			case item.UnitPrice.Currency == "":
+				item.UnitPrice = p
+			case item.UnitPrice != p:
+				item.PriceChanged, item.CurrentUnitPrice = true, p
+			}
+		}
+	}
+
+	return nil
+}
+
+// reserveStock reserves stock for the quantities of the cart items, or only checks that the
+// quantities are available if reservations are disabled. Reservations are made within the cart
+// transaction so a failed reservation rolls the mutation back, a reservation of a mutation
+// which fails to commit expires.
+func (sc *ShoppingCart) reserveStock(ctx context.Context, cartID int64, items []*LineItem) error {
+	if sc.inventory == nil || len(items) == 0 {
+		return nil
+	}
+
+	quantities := make(map[int64]int64, len(items))
+	for _, i := range items {
+		quantities[i.ProductID] = i.Quantity
+	}
+
+	var until time.Time
+	if sc.reservationTTL > 0 {
+		until = time.Now().Add(sc.reservationTTL)
+	}
+
+	if err := sc.inventory.Reserve(ctx, cartID, quantities, until); err != nil {
+		return fmt.Errorf("inventory: %w", err)
+	}
+	return nil
+}
+
+// withdrawStock takes the quantities of the items of a checked out cart out of stock, returns
+// the withdrawn quantities.
+func (sc *ShoppingCart) withdrawStock(ctx context.Context, cartID int64, items []*LineItem) (map[int64]int64, error) {
+	if sc.inventory == nil {
+		return nil, nil
+	}
+
+	quantities := make(map[int64]int64, len(items))
+	for _, i := range items {
+		quantities[i.ProductID] = i.Quantity
+	}
+
+	if err := sc.inventory.Withdraw(ctx, cartID, quantities); err != nil {
+		return nil, fmt.Errorf("inventory: %w", err)
+	}
+	return quantities, nil
+}
+
+// restock gives back withdrawn stock. A new context is used as the failed request's one may
+// be done already.
+func (sc *ShoppingCart) restock(quantities map[int64]int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := sc.inventory.Restock(ctx, quantities); err != nil {
+		return fmt.Errorf("inventory: %w", err)
+	}
+	return nil
+}
+
+// releaseStock releases stock reserved for the cart, of the given products only if any.
+func (sc *ShoppingCart) releaseStock(ctx context.Context, cartID int64, productIDs ...int64) error {
+	if sc.inventory == nil {
+		return nil
+	}
+
+	if err := sc.inventory.Release(ctx, cartID, productIDs...); err != nil {
+		return fmt.Errorf("inventory: %w", err)
+	}
+	return nil
+}
+
+// voidPayment voids an authorized payment.
+func (sc *ShoppingCart) voidPayment(ctx context.Context, p *Payment) error {
+	if err := sc.payments.Void(ctx, p.AuthorizationID); err != nil {
+		return fmt.Errorf("payment %d void: %w", p.ID, err)
+	}
+
+	if err := sc.storage.PaymentStatusSet(ctx, p.ID, PaymentVoided); err != nil {
+		return fmt.Errorf("payment %d: %w", p.ID, err)
+	}
+
+	p.Status = PaymentVoided
+	return nil
+}
+
+// voidCartPayment voids the payment of the cart if it is authorized.
+func (sc *ShoppingCart) voidCartPayment(ctx context.Context, cartID int64) error {
+	if sc.payments == nil {
+		return nil
+	}
+
+	p, err := sc.storage.PaymentByCartID(ctx, cartID)
+	switch {
+	case errors.Is(err, ErrPaymentNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("payment: %w", err)
+	case p.Status != PaymentAuthorized:
+		return nil
+	}
+
+	return sc.voidPayment(ctx, p)
+}
+
+// voidStalePayment voids the authorized payment of a cart modified since the payment. Errors
+// are logged only as the modification has been committed, a payment left authorized is voided
+// when the cart is paid or modified again.
+func (sc *ShoppingCart) voidStalePayment(ctx context.Context, cartID int64) {
+	if sc.payments == nil {
+		return
+	}
+
+	p, err := sc.storage.PaymentByCartID(ctx, cartID)
+	if errors.Is(err, ErrPaymentNotFound) {
+		return
+	} else if err != nil {
+		log.Printf("cart %d payment: %s", cartID, err)
+		return
+	}
+
+	if p.Status != PaymentAuthorized {
+		return
+	}
+
+	cart, err := sc.storage.CartWithItemsByCartID(ctx, cartID)
+	if err != nil {
+		log.Printf("cart %d: %s", cartID, err)
+		return
+	}
+
+	if cart.Version == p.CartVersion {
+		return
+	}
+
+	if err := sc.voidPayment(ctx, p); err != nil {
+		log.Printf("cart %d: %s", cartID, err)
+	}
+}
+
+// shippingRate returns the rate of the shipping method of the enriched cart.
+func (sc *ShoppingCart) shippingRate(ctx context.Context, cart *Cart) (ShippingRate, error) {
+	if sc.shipping == nil {
+		return ShippingRate{}, fmt.Errorf("shipping method %q: %w", cart.ShippingMethod, ErrShippingMethodNotAvailable)
+	}
+
+	rates, err := sc.shipping.ShippingRates(ctx, cart)
+	if err != nil {
+		return ShippingRate{}, fmt.Errorf("shipping rates: %w", err)
+	}
+
+	for _, r := range rates {
+		if r.Code == cart.ShippingMethod {
+			return r, nil
+		}
+	}
+
+	return ShippingRate{}, fmt.Errorf("shipping method %q: %w", cart.ShippingMethod, ErrShippingMethodNotAvailable)
+}
+
+// revalidateShippingMethod removes the shipping method of a mutated cart if the method is not
+// available for its items and shipping address any more.
+func (sc *ShoppingCart) revalidateShippingMethod(ctx context.Context, tx storer, cartID int64) error {
+	if sc.shipping == nil {
+		return nil
+	}
+
+	cart, err := tx.CartWithItemsByCartID(ctx, cartID)
+	if err != nil {
+		return fmt.Errorf("cart: %w", err)
+	}
+
+	if cart.ShippingMethod == "" {
+		return nil
+	}
+
+	if err := sc.enrichLineItems(ctx, cart.LineItems); err != nil {
+		return err
+	}
+
+	if _, err := sc.shippingRate(ctx, cart); !errors.Is(err, ErrShippingMethodNotAvailable) {
+		return err
+	}
+
+	if err := tx.CartShippingMethodSet(ctx, cartID, ""); err != nil {
+		return fmt.Errorf("shipping method: %w", err)
+	}
+
+	return nil
+}
+
+// applyShipping adds the shipping charge of the shipping method of the enriched cart. Methods
+// not available any more are not charged.
+func (sc *ShoppingCart) applyShipping(ctx context.Context, cart *Cart) error {
+	if sc.shipping == nil || cart.ShippingMethod == "" {
+		return nil
+	}
+
+	r, err := sc.shippingRate(ctx, cart)
+	if errors.Is(err, ErrShippingMethodNotAvailable) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	cart.Adjustments = append(cart.Adjustments, Adjustment{Kind: AdjustmentShipping, Label: r.Name, Amount: r.Amount})
+	return nil
+}
+
+// applyPromotions adds discounts of the rules the cart qualifies for and of its coupons.
+func (sc *ShoppingCart) applyPromotions(ctx context.Context, cart *Cart) error {
+	if err := sc.applyRules(ctx, cart); err != nil {
+		return err
+	}
+
+	return sc.applyCoupons(ctx, cart)
+}
+
+// applyRules adds discounts of the rules the cart qualifies for and records the rules fired.
+func (sc *ShoppingCart) applyRules(ctx context.Context, cart *Cart) error {
+	if sc.rules == nil {
+		return nil
+	}
+
+	rules, err := sc.rules.Rules(ctx)
+	if err != nil {
+		return fmt.Errorf("rules: %w", err)
+	}
+
+	tm := time.Now()
+	for _, r := range rules {
+		if r.applicable(cart, tm) != nil {
+			continue
+		}
+
+		n := adjustmentsNum(cart)
+		if err := r.apply(cart); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+
+		// Rules adding no adjustment, e.g. without items in their category, have not fired.
+		if adjustmentsNum(cart) == n {
+			continue
+		}
+
+		cart.FiredRules = append(cart.FiredRules, FiredRule{Name: r.Name, Description: r.Description})
+	}
+
+	return nil
+}
+
+// adjustmentsNum returns the number of adjustments of the cart and its items.
+func adjustmentsNum(cart *Cart) int {
+	n := len(cart.Adjustments)
+	for _, i := range cart.LineItems {
+		n += len(i.Adjustments)
+	}
+	return n
+}
+
+// applyCoupons adds discounts of the coupons applied to the cart. Coupons of promotions gone
+// or not applicable any more are skipped.
+func (sc *ShoppingCart) applyCoupons(ctx context.Context, cart *Cart) error {
+	if sc.promotions == nil {
+		return nil
+	}
+
+	tm := time.Now()
+	for _, code := range cart.Coupons {
+		p, err := sc.promotions.PromotionByCode(ctx, code)
+		if errors.Is(err, ErrCouponNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("promotions: %w", err)
+		}
+
+		if p.applicable(cart, tm) != nil {
+			continue
+		}
+
+		if err := p.apply(cart); err != nil {
+			return fmt.Errorf("coupon %q: %w", code, err)
+		}
+	}
+
+	return nil
+}
+
+// applyTaxes adds taxes of the discounted cart.
+func (sc *ShoppingCart) applyTaxes(ctx context.Context, cart *Cart) error {
+	if sc.taxes == nil || cart.ShippingAddress == nil {
+		return nil
+	}
+
+	taxes, err := sc.taxes.Taxes(ctx, cart)
+	if err != nil {
+		return fmt.Errorf("taxes: %w", err)
+	}
+
+	cart.Adjustments = append(cart.Adjustments, taxes...)
+	return nil
+}
+
+// priceCart enriches items of the cart, applies its shipping charges, promotions and taxes and
+// computes the cart totals.
+func (sc *ShoppingCart) priceCart(ctx context.Context, cart *Cart) error {
+	if err := sc.enrichLineItems(ctx, cart.LineItems); err != nil {
+		return err
+	}
+
+	return sc.totalCart(ctx, cart)
+}
+
+// totalCart applies shipping charges, promotions and taxes to the enriched cart and computes
+// its totals. Shipping charges go first so that promotions may discount them.
+func (sc *ShoppingCart) totalCart(ctx context.Context, cart *Cart) error {
+	if err := sc.applyShipping(ctx, cart); err != nil {
+		return err
+	}
+
+	if err := sc.applyPromotions(ctx, cart); err != nil {
+		return err
+	}
+
+	if err := sc.applyTaxes(ctx, cart); err != nil {
+		return err
+	}
+
+	t, err := CartTotals(cart)
+	if err != nil {
+		return fmt.Errorf("cart %d totals: %w", cart.ID, err)
+	}
+
+	cart.Totals = &t
+	return nil
+}
+
+// priceLineItems prices the cart and updates the items with their priced versions of the cart.
+func (sc *ShoppingCart) priceLineItems(ctx context.Context, cart *Cart, items []*LineItem) error {
+	if err := sc.priceCart(ctx, cart); err != nil {
+		return err
+	}
+
+	priced := make(map[int64]*LineItem, len(cart.LineItems))
+	for _, i := range cart.LineItems {
+		priced[i.ID] = i
+	}
+
+	for _, item := range items {
+		if i, ok := priced[item.ID]; ok && i != item {
+			*item = *i
+		}
+	}
+
+	return nil
+}
+
+// cartVersion returns the version of a cart read within the transaction mutating it, the
+// version of the mutated cart.
+func cartVersion(ctx context.Context, tx storer, cartID int64) (int64, error) {
+	cart, err := tx.CartWithItemsByCartID(ctx, cartID)
+	if err != nil {
+		return 0, fmt.Errorf("cart: %w", err)
+	}
+	return cart.Version, nil
+}
+
+// validateLineItems checks items to be added to a cart, each product may be given once.
+func (sc *ShoppingCart) validateLineItems(items []*LineItem) error {
+	seen := make(map[int64]bool, len(items))
+	for j, item := range items {
+		switch {
+		case item.ProductID <= 0:
+			return &InvalidParamError{Name: fmt.Sprintf("line_items[%d].product_id", j), Err: ErrInvalidProduct}
+		case item.Quantity <= 0:
+			return &InvalidParamError{Name: fmt.Sprintf("line_items[%d].quantity", j), Err: ErrInvalidQuantity}
+		case seen[item.ProductID]:
+			return &InvalidParamError{Name: fmt.Sprintf("line_items[%d].product_id", j), Err: fmt.Errorf("product %d given twice: %w", item.ProductID, ErrInvalidArgument)}
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
 }
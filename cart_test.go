@@ -3,12 +3,74 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gojuno/minimock/v3"
 )
 
+func TestShoppingCart_WithTx(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CommitMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		err := sc.WithTx(context.Background(), nil, func(storer) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		errFn := errors.New("fn")
+		err := sc.WithTx(context.Background(), nil, func(storer) error { return errFn })
+		if !errors.Is(err, errFn) {
+			t.Errorf("err exp: %v, got: %v", errFn, err)
+		}
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		defer func() {
+			if p := recover(); p != "fn" {
+				t.Errorf("panic exp: %v, got: %v", "fn", p)
+			}
+		}()
+
+		_ = sc.WithTx(context.Background(), nil, func(storer) error { panic("fn") })
+	})
+}
+
 func TestShoppingCart_CartCreate(t *testing.T) {
 	c := &Cart{
 		UserID: 10,
@@ -17,7 +79,9 @@ func TestShoppingCart_CartCreate(t *testing.T) {
 		},
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	pctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	ctx, cancel := context.WithCancel(pctx)
 	defer cancel()
 
 	mc := minimock.NewController(t)
@@ -25,7 +89,7 @@ func TestShoppingCart_CartCreate(t *testing.T) {
 
 	tx := NewStorerMock(mc)
 	tx = tx.CartCreateMock.Expect(ctx, c).Return(nil)
-	tx = tx.LineItemsUpsertMock.Expect(ctx, c.ID, c.LineItems...).Return(nil)
+	tx = tx.LineItemsUpsertMock.Expect(ctx, c.ID, UpsertSet, c.LineItems...).Return(nil)
 	tx = tx.CommitMock.Expect().Return(nil)
 
 	st := NewStorerMock(mc)
@@ -33,24 +97,534 @@ func TestShoppingCart_CartCreate(t *testing.T) {
 
 	sc := &ShoppingCart{storage: st}
 
-	cart, err := sc.CartCreate(context.Background(), c.UserID, c.LineItems)
+	cart, err := sc.CartCreate(pctx, c.UserID, c.LineItems)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	// Items are not priced without a price source.
+	c.Totals = &Totals{}
+
 	if !reflect.DeepEqual(c, cart) {
 		t.Errorf("carts do not match\nexp: %+v\ngot: %+v", c, cart)
 	}
+
+	t.Run("other user", func(t *testing.T) {
+		sc := &ShoppingCart{storage: NewStorerMock(t)}
+
+		if _, err := sc.CartCreate(pctx, 11, c.LineItems); !errors.Is(err, ErrForbidden) {
+			t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartCreateMock.Return(nil)
+		tx = tx.LineItemsUpsertMock.Return(errors.New("upsert"))
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		if _, err := sc.CartCreate(pctx, c.UserID, c.LineItems); err == nil {
+			t.Error("err exp, got none")
+		}
+	})
+	t.Run("catalog", func(t *testing.T) {
+		products := map[int64]*Product{
+			1: {ID: 1, SKU: "SKU-1", Name: "One", UnitPrice: Money{Amount: 499, Currency: "EUR"}, Active: true},
+			2: {ID: 2, SKU: "SKU-2", Name: "Two", UnitPrice: Money{Amount: 1250, Currency: "EUR"}},
+		}
+
+		tests := []struct {
+			name  string
+			items []*LineItem
+			err   error
+		}{
+			{"available", []*LineItem{{ProductID: 1, Quantity: 2}}, nil},
+			{"inactive", []*LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}, ErrInvalidProduct},
+			{"unknown", []*LineItem{{ProductID: 3, Quantity: 1}}, ErrInvalidProduct},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mc := minimock.NewController(t)
+				defer mc.Finish()
+
+				cat := NewCatalogMock(mc)
+				cat = cat.ProductsMock.Return(products, nil)
+
+				st := NewStorerMock(mc)
+				if tt.err == nil {
+					tx := NewStorerMock(mc)
+					tx = tx.CartCreateMock.Return(nil)
+					tx = tx.LineItemsUpsertMock.Return(nil)
+					tx = tx.CommitMock.Expect().Return(nil)
+
+					st = st.BeginTxMock.Return(tx, nil)
+				}
+
+				sc := &ShoppingCart{storage: st, catalog: cat, prices: CatalogPrices{cat}}
+
+				cart, err := sc.CartCreate(pctx, c.UserID, tt.items)
+				if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+					t.Fatalf("err exp: %v, got: %v", tt.err, err)
+				}
+
+				if tt.err != nil {
+					return
+				}
+
+				exp := &LineItem{ProductID: 1, Quantity: 2, SKU: "SKU-1", Name: "One", UnitPrice: Money{Amount: 499, Currency: "EUR"}}
+				if !reflect.DeepEqual(exp, cart.LineItems[0]) {
+					t.Errorf("items do not match\nexp: %+v\ngot: %+v", exp, cart.LineItems[0])
+				}
+			})
+		}
+	})
+}
+
+func TestShoppingCart_CartShow(t *testing.T) {
+	c := &Cart{ID: 1, UserID: 10}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		err       error
+	}{
+		{"owner", &Principal{UserID: 10}, nil},
+		{"admin", &Principal{UserID: 1, Scopes: []string{ScopeAdmin}}, nil},
+		{"other user", &Principal{UserID: 11}, ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := withPrincipal(context.Background(), tt.principal)
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			st := NewStorerMock(mc)
+			st = st.CartWithItemsByCartIDMock.Expect(ctx, c.ID).Return(c, nil)
+
+			sc := &ShoppingCart{storage: st}
+
+			cart, err := sc.CartShow(ctx, c.ID)
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Fatalf("err exp: %v, got: %v", tt.err, err)
+			}
+
+			if tt.err == nil && cart != c {
+				t.Errorf("carts do not match\nexp: %+v\ngot: %+v", c, cart)
+			}
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		st := NewStorerMock(mc)
+		st = st.CartWithItemsByCartIDMock.Return(c, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		if _, err := sc.CartShow(context.Background(), c.ID); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("err exp: %v, got: %v", ErrUnauthenticated, err)
+		}
+	})
+	t.Run("catalog", func(t *testing.T) {
+		c := &Cart{ID: 1, UserID: 10, LineItems: []*LineItem{{ID: 5, ProductID: 1, Quantity: 2}, {ID: 6, ProductID: 3, Quantity: 1}}}
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		st := NewStorerMock(mc)
+		st = st.CartWithItemsByCartIDMock.Return(c, nil)
+
+		cat := NewCatalogMock(mc)
+		cat = cat.ProductsMock.Set(func(_ context.Context, ids ...int64) (map[int64]*Product, error) {
+			if !reflect.DeepEqual([]int64{1, 3}, ids) {
+				t.Errorf("ids exp: %v, got: %v", []int64{1, 3}, ids)
+			}
+			return map[int64]*Product{1: {ID: 1, SKU: "SKU-1", Name: "One", UnitPrice: Money{Amount: 499, Currency: "EUR"}}}, nil
+		})
+
+		sc := &ShoppingCart{storage: st, catalog: cat, prices: CatalogPrices{cat}}
+
+		cart, err := sc.CartShow(withPrincipal(context.Background(), &Principal{UserID: 10}), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := []*LineItem{
+			{ID: 5, ProductID: 1, Quantity: 2, SKU: "SKU-1", Name: "One", UnitPrice: Money{Amount: 499, Currency: "EUR"}},
+			// Products gone from the catalog are kept as they are.
+			{ID: 6, ProductID: 3, Quantity: 1},
+		}
+
+		if !reflect.DeepEqual(exp, cart.LineItems) {
+			t.Errorf("items do not match\nexp: %+v\ngot: %+v", exp, cart.LineItems)
+		}
+
+		eur := Money{Amount: 998, Currency: "EUR"}
+		if expT := (&Totals{eur, Money{Currency: "EUR"}, Money{Currency: "EUR"}, Money{Currency: "EUR"}, eur}); !reflect.DeepEqual(expT, cart.Totals) {
+			t.Errorf("totals do not match\nexp: %+v\ngot: %+v", expT, cart.Totals)
+		}
+	})
+}
+
+func TestShoppingCart_CartReprice(t *testing.T) {
+	old, cur := Money{Amount: 499, Currency: "EUR"}, Money{Amount: 549, Currency: "EUR"}
+
+	prices := PriceTable{1: old, 2: {Amount: 1250, Currency: "EUR"}}
+	sc := &ShoppingCart{storage: NewMemory(), prices: prices}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices[1] = cur
+
+	// Adding more of the product keeps the price it has been added at.
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i := cart.LineItems[0]; !i.PriceChanged || i.UnitPrice != old || i.CurrentUnitPrice != cur {
+		t.Errorf("changed item exp: %v, %v, got: %+v", old, cur, i)
+	}
+	if i := cart.LineItems[1]; i.PriceChanged {
+		t.Errorf("unchanged item exp, got: %+v", i)
+	}
+	if g := cart.Totals.GrandTotal.Amount; g != 3*499+1250 {
+		t.Errorf("grand total exp: %d, got: %d", 3*499+1250, g)
+	}
+
+	repriced, err := sc.CartReprice(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i := repriced.LineItems[0]; i.PriceChanged || i.UnitPrice != cur {
+		t.Errorf("repriced item exp: %v, got: %+v", cur, i)
+	}
+	if g := repriced.Totals.GrandTotal.Amount; g != 3*549+1250 {
+		t.Errorf("grand total exp: %d, got: %d", 3*549+1250, g)
+	}
+	if repriced.Version <= cart.Version {
+		t.Errorf("version exp > %d, got: %d", cart.Version, repriced.Version)
+	}
+
+	// The new prices are kept.
+	cart, err = sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := cart.LineItems[0]; i.PriceChanged || i.UnitPrice != cur {
+		t.Errorf("repriced item exp: %v, got: %+v", cur, i)
+	}
+
+	// Nothing to reprice.
+	again, err := sc.CartReprice(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Version != repriced.Version {
+		t.Errorf("version exp: %d, got: %d", repriced.Version, again.Version)
+	}
+
+	if _, err := sc.CartReprice(withPrincipal(context.Background(), &Principal{UserID: 11}), c.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+	}
+}
+
+func TestShoppingCart_CartsByUser(t *testing.T) {
+	carts := []*Cart{{ID: 1, UserID: 10}, {ID: 2, UserID: 10}, {ID: 3, UserID: 10}}
+
+	tests := []struct {
+		name   string
+		q      CartsQuery
+		stQ    CartsQuery
+		carts  []*Cart
+		exp    []*Cart
+		nextID int64
+	}{
+		{"default limit", CartsQuery{}, CartsQuery{Limit: cartsDefaultLimit + 1}, carts, carts, 0},
+		{"next page", CartsQuery{Limit: 2}, CartsQuery{Limit: 3}, carts, carts[:2], 2},
+		{"last page", CartsQuery{AfterID: 2, Limit: 2}, CartsQuery{AfterID: 2, Limit: 3}, carts[2:], carts[2:], 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			st := NewStorerMock(mc)
+			st = st.CartsByUserIDMock.Expect(ctx, 10, tt.stQ).Return(tt.carts, nil)
+
+			sc := &ShoppingCart{storage: st}
+
+			got, nextID, err := sc.CartsByUser(ctx, 10, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tt.exp, got) {
+				t.Errorf("carts do not match\nexp: %+v\ngot: %+v", tt.exp, got)
+			}
+			if nextID != tt.nextID {
+				t.Errorf("next id exp: %d, got: %d", tt.nextID, nextID)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name      string
+		principal *Principal
+		q         CartsQuery
+		err       error
+	}{
+		{"other user", &Principal{UserID: 11}, CartsQuery{}, ErrForbidden},
+		{"negative limit", &Principal{UserID: 10}, CartsQuery{Limit: -1}, ErrInvalidArgument},
+		{"limit too big", &Principal{UserID: 10}, CartsQuery{Limit: cartsMaxLimit + 1}, ErrInvalidArgument},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			sc := &ShoppingCart{storage: NewStorerMock(mc)}
+
+			_, _, err := sc.CartsByUser(withPrincipal(context.Background(), tt.principal), 10, tt.q)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_ActiveCart(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	sc := &ShoppingCart{storage: NewMemory(), prices: PriceTable{1: eur(500)}}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.ActiveCart(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID == 0 || c.UserID != 10 || c.Status != CartActive || c.Totals == nil {
+		t.Errorf("created active cart exp, got: %+v", c)
+	}
+
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := sc.ActiveCart(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cart.ID != c.ID || len(cart.LineItems) != 1 || cart.Totals.GrandTotal != eur(1000) {
+		t.Errorf("cart %d with items exp, got: %+v", c.ID, cart)
+	}
+
+	// A new cart supersedes the active one.
+	created, err := sc.CartCreate(ctx, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cart, err = sc.ActiveCart(ctx, 10); err != nil || cart.ID != created.ID {
+		t.Errorf("active cart exp: %d, got: %+v, %v", created.ID, cart, err)
+	}
+	if cart, err = sc.CartShow(ctx, c.ID); err != nil || cart.Status != CartAbandoned {
+		t.Errorf("cart status exp: %s, got: %+v, %v", CartAbandoned, cart, err)
+	}
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); !errors.Is(err, ErrCartInactive) {
+		t.Errorf("err exp: %v, got: %v", ErrCartInactive, err)
+	}
+
+	// A checked out cart is replaced by a new one.
+	if _, _, err := sc.LineItemAdd(ctx, created.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sc.Checkout(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if cart, err = sc.ActiveCart(ctx, 10); err != nil || cart.ID == created.ID || len(cart.LineItems) != 0 {
+		t.Errorf("new empty cart exp, got: %+v, %v", cart, err)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{"anonymous", context.Background(), ErrUnauthenticated},
+		{"other user", withPrincipal(context.Background(), &Principal{UserID: 11}), ErrForbidden},
+		{"guest", withPrincipal(context.Background(), &Principal{GuestToken: "tok"}), ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sc.ActiveCart(tt.ctx, 10); !errors.Is(err, tt.err) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("abandoned cart", func(t *testing.T) {
+		inv := newStubInventory(map[int64]int64{1: 3})
+		gw := newFakeGateway()
+		sc := &ShoppingCart{storage: NewMemory(), prices: PriceTable{1: eur(500)}, inventory: inv, reservationTTL: time.Minute, payments: gw}
+
+		c, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _, err := sc.CartPay(ctx, c.ID, "tok_visa")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The stock held by the abandoned cart is available to the new one.
+		created, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 3}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s := gw.status(p.AuthorizationID); s != PaymentVoided {
+			t.Errorf("abandoned cart payment status exp: %s, got: %s", PaymentVoided, s)
+		}
+		if inv.reserved(c.ID, 1) != 0 || inv.reserved(created.ID, 1) != 3 {
+			t.Errorf("reserved exp: %d, %d, got: %d, %d", 0, 3, inv.reserved(c.ID, 1), inv.reserved(created.ID, 1))
+		}
+	})
+
+	t.Run("created concurrently", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		c := &Cart{ID: 7, UserID: 10, Status: CartActive}
+
+		tx := NewStorerMock(mc)
+		tx = tx.ActiveCartByUserIDMock.Return(nil, ErrCartNotFound)
+		tx = tx.CartCreateMock.Return(ErrConflict)
+		tx = tx.RollbackMock.Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+		st = st.ActiveCartByUserIDMock.Return(c, nil)
+
+		sc := &ShoppingCart{storage: st}
+		cart, err := sc.ActiveCart(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.ID != c.ID {
+			t.Errorf("cart exp: %d, got: %d", c.ID, cart.ID)
+		}
+	})
 }
 
-func TestShoppingCart_CartShow(t *testing.T) {}
+func TestShoppingCart_CartEmpty(t *testing.T) {
+	c := &Cart{ID: 1, UserID: 10, Status: CartActive}
+
+	t.Run("owner", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(c, nil)
+		tx = tx.CartEmptyMock.Return(nil)
+		tx = tx.CommitMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		if _, err := sc.CartEmpty(withPrincipal(context.Background(), &Principal{UserID: 10}), c.ID); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(c, nil)
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		_, err := sc.CartEmpty(withPrincipal(context.Background(), &Principal{UserID: 11}), c.ID)
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+		}
+	})
+
+	t.Run("precondition", func(t *testing.T) {
+		c := &Cart{ID: 1, UserID: 10, Status: CartActive, Version: 3}
+		ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+		tests := []struct {
+			name     string
+			versions []int64
+			err      error
+		}{
+			{"match", []int64{2, 3}, nil},
+			{"mismatch", []int64{2}, ErrPreconditionFailed},
+			{"no versions", nil, ErrPreconditionFailed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mc := minimock.NewController(t)
+				defer mc.Finish()
+
+				tx := NewStorerMock(mc)
+				tx = tx.CartWithItemsByCartIDMock.Return(c, nil)
+				if tt.err == nil {
+					tx = tx.CartEmptyMock.Return(nil)
+					tx = tx.CommitMock.Expect().Return(nil)
+				} else {
+					tx = tx.RollbackMock.Expect().Return(nil)
+				}
+
+				st := NewStorerMock(mc)
+				st = st.BeginTxMock.Return(tx, nil)
+
+				sc := &ShoppingCart{storage: st}
 
-func TestShoppingCart_CartEmpty(t *testing.T) {}
+				_, err := sc.CartEmpty(WithCartVersions(ctx, tt.versions...), c.ID)
+				if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+					t.Errorf("err exp: %v, got: %v", tt.err, err)
+				}
+			})
+		}
+	})
+}
 
 func TestShoppingCart_LineItemAdd(t *testing.T) {
 	c := &Cart{
 		ID:     1,
 		UserID: 10,
+		Status: CartActive,
 		LineItems: []*LineItem{
 			{ID: 1, ProductID: 1, Quantity: 1},
 			{ID: 2, ProductID: 2, Quantity: 2},
@@ -62,20 +636,51 @@ func TestShoppingCart_LineItemAdd(t *testing.T) {
 		{ProductID: 3, Quantity: 1},
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	pctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	ctx, cancel := context.WithCancel(pctx)
 	defer cancel()
 
 	mc := minimock.NewController(t)
 	defer mc.Finish()
 
+	// The cart is read to be authorized and then to price the added items.
+	added := &Cart{
+		ID:      1,
+		UserID:  10,
+		Version: 3,
+		LineItems: []*LineItem{
+			{ID: 1, CartID: 1, ProductID: 1, Quantity: 1},
+			{ID: 2, CartID: 1, ProductID: 2, Quantity: 4},
+			{ID: 99, CartID: 1, ProductID: 3, Quantity: 1},
+		},
+	}
+	var reads int
+
 	tx := NewStorerMock(mc)
-	tx = tx.CartWithItemsByCartIDMock.Expect(ctx, c.ID).Return(c, nil)
-	tx = tx.LineItemsUpsertMock.Set(func(_ context.Context, cartID int64, items ...*LineItem) error {
+	tx = tx.CartWithItemsByCartIDMock.Set(func(_ context.Context, cartID int64) (*Cart, error) {
 		if cartID != c.ID {
 			t.Errorf("cartID exp: %d, got: %d", c.ID, cartID)
 		}
 
+		reads++
+		if reads == 1 {
+			return c, nil
+		}
+		return added, nil
+	})
+	tx = tx.LineItemsUpsertMock.Set(func(_ context.Context, cartID int64, mode UpsertMode, items ...*LineItem) error {
+		if cartID != c.ID {
+			t.Errorf("cartID exp: %d, got: %d", c.ID, cartID)
+		}
+		if mode != UpsertIncrement {
+			t.Errorf("mode exp: %d, got: %d", UpsertIncrement, mode)
+		}
+
+		// The storer reports summed up quantities.
+		items[0].ID = 2
 		items[0].CartID = cartID
+		items[0].Quantity += 2
 
 		items[1].ID = 99
 		items[1].CartID = cartID
@@ -85,26 +690,931 @@ func TestShoppingCart_LineItemAdd(t *testing.T) {
 	tx = tx.CommitMock.Expect().Return(nil)
 
 	st := NewStorerMock(mc)
-	st = st.BeginTxMock.Expect(ctx, &sql.TxOptions{ReadOnly: true}).Return(tx, nil)
+	st = st.BeginTxMock.Expect(ctx, nil).Return(tx, nil)
 
 	sc := &ShoppingCart{storage: st}
 
-	items, err := sc.LineItemAdd(context.Background(), c.ID, ii)
+	items, version, err := sc.LineItemAdd(pctx, c.ID, ii)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if version != added.Version {
+		t.Errorf("version exp: %d, got: %d", added.Version, version)
+	}
+
 	exp := []*LineItem{
 		{ID: 2, CartID: 1, ProductID: 2, Quantity: 4},
 		{ID: 99, CartID: 1, ProductID: 3, Quantity: 1},
 	}
 
+	if reads != 2 {
+		t.Errorf("cart reads exp: %d, got: %d", 2, reads)
+	}
+
 	for i := range items {
 		if !reflect.DeepEqual(items[i], exp[i]) {
 			t.Errorf("items do not match\nexp: %+v\ngot: %+v", exp[i], items[i])
 		}
 
 	}
+
+	t.Run("other user", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(c, nil)
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		// The catalog is not looked up, unknown products are not told apart from known ones.
+		cat := NewCatalogMock(mc)
+
+		sc := &ShoppingCart{storage: st, catalog: cat, prices: CatalogPrices{cat}}
+
+		_, _, err := sc.LineItemAdd(withPrincipal(context.Background(), &Principal{UserID: 11}), c.ID, []*LineItem{{ProductID: 404, Quantity: 1}})
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+		}
+	})
 }
 
-func TestShoppingCart_LineItemRemove(t *testing.T) {}
+func TestShoppingCart_LineItemSetQuantity(t *testing.T) {
+	newCart := func() *Cart {
+		return &Cart{ID: 1, UserID: 10, Status: CartActive, LineItems: []*LineItem{
+			{ID: 5, CartID: 1, ProductID: 7, Quantity: 3},
+			{ID: 6, CartID: 1, ProductID: 8, Quantity: 1},
+		}}
+	}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	t.Run("set", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(newCart(), nil)
+		tx = tx.LineItemsUpsertMock.Set(func(_ context.Context, cartID int64, mode UpsertMode, items ...*LineItem) error {
+			if cartID != 1 || mode != UpsertSet || len(items) != 1 || items[0].ProductID != 7 || items[0].Quantity != 1 {
+				t.Errorf("unexpected upsert of cart %d: %+v", cartID, items)
+			}
+			return nil
+		})
+		tx = tx.CommitMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		item, _, err := sc.LineItemSetQuantity(ctx, 1, 5, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if item == nil || item.ID != 5 || item.Quantity != 1 {
+			t.Errorf("unexpected item: %+v", item)
+		}
+	})
+
+	t.Run("zero removes", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(newCart(), nil)
+		tx = tx.LineItemRemoveMock.Set(func(_ context.Context, cartID, itemID int64) error {
+			if cartID != 1 || itemID != 6 {
+				t.Errorf("cart, item exp: %d, %d, got: %d, %d", 1, 6, cartID, itemID)
+			}
+			return nil
+		})
+		tx = tx.CommitMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		item, _, err := sc.LineItemSetQuantity(ctx, 1, 6, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if item != nil {
+			t.Errorf("removed item exp nil, got: %+v", item)
+		}
+	})
+
+	t.Run("unknown item", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(newCart(), nil)
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		if _, _, err := sc.LineItemSetQuantity(ctx, 1, 9, 1); !errors.Is(err, ErrLineItemNotFound) {
+			t.Errorf("err exp: %v, got: %v", ErrLineItemNotFound, err)
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		sc := &ShoppingCart{storage: NewStorerMock(mc)}
+
+		if _, _, err := sc.LineItemSetQuantity(ctx, 1, 5, -1); !errors.Is(err, ErrInvalidQuantity) {
+			t.Errorf("err exp: %v, got: %v", ErrInvalidQuantity, err)
+		}
+	})
+}
+
+func TestShoppingCart_LineItemRemove(t *testing.T) {
+	c := &Cart{ID: 1, UserID: 10, Status: CartActive}
+
+	t.Run("owner", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(c, nil)
+		tx = tx.LineItemRemoveMock.Set(func(_ context.Context, cartID, itemID int64) error {
+			if cartID != c.ID || itemID != 5 {
+				t.Errorf("cart, item exp: %d, %d, got: %d, %d", c.ID, 5, cartID, itemID)
+			}
+			return nil
+		})
+		tx = tx.CommitMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		if _, err := sc.LineItemRemove(withPrincipal(context.Background(), &Principal{UserID: 10}), c.ID, 5); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		tx := NewStorerMock(mc)
+		tx = tx.CartWithItemsByCartIDMock.Return(c, nil)
+		tx = tx.RollbackMock.Expect().Return(nil)
+
+		st := NewStorerMock(mc)
+		st = st.BeginTxMock.Return(tx, nil)
+
+		sc := &ShoppingCart{storage: st}
+
+		_, err := sc.LineItemRemove(withPrincipal(context.Background(), &Principal{UserID: 11}), c.ID, 5)
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+		}
+	})
+}
+
+func TestShoppingCart_CouponApply(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	promotions := &JSONPromotions{promotions: map[string]*Promotion{
+		"WELCOME10": {Code: "WELCOME10", Kind: PromotionPercentOff, Percent: 10, MaxUsesPerUser: 1},
+		"B2G1":      {Code: "B2G1", Kind: PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, ProductIDs: []int64{1}},
+		"BIG":       {Code: "BIG", Kind: PromotionAmountOff, Amount: eur(500), MinSubtotal: eur(5000)},
+		"OVER":      {Code: "OVER", Kind: PromotionAmountOff, Amount: eur(500), EndsAt: time.Now().Add(-time.Hour)},
+	}}
+
+	sc := &ShoppingCart{storage: NewMemory(), prices: PriceTable{1: eur(500), 2: eur(1000)}, promotions: promotions}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := sc.CouponApply(ctx, c.ID, " welcome10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err = sc.CouponApply(ctx, c.ID, "B2G1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := []string{"WELCOME10", "B2G1"}; !reflect.DeepEqual(exp, cart.Coupons) {
+		t.Errorf("coupons exp: %v, got: %v", exp, cart.Coupons)
+	}
+	if exp := []Adjustment{{Kind: AdjustmentDiscount, Label: "WELCOME10", Amount: eur(250)}}; !reflect.DeepEqual(exp, cart.Adjustments) {
+		t.Errorf("cart discounts exp: %+v, got: %+v", exp, cart.Adjustments)
+	}
+	if exp := []Adjustment{{Kind: AdjustmentDiscount, Label: "B2G1", Amount: eur(500)}}; !reflect.DeepEqual(exp, cart.LineItems[0].Adjustments) {
+		t.Errorf("item discounts exp: %+v, got: %+v", exp, cart.LineItems[0].Adjustments)
+	}
+	if exp := (Totals{eur(2500), eur(750), Money{Currency: "EUR"}, Money{Currency: "EUR"}, eur(1750)}); !reflect.DeepEqual(&exp, cart.Totals) {
+		t.Errorf("totals exp: %+v, got: %+v", exp, cart.Totals)
+	}
+
+	// Applying a coupon again has no effect.
+	again, err := sc.CouponApply(ctx, c.ID, "WELCOME10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Version != cart.Version {
+		t.Errorf("version exp: %d, got: %d", cart.Version, again.Version)
+	}
+
+	t.Run("remove", func(t *testing.T) {
+		if _, err := sc.CouponRemove(ctx, c.ID, "b2g1"); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err := sc.CartShow(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"WELCOME10"}; !reflect.DeepEqual(exp, cart.Coupons) || len(cart.LineItems[0].Adjustments) != 0 {
+			t.Errorf("coupons exp: %v, got: %v, %+v", exp, cart.Coupons, cart.LineItems[0].Adjustments)
+		}
+
+		if _, err := sc.CouponRemove(ctx, c.ID, "B2G1"); !errors.Is(err, ErrCouponNotFound) {
+			t.Errorf("err exp: %v, got: %v", ErrCouponNotFound, err)
+		}
+	})
+
+	t.Run("abandoned cart", func(t *testing.T) {
+		ctx := withPrincipal(context.Background(), &Principal{UserID: 12})
+
+		for j := 0; j < 2; j++ {
+			c, err := sc.CartCreate(ctx, 12, []*LineItem{{ProductID: 1, Quantity: 1}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The coupon has not been redeemed with the abandoned cart.
+			if _, err := sc.CouponApply(ctx, c.ID, "WELCOME10"); err != nil {
+				t.Fatalf("cart %d: %v", j, err)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := sc.Checkout(ctx, c.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		other, err := sc.CartCreate(ctx, 10, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name string
+			code string
+			err  error
+		}{
+			{"unknown", "UNKNOWN", ErrCouponNotFound},
+			{"empty", " ", ErrInvalidArgument},
+			{"used up", "WELCOME10", ErrCouponNotApplicable},
+			{"min subtotal", "BIG", ErrCouponNotApplicable},
+			{"expired", "OVER", ErrCouponNotApplicable},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := sc.CouponApply(ctx, other.ID, tt.code); !errors.Is(err, tt.err) {
+					t.Errorf("err exp: %v, got: %v", tt.err, err)
+				}
+			})
+		}
+
+		if _, err := sc.CouponApply(withPrincipal(context.Background(), &Principal{UserID: 11}), c.ID, "B2G1"); !errors.Is(err, ErrForbidden) {
+			t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+		}
+	})
+}
+
+func TestShoppingCart_rules(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	cat := &JSONCatalog{products: map[int64]*Product{
+		1: {ID: 1, Category: "tea", UnitPrice: eur(500), Active: true},
+		2: {ID: 2, Category: "mug", UnitPrice: eur(1000), Active: true},
+	}}
+	rules := &JSONRules{rules: []*Promotion{
+		{Name: "tea-3-10", Description: "10% off 3 or more teas", Kind: PromotionPercentOff, Percent: 10, Category: "tea", MinQuantity: 3},
+		{Name: "big", Kind: PromotionAmountOff, Amount: eur(500), MinSubtotal: eur(10000)},
+		// Applicable to every cart, but the cart has no glasses to discount.
+		{Name: "glasses-volume", Kind: PromotionTiered, Tiers: []PromotionTier{{MinQuantity: 1, Percent: 5}}, Category: "glass"},
+	}}
+
+	sc := &ShoppingCart{storage: NewMemory(), catalog: cat, prices: CatalogPrices{cat}, rules: rules}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cart.FiredRules) != 0 {
+		t.Errorf("no rules fired exp, got: %+v", cart.FiredRules)
+	}
+
+	// Adding a third tea fires the rule.
+	items, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []Adjustment{{Kind: AdjustmentDiscount, Label: "tea-3-10", Amount: eur(150)}}; !reflect.DeepEqual(exp, items[0].Adjustments) {
+		t.Errorf("item discounts exp: %+v, got: %+v", exp, items[0].Adjustments)
+	}
+
+	cart, err = sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []FiredRule{{Name: "tea-3-10", Description: "10% off 3 or more teas"}}; !reflect.DeepEqual(exp, cart.FiredRules) {
+		t.Errorf("fired rules exp: %+v, got: %+v", exp, cart.FiredRules)
+	}
+	if exp := (Totals{eur(2500), eur(150), Money{Currency: "EUR"}, Money{Currency: "EUR"}, eur(2350)}); !reflect.DeepEqual(&exp, cart.Totals) {
+		t.Errorf("totals exp: %+v, got: %+v", exp, cart.Totals)
+	}
+
+	// Removing a tea does not qualify the cart any more.
+	item, _, err := sc.LineItemSetQuantity(ctx, c.ID, items[0].ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(item.Adjustments) != 0 {
+		t.Errorf("no item discounts exp, got: %+v", item.Adjustments)
+	}
+}
+
+func TestShoppingCart_taxes(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	cat := &JSONCatalog{products: map[int64]*Product{
+		1: {ID: 1, TaxClass: "food", UnitPrice: eur(1070), Active: true},
+		2: {ID: 2, UnitPrice: eur(1190), Active: true},
+	}}
+	taxes := TaxTable{
+		{Country: "DE", Rate: 1900, Label: "VAT 19%", Inclusive: true},
+		{Country: "DE", TaxClass: "food", Rate: 700, Label: "VAT 7%", Inclusive: true},
+	}
+
+	st := NewMemory()
+	sc := &ShoppingCart{storage: st, catalog: cat, prices: CatalogPrices{cat}, taxes: taxes}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Carts without an address are not taxed.
+	if len(c.Adjustments) != 0 {
+		t.Errorf("no taxes exp, got: %+v", c.Adjustments)
+	}
+
+	if err := st.CartShippingAddressSet(ctx, c.ID, &Address{Country: "DE"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []Adjustment{
+		{Kind: AdjustmentTax, Label: "VAT 7%", Amount: eur(70), Included: true},
+		{Kind: AdjustmentTax, Label: "VAT 19%", Amount: eur(190), Included: true},
+	}
+	if !reflect.DeepEqual(exp, cart.Adjustments) {
+		t.Errorf("taxes exp: %+v, got: %+v", exp, cart.Adjustments)
+	}
+	if exp := (Totals{eur(2260), eur(0), eur(260), eur(0), eur(2260)}); !reflect.DeepEqual(&exp, cart.Totals) {
+		t.Errorf("totals exp: %+v, got: %+v", exp, cart.Totals)
+	}
+}
+
+func TestShoppingCart_shipping(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	shipping := ShippingTable{
+		{Code: "standard", Name: "Standard", Countries: []string{"DE"}, Amount: eur(490)},
+		{Code: "letter", Name: "Letter", Countries: []string{"DE"}, Amount: eur(190), MaxQuantity: 2},
+	}
+	promotions := &JSONPromotions{promotions: map[string]*Promotion{
+		"FREESHIP": {Code: "FREESHIP", Kind: PromotionFreeShipping},
+	}}
+
+	sc := &ShoppingCart{storage: NewMemory(), prices: PriceTable{1: eur(500)}, promotions: promotions, shipping: shipping}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nowhere to ship to yet.
+	if _, err := sc.CartShippingMethodSet(ctx, c.ID, "letter"); !errors.Is(err, ErrShippingMethodNotAvailable) {
+		t.Errorf("err exp: %v, got: %v", ErrShippingMethodNotAvailable, err)
+	}
+
+	if _, err := sc.CartShippingAddressSet(ctx, c.ID, &Address{Line1: "1 Main St", City: "Berlin", Country: "Germany"}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err exp: %v, got: %v", ErrInvalidArgument, err)
+	}
+
+	cart, err := sc.CartShippingAddressSet(ctx, c.ID, &Address{Line1: "1 Main St", City: "Berlin", Country: "de"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (&Address{Line1: "1 Main St", City: "Berlin", Country: "DE"}); !reflect.DeepEqual(exp, cart.ShippingAddress) {
+		t.Errorf("address exp: %+v, got: %+v", exp, cart.ShippingAddress)
+	}
+
+	rates, err := sc.ShippingMethods(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []ShippingRate{{Code: "standard", Name: "Standard", Amount: eur(490)}, {Code: "letter", Name: "Letter", Amount: eur(190)}}; !reflect.DeepEqual(exp, rates) {
+		t.Errorf("rates exp: %+v, got: %+v", exp, rates)
+	}
+
+	if _, err := sc.CartShippingMethodSet(ctx, c.ID, "pigeon"); !errors.Is(err, ErrShippingMethodNotAvailable) {
+		t.Errorf("err exp: %v, got: %v", ErrShippingMethodNotAvailable, err)
+	}
+
+	cart, err = sc.CartShippingMethodSet(ctx, c.ID, "letter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (Totals{eur(1000), eur(0), eur(0), eur(190), eur(1190)}); cart.ShippingMethod != "letter" || !reflect.DeepEqual(&exp, cart.Totals) {
+		t.Errorf("method %s, totals exp: %+v, got: %s, %+v", "letter", exp, cart.ShippingMethod, cart.Totals)
+	}
+
+	// Shipping charges are discounted by promotions.
+	cart, err = sc.CouponApply(ctx, c.ID, "FREESHIP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (Totals{eur(1000), eur(190), eur(0), eur(190), eur(1000)}); !reflect.DeepEqual(&exp, cart.Totals) {
+		t.Errorf("totals exp: %+v, got: %+v", exp, cart.Totals)
+	}
+
+	// Letters take 2 items at most.
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err = sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cart.ShippingMethod != "" || cart.Totals.ShippingTotal.Amount != 0 {
+		t.Errorf("no method exp, got: %s, %+v", cart.ShippingMethod, cart.Totals)
+	}
+}
+
+func TestShoppingCart_inventory(t *testing.T) {
+	inv := newStubInventory(map[int64]int64{1: 3})
+	sc := &ShoppingCart{storage: NewMemory(), inventory: inv, reservationTTL: time.Minute}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+	other := withPrincipal(context.Background(), &Principal{UserID: 11})
+
+	if _, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 4}}); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("err exp: %v, got: %v", ErrOutOfStock, err)
+	}
+
+	c1, err := sc.CartCreate(ctx, 10, []*LineItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := sc.CartCreate(other, 11, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Quantities are summed up.
+	if _, _, err := sc.LineItemAdd(ctx, c1.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if q := inv.reserved(c1.ID, 1); q != 2 {
+		t.Errorf("reserved exp: %d, got: %d", 2, q)
+	}
+
+	if _, _, err := sc.LineItemAdd(other, c2.ID, []*LineItem{{ProductID: 1, Quantity: 2}}); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("err exp: %v, got: %v", ErrOutOfStock, err)
+	}
+
+	// The failed addition has been rolled back.
+	cart, err := sc.CartShow(other, c2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cart.LineItems) != 0 {
+		t.Errorf("no items exp, got: %+v", cart.LineItems)
+	}
+
+	cart, err = sc.CartShow(ctx, c1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	itemID := cart.LineItems[0].ID
+
+	if _, _, err := sc.LineItemSetQuantity(ctx, c1.ID, itemID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sc.LineItemAdd(other, c2.ID, []*LineItem{{ProductID: 1, Quantity: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := sc.LineItemSetQuantity(ctx, c1.ID, itemID, 2); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("err exp: %v, got: %v", ErrOutOfStock, err)
+	}
+
+	if _, err := sc.LineItemRemove(ctx, c1.ID, itemID); err != nil {
+		t.Fatal(err)
+	}
+	if q := inv.reserved(c1.ID, 1); q != 0 {
+		t.Errorf("reserved exp: %d, got: %d", 0, q)
+	}
+
+	if _, err := sc.CartEmpty(other, c2.ID); err != nil {
+		t.Fatal(err)
+	}
+	if q := inv.reserved(c2.ID, 1); q != 0 {
+		t.Errorf("reserved exp: %d, got: %d", 0, q)
+	}
+
+	// Stock is only checked without reservations.
+	sc.reservationTTL = 0
+	if _, _, err := sc.LineItemAdd(ctx, c1.ID, []*LineItem{{ProductID: 1, Quantity: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if q := inv.reserved(c1.ID, 1); q != 0 {
+		t.Errorf("reserved exp: %d, got: %d", 0, q)
+	}
+}
+
+func TestShoppingCart_Checkout(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	inv := newStubInventory(map[int64]int64{1: 5})
+	prices := PriceTable{1: eur(500), 2: eur(200)}
+	sc := &ShoppingCart{storage: NewMemory(), prices: prices, inventory: inv, reservationTTL: time.Minute}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sc.Checkout(ctx, c.ID); !errors.Is(err, ErrCartEmpty) {
+		t.Errorf("err exp: %v, got: %v", ErrCartEmpty, err)
+	}
+
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Prices have to be acknowledged.
+	prices[2] = eur(250)
+	if _, err := sc.Checkout(ctx, c.ID); !errors.Is(err, ErrPriceChanged) {
+		t.Errorf("err exp: %v, got: %v", ErrPriceChanged, err)
+	}
+	if _, err := sc.CartReprice(ctx, c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stock taken by another cart in the meantime.
+	inv.stock[1] = 1
+	if _, err := sc.Checkout(ctx, c.ID); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("err exp: %v, got: %v", ErrOutOfStock, err)
+	}
+	inv.stock[1] = 5
+
+	// Stock of an order failing to commit is given back.
+	mem := sc.storage
+	sc.storage = commitFailingStorer{mem}
+	if _, err := sc.Checkout(ctx, c.ID); err == nil {
+		t.Error("err exp, got none")
+	}
+	sc.storage = mem
+	if inv.stock[1] != 5 {
+		t.Errorf("stock exp: %d, got: %d", 5, inv.stock[1])
+	}
+
+	orderID, err := sc.Checkout(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := sc.CartShow(ctx, c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cart.OrderID != orderID {
+		t.Errorf("order exp: %d, got: %d", orderID, cart.OrderID)
+	}
+
+	if inv.stock[1] != 3 || inv.reserved(c.ID, 1) != 0 {
+		t.Errorf("stock exp: %d, no reservation, got: %d, %d", 3, inv.stock[1], inv.reserved(c.ID, 1))
+	}
+
+	// The cart is frozen.
+	if _, err := sc.Checkout(ctx, c.ID); !errors.Is(err, ErrCartCheckedOut) {
+		t.Errorf("err exp: %v, got: %v", ErrCartCheckedOut, err)
+	}
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); !errors.Is(err, ErrCartCheckedOut) {
+		t.Errorf("err exp: %v, got: %v", ErrCartCheckedOut, err)
+	}
+	if _, err := sc.CartEmpty(ctx, c.ID); !errors.Is(err, ErrCartCheckedOut) {
+		t.Errorf("err exp: %v, got: %v", ErrCartCheckedOut, err)
+	}
+
+	// The order copies the cart.
+	st, err := sc.storage.(*Memory).read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := st.orders[orderID]
+	exp := []*OrderItem{
+		{ID: 1, ProductID: 1, Quantity: 2, UnitPrice: eur(500), Discount: eur(0)},
+		{ID: 2, ProductID: 2, Quantity: 1, UnitPrice: eur(250), Discount: eur(0)},
+	}
+	if !reflect.DeepEqual(exp, o.LineItems) {
+		t.Errorf("items do not match\nexp: %+v\ngot: %+v", exp, o.LineItems)
+	}
+	if expTotals := (Totals{eur(1250), eur(0), eur(0), eur(0), eur(1250)}); o.CartID != c.ID || o.Totals != expTotals {
+		t.Errorf("order cart %d, totals exp: %+v, got: %d, %+v", c.ID, expTotals, o.CartID, o.Totals)
+	}
+}
+
+// commitFailingStorer is a storer whose transactions fail to commit, they are rolled back.
+type commitFailingStorer struct {
+	storer
+}
+
+func (s commitFailingStorer) BeginTx(ctx context.Context, opts *sql.TxOptions) (storer, error) {
+	tx, err := s.storer.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return commitFailingStorer{tx}, nil
+}
+
+func (s commitFailingStorer) Commit() error {
+	_ = s.storer.Rollback()
+	return errors.New("commit failed")
+}
+
+func TestShoppingCart_CartPay(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	gw := newFakeGateway()
+	sc := &ShoppingCart{storage: NewMemory(), prices: PriceTable{1: eur(500)}, payments: gw}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 10})
+
+	c, err := sc.CartCreate(ctx, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := sc.CartPay(ctx, c.ID, "tok_visa"); !errors.Is(err, ErrCartEmpty) {
+		t.Errorf("err exp: %v, got: %v", ErrCartEmpty, err)
+	}
+	if _, _, err := sc.CartPay(ctx, c.ID, " "); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err exp: %v, got: %v", ErrInvalidArgument, err)
+	}
+
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := sc.CartPay(ctx, c.ID, "declined"); !errors.Is(err, ErrPaymentDeclined) {
+		t.Errorf("err exp: %v, got: %v", ErrPaymentDeclined, err)
+	}
+
+	p, created, err := sc.CartPay(ctx, c.ID, "tok_visa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Error("created payment exp")
+	}
+	if p.Amount != eur(1000) || p.Status != PaymentAuthorized || gw.status(p.AuthorizationID) != PaymentAuthorized {
+		t.Errorf("authorized %v exp, got: %+v", eur(1000), p)
+	}
+
+	// Paying the same cart again does not authorize twice.
+	again, created, err := sc.CartPay(ctx, c.ID, "tok_visa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created {
+		t.Error("existing payment exp")
+	}
+	if again.ID != p.ID || again.AuthorizationID != p.AuthorizationID {
+		t.Errorf("payment exp: %+v, got: %+v", p, again)
+	}
+
+	// Modifying the cart voids the payment.
+	if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if s := gw.status(p.AuthorizationID); s != PaymentVoided {
+		t.Errorf("gateway status exp: %s, got: %s", PaymentVoided, s)
+	}
+	if stored, err := sc.storage.PaymentByCartID(ctx, c.ID); err != nil || stored.Status != PaymentVoided {
+		t.Errorf("status exp: %s, got: %+v, %v", PaymentVoided, stored, err)
+	}
+
+	repaid, created, err := sc.CartPay(ctx, c.ID, "tok_visa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || repaid.ID == p.ID || repaid.AuthorizationID == p.AuthorizationID || repaid.Amount != eur(1500) {
+		t.Errorf("new payment of %v exp, got: %+v", eur(1500), repaid)
+	}
+
+	// Paid carts are checked out as usual.
+	if _, err := sc.Checkout(ctx, c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sc.CartPay(ctx, c.ID, "tok_visa"); !errors.Is(err, ErrCartCheckedOut) {
+		t.Errorf("err exp: %v, got: %v", ErrCartCheckedOut, err)
+	}
+}
+
+func TestShoppingCart_guests(t *testing.T) {
+	promotions := &JSONPromotions{promotions: map[string]*Promotion{
+		"ONCE": {Code: "ONCE", Kind: PromotionPercentOff, Percent: 10, MaxUsesPerUser: 1},
+	}}
+	sc := &ShoppingCart{storage: NewMemory(), promotions: promotions}
+
+	guest := withPrincipal(context.Background(), &Principal{GuestToken: "tok"})
+	c, err := sc.CartCreate(guest, 0, []*LineItem{{ProductID: 1, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.UserID != 0 || c.GuestToken != hashGuestToken("tok") {
+		t.Errorf("guest cart exp, got: %+v", c)
+	}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		err       error
+	}{
+		{"guest", &Principal{GuestToken: "tok"}, nil},
+		{"user holding the token", &Principal{UserID: 10, GuestToken: "tok"}, nil},
+		{"admin", &Principal{UserID: 1, Scopes: []string{ScopeAdmin}}, nil},
+		{"other guest", &Principal{GuestToken: "other"}, ErrForbidden},
+		{"user", &Principal{UserID: 10}, ErrForbidden},
+		{"anonymous", &Principal{}, ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sc.CartShow(withPrincipal(context.Background(), tt.principal), c.ID); !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+
+	// Guests own no user carts.
+	if _, _, err := sc.CartsByUser(guest, 0, CartsQuery{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("err exp: %v, got: %v", ErrForbidden, err)
+	}
+	if _, err := sc.CartCreate(withPrincipal(context.Background(), &Principal{}), 0, nil); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("err exp: %v, got: %v", ErrUnauthenticated, err)
+	}
+
+	// Uses of coupons limited per user can not be counted for guests.
+	if _, err := sc.CouponApply(guest, c.ID, "ONCE"); !errors.Is(err, ErrCouponNotApplicable) {
+		t.Errorf("err exp: %v, got: %v", ErrCouponNotApplicable, err)
+	}
+}
+
+func TestShoppingCart_CartMerge(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	inv := newStubInventory(map[int64]int64{1: 5})
+	gw := newFakeGateway()
+	sc := &ShoppingCart{storage: NewMemory(), prices: PriceTable{1: eur(500), 2: eur(200)}, inventory: inv, reservationTTL: time.Minute, payments: gw}
+
+	guestCtx := withPrincipal(context.Background(), &Principal{GuestToken: "tok"})
+	userCtx := withPrincipal(context.Background(), &Principal{UserID: 10, GuestToken: "tok"})
+
+	guest, err := sc.CartCreate(guestCtx, 0, []*LineItem{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _, err := sc.CartPay(guestCtx, guest.ID, "tok_visa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := sc.CartCreate(userCtx, 10, []*LineItem{{ProductID: 1, Quantity: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stock taken in the meantime.
+	inv.stock[1] = 4
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		cartID      int64
+		guestCartID int64
+		strategy    MergeStrategy
+		err         error
+	}{
+		{"unknown strategy", userCtx, c.ID, guest.ID, "min", ErrInvalidArgument},
+		{"itself", userCtx, c.ID, c.ID, MergeSum, ErrInvalidArgument},
+		{"not a guest cart", userCtx, guest.ID, c.ID, MergeSum, ErrInvalidArgument},
+		{"without the guest token", withPrincipal(context.Background(), &Principal{UserID: 10}), c.ID, guest.ID, MergeSum, ErrForbidden},
+		{"unknown guest cart", userCtx, c.ID, -1, MergeSum, ErrCartNotFound},
+		{"out of stock", userCtx, c.ID, guest.ID, MergeSum, ErrOutOfStock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sc.CartMerge(tt.ctx, tt.cartID, tt.guestCartID, tt.strategy); !errors.Is(err, tt.err) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+
+	cart, err := sc.CartMerge(userCtx, c.ID, guest.ID, MergeMax)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*LineItem{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 1}}
+	if len(cart.LineItems) != len(exp) {
+		t.Fatalf("items num exp: %d, got: %d", len(exp), len(cart.LineItems))
+	}
+	for j, i := range cart.LineItems {
+		if i.ProductID != exp[j].ProductID || i.Quantity != exp[j].Quantity || i.CartID != c.ID {
+			t.Errorf("item exp: %+v, got: %+v", exp[j], i)
+		}
+	}
+	if exp := eur(1700); cart.Totals == nil || cart.Totals.GrandTotal != exp {
+		t.Errorf("grand total exp: %v, got: %+v", exp, cart.Totals)
+	}
+
+	if g, err := sc.CartShow(guestCtx, guest.ID); err != nil || g.Status != CartMerged {
+		t.Errorf("guest cart status exp: %s, got: %+v, %v", CartMerged, g, err)
+	}
+	if _, _, err := sc.LineItemAdd(guestCtx, guest.ID, []*LineItem{{ProductID: 2, Quantity: 1}}); !errors.Is(err, ErrCartInactive) {
+		t.Errorf("merged guest cart err exp: %v, got: %v", ErrCartInactive, err)
+	}
+	if _, err := sc.CartMerge(userCtx, c.ID, guest.ID, MergeSum); !errors.Is(err, ErrCartInactive) {
+		t.Errorf("merging again err exp: %v, got: %v", ErrCartInactive, err)
+	}
+	if s := gw.status(p.AuthorizationID); s != PaymentVoided {
+		t.Errorf("guest payment status exp: %s, got: %s", PaymentVoided, s)
+	}
+	if inv.reserved(guest.ID, 1) != 0 || inv.reserved(c.ID, 1) != 3 {
+		t.Errorf("reserved exp: %d, %d, got: %d, %d", 0, 3, inv.reserved(guest.ID, 1), inv.reserved(c.ID, 1))
+	}
+}
+
+func TestShoppingCart_validateLineItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []*LineItem
+		err   error
+	}{
+		{"ok", []*LineItem{{ProductID: 1, Quantity: 1}}, nil},
+		{"product", []*LineItem{{ProductID: 1, Quantity: 1}, {ProductID: 0, Quantity: 1}}, ErrInvalidProduct},
+		{"zero quantity", []*LineItem{{ProductID: 1, Quantity: 0}}, ErrInvalidQuantity},
+		{"negative quantity", []*LineItem{{ProductID: 1, Quantity: -1}}, ErrInvalidQuantity},
+		{"duplicate product", []*LineItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 2}}, ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&ShoppingCart{}).validateLineItems(tt.items)
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Product is an item of the catalog.
+type Product struct {
+	ID        int64
+	SKU       string
+	Name      string
+	Category  string
+	TaxClass  string // products of the standard rate have no tax class
+	UnitPrice Money
+	Active    bool // inactive products can not be added to carts
+}
+
+// Catalog looks products up.
+type Catalog interface {
+	// Products returns products by ID, unknown IDs are omitted.
+	Products(ctx context.Context, ids ...int64) (map[int64]*Product, error)
+}
+
+// JSONCatalog is a catalog loaded from a JSON file.
+type JSONCatalog struct {
+	products map[int64]*Product
+}
+
+// LoadJSONCatalog reads a catalog from a JSON file of the form
+// [{"id": 1, "sku": "SKU-1", "name": "Name", "category": "tea", "tax_class": "food", "unit_price": 1999, "currency": "EUR", "active": true}].
+func LoadJSONCatalog(path string) (*JSONCatalog, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pp []struct {
+		ID        int64  `json:"id"`
+		SKU       string `json:"sku"`
+		Name      string `json:"name"`
+		Category  string `json:"category"`
+		TaxClass  string `json:"tax_class"`
+		UnitPrice int64  `json:"unit_price"`
+		Currency  string `json:"currency"`
+		Active    bool   `json:"active"`
+	}
+	if err := json.Unmarshal(b, &pp); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	c := &JSONCatalog{products: make(map[int64]*Product, len(pp))}
+	for _, p := range pp {
+		c.products[p.ID] = &Product{
+			ID:        p.ID,
+			SKU:       p.SKU,
+			Name:      p.Name,
+			Category:  p.Category,
+			TaxClass:  p.TaxClass,
+			UnitPrice: Money{Amount: p.UnitPrice, Currency: p.Currency},
+			Active:    p.Active,
+		}
+	}
+	return c, nil
+}
+
+func (c *JSONCatalog) Products(ctx context.Context, ids ...int64) (map[int64]*Product, error) {
+	products := make(map[int64]*Product, len(ids))
+	for _, id := range ids {
+		if p, ok := c.products[id]; ok {
+			cp := *p
+			products[id] = &cp
+		}
+	}
+	return products, nil
+}
+
+// SQLite3Catalog is a catalog kept in the products table of a SQLite DB,
+// see ./migrations/catalog.
+type SQLite3Catalog struct {
+	db *sql.DB
+}
+
+// NewSQLite3Catalog instantiates SQLite3Catalog.
+func NewSQLite3Catalog(db *sql.DB) *SQLite3Catalog {
+	return &SQLite3Catalog{db: db}
+}
+
+func (c *SQLite3Catalog) Products(ctx context.Context, ids ...int64) (map[int64]*Product, error) {
+	products := make(map[int64]*Product, len(ids))
+	if len(ids) == 0 {
+		return products, nil
+	}
+
+	ph, args := make([]string, len(ids)), make([]interface{}, len(ids))
+	for j, id := range ids {
+		ph[j], args[j] = "?", id
+	}
+
+	rows, err := c.db.QueryContext(
+		ctx,
+		`SELECT id, sku, name, category, tax_class, unit_price, currency, active
+		FROM products
+		WHERE id IN (`+strings.Join(ph, ", ")+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("product query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := &Product{}
+		err := rows.Scan(
+			&p.ID,
+			&p.SKU,
+			&p.Name,
+			&p.Category,
+			&p.TaxClass,
+			&p.UnitPrice.Amount,
+			&p.UnitPrice.Currency,
+			&p.Active,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("product scan: %w", err)
+		}
+
+		products[p.ID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("product rows: %w", err)
+	}
+
+	return products, nil
+}
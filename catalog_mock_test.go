@@ -0,0 +1,285 @@
+package main
+
+// Code generated by http://github.com/gojuno/minimock (dev). DO NOT EDIT.
+
+//go:generate minimock -i shoppingcart.Catalog -o ./catalog_mock_test.go
+
+import (
+	"context"
+	"sync"
+	mm_atomic "sync/atomic"
+	mm_time "time"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+// CatalogMock implements Catalog
+type CatalogMock struct {
+	t minimock.Tester
+
+	funcProducts          func(ctx context.Context, ids ...int64) (m1 map[int64]*Product, err error)
+	inspectFuncProducts   func(ctx context.Context, ids ...int64)
+	afterProductsCounter  uint64
+	beforeProductsCounter uint64
+	ProductsMock          mCatalogMockProducts
+}
+
+// NewCatalogMock returns a mock for Catalog
+func NewCatalogMock(t minimock.Tester) *CatalogMock {
+	m := &CatalogMock{t: t}
+	if controller, ok := t.(minimock.MockController); ok {
+		controller.RegisterMocker(m)
+	}
+
+	m.ProductsMock = mCatalogMockProducts{mock: m}
+	m.ProductsMock.callArgs = []*CatalogMockProductsParams{}
+
+	return m
+}
+
+type mCatalogMockProducts struct {
+	mock               *CatalogMock
+	defaultExpectation *CatalogMockProductsExpectation
+	expectations       []*CatalogMockProductsExpectation
+
+	callArgs []*CatalogMockProductsParams
+	mutex    sync.RWMutex
+}
+
+// CatalogMockProductsExpectation specifies expectation struct of the Catalog.Products
+type CatalogMockProductsExpectation struct {
+	mock    *CatalogMock
+	params  *CatalogMockProductsParams
+	results *CatalogMockProductsResults
+	Counter uint64
+}
+
+// CatalogMockProductsParams contains parameters of the Catalog.Products
+type CatalogMockProductsParams struct {
+	ctx context.Context
+	ids []int64
+}
+
+// CatalogMockProductsResults contains results of the Catalog.Products
+type CatalogMockProductsResults struct {
+	m1  map[int64]*Product
+	err error
+}
+
+// Expect sets up expected params for Catalog.Products
+func (mmProducts *mCatalogMockProducts) Expect(ctx context.Context, ids ...int64) *mCatalogMockProducts {
+	if mmProducts.mock.funcProducts != nil {
+		mmProducts.mock.t.Fatalf("CatalogMock.Products mock is already set by Set")
+	}
+
+	if mmProducts.defaultExpectation == nil {
+		mmProducts.defaultExpectation = &CatalogMockProductsExpectation{}
+	}
+
+	mmProducts.defaultExpectation.params = &CatalogMockProductsParams{ctx, ids}
+	for _, e := range mmProducts.expectations {
+		if minimock.Equal(e.params, mmProducts.defaultExpectation.params) {
+			mmProducts.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmProducts.defaultExpectation.params)
+		}
+	}
+
+	return mmProducts
+}
+
+// Inspect accepts an inspector function that has same arguments as the Catalog.Products
+func (mmProducts *mCatalogMockProducts) Inspect(f func(ctx context.Context, ids ...int64)) *mCatalogMockProducts {
+	if mmProducts.mock.inspectFuncProducts != nil {
+		mmProducts.mock.t.Fatalf("Inspect function is already set for CatalogMock.Products")
+	}
+
+	mmProducts.mock.inspectFuncProducts = f
+
+	return mmProducts
+}
+
+// Return sets up results that will be returned by Catalog.Products
+func (mmProducts *mCatalogMockProducts) Return(m1 map[int64]*Product, err error) *CatalogMock {
+	if mmProducts.mock.funcProducts != nil {
+		mmProducts.mock.t.Fatalf("CatalogMock.Products mock is already set by Set")
+	}
+
+	if mmProducts.defaultExpectation == nil {
+		mmProducts.defaultExpectation = &CatalogMockProductsExpectation{mock: mmProducts.mock}
+	}
+	mmProducts.defaultExpectation.results = &CatalogMockProductsResults{m1, err}
+	return mmProducts.mock
+}
+
+//Set uses given function f to mock the Catalog.Products method
+func (mmProducts *mCatalogMockProducts) Set(f func(ctx context.Context, ids ...int64) (m1 map[int64]*Product, err error)) *CatalogMock {
+	if mmProducts.defaultExpectation != nil {
+		mmProducts.mock.t.Fatalf("Default expectation is already set for the Catalog.Products method")
+	}
+
+	if len(mmProducts.expectations) > 0 {
+		mmProducts.mock.t.Fatalf("Some expectations are already set for the Catalog.Products method")
+	}
+
+	mmProducts.mock.funcProducts = f
+	return mmProducts.mock
+}
+
+// When sets expectation for the Catalog.Products which will trigger the result defined by the following
+// Then helper
+func (mmProducts *mCatalogMockProducts) When(ctx context.Context, ids ...int64) *CatalogMockProductsExpectation {
+	if mmProducts.mock.funcProducts != nil {
+		mmProducts.mock.t.Fatalf("CatalogMock.Products mock is already set by Set")
+	}
+
+	expectation := &CatalogMockProductsExpectation{
+		mock:   mmProducts.mock,
+		params: &CatalogMockProductsParams{ctx, ids},
+	}
+	mmProducts.expectations = append(mmProducts.expectations, expectation)
+	return expectation
+}
+
+// Then sets up Catalog.Products return parameters for the expectation previously defined by the When method
+func (e *CatalogMockProductsExpectation) Then(m1 map[int64]*Product, err error) *CatalogMock {
+	e.results = &CatalogMockProductsResults{m1, err}
+	return e.mock
+}
+
+// Products implements Catalog
+func (mmProducts *CatalogMock) Products(ctx context.Context, ids ...int64) (m1 map[int64]*Product, err error) {
+	mm_atomic.AddUint64(&mmProducts.beforeProductsCounter, 1)
+	defer mm_atomic.AddUint64(&mmProducts.afterProductsCounter, 1)
+
+	if mmProducts.inspectFuncProducts != nil {
+		mmProducts.inspectFuncProducts(ctx, ids...)
+	}
+
+	mm_params := &CatalogMockProductsParams{ctx, ids}
+
+	// Record call args
+	mmProducts.ProductsMock.mutex.Lock()
+	mmProducts.ProductsMock.callArgs = append(mmProducts.ProductsMock.callArgs, mm_params)
+	mmProducts.ProductsMock.mutex.Unlock()
+
+	for _, e := range mmProducts.ProductsMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.m1, e.results.err
+		}
+	}
+
+	if mmProducts.ProductsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmProducts.ProductsMock.defaultExpectation.Counter, 1)
+		mm_want := mmProducts.ProductsMock.defaultExpectation.params
+		mm_got := CatalogMockProductsParams{ctx, ids}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmProducts.t.Errorf("CatalogMock.Products got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmProducts.ProductsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmProducts.t.Fatal("No results are set for the CatalogMock.Products")
+		}
+		return (*mm_results).m1, (*mm_results).err
+	}
+	if mmProducts.funcProducts != nil {
+		return mmProducts.funcProducts(ctx, ids...)
+	}
+	mmProducts.t.Fatalf("Unexpected call to CatalogMock.Products. %v %v", ctx, ids)
+	return
+}
+
+// ProductsAfterCounter returns a count of finished CatalogMock.Products invocations
+func (mmProducts *CatalogMock) ProductsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProducts.afterProductsCounter)
+}
+
+// ProductsBeforeCounter returns a count of CatalogMock.Products invocations
+func (mmProducts *CatalogMock) ProductsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmProducts.beforeProductsCounter)
+}
+
+// Calls returns a list of arguments used in each call to CatalogMock.Products.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmProducts *mCatalogMockProducts) Calls() []*CatalogMockProductsParams {
+	mmProducts.mutex.RLock()
+
+	argCopy := make([]*CatalogMockProductsParams, len(mmProducts.callArgs))
+	copy(argCopy, mmProducts.callArgs)
+
+	mmProducts.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockProductsDone returns true if the count of the Products invocations corresponds
+// the number of defined expectations
+func (m *CatalogMock) MinimockProductsDone() bool {
+	for _, e := range m.ProductsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductsCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProducts != nil && mm_atomic.LoadUint64(&m.afterProductsCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockProductsInspect logs each unmet expectation
+func (m *CatalogMock) MinimockProductsInspect() {
+	for _, e := range m.ProductsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to CatalogMock.Products with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ProductsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterProductsCounter) < 1 {
+		if m.ProductsMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to CatalogMock.Products")
+		} else {
+			m.t.Errorf("Expected call to CatalogMock.Products with params: %#v", *m.ProductsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcProducts != nil && mm_atomic.LoadUint64(&m.afterProductsCounter) < 1 {
+		m.t.Error("Expected call to CatalogMock.Products")
+	}
+}
+
+// MinimockFinish checks that all mocked methods have been called the expected number of times
+func (m *CatalogMock) MinimockFinish() {
+	if !m.minimockDone() {
+		m.MinimockProductsInspect()
+		m.t.FailNow()
+	}
+}
+
+// MinimockWait waits for all mocked methods to be called the expected number of times
+func (m *CatalogMock) MinimockWait(timeout mm_time.Duration) {
+	timeoutCh := mm_time.After(timeout)
+	for {
+		if m.minimockDone() {
+			return
+		}
+		select {
+		case <-timeoutCh:
+			m.MinimockFinish()
+			return
+		case <-mm_time.After(10 * mm_time.Millisecond):
+		}
+	}
+}
+
+func (m *CatalogMock) minimockDone() bool {
+	done := true
+	return done &&
+		m.MinimockProductsDone()
+}
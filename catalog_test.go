@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestLoadJSONCatalog(t *testing.T) {
+	path := writeTestFile(t, "products.json", `[
+		{"id":1,"sku":"SKU-1","name":"One","category":"tea","tax_class":"food","unit_price":499,"currency":"EUR","active":true},
+		{"id":2,"sku":"SKU-2","name":"Two","unit_price":1250,"currency":"EUR"}
+	]`)
+
+	c, err := LoadJSONCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	products, err := c.Products(context.Background(), 1, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[int64]*Product{
+		1: {ID: 1, SKU: "SKU-1", Name: "One", Category: "tea", TaxClass: "food", UnitPrice: Money{Amount: 499, Currency: "EUR"}, Active: true},
+		2: {ID: 2, SKU: "SKU-2", Name: "Two", UnitPrice: Money{Amount: 1250, Currency: "EUR"}},
+	}
+
+	if !reflect.DeepEqual(exp, products) {
+		t.Errorf("products do not match\nexp: %+v\ngot: %+v", exp, products)
+	}
+
+	// Callers get copies.
+	products[1].Name = "Changed"
+	if products, _ := c.Products(context.Background(), 1); products[1].Name != "One" {
+		t.Errorf("name exp: %s, got: %s", "One", products[1].Name)
+	}
+}
+
+func TestSQLite3Catalog_Products(t *testing.T) {
+	db := migrateDB(t, "file:catalog?mode=memory&cache=shared", "./migrations/catalog")
+	defer db.Close()
+
+	_, err := db.Exec(
+		`INSERT INTO products(id, sku, name, category, tax_class, unit_price, currency, active) VALUES(?, ?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?, ?)`,
+		1, "SKU-1", "One", "tea", "food", 499, "EUR", true,
+		2, "SKU-2", "Two", "", "", 1250, "EUR", false,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	products, err := NewSQLite3Catalog(db).Products(context.Background(), 1, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[int64]*Product{
+		1: {ID: 1, SKU: "SKU-1", Name: "One", Category: "tea", TaxClass: "food", UnitPrice: Money{Amount: 499, Currency: "EUR"}, Active: true},
+		2: {ID: 2, SKU: "SKU-2", Name: "Two", UnitPrice: Money{Amount: 1250, Currency: "EUR"}},
+	}
+
+	if !reflect.DeepEqual(exp, products) {
+		t.Errorf("products do not match\nexp: %+v\ngot: %+v", exp, products)
+	}
+
+	if products, err := NewSQLite3Catalog(db).Products(context.Background()); err != nil || len(products) != 0 {
+		t.Errorf("no ids exp no products, got: %+v, %v", products, err)
+	}
+}
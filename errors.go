@@ -0,0 +1,44 @@
+package main
+
+import "errors"
+
+// Domain errors, storers and ShoppingCart wrap or return them so callers do not depend on a
+// particular storage.
+var (
+	ErrCartNotFound               = errors.New("cart not found")
+	ErrLineItemNotFound           = errors.New("line item not found")
+	ErrIdempotencyKeyNotFound     = errors.New("idempotency key not found")
+	ErrCouponNotFound             = errors.New("coupon not found")
+	ErrCouponNotApplicable        = errors.New("coupon not applicable")
+	ErrShippingMethodNotAvailable = errors.New("shipping method not available")
+	ErrOutOfStock                 = errors.New("out of stock")
+	ErrCartEmpty                  = errors.New("cart empty")
+	ErrCartCheckedOut             = errors.New("cart checked out")
+	ErrCartInactive               = errors.New("cart inactive")
+	ErrPriceChanged               = errors.New("price changed")
+	ErrPaymentNotFound            = errors.New("payment not found")
+	ErrPaymentDeclined            = errors.New("payment declined")
+	ErrInvalidArgument            = errors.New("invalid argument")
+	ErrInvalidProduct             = errors.New("invalid product")
+	ErrInvalidQuantity            = errors.New("invalid quantity")
+	ErrCurrencyMismatch           = errors.New("currency mismatch")
+	ErrConflict                   = errors.New("conflict")
+	ErrUnavailable                = errors.New("unavailable") // the storage is busy, the request may be retried
+	ErrPreconditionFailed         = errors.New("precondition failed")
+	ErrUnauthenticated            = errors.New("unauthenticated")
+	ErrForbidden                  = errors.New("forbidden")
+)
+
+// InvalidParamError is a validation error of a named parameter.
+type InvalidParamError struct {
+	Name string
+	Err  error
+}
+
+func (e *InvalidParamError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+func (e *InvalidParamError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Guest token transport, guests pass their token in the header or the cookie.
+const (
+	guestTokenHeader = "Guest-Token"
+	guestTokenCookie = "guest_token"
+)
+
+// guestTokenMaxAge is the lifetime of the guest token cookie in seconds.
+const guestTokenMaxAge = 30 * 24 * 60 * 60
+
+// newGuestToken generates an opaque guest token.
+func newGuestToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("guest token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashGuestToken returns the hash of a guest token carts are stored with, so tokens can not be
+// read from the storage.
+func hashGuestToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// guestToken returns the guest token of the request, the header takes precedence over the cookie.
+func guestToken(r *http.Request) string {
+	if t := strings.TrimSpace(r.Header.Get(guestTokenHeader)); t != "" {
+		return t
+	}
+	if c, err := r.Cookie(guestTokenCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// setGuestToken passes a newly issued guest token to the client.
+func setGuestToken(w http.ResponseWriter, r *http.Request, token string) {
+	w.Header().Set(guestTokenHeader, token)
+	http.SetCookie(w, &http.Cookie{
+		Name:     guestTokenCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   guestTokenMaxAge,
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// MergeStrategy defines the quantity of a product in both carts merged.
+type MergeStrategy string
+
+const (
+	MergeSum   MergeStrategy = "sum"   // sum the quantities up
+	MergeMax   MergeStrategy = "max"   // keep the greater quantity
+	MergeNewer MergeStrategy = "newer" // keep the item updated last
+)
+
+// validate checks the strategy, an empty strategy defaults to MergeSum.
+func (s *MergeStrategy) validate() error {
+	switch *s {
+	case "":
+		*s = MergeSum
+	case MergeSum, MergeMax, MergeNewer:
+	default:
+		return &InvalidParamError{Name: "strategy", Err: fmt.Errorf("unknown strategy %q: %w", *s, ErrInvalidArgument)}
+	}
+	return nil
+}
+
+// mergeLineItems merges items of a guest cart into items of a cart, returns the items of the
+// cart to be upserted. Items keep the price they have been added at.
+func mergeLineItems(items, guest []*LineItem, s MergeStrategy) []*LineItem {
+	byProductID := make(map[int64]*LineItem, len(items))
+	for _, i := range items {
+		byProductID[i.ProductID] = i
+	}
+
+	var merged []*LineItem
+	for _, g := range guest {
+		i, ok := byProductID[g.ProductID]
+		if !ok {
+			merged = append(merged, &LineItem{ProductID: g.ProductID, Quantity: g.Quantity, UnitPrice: g.UnitPrice})
+			continue
+		}
+
+		m := &LineItem{ProductID: i.ProductID, Quantity: i.Quantity, UnitPrice: i.UnitPrice}
+		switch s {
+		case MergeSum:
+			m.Quantity += g.Quantity
+		case MergeMax:
+			if g.Quantity > m.Quantity {
+				m.Quantity = g.Quantity
+			}
+		case MergeNewer:
+			if g.UpdatedAt.After(i.UpdatedAt) {
+				m.Quantity, m.UnitPrice = g.Quantity, g.UnitPrice
+			}
+		}
+
+		if m.Quantity != i.Quantity || m.UnitPrice != i.UnitPrice {
+			merged = append(merged, m)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeLineItems(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+	older, newer := time.Now().Add(-time.Hour), time.Now()
+
+	items := []*LineItem{
+		{ID: 1, ProductID: 10, Quantity: 2, UnitPrice: eur(100), UpdatedAt: older},
+		{ID: 2, ProductID: 20, Quantity: 5, UnitPrice: eur(200), UpdatedAt: newer},
+		{ID: 3, ProductID: 40, Quantity: 1, UnitPrice: eur(400), UpdatedAt: older},
+	}
+	guest := []*LineItem{
+		{ID: 7, ProductID: 10, Quantity: 3, UnitPrice: eur(110), UpdatedAt: newer},
+		{ID: 8, ProductID: 20, Quantity: 1, UnitPrice: eur(200), UpdatedAt: older},
+		{ID: 9, ProductID: 30, Quantity: 4, UnitPrice: eur(300), UpdatedAt: older},
+	}
+
+	tests := []struct {
+		strategy MergeStrategy
+		exp      []*LineItem
+	}{
+		{MergeSum, []*LineItem{
+			{ProductID: 10, Quantity: 5, UnitPrice: eur(100)},
+			{ProductID: 20, Quantity: 6, UnitPrice: eur(200)},
+			{ProductID: 30, Quantity: 4, UnitPrice: eur(300)},
+		}},
+		{MergeMax, []*LineItem{
+			{ProductID: 10, Quantity: 3, UnitPrice: eur(100)},
+			{ProductID: 30, Quantity: 4, UnitPrice: eur(300)},
+		}},
+		{MergeNewer, []*LineItem{
+			{ProductID: 10, Quantity: 3, UnitPrice: eur(110)},
+			{ProductID: 30, Quantity: 4, UnitPrice: eur(300)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			merged := mergeLineItems(items, guest, tt.strategy)
+			if !reflect.DeepEqual(tt.exp, merged) {
+				t.Errorf("items do not match\nexp: %+v\ngot: %+v", tt.exp, merged)
+			}
+		})
+	}
+}
+
+func TestMergeStrategy_validate(t *testing.T) {
+	tests := []struct {
+		strategy MergeStrategy
+		exp      MergeStrategy
+		err      error
+	}{
+		{"", MergeSum, nil},
+		{"max", MergeMax, nil},
+		{"newer", MergeNewer, nil},
+		{"min", "min", ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			s := tt.strategy
+			if err := s.validate(); !errors.Is(err, tt.err) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+			if s != tt.exp {
+				t.Errorf("strategy exp: %s, got: %s", tt.exp, s)
+			}
+		})
+	}
+}
@@ -2,13 +2,15 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 )
@@ -16,22 +18,144 @@ import (
 type apiv1Cart struct {
 	ID        int64           `json:"id"`
 	UserID    int64           `json:"user_id"`
+	Status    CartStatus      `json:"status,omitempty"`
+	OrderID   int64           `json:"order_id,omitempty"` // set once checked out
 	LineItems []apiv1LineItem `json:"line_items,omitempty"`
+
+	ShippingAddress *apiv1Address `json:"shipping_address,omitempty"`
+	ShippingMethod  string        `json:"shipping_method,omitempty"`
+
+	Coupons     []string          `json:"coupons,omitempty"`
+	FiredRules  []apiv1FiredRule  `json:"fired_rules,omitempty"`
+	Adjustments []apiv1Adjustment `json:"adjustments,omitempty"`
+
+	// Totals in minor units of the currency, omitted unless the cart is priced.
+	Currency      string `json:"currency,omitempty"`
+	Subtotal      *int64 `json:"subtotal,omitempty"`
+	DiscountTotal *int64 `json:"discount_total,omitempty"`
+	TaxTotal      *int64 `json:"tax_total,omitempty"`
+	ShippingTotal *int64 `json:"shipping_total,omitempty"`
+	GrandTotal    *int64 `json:"grand_total,omitempty"`
+}
+
+type apiv1Carts struct {
+	Carts      []apiv1Cart `json:"carts"`
+	NextCursor string      `json:"next_cursor,omitempty"`
 }
 
 type apiv1LineItem struct {
-	ID        int64 `json:"id"`
-	CartID    int64 `json:"cart_id"`
-	ProductID int64 `json:"product_id"`
-	Quantity  int64 `json:"quantity"`
+	ID        int64  `json:"id"`
+	CartID    int64  `json:"cart_id"`
+	ProductID int64  `json:"product_id"`
+	Quantity  int64  `json:"quantity"`
+	SKU       string `json:"sku,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Category  string `json:"category,omitempty"`
+	UnitPrice int64  `json:"unit_price,omitempty"` // in minor units of the currency
+	Currency  string `json:"currency,omitempty"`
+
+	// The current unit price if it differs from the one the item has been added at.
+	PriceChanged     bool   `json:"price_changed,omitempty"`
+	CurrentUnitPrice *int64 `json:"current_unit_price,omitempty"`
+
+	Discounts []apiv1Adjustment `json:"discounts,omitempty"`
+}
+
+type apiv1Adjustment struct {
+	Kind     string `json:"kind"`
+	Label    string `json:"label"`
+	Amount   int64  `json:"amount"` // in minor units of the currency
+	Currency string `json:"currency,omitempty"`
+	Included bool   `json:"included,omitempty"` // a tax included in the unit prices
+	Shipping bool   `json:"shipping,omitempty"` // a discount of the shipping charges
+}
+
+type apiv1Address struct {
+	Name       string `json:"name,omitempty"`
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Region     string `json:"region,omitempty"`
+	Country    string `json:"country"`
+}
+
+type apiv1ShippingMethod struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"` // in minor units of the currency
+	Currency string `json:"currency"`
+}
+
+type apiv1ShippingMethods struct {
+	ShippingMethods []apiv1ShippingMethod `json:"shipping_methods"`
+}
+
+type apiv1Checkout struct {
+	OrderID int64 `json:"order_id"`
+}
+
+type apiv1Payment struct {
+	ID       int64  `json:"id"`
+	CartID   int64  `json:"cart_id"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Status   string `json:"status"`
+}
+
+type apiv1FiredRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// apiv1Errors maps domain errors to problems.
+var apiv1Errors = []struct {
+	err    error
+	status int
+	code   string
+	title  string
+}{
+	{ErrCartNotFound, http.StatusNotFound, "cart-not-found", "Cart not found"},
+	{ErrLineItemNotFound, http.StatusNotFound, "line-item-not-found", "Line item not found"},
+	{ErrCouponNotFound, http.StatusNotFound, "coupon-not-found", "Coupon not found"},
+	{ErrCouponNotApplicable, http.StatusUnprocessableEntity, "coupon-not-applicable", "Coupon not applicable"},
+	{ErrShippingMethodNotAvailable, http.StatusUnprocessableEntity, "shipping-method-not-available", "Shipping method not available"},
+	{ErrOutOfStock, http.StatusConflict, "out-of-stock", "Not enough stock"},
+	{ErrCartEmpty, http.StatusUnprocessableEntity, "cart-empty", "Cart is empty"},
+	{ErrCartCheckedOut, http.StatusConflict, "cart-checked-out", "Cart has been checked out"},
+	{ErrCartInactive, http.StatusConflict, "cart-inactive", "Cart has been abandoned or merged"},
+	{ErrPriceChanged, http.StatusConflict, "price-changed", "Prices have changed, reprice the cart"},
+	{ErrPaymentNotFound, http.StatusNotFound, "payment-not-found", "Payment not found"},
+	{ErrPaymentDeclined, http.StatusPaymentRequired, "payment-declined", "Payment declined"},
+	{ErrInvalidArgument, http.StatusBadRequest, "invalid-params", "Invalid request parameters"},
+	{ErrInvalidProduct, http.StatusUnprocessableEntity, "invalid-product", "Invalid product"},
+	{ErrInvalidQuantity, http.StatusUnprocessableEntity, "invalid-quantity", "Invalid quantity"},
+	{ErrCurrencyMismatch, http.StatusConflict, "currency-mismatch", "Cart mixes currencies"},
+	{ErrConflict, http.StatusConflict, "conflict", "Conflicting update"},
+	{ErrUnavailable, http.StatusServiceUnavailable, "unavailable", "Service temporarily unavailable"},
+	{ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition-failed", "Cart has been modified"},
+	{ErrUnauthenticated, http.StatusUnauthorized, "unauthorized", "Authentication required"},
+	{ErrForbidden, http.StatusForbidden, "forbidden", "Access denied"},
 }
 
 type service interface {
 	CartCreate(ctx context.Context, userID int64, items []*LineItem) (*Cart, error)
 	CartShow(ctx context.Context, cartID int64) (*Cart, error)
-	CartEmpty(ctx context.Context, cartID int64) error
-	LineItemAdd(ctx context.Context, cartID int64, items []*LineItem) ([]*LineItem, error)
-	LineItemRemove(ctx context.Context, cartID, itemID int64) error
+	CartReprice(ctx context.Context, cartID int64) (*Cart, error)
+	CartsByUser(ctx context.Context, userID int64, q CartsQuery) ([]*Cart, int64, error)
+	ActiveCart(ctx context.Context, userID int64) (*Cart, error)
+	CartEmpty(ctx context.Context, cartID int64) (int64, error)
+	LineItemAdd(ctx context.Context, cartID int64, items []*LineItem) ([]*LineItem, int64, error)
+	LineItemSetQuantity(ctx context.Context, cartID, itemID, quantity int64) (*LineItem, int64, error)
+	LineItemRemove(ctx context.Context, cartID, itemID int64) (int64, error)
+	CouponApply(ctx context.Context, cartID int64, code string) (*Cart, error)
+	CouponRemove(ctx context.Context, cartID int64, code string) (int64, error)
+	CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) (*Cart, error)
+	CartShippingMethodSet(ctx context.Context, cartID int64, code string) (*Cart, error)
+	ShippingMethods(ctx context.Context, cartID int64) ([]ShippingRate, error)
+	Checkout(ctx context.Context, cartID int64) (int64, error)
+	CartPay(ctx context.Context, cartID int64, source string) (*Payment, bool, error)
+	CartMerge(ctx context.Context, cartID, guestCartID int64, strategy MergeStrategy) (*Cart, error)
 }
 
 // APIv1 describes Shopping Cart REST API v1.
@@ -39,48 +163,90 @@ type APIv1 struct {
 	service service
 }
 
-// NewAPIv1 instantiates APIv1.
-func NewAPIv1(srv service) *chi.Mux {
+// NewAPIv1 instantiates APIv1, requests are authenticated with auth and then passed through
+// middlewares. Anonymous requests are served as guests if guests is true.
+func NewAPIv1(srv service, auth Authenticator, guests bool, middlewares ...func(http.Handler) http.Handler) *chi.Mux {
 	h := APIv1{service: srv}
 
 	r := chi.NewRouter()
 
-	r.Use(APIv1AuthMiddleware(nil))
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		newProblem(r, http.StatusNotFound, "not-found", "Not found").write(w)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		newProblem(r, http.StatusMethodNotAllowed, "method-not-allowed", "Method not allowed").write(w)
+	})
+
+	r.Use(APIv1AuthMiddleware(auth, guests))
+	r.Use(APIv1IfMatchMiddleware)
+	r.Use(middlewares...)
 
 	r.Post("/v1/cart", h.CartCreate)
 	r.Get("/v1/cart/{cartID}", h.CartShow)
 	r.Delete("/v1/cart/{cartID}", h.CartEmpty)
+	r.Post("/v1/cart/{cartID}/reprice", h.CartReprice)
 
 	r.Put("/v1/cart/{cartID}/item", h.LineItemAdd)
+	r.Patch("/v1/cart/{cartID}/item/{itemID}", h.LineItemSetQuantity)
 	r.Delete("/v1/cart/{cartID}/item/{itemID}", h.LineItemRemove)
 
+	r.Post("/v1/cart/{cartID}/coupons", h.CouponApply)
+	r.Delete("/v1/cart/{cartID}/coupons/{code}", h.CouponRemove)
+
+	r.Put("/v1/cart/{cartID}/shipping-address", h.CartShippingAddressSet)
+	r.Get("/v1/cart/{cartID}/shipping-methods", h.ShippingMethods)
+	r.Put("/v1/cart/{cartID}/shipping-method", h.CartShippingMethodSet)
+
+	r.Post("/v1/cart/{cartID}/checkout", h.Checkout)
+	r.Post("/v1/cart/{cartID}/payment", h.CartPay)
+	r.Post("/v1/cart/{cartID}/merge", h.CartMerge)
+
+	r.Get("/v1/users/{userID}/carts", h.CartsByUser)
+	r.Get("/v1/users/{userID}/cart", h.ActiveCart)
+
 	return r
 }
 
 // CartCreate creates and persists a shopping cart.
+// NOTE: The cart belongs to the caller unless user_id of another user is given by an admin.
 func (h *APIv1) CartCreate(w http.ResponseWriter, r *http.Request) {
 	var c apiv1Cart
 	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "json: %s", err)
-		return
-	} else if c.UserID == 0 {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "user ID required")
+		h.malformedBody(w, r, err)
 		return
 	}
 
-	cart, err := h.service.CartCreate(r.Context(), c.UserID, h.fromAPIv1LineItem(c.LineItems))
-	switch {
-	case r.Context().Err() != nil:
-		w.WriteHeader(http.StatusRequestTimeout)
+	p, ok := PrincipalFromContext(r.Context())
+	if ok && c.UserID == 0 {
+		c.UserID = p.UserID
+	}
+
+	if !ok && c.UserID == 0 {
+		h.invalidParams(w, r, invalidParam{Name: "user_id", Reason: "required"})
 		return
-	case err != nil:
-		log.Printf("CartCreate(%+v): %s", c, err)
-		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	// Guests are issued their token along with their first cart.
+	ctx, token := r.Context(), ""
+	if ok && c.UserID == 0 && p.GuestToken == "" {
+		var err error
+		if token, err = newGuestToken(); err != nil {
+			h.error(w, r, err)
+			return
+		}
+		ctx = withPrincipal(ctx, &Principal{GuestToken: token})
+	}
+
+	cart, err := h.service.CartCreate(ctx, c.UserID, h.fromAPIv1LineItem(c.LineItems))
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
 		return
 	}
 
+	if token != "" {
+		setGuestToken(w, r, token)
+	}
+	w.Header().Set("ETag", cartETag(cart.Version))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
 		log.Printf("CartCreate Encode(%+v): %s", cart, err)
@@ -95,27 +261,106 @@ func (h *APIv1) CartCreate(w http.ResponseWriter, r *http.Request) {
 func (h *APIv1) CartShow(w http.ResponseWriter, r *http.Request) {
 	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "cartID: %s", err)
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
 		return
 	}
 
 	cart, err := h.service.CartShow(r.Context(), cartID)
-	switch {
-	case r.Context().Err() != nil:
-		w.WriteHeader(http.StatusRequestTimeout)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
 		return
-	case errors.Is(err, sql.ErrNoRows):
-		w.WriteHeader(http.StatusNotFound)
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
+	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
+		log.Printf("CartShow Encode(%+v): %s", cart, err)
+		w.WriteHeader(http.StatusInternalServerError)
 		return
-	case err != nil:
-		log.Printf("CartShow(%d): %s", cartID, err)
+	}
+
+	return
+}
+
+// CartReprice updates prices of the cart items to the current prices, acknowledging price changes.
+func (h *APIv1) CartReprice(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	cart, err := h.service.CartReprice(r.Context(), cartID)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
+	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
+		log.Printf("CartReprice Encode(%+v): %s", cart, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	return
+}
+
+// CartsByUser returns a page of carts of a user.
+// Query parameters: cursor (next_cursor of the previous page), limit, created_from,
+// created_to, updated_from, updated_to (RFC 3339) and with_items.
+func (h *APIv1) CartsByUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.parseInt(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "userID", Reason: err.Error()})
+		return
+	}
+
+	q, params := h.parseCartsQuery(r)
+	if len(params) > 0 {
+		h.invalidParams(w, r, params...)
+		return
+	}
+
+	carts, nextID, err := h.service.CartsByUser(r.Context(), userID, q)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	resp := apiv1Carts{Carts: make([]apiv1Cart, len(carts))}
+	for j, c := range carts {
+		resp.Carts[j] = h.toAPIv1Cart(c)
+	}
+	if nextID != 0 {
+		resp.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(nextID, 10)))
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("CartsByUser Encode(%+v): %s", resp, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// ActiveCart returns the active cart of a user, creating an empty one if the user has none.
+func (h *APIv1) ActiveCart(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.parseInt(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "userID", Reason: err.Error()})
+		return
+	}
+
+	cart, err := h.service.ActiveCart(r.Context(), userID)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
 	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
-		log.Printf("CartShow Encode(%+v): %s", cart, err)
+		log.Printf("ActiveCart Encode(%+v): %s", cart, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -128,22 +373,17 @@ func (h *APIv1) CartShow(w http.ResponseWriter, r *http.Request) {
 func (h *APIv1) CartEmpty(w http.ResponseWriter, r *http.Request) {
 	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "cartID: %s", err)
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
 		return
 	}
 
-	err = h.service.CartEmpty(r.Context(), cartID)
-	switch {
-	case r.Context().Err() != nil:
-		w.WriteHeader(http.StatusRequestTimeout)
-		return
-	case err != nil:
-		log.Printf("CartEmpty(%d): %s", cartID, err)
-		w.WriteHeader(http.StatusInternalServerError)
+	version, err := h.service.CartEmpty(r.Context(), cartID)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", cartETag(version))
 	w.WriteHeader(http.StatusNoContent)
 	return
 }
@@ -152,33 +392,23 @@ func (h *APIv1) CartEmpty(w http.ResponseWriter, r *http.Request) {
 func (h *APIv1) LineItemAdd(w http.ResponseWriter, r *http.Request) {
 	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "cartID: %s", err)
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
 		return
 	}
 
 	var ii []apiv1LineItem
 	if err := json.NewDecoder(r.Body).Decode(&ii); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "json: %s", err)
+		h.malformedBody(w, r, err)
 		return
 	}
 
-	items, err := h.service.LineItemAdd(r.Context(), cartID, h.fromAPIv1LineItem(ii))
-	switch {
-	case r.Context().Err() != nil:
-		w.WriteHeader(http.StatusRequestTimeout)
-		return
-	case errors.Is(err, sql.ErrNoRows):
-		w.WriteHeader(http.StatusNotFound)
-		fmt.Fprintf(w, "cart does not exist")
-		return
-	case err != nil:
-		log.Printf("LineItemAdd(%d, %+v): %s", cartID, ii, err)
-		w.WriteHeader(http.StatusInternalServerError)
+	items, version, err := h.service.LineItemAdd(r.Context(), cartID, h.fromAPIv1LineItem(ii))
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", cartETag(version))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(h.toAPIv1LineItem(items)); err != nil {
 		log.Printf("LineItemAdd Encode(%+v): %s", items, err)
@@ -189,40 +419,395 @@ func (h *APIv1) LineItemAdd(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// LineItemSetQuantity sets the quantity of a cart item.
+// NOTE: Zero quantity removes the item, the response has no content then.
+func (h *APIv1) LineItemSetQuantity(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	itemID, err := h.parseInt(chi.URLParam(r, "itemID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "itemID", Reason: err.Error()})
+		return
+	}
+
+	var body struct {
+		Quantity *int64 `json:"quantity"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.malformedBody(w, r, err)
+		return
+	}
+
+	if body.Quantity == nil {
+		h.invalidParams(w, r, invalidParam{Name: "quantity", Reason: "required"})
+		return
+	}
+
+	item, version, err := h.service.LineItemSetQuantity(r.Context(), cartID, itemID, *body.Quantity)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(version))
+	if item == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(h.toAPIv1LineItem([]*LineItem{item})[0]); err != nil {
+		log.Printf("LineItemSetQuantity Encode(%+v): %s", item, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
 // LineItemRemove removes products from a shopping cart.
 func (h *APIv1) LineItemRemove(w http.ResponseWriter, r *http.Request) {
 	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "cartID: %s", err)
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
 		return
 	}
 
 	itemID, err := h.parseInt(chi.URLParam(r, "itemID"))
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprintf(w, "itemID: %s", err)
+		h.invalidParams(w, r, invalidParam{Name: "itemID", Reason: err.Error()})
 		return
 	}
 
-	err = h.service.LineItemRemove(r.Context(), cartID, itemID)
+	version, err := h.service.LineItemRemove(r.Context(), cartID, itemID)
 	switch {
 	case r.Context().Err() != nil:
-		w.WriteHeader(http.StatusRequestTimeout)
+		h.error(w, r, err)
 		return
-	case errors.Is(err, sql.ErrNoRows):
+	case errors.Is(err, ErrCartNotFound), errors.Is(err, ErrLineItemNotFound):
 		// Being idempotent.
 		// Ignoring Cart doesn't exist error assuming the item doesn't exist as well.
 	case err != nil:
-		log.Printf("LineItemRemove(%d, %d): %s", cartID, itemID, err)
+		h.error(w, r, err)
+		return
+	default:
+		w.Header().Set("ETag", cartETag(version))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return
+}
+
+// CouponApply applies a coupon to a shopping cart.
+func (h *APIv1) CouponApply(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.malformedBody(w, r, err)
+		return
+	}
+
+	if body.Code == "" {
+		h.invalidParams(w, r, invalidParam{Name: "code", Reason: "required"})
+		return
+	}
+
+	cart, err := h.service.CouponApply(r.Context(), cartID, body.Code)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
+		log.Printf("CouponApply Encode(%+v): %s", cart, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	return
+}
+
+// CouponRemove removes a coupon from a shopping cart.
+func (h *APIv1) CouponRemove(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	version, err := h.service.CouponRemove(r.Context(), cartID, chi.URLParam(r, "code"))
+	switch {
+	case r.Context().Err() != nil:
+		h.error(w, r, err)
+		return
+	case errors.Is(err, ErrCartNotFound), errors.Is(err, ErrCouponNotFound):
+		// Being idempotent the same way as LineItemRemove.
+	case err != nil:
+		h.error(w, r, err)
+		return
+	default:
+		w.Header().Set("ETag", cartETag(version))
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 	return
 }
 
+// CartShippingAddressSet sets the shipping address of a shopping cart.
+func (h *APIv1) CartShippingAddressSet(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	var a apiv1Address
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		h.malformedBody(w, r, err)
+		return
+	}
+
+	cart, err := h.service.CartShippingAddressSet(r.Context(), cartID, &Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Region:     a.Region,
+		Country:    a.Country,
+	})
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
+	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
+		log.Printf("CartShippingAddressSet Encode(%+v): %s", cart, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// ShippingMethods returns the shipping methods available for a shopping cart.
+func (h *APIv1) ShippingMethods(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	rates, err := h.service.ShippingMethods(r.Context(), cartID)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	resp := apiv1ShippingMethods{ShippingMethods: make([]apiv1ShippingMethod, len(rates))}
+	for j, rate := range rates {
+		resp.ShippingMethods[j] = apiv1ShippingMethod{Code: rate.Code, Name: rate.Name, Amount: rate.Amount.Amount, Currency: rate.Amount.Currency}
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("ShippingMethods Encode(%+v): %s", rates, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// CartShippingMethodSet sets the shipping method of a shopping cart.
+func (h *APIv1) CartShippingMethodSet(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.malformedBody(w, r, err)
+		return
+	}
+
+	if body.Code == "" {
+		h.invalidParams(w, r, invalidParam{Name: "code", Reason: "required"})
+		return
+	}
+
+	cart, err := h.service.CartShippingMethodSet(r.Context(), cartID, body.Code)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
+	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
+		log.Printf("CartShippingMethodSet Encode(%+v): %s", cart, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// Checkout converts a shopping cart into an order, the cart can not be changed afterwards.
+func (h *APIv1) Checkout(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	orderID, err := h.service.Checkout(r.Context(), cartID)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(apiv1Checkout{OrderID: orderID}); err != nil {
+		log.Printf("Checkout Encode(%d): %s", orderID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// CartMerge merges a guest cart into a shopping cart of the user.
+func (h *APIv1) CartMerge(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	var body struct {
+		GuestCartID int64  `json:"guest_cart_id"`
+		Strategy    string `json:"strategy"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.malformedBody(w, r, err)
+		return
+	}
+
+	if body.GuestCartID == 0 {
+		h.invalidParams(w, r, invalidParam{Name: "guest_cart_id", Reason: "required"})
+		return
+	}
+
+	cart, err := h.service.CartMerge(r.Context(), cartID, body.GuestCartID, MergeStrategy(body.Strategy))
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(cart.Version))
+	if err := json.NewEncoder(w).Encode(h.toAPIv1Cart(cart)); err != nil {
+		log.Printf("CartMerge Encode(%+v): %s", cart, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// CartPay authorizes payment of a shopping cart, the payment of a cart paid for already is
+// returned with 200 OK.
+func (h *APIv1) CartPay(w http.ResponseWriter, r *http.Request) {
+	cartID, err := h.parseInt(chi.URLParam(r, "cartID"))
+	if err != nil {
+		h.invalidParams(w, r, invalidParam{Name: "cartID", Reason: err.Error()})
+		return
+	}
+
+	var body struct {
+		Source string `json:"source"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.malformedBody(w, r, err)
+		return
+	}
+
+	p, created, err := h.service.CartPay(r.Context(), cartID, body.Source)
+	if err != nil || r.Context().Err() != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	resp := apiv1Payment{ID: p.ID, CartID: p.CartID, Amount: p.Amount.Amount, Currency: p.Amount.Currency, Status: string(p.Status)}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("CartPay Encode(%+v): %s", p, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	return
+}
+
+// error writes err as a problem. Errors unknown to apiv1Errors are logged and reported as
+// internal server errors without details.
+func (h *APIv1) error(w http.ResponseWriter, r *http.Request, err error) {
+	if r.Context().Err() != nil {
+		newProblem(r, http.StatusRequestTimeout, "timeout", "Request timeout").write(w)
+		return
+	}
+
+	for _, e := range apiv1Errors {
+		if !errors.Is(err, e.err) {
+			continue
+		}
+
+		p := newProblem(r, e.status, e.code, e.title)
+
+		var perr *InvalidParamError
+		if errors.As(err, &perr) {
+			p.InvalidParams = []invalidParam{{Name: perr.Name, Reason: perr.Err.Error()}}
+		} else {
+			p.Detail = err.Error()
+		}
+
+		p.write(w)
+		return
+	}
+
+	log.Printf("%s %s: %s", r.Method, r.URL.Path, err)
+	newProblem(r, http.StatusInternalServerError, "internal", "Internal server error").write(w)
+}
+
+func (h *APIv1) invalidParams(w http.ResponseWriter, r *http.Request, params ...invalidParam) {
+	p := newProblem(r, http.StatusBadRequest, "invalid-params", "Invalid request parameters")
+	p.InvalidParams = params
+	p.write(w)
+}
+
+func (h *APIv1) malformedBody(w http.ResponseWriter, r *http.Request, err error) {
+	p := newProblem(r, http.StatusBadRequest, "malformed-body", "Malformed request body")
+	p.Detail = err.Error()
+	p.write(w)
+}
+
 func (h *APIv1) fromAPIv1LineItem(ii []apiv1LineItem) []*LineItem {
 	items := make([]*LineItem, len(ii))
 	for j, i := range ii {
@@ -236,12 +821,37 @@ func (h *APIv1) fromAPIv1LineItem(ii []apiv1LineItem) []*LineItem {
 
 func (h *APIv1) toAPIv1Cart(cart *Cart) apiv1Cart {
 	c := apiv1Cart{
-		ID:     cart.ID,
-		UserID: cart.UserID,
+		ID:      cart.ID,
+		UserID:  cart.UserID,
+		Status:  cart.Status,
+		OrderID: cart.OrderID,
 	}
 	if len(cart.LineItems) > 0 {
 		c.LineItems = h.toAPIv1LineItem(cart.LineItems)
 	}
+	if a := cart.ShippingAddress; a != nil {
+		c.ShippingAddress = &apiv1Address{
+			Name:       a.Name,
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			PostalCode: a.PostalCode,
+			Region:     a.Region,
+			Country:    a.Country,
+		}
+	}
+	c.ShippingMethod = cart.ShippingMethod
+	c.Coupons = cart.Coupons
+	for _, r := range cart.FiredRules {
+		c.FiredRules = append(c.FiredRules, apiv1FiredRule{Name: r.Name, Description: r.Description})
+	}
+	c.Adjustments = h.toAPIv1Adjustments(cart.Adjustments)
+	if t := cart.Totals; t != nil {
+		c.Currency = t.GrandTotal.Currency
+		c.Subtotal, c.DiscountTotal = &t.Subtotal.Amount, &t.DiscountTotal.Amount
+		c.TaxTotal, c.ShippingTotal = &t.TaxTotal.Amount, &t.ShippingTotal.Amount
+		c.GrandTotal = &t.GrandTotal.Amount
+	}
 	return c
 }
 
@@ -253,11 +863,93 @@ func (h *APIv1) toAPIv1LineItem(items []*LineItem) []apiv1LineItem {
 			CartID:    item.CartID,
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
+			SKU:       item.SKU,
+			Name:      item.Name,
+			Category:  item.Category,
+			UnitPrice: item.UnitPrice.Amount,
+			Currency:  item.UnitPrice.Currency,
+		}
+		ii[j].Discounts = h.toAPIv1Adjustments(item.Adjustments)
+		if item.PriceChanged {
+			current := item.CurrentUnitPrice.Amount
+			ii[j].PriceChanged, ii[j].CurrentUnitPrice = true, &current
 		}
 	}
 	return ii
 }
 
+func (h *APIv1) toAPIv1Adjustments(adjustments []Adjustment) []apiv1Adjustment {
+	if len(adjustments) == 0 {
+		return nil
+	}
+
+	aa := make([]apiv1Adjustment, len(adjustments))
+	for j, a := range adjustments {
+		aa[j] = apiv1Adjustment{Kind: string(a.Kind), Label: a.Label, Amount: a.Amount.Amount, Currency: a.Amount.Currency, Included: a.Included, Shipping: a.Shipping}
+	}
+	return aa
+}
+
+// parseCartsQuery parses query parameters of CartsByUser.
+func (h *APIv1) parseCartsQuery(r *http.Request) (CartsQuery, []invalidParam) {
+	var (
+		q      CartsQuery
+		params []invalidParam
+	)
+
+	v := r.URL.Query()
+
+	if s := v.Get("cursor"); s != "" {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err == nil {
+			q.AfterID, err = h.parseInt(string(b))
+		}
+		if err != nil {
+			params = append(params, invalidParam{Name: "cursor", Reason: "invalid cursor"})
+		}
+	}
+
+	if s := v.Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil || l <= 0 {
+			params = append(params, invalidParam{Name: "limit", Reason: fmt.Sprintf("%q is not a positive integer", s)})
+		}
+		q.Limit = l
+	}
+
+	for _, f := range []struct {
+		name string
+		tm   *time.Time
+	}{
+		{"created_from", &q.CreatedFrom},
+		{"created_to", &q.CreatedTo},
+		{"updated_from", &q.UpdatedFrom},
+		{"updated_to", &q.UpdatedTo},
+	} {
+		s := v.Get(f.name)
+		if s == "" {
+			continue
+		}
+
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			params = append(params, invalidParam{Name: f.name, Reason: fmt.Sprintf("%q is not an RFC 3339 time", s)})
+			continue
+		}
+		*f.tm = tm
+	}
+
+	if s := v.Get("with_items"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			params = append(params, invalidParam{Name: "with_items", Reason: fmt.Sprintf("%q is not a boolean", s)})
+		}
+		q.WithItems = b
+	}
+
+	return q, params
+}
+
 func (h *APIv1) parseInt(s string) (int64, error) {
 	i, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
@@ -273,25 +965,97 @@ type ctxAuthKey uint8
 
 const ctxAuth ctxAuthKey = 0
 
-// APIv1AuthMiddleware returns an authentication middleware which auth users over auth service.
-func APIv1AuthMiddleware(authsrv interface{}) func(next http.Handler) http.Handler {
+// APIv1AuthMiddleware returns an authentication middleware which authenticates bearer tokens
+// with auth and places the principal in the request's context. If guests is true, requests
+// without the Authorization header are served as guests and the guest token of the request is
+// passed along, see guestToken.
+func APIv1AuthMiddleware(auth Authenticator, guests bool) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			user, pass, ok := r.BasicAuth() // NOTE: let's pretend it's a token
+			var guest string
+			if guests {
+				guest = guestToken(r)
+			}
+
+			token, ok := bearerToken(r)
+			if !ok && guests && r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{GuestToken: guest})))
+				return
+			}
 			if !ok {
-				w.WriteHeader(http.StatusUnauthorized)
+				w.Header().Set("WWW-Authenticate", `Bearer realm="shoppingcart"`)
+				newProblem(r, http.StatusUnauthorized, "unauthorized", "Authentication required").write(w)
 				return
 			}
 
-			// NOTE: sending _the token_ to the imaginary authsrv service.
-			if user != "Aladdin" || pass != "OpenSesame" {
-				w.WriteHeader(http.StatusForbidden)
+			principal, err := auth.Authenticate(r.Context(), token)
+			switch {
+			case errors.Is(err, ErrUnauthenticated):
+				w.Header().Set("WWW-Authenticate", `Bearer realm="shoppingcart", error="invalid_token"`)
+				p := newProblem(r, http.StatusUnauthorized, "invalid-token", "Invalid token")
+				p.Detail = "the access token is malformed, expired or revoked"
+				p.write(w)
 				return
+			case err != nil:
+				log.Printf("%s %s: authenticate: %s", r.Method, r.URL.Path, err)
+				newProblem(r, http.StatusInternalServerError, "internal", "Internal server error").write(w)
+				return
+			}
+
+			// Users keep access to their guest carts to merge them. Principals of API keys are
+			// shared, so the principal is copied.
+			if guest != "" {
+				p := *principal
+				p.GuestToken = guest
+				principal = &p
 			}
 
-			// NOTE: adding auth info the request's context.
-			ctx := context.WithValue(r.Context(), ctxAuth, "info")
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), principal)))
 		})
 	}
 }
+
+// bearerToken returns the token of the Bearer authorization scheme.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token := "", ""
+	if ss := strings.SplitN(r.Header.Get("Authorization"), " ", 2); len(ss) == 2 {
+		scheme, token = ss[0], strings.TrimSpace(ss[1])
+	}
+
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// APIv1IfMatchMiddleware restricts cart mutations to the cart versions of the If-Match header.
+// The header holds ETags of CartShow, mutations of a cart modified since fail with 412.
+func APIv1IfMatchMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := strings.TrimSpace(r.Header.Get("If-Match"))
+		if h == "" || h == "*" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Weak and malformed tags never match, a header without valid tags fails every mutation.
+		versions := []int64{}
+		for _, tag := range strings.Split(h, ",") {
+			tag = strings.TrimSpace(tag)
+			if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+				continue
+			}
+
+			if v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil {
+				versions = append(versions, v)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithCartVersions(r.Context(), versions...)))
+	})
+}
+
+// cartETag returns the entity tag of a cart version.
+func cartETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
-	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -59,6 +59,86 @@ func TestAPIv1_CartCreate(t *testing.T) {
 		}
 	})
 
+	t.Run("user from token", func(t *testing.T) {
+		c := Cart{UserID: 16}
+
+		ctx := withPrincipal(context.Background(), &Principal{UserID: c.UserID})
+		r := httptest.NewRequest(http.MethodPost, "/v1/cart", bytes.NewBufferString(`{}`)).WithContext(ctx)
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CartCreateMock.Expect(r.Context(), c.UserID, []*LineItem{}).Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartCreate(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
+		}
+	})
+
+	t.Run("guest", func(t *testing.T) {
+		c := Cart{ID: 10, GuestToken: "hash"}
+
+		ctx := withPrincipal(context.Background(), &Principal{})
+		r := httptest.NewRequest(http.MethodPost, "/v1/cart", bytes.NewBufferString(`{}`)).WithContext(ctx)
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		var token string
+		s := NewServiceMock(mc)
+		s = s.CartCreateMock.Set(func(ctx context.Context, userID int64, items []*LineItem) (*Cart, error) {
+			p, _ := PrincipalFromContext(ctx)
+			if userID != 0 || p == nil || p.GuestToken == "" {
+				t.Errorf("guest cart with a token exp, got: user %d, %+v", userID, p)
+			} else {
+				token = p.GuestToken
+			}
+			return &c, nil
+		})
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartCreate(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
+		}
+		if h := w.Header().Get(guestTokenHeader); h != token {
+			t.Errorf("guest token exp: %s, got: %s", token, h)
+		}
+
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != guestTokenCookie || cookies[0].Value != token || !cookies[0].HttpOnly {
+			t.Errorf("guest token cookie exp: %s, got: %+v", token, cookies)
+		}
+	})
+
+	t.Run("guest with token", func(t *testing.T) {
+		c := Cart{ID: 10, GuestToken: "hash"}
+
+		ctx := withPrincipal(context.Background(), &Principal{GuestToken: "tok"})
+		r := httptest.NewRequest(http.MethodPost, "/v1/cart", bytes.NewBufferString(`{}`)).WithContext(ctx)
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CartCreateMock.Expect(r.Context(), 0, []*LineItem{}).Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartCreate(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
+		}
+		if h := w.Header().Get(guestTokenHeader); h != "" {
+			t.Errorf("no guest token exp, got: %s", h)
+		}
+	})
+
 	t.Run("context", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -86,13 +166,30 @@ func TestAPIv1_CartCreate(t *testing.T) {
 func TestAPIv1_CartShow(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		c := Cart{
-			ID:     10,
-			UserID: 15,
+			ID:      10,
+			UserID:  15,
+			Version: 4,
 			LineItems: []*LineItem{
-				{ID: 20, CartID: 10, ProductID: 30, Quantity: 2, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+				{ID: 20, CartID: 10, ProductID: 30, Quantity: 2, SKU: "SKU-30", Name: "Thirty", Category: "tea", UnitPrice: Money{Amount: 499, Currency: "EUR"}, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			},
+			CreatedAt:  time.Now().Add(-10 * time.Minute),
+			UpdatedAt:  time.Now().Add(-10 * time.Minute),
+			FiredRules: []FiredRule{{Name: "tea-10", Description: "10% off teas"}},
+			ShippingAddress: &Address{
+				Name:       "Jo Doe",
+				Line1:      "1 Main St",
+				City:       "Berlin",
+				PostalCode: "10115",
+				Country:    "DE",
+			},
+			Adjustments: []Adjustment{{Kind: AdjustmentTax, Label: "VAT 19%", Amount: Money{Amount: 171, Currency: "EUR"}, Included: true}},
+			Totals: &Totals{
+				Subtotal:      Money{Amount: 998, Currency: "EUR"},
+				DiscountTotal: Money{Amount: 100, Currency: "EUR"},
+				TaxTotal:      Money{Amount: 171, Currency: "EUR"},
+				ShippingTotal: Money{Amount: 0, Currency: "EUR"},
+				GrandTotal:    Money{Amount: 1069, Currency: "EUR"},
 			},
-			CreatedAt: time.Now().Add(-10 * time.Minute),
-			UpdatedAt: time.Now().Add(-10 * time.Minute),
 		}
 
 		uri := fmt.Sprintf("/v1/cart/%d", c.ID)
@@ -111,6 +208,9 @@ func TestAPIv1_CartShow(t *testing.T) {
 		if w.Code != http.StatusOK {
 			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
 		}
+		if etag := w.Header().Get("ETag"); etag != `"4"` {
+			t.Errorf("etag exp: %s, got: %s", `"4"`, etag)
+		}
 
 		var cart apiv1Cart
 		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
@@ -121,8 +221,23 @@ func TestAPIv1_CartShow(t *testing.T) {
 			ID:     c.ID,
 			UserID: c.UserID,
 			LineItems: []apiv1LineItem{
-				{ID: c.LineItems[0].ID, CartID: c.LineItems[0].CartID, ProductID: c.LineItems[0].ProductID, Quantity: c.LineItems[0].Quantity},
+				{ID: c.LineItems[0].ID, CartID: c.LineItems[0].CartID, ProductID: c.LineItems[0].ProductID, Quantity: c.LineItems[0].Quantity, SKU: "SKU-30", Name: "Thirty", Category: "tea", UnitPrice: 499, Currency: "EUR"},
+			},
+			ShippingAddress: &apiv1Address{
+				Name:       "Jo Doe",
+				Line1:      "1 Main St",
+				City:       "Berlin",
+				PostalCode: "10115",
+				Country:    "DE",
 			},
+			FiredRules:    []apiv1FiredRule{{Name: "tea-10", Description: "10% off teas"}},
+			Adjustments:   []apiv1Adjustment{{Kind: "tax", Label: "VAT 19%", Amount: 171, Currency: "EUR", Included: true}},
+			Currency:      "EUR",
+			Subtotal:      int64p(998),
+			DiscountTotal: int64p(100),
+			TaxTotal:      int64p(171),
+			ShippingTotal: int64p(0),
+			GrandTotal:    int64p(1069),
 		}
 
 		if !reflect.DeepEqual(exp, cart) {
@@ -135,7 +250,8 @@ func TestAPIv1_CartShow(t *testing.T) {
 		err  error
 		code int
 	}{
-		{"no rows", sql.ErrNoRows, http.StatusNotFound},
+		{"not found", fmt.Errorf("cart: %w", ErrCartNotFound), http.StatusNotFound},
+		{"conflict", ErrConflict, http.StatusConflict},
 		{"any error", errors.New("any"), http.StatusInternalServerError},
 	}
 	for _, tt := range tests {
@@ -160,6 +276,272 @@ func TestAPIv1_CartShow(t *testing.T) {
 	}
 }
 
+func TestAPIv1_CartReprice(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		c := Cart{
+			ID:      10,
+			UserID:  15,
+			Version: 5,
+			LineItems: []*LineItem{
+				{ID: 20, CartID: 10, ProductID: 30, Quantity: 2, UnitPrice: Money{Amount: 549, Currency: "EUR"}},
+				{ID: 21, CartID: 10, ProductID: 31, Quantity: 1, UnitPrice: Money{Amount: 100, Currency: "EUR"}, PriceChanged: true, CurrentUnitPrice: Money{Amount: 0, Currency: "EUR"}},
+			},
+		}
+
+		uri := fmt.Sprintf("/v1/cart/%d/reprice", c.ID)
+		r := httptest.NewRequest(http.MethodPost, uri, nil)
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/reprice", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CartRepriceMock.Expect(r.Context(), c.ID).Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartReprice(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"5"` {
+			t.Errorf("etag exp: %s, got: %s", `"5"`, etag)
+		}
+
+		var cart apiv1Cart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := apiv1Cart{
+			ID:     c.ID,
+			UserID: c.UserID,
+			LineItems: []apiv1LineItem{
+				{ID: 20, CartID: 10, ProductID: 30, Quantity: 2, UnitPrice: 549, Currency: "EUR"},
+				// A price changed to zero is still reported.
+				{ID: 21, CartID: 10, ProductID: 31, Quantity: 1, UnitPrice: 100, Currency: "EUR", PriceChanged: true, CurrentUnitPrice: int64p(0)},
+			},
+		}
+
+		if !reflect.DeepEqual(exp, cart) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, cart)
+		}
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", fmt.Errorf("cart: %w", ErrCartNotFound), http.StatusNotFound},
+		{"precondition failed", ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{"any error", errors.New("any"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/reprice"
+			r := httptest.NewRequest(http.MethodPost, uri, nil)
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/reprice", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.CartRepriceMock.Expect(r.Context(), 100).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartReprice(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_CartsByUser(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		carts := []*Cart{
+			{ID: 10, UserID: 15, LineItems: []*LineItem{{ID: 20, CartID: 10, ProductID: 30, Quantity: 2}}},
+			{ID: 11, UserID: 15},
+		}
+
+		cursor := base64.RawURLEncoding.EncodeToString([]byte("9"))
+		uri := "/v1/users/15/carts?cursor=" + cursor + "&limit=2&created_from=2020-01-02T15:04:05Z&with_items=true"
+		r := httptest.NewRequest(http.MethodGet, uri, nil)
+		r = r.WithContext(chiRouteContext(t, "/v1/users/{userID}/carts", r.URL.Path))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		q := CartsQuery{
+			AfterID:     9,
+			Limit:       2,
+			CreatedFrom: time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC),
+			WithItems:   true,
+		}
+
+		s := NewServiceMock(mc)
+		s = s.CartsByUserMock.Expect(r.Context(), 15, q).Return(carts, 11, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartsByUser(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+		}
+
+		var got apiv1Carts
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := apiv1Carts{
+			Carts: []apiv1Cart{
+				{ID: 10, UserID: 15, LineItems: []apiv1LineItem{{ID: 20, CartID: 10, ProductID: 30, Quantity: 2}}},
+				{ID: 11, UserID: 15},
+			},
+			NextCursor: base64.RawURLEncoding.EncodeToString([]byte("11")),
+		}
+
+		if !reflect.DeepEqual(exp, got) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, got)
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		uri := "/v1/users/15/carts?cursor=x&limit=0&updated_to=yesterday&with_items=sure"
+		r := httptest.NewRequest(http.MethodGet, uri, nil)
+		r = r.WithContext(chiRouteContext(t, "/v1/users/{userID}/carts", r.URL.Path))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: NewServiceMock(mc)}).CartsByUser(w, r)
+
+		assertProblem(t, w, problem{
+			Type:     "/problems/invalid-params",
+			Title:    "Invalid request parameters",
+			Status:   http.StatusBadRequest,
+			Instance: uri,
+			InvalidParams: []invalidParam{
+				{Name: "cursor", Reason: "invalid cursor"},
+				{Name: "limit", Reason: `"0" is not a positive integer`},
+				{Name: "updated_to", Reason: `"yesterday" is not an RFC 3339 time`},
+				{Name: "with_items", Reason: `"sure" is not a boolean`},
+			},
+		})
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"forbidden", ErrForbidden, http.StatusForbidden},
+		{"invalid limit", &InvalidParamError{Name: "limit", Err: ErrInvalidArgument}, http.StatusBadRequest},
+		{"any error", errors.New("any"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/users/15/carts"
+			r := httptest.NewRequest(http.MethodGet, uri, nil)
+			r = r.WithContext(chiRouteContext(t, "/v1/users/{userID}/carts", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.CartsByUserMock.Expect(r.Context(), 15, CartsQuery{}).Return(nil, 0, tt.err)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartsByUser(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_ActiveCart(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		c := Cart{ID: 10, UserID: 15, Version: 2, Status: CartActive}
+
+		uri := "/v1/users/15/cart"
+		r := httptest.NewRequest(http.MethodGet, uri, nil)
+		r = r.WithContext(chiRouteContext(t, "/v1/users/{userID}/cart", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.ActiveCartMock.Expect(r.Context(), 15).Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).ActiveCart(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"2"` {
+			t.Errorf("etag exp: %s, got: %s", `"2"`, etag)
+		}
+
+		var cart apiv1Cart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatal(err)
+		}
+		if exp := (apiv1Cart{ID: 10, UserID: 15, Status: CartActive}); !reflect.DeepEqual(exp, cart) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, cart)
+		}
+	})
+
+	t.Run("invalid user", func(t *testing.T) {
+		uri := "/v1/users/abc/cart"
+		r := httptest.NewRequest(http.MethodGet, uri, nil)
+		r = r.WithContext(chiRouteContext(t, "/v1/users/{userID}/cart", uri))
+
+		w := httptest.NewRecorder()
+		(&APIv1{}).ActiveCart(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("code exp: %d, got: %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"forbidden", ErrForbidden, http.StatusForbidden},
+		{"conflict", ErrConflict, http.StatusConflict},
+		{"any error", errors.New("any"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/users/15/cart"
+			r := httptest.NewRequest(http.MethodGet, uri, nil)
+			r = r.WithContext(chiRouteContext(t, "/v1/users/{userID}/cart", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.ActiveCartMock.Expect(r.Context(), 15).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).ActiveCart(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
 func TestAPIv1_CartEmpty(t *testing.T) {
 	var cartID int64 = 10
 	uri := fmt.Sprintf("/v1/cart/%d", cartID)
@@ -170,7 +552,7 @@ func TestAPIv1_CartEmpty(t *testing.T) {
 	defer mc.Finish()
 
 	s := NewServiceMock(mc)
-	s = s.CartEmptyMock.Expect(r.Context(), cartID).Return(nil)
+	s = s.CartEmptyMock.Expect(r.Context(), cartID).Return(4, nil)
 
 	w := httptest.NewRecorder()
 	(&APIv1{service: s}).CartEmpty(w, r)
@@ -179,6 +561,10 @@ func TestAPIv1_CartEmpty(t *testing.T) {
 		t.Errorf("code exp: %d, got: %d", http.StatusNoContent, w.Code)
 	}
 
+	if etag := w.Header().Get("ETag"); etag != `"4"` {
+		t.Errorf("etag exp: %s, got: %s", `"4"`, etag)
+	}
+
 	// TODO tests
 }
 
@@ -196,7 +582,7 @@ func TestAPIv1_LineItemAdd(t *testing.T) {
 	defer mc.Finish()
 
 	s := NewServiceMock(mc)
-	s = s.LineItemAddMock.Expect(r.Context(), cartID, ii).Return(ii, nil)
+	s = s.LineItemAddMock.Expect(r.Context(), cartID, ii).Return(ii, 2, nil)
 
 	w := httptest.NewRecorder()
 	(&APIv1{service: s}).LineItemAdd(w, r)
@@ -205,6 +591,10 @@ func TestAPIv1_LineItemAdd(t *testing.T) {
 		t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
 	}
 
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("etag exp: %s, got: %s", `"2"`, etag)
+	}
+
 	var items []apiv1LineItem
 	if err := json.NewDecoder(w.Body).Decode(&items); err != nil {
 		t.Fatal(err)
@@ -221,30 +611,975 @@ func TestAPIv1_LineItemAdd(t *testing.T) {
 	// TODO tests
 }
 
-func TestAPIv1_LineItemRemove(t *testing.T) {
-	var (
-		cartID int64 = 10
-		itemID int64 = 20
-	)
+func TestAPIv1_LineItemSetQuantity(t *testing.T) {
+	uri := "/v1/cart/10/item/20"
 
-	uri := fmt.Sprintf("/v1/cart/%d/item/%d", cartID, itemID)
-	r := httptest.NewRequest(http.MethodDelete, uri, nil)
-	r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/item/{itemID}", uri))
+	tests := []struct {
+		name string
+		item *LineItem
+		err  error
+		code int
+		etag string
+	}{
+		{"ok", &LineItem{ID: 20, CartID: 10, ProductID: 30, Quantity: 2}, nil, http.StatusOK, `"7"`},
+		{"removed", nil, nil, http.StatusNoContent, `"7"`},
+		{"not found", nil, ErrLineItemNotFound, http.StatusNotFound, ""},
+		{"negative", nil, &InvalidParamError{Name: "quantity", Err: ErrInvalidQuantity}, http.StatusUnprocessableEntity, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, uri, bytes.NewBufferString(`{"quantity":2}`))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/item/{itemID}", uri))
 
-	mc := minimock.NewController(t)
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.LineItemSetQuantityMock.Expect(r.Context(), 10, 20, 2).Return(tt.item, 7, tt.err)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).LineItemSetQuantity(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+
+			if etag := w.Header().Get("ETag"); etag != tt.etag {
+				t.Errorf("etag exp: %s, got: %s", tt.etag, etag)
+			}
+
+			if tt.item == nil {
+				return
+			}
+
+			var item apiv1LineItem
+			if err := json.NewDecoder(w.Body).Decode(&item); err != nil {
+				t.Fatal(err)
+			}
+
+			if exp := (apiv1LineItem{ID: 20, CartID: 10, ProductID: 30, Quantity: 2}); !reflect.DeepEqual(exp, item) {
+				t.Errorf("items do not match\nexp: %+v\ngot: %+v", exp, item)
+			}
+		})
+	}
+
+	t.Run("quantity required", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, uri, bytes.NewBufferString(`{}`))
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/item/{itemID}", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: NewServiceMock(mc)}).LineItemSetQuantity(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("code exp: %d, got: %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+func TestAPIv1_LineItemRemove(t *testing.T) {
+	var (
+		cartID int64 = 10
+		itemID int64 = 20
+	)
+
+	uri := fmt.Sprintf("/v1/cart/%d/item/%d", cartID, itemID)
+	r := httptest.NewRequest(http.MethodDelete, uri, nil)
+	r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/item/{itemID}", uri))
+
+	mc := minimock.NewController(t)
 	defer mc.Finish()
 
-	s := NewServiceMock(mc)
-	s = s.LineItemRemoveMock.Expect(r.Context(), cartID, itemID).Return(nil)
+	s := NewServiceMock(mc)
+	s = s.LineItemRemoveMock.Expect(r.Context(), cartID, itemID).Return(3, nil)
+
+	w := httptest.NewRecorder()
+	(&APIv1{service: s}).LineItemRemove(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("code exp: %d, got: %d", http.StatusNoContent, w.Code)
+	}
+
+	if etag := w.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("etag exp: %s, got: %s", `"3"`, etag)
+	}
+
+	t.Run("idempotent", func(t *testing.T) {
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.LineItemRemoveMock.Expect(r.Context(), cartID, itemID).Return(0, ErrLineItemNotFound)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).LineItemRemove(w, r)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("code exp: %d, got: %d", http.StatusNoContent, w.Code)
+		}
+	})
+
+	// TODO tests
+}
+
+func TestAPIv1_CouponApply(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		c := Cart{
+			ID:          10,
+			UserID:      15,
+			Version:     3,
+			Coupons:     []string{"B2G1"},
+			Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Label: "B2G1", Amount: Money{Amount: 0, Currency: "EUR"}}},
+			LineItems: []*LineItem{
+				{ID: 20, CartID: 10, ProductID: 30, Quantity: 3, UnitPrice: Money{Amount: 500, Currency: "EUR"}, Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Label: "B2G1", Amount: Money{Amount: 500, Currency: "EUR"}}}},
+			},
+		}
+
+		uri := fmt.Sprintf("/v1/cart/%d/coupons", c.ID)
+		r := httptest.NewRequest(http.MethodPost, uri, bytes.NewBufferString(`{"code":"b2g1"}`))
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/coupons", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CouponApplyMock.Expect(r.Context(), c.ID, "b2g1").Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CouponApply(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"3"` {
+			t.Errorf("etag exp: %s, got: %s", `"3"`, etag)
+		}
+
+		var cart apiv1Cart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := apiv1Cart{
+			ID:          c.ID,
+			UserID:      c.UserID,
+			Coupons:     []string{"B2G1"},
+			Adjustments: []apiv1Adjustment{{Kind: "discount", Label: "B2G1", Amount: 0, Currency: "EUR"}},
+			LineItems: []apiv1LineItem{
+				{ID: 20, CartID: 10, ProductID: 30, Quantity: 3, UnitPrice: 500, Currency: "EUR", Discounts: []apiv1Adjustment{{Kind: "discount", Label: "B2G1", Amount: 500, Currency: "EUR"}}},
+			},
+		}
+
+		if !reflect.DeepEqual(exp, cart) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, cart)
+		}
+	})
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"no code", `{}`, nil, http.StatusBadRequest},
+		{"malformed", `{`, nil, http.StatusBadRequest},
+		{"unknown", `{"code":"X"}`, fmt.Errorf("coupon: %w", ErrCouponNotFound), http.StatusNotFound},
+		{"not applicable", `{"code":"X"}`, ErrCouponNotApplicable, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/coupons"
+			r := httptest.NewRequest(http.MethodPost, uri, bytes.NewBufferString(tt.body))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/coupons", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			if tt.err != nil {
+				s = s.CouponApplyMock.Expect(r.Context(), 100, "X").Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CouponApply(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_CouponRemove(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+		etag string
+	}{
+		{"ok", nil, http.StatusNoContent, `"5"`},
+		{"idempotent", ErrCouponNotFound, http.StatusNoContent, ""},
+		{"precondition failed", ErrPreconditionFailed, http.StatusPreconditionFailed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/10/coupons/B2G1"
+			r := httptest.NewRequest(http.MethodDelete, uri, nil)
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/coupons/{code}", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.CouponRemoveMock.Expect(r.Context(), 10, "B2G1").Return(5, tt.err)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CouponRemove(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+
+			if etag := w.Header().Get("ETag"); etag != tt.etag {
+				t.Errorf("etag exp: %s, got: %s", tt.etag, etag)
+			}
+		})
+	}
+}
+
+func TestAPIv1_CartShippingAddressSet(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		a := &Address{Name: "Jo Doe", Line1: "1 Main St", City: "Berlin", PostalCode: "10115", Country: "de"}
+		c := Cart{ID: 10, UserID: 15, Version: 5, ShippingAddress: &Address{Name: "Jo Doe", Line1: "1 Main St", City: "Berlin", PostalCode: "10115", Country: "DE"}}
+
+		uri := fmt.Sprintf("/v1/cart/%d/shipping-address", c.ID)
+		r := httptest.NewRequest(http.MethodPut, uri, bytes.NewBufferString(`{"name":"Jo Doe","line1":"1 Main St","city":"Berlin","postal_code":"10115","country":"de"}`))
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/shipping-address", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CartShippingAddressSetMock.Expect(r.Context(), c.ID, a).Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartShippingAddressSet(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"5"` {
+			t.Errorf("etag exp: %s, got: %s", `"5"`, etag)
+		}
+
+		var cart apiv1Cart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := apiv1Cart{
+			ID:              c.ID,
+			UserID:          c.UserID,
+			ShippingAddress: &apiv1Address{Name: "Jo Doe", Line1: "1 Main St", City: "Berlin", PostalCode: "10115", Country: "DE"},
+		}
+		if !reflect.DeepEqual(exp, cart) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, cart)
+		}
+	})
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"malformed", `{`, nil, http.StatusBadRequest},
+		{"invalid", `{"country":"X"}`, &InvalidParamError{Name: "line1", Err: ErrInvalidArgument}, http.StatusBadRequest},
+		{"not found", `{"country":"X"}`, ErrCartNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/shipping-address"
+			r := httptest.NewRequest(http.MethodPut, uri, bytes.NewBufferString(tt.body))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/shipping-address", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			if tt.err != nil {
+				s = s.CartShippingAddressSetMock.Expect(r.Context(), 100, &Address{Country: "X"}).Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartShippingAddressSet(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_ShippingMethods(t *testing.T) {
+	uri := "/v1/cart/10/shipping-methods"
+	r := httptest.NewRequest(http.MethodGet, uri, nil)
+	r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/shipping-methods", uri))
+
+	mc := minimock.NewController(t)
+	defer mc.Finish()
+
+	s := NewServiceMock(mc)
+	s = s.ShippingMethodsMock.Expect(r.Context(), 10).Return([]ShippingRate{{Code: "standard", Name: "Standard", Amount: Money{Amount: 490, Currency: "EUR"}}}, nil)
+
+	w := httptest.NewRecorder()
+	(&APIv1{service: s}).ShippingMethods(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+	}
+
+	var methods apiv1ShippingMethods
+	if err := json.NewDecoder(w.Body).Decode(&methods); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := apiv1ShippingMethods{ShippingMethods: []apiv1ShippingMethod{{Code: "standard", Name: "Standard", Amount: 490, Currency: "EUR"}}}
+	if !reflect.DeepEqual(exp, methods) {
+		t.Errorf("methods do not match\nexp: %+v\ngot: %+v\n", exp, methods)
+	}
+}
+
+func TestAPIv1_CartShippingMethodSet(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		c := Cart{
+			ID:             10,
+			UserID:         15,
+			Version:        6,
+			ShippingMethod: "standard",
+			Adjustments:    []Adjustment{{Kind: AdjustmentShipping, Label: "Standard", Amount: Money{Amount: 490, Currency: "EUR"}}},
+		}
+
+		uri := fmt.Sprintf("/v1/cart/%d/shipping-method", c.ID)
+		r := httptest.NewRequest(http.MethodPut, uri, bytes.NewBufferString(`{"code":"standard"}`))
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/shipping-method", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CartShippingMethodSetMock.Expect(r.Context(), c.ID, "standard").Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartShippingMethodSet(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"6"` {
+			t.Errorf("etag exp: %s, got: %s", `"6"`, etag)
+		}
+
+		var cart apiv1Cart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := apiv1Cart{
+			ID:             c.ID,
+			UserID:         c.UserID,
+			ShippingMethod: "standard",
+			Adjustments:    []apiv1Adjustment{{Kind: "shipping", Label: "Standard", Amount: 490, Currency: "EUR"}},
+		}
+		if !reflect.DeepEqual(exp, cart) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, cart)
+		}
+	})
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"no code", `{}`, nil, http.StatusBadRequest},
+		{"malformed", `{`, nil, http.StatusBadRequest},
+		{"not available", `{"code":"X"}`, fmt.Errorf("shipping method: %w", ErrShippingMethodNotAvailable), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/shipping-method"
+			r := httptest.NewRequest(http.MethodPut, uri, bytes.NewBufferString(tt.body))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/shipping-method", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			if tt.err != nil {
+				s = s.CartShippingMethodSetMock.Expect(r.Context(), 100, "X").Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartShippingMethodSet(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_Checkout(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		uri := "/v1/cart/10/checkout"
+		r := httptest.NewRequest(http.MethodPost, uri, nil)
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/checkout", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CheckoutMock.Expect(r.Context(), 10).Return(70, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).Checkout(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
+		}
+
+		var checkout apiv1Checkout
+		if err := json.NewDecoder(w.Body).Decode(&checkout); err != nil {
+			t.Fatal(err)
+		}
+
+		if exp := (apiv1Checkout{OrderID: 70}); checkout != exp {
+			t.Errorf("checkout exp: %+v, got: %+v", exp, checkout)
+		}
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", fmt.Errorf("cart: %w", ErrCartNotFound), http.StatusNotFound},
+		{"checked out", ErrCartCheckedOut, http.StatusConflict},
+		{"empty", ErrCartEmpty, http.StatusUnprocessableEntity},
+		{"price changed", ErrPriceChanged, http.StatusConflict},
+		{"out of stock", ErrOutOfStock, http.StatusConflict},
+		{"any error", errors.New("any"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/checkout"
+			r := httptest.NewRequest(http.MethodPost, uri, nil)
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/checkout", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.CheckoutMock.Expect(r.Context(), 100).Return(0, tt.err)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).Checkout(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_CartMerge(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		c := Cart{
+			ID:        10,
+			UserID:    15,
+			Version:   3,
+			LineItems: []*LineItem{{ID: 20, CartID: 10, ProductID: 30, Quantity: 4}},
+		}
+
+		uri := "/v1/cart/10/merge"
+		r := httptest.NewRequest(http.MethodPost, uri, bytes.NewBufferString(`{"guest_cart_id":11,"strategy":"max"}`))
+		r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/merge", uri))
+
+		mc := minimock.NewController(t)
+		defer mc.Finish()
+
+		s := NewServiceMock(mc)
+		s = s.CartMergeMock.Expect(r.Context(), 10, 11, MergeMax).Return(&c, nil)
+
+		w := httptest.NewRecorder()
+		(&APIv1{service: s}).CartMerge(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("code exp: %d, got: %d", http.StatusOK, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"3"` {
+			t.Errorf("etag exp: %s, got: %s", `"3"`, etag)
+		}
+
+		var cart apiv1Cart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatal(err)
+		}
+
+		exp := apiv1Cart{ID: 10, UserID: 15, LineItems: []apiv1LineItem{{ID: 20, CartID: 10, ProductID: 30, Quantity: 4}}}
+		if !reflect.DeepEqual(exp, cart) {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v\n", exp, cart)
+		}
+	})
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"no guest cart", `{}`, nil, http.StatusBadRequest},
+		{"malformed", `{`, nil, http.StatusBadRequest},
+		{"unknown strategy", `{"guest_cart_id":11}`, &InvalidParamError{Name: "strategy", Err: ErrInvalidArgument}, http.StatusBadRequest},
+		{"guest cart not found", `{"guest_cart_id":11}`, fmt.Errorf("cart: %w", ErrCartNotFound), http.StatusNotFound},
+		{"other guest", `{"guest_cart_id":11}`, ErrForbidden, http.StatusForbidden},
+		{"checked out", `{"guest_cart_id":11}`, ErrCartCheckedOut, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/merge"
+			r := httptest.NewRequest(http.MethodPost, uri, bytes.NewBufferString(tt.body))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/merge", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			if tt.err != nil {
+				s = s.CartMergeMock.Expect(r.Context(), 100, 11, "").Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartMerge(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_CartPay(t *testing.T) {
+	ok := []struct {
+		name    string
+		created bool
+		code    int
+	}{
+		{"created", true, http.StatusCreated},
+		{"existing", false, http.StatusOK},
+	}
+	for _, tt := range ok {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Payment{ID: 3, CartID: 10, CartVersion: 4, Amount: Money{Amount: 1999, Currency: "EUR"}, Status: PaymentAuthorized, AuthorizationID: "pi_1"}
+
+			uri := "/v1/cart/10/payment"
+			r := httptest.NewRequest(http.MethodPost, uri, bytes.NewBufferString(`{"source":"pm_card_visa"}`))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/payment", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			s = s.CartPayMock.Expect(r.Context(), 10, "pm_card_visa").Return(p, tt.created, nil)
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartPay(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+
+			var payment apiv1Payment
+			if err := json.NewDecoder(w.Body).Decode(&payment); err != nil {
+				t.Fatal(err)
+			}
+
+			if exp := (apiv1Payment{ID: 3, CartID: 10, Amount: 1999, Currency: "EUR", Status: "authorized"}); payment != exp {
+				t.Errorf("payment exp: %+v, got: %+v", exp, payment)
+			}
+		})
+	}
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"malformed", `{`, nil, http.StatusBadRequest},
+		{"no source", `{"source":"X"}`, &InvalidParamError{Name: "source", Err: ErrInvalidArgument}, http.StatusBadRequest},
+		{"declined", `{"source":"X"}`, fmt.Errorf("payments: %w", ErrPaymentDeclined), http.StatusPaymentRequired},
+		{"empty", `{"source":"X"}`, ErrCartEmpty, http.StatusUnprocessableEntity},
+		{"checked out", `{"source":"X"}`, ErrCartCheckedOut, http.StatusConflict},
+		{"not found", `{"source":"X"}`, ErrCartNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "/v1/cart/100/payment"
+			r := httptest.NewRequest(http.MethodPost, uri, bytes.NewBufferString(tt.body))
+			r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}/payment", uri))
+
+			mc := minimock.NewController(t)
+			defer mc.Finish()
+
+			s := NewServiceMock(mc)
+			if tt.err != nil {
+				s = s.CartPayMock.Expect(r.Context(), 100, "X").Return(nil, false, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			(&APIv1{service: s}).CartPay(w, r)
+
+			if w.Code != tt.code {
+				t.Errorf("code exp: %d, got: %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIv1_error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  problem
+	}{
+		{"cart", fmt.Errorf("cart 1: %w", ErrCartNotFound), problem{Type: "/problems/cart-not-found", Title: "Cart not found", Status: http.StatusNotFound, Detail: "cart 1: cart not found"}},
+		{"item", ErrLineItemNotFound, problem{Type: "/problems/line-item-not-found", Title: "Line item not found", Status: http.StatusNotFound, Detail: "line item not found"}},
+		{"product", &InvalidParamError{Name: "line_items[0].product_id", Err: ErrInvalidProduct}, problem{Type: "/problems/invalid-product", Title: "Invalid product", Status: http.StatusUnprocessableEntity, InvalidParams: []invalidParam{{Name: "line_items[0].product_id", Reason: "invalid product"}}}},
+		{"quantity", &InvalidParamError{Name: "line_items[1].quantity", Err: ErrInvalidQuantity}, problem{Type: "/problems/invalid-quantity", Title: "Invalid quantity", Status: http.StatusUnprocessableEntity, InvalidParams: []invalidParam{{Name: "line_items[1].quantity", Reason: "invalid quantity"}}}},
+		{"conflict", ErrConflict, problem{Type: "/problems/conflict", Title: "Conflicting update", Status: http.StatusConflict, Detail: "conflict"}},
+		{"unavailable", ErrUnavailable, problem{Type: "/problems/unavailable", Title: "Service temporarily unavailable", Status: http.StatusServiceUnavailable, Detail: "unavailable"}},
+		{"unknown", errors.New("secret"), problem{Type: "/problems/internal", Title: "Internal server error", Status: http.StatusInternalServerError}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/cart/1", nil)
+
+			w := httptest.NewRecorder()
+			(&APIv1{}).error(w, r, tt.err)
+
+			tt.exp.Instance = "/v1/cart/1"
+			assertProblem(t, w, tt.exp)
+		})
+	}
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/cart/1", nil).WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		(&APIv1{}).error(w, r, ctx.Err())
+
+		assertProblem(t, w, problem{Type: "/problems/timeout", Title: "Request timeout", Status: http.StatusRequestTimeout, Instance: "/v1/cart/1"})
+	})
+}
+
+func TestAPIv1_invalidParams(t *testing.T) {
+	uri := "/v1/cart/abc"
+	r := httptest.NewRequest(http.MethodGet, uri, nil)
+	r = r.WithContext(chiRouteContext(t, "/v1/cart/{cartID}", uri))
 
 	w := httptest.NewRecorder()
-	(&APIv1{service: s}).LineItemRemove(w, r)
+	(&APIv1{}).CartShow(w, r)
 
-	if w.Code != http.StatusNoContent {
-		t.Errorf("code exp: %d, got: %d", http.StatusNoContent, w.Code)
+	assertProblem(t, w, problem{
+		Type:     "/problems/invalid-params",
+		Title:    "Invalid request parameters",
+		Status:   http.StatusBadRequest,
+		Instance: uri,
+		InvalidParams: []invalidParam{
+			{Name: "cartID", Reason: `"abc": strconv.ParseInt: parsing "abc": invalid syntax`},
+		},
+	})
+}
+
+func TestAPIv1AuthMiddleware(t *testing.T) {
+	principal := &Principal{UserID: 15}
+
+	h := APIv1AuthMiddleware(NewAPIKeyAuthenticator(map[string]*Principal{"key": principal}), false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := PrincipalFromContext(r.Context()); !ok || p != principal {
+			t.Errorf("principal exp: %+v, got: %+v", principal, p)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	t.Run("ok", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/cart/1", nil)
+		r.Header.Set("Authorization", "bearer key")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("code exp: %d, got: %d", http.StatusNoContent, w.Code)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		exp           problem
+	}{
+		{"no credentials", "", problem{Type: "/problems/unauthorized", Title: "Authentication required", Status: http.StatusUnauthorized}},
+		{"basic", "Basic QWxhZGRpbjpPcGVuU2VzYW1l", problem{Type: "/problems/unauthorized", Title: "Authentication required", Status: http.StatusUnauthorized}},
+		{"empty token", "Bearer ", problem{Type: "/problems/unauthorized", Title: "Authentication required", Status: http.StatusUnauthorized}},
+		{"invalid token", "Bearer nokey", problem{Type: "/problems/invalid-token", Title: "Invalid token", Status: http.StatusUnauthorized, Detail: "the access token is malformed, expired or revoked"}},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/cart/1", nil)
+			r.Header.Set("Authorization", tt.authorization)
 
-	// TODO tests
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header exp")
+			}
+
+			tt.exp.Instance = "/v1/cart/1"
+			assertProblem(t, w, tt.exp)
+		})
+	}
+}
+
+func TestAPIv1AuthMiddleware_guests(t *testing.T) {
+	principal := &Principal{UserID: 15}
+	auth := NewAPIKeyAuthenticator(map[string]*Principal{"key": principal})
+
+	tests := []struct {
+		name          string
+		authorization string
+		header        string
+		cookie        string
+		exp           *Principal
+	}{
+		{"anonymous", "", "", "", &Principal{}},
+		{"header", "", "tok", "", &Principal{GuestToken: "tok"}},
+		{"cookie", "", "", "tok", &Principal{GuestToken: "tok"}},
+		{"header over cookie", "", "tok", "other", &Principal{GuestToken: "tok"}},
+		{"user", "Bearer key", "", "", principal},
+		{"user with guest token", "Bearer key", "", "tok", &Principal{UserID: 15, GuestToken: "tok"}},
+		{"basic", "Basic QWxhZGRpbjpPcGVuU2VzYW1l", "tok", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Principal
+			h := APIv1AuthMiddleware(auth, true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = PrincipalFromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/v1/cart/1", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.header != "" {
+				r.Header.Set(guestTokenHeader, tt.header)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: guestTokenCookie, Value: tt.cookie})
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if !reflect.DeepEqual(tt.exp, got) {
+				t.Errorf("principal exp: %+v, got: %+v", tt.exp, got)
+			}
+			if tt.exp == nil && w.Code != http.StatusUnauthorized {
+				t.Errorf("code exp: %d, got: %d", http.StatusUnauthorized, w.Code)
+			}
+		})
+	}
+
+	if principal.GuestToken != "" {
+		t.Errorf("shared principal modified: %+v", principal)
+	}
+}
+
+func TestAPIv1IfMatchMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		versions []int64
+		ok       bool
+	}{
+		{"none", "", nil, false},
+		{"any", "*", nil, false},
+		{"one", `"3"`, []int64{3}, true},
+		{"list", `"3", W/"4", "x", "5"`, []int64{3, 5}, true},
+		{"malformed", `3`, []int64{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := APIv1IfMatchMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				versions, ok := cartVersionsFromContext(r.Context())
+				if ok != tt.ok || !reflect.DeepEqual(tt.versions, versions) {
+					t.Errorf("versions exp: %v (%t), got: %v (%t)", tt.versions, tt.ok, versions, ok)
+				}
+			}))
+
+			r := httptest.NewRequest(http.MethodDelete, "/v1/cart/1", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), r)
+		})
+	}
+}
+
+func TestNewAPIv1(t *testing.T) {
+	auth := NewAPIKeyAuthenticator(map[string]*Principal{"key": {UserID: 15}, "other": {UserID: 16}})
+
+	srv := httptest.NewServer(NewAPIv1(&ShoppingCart{storage: NewMemory()}, auth, false))
+	defer srv.Close()
+
+	do := func(method, uri, body string, headers ...string) *http.Response {
+		t.Helper()
+
+		r, err := http.NewRequest(method, srv.URL+uri, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Authorization", "Bearer key")
+		for j := 0; j+1 < len(headers); j += 2 {
+			r.Header.Set(headers[j], headers[j+1])
+		}
+
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/v1/cart", `{"user_id":15,"line_items":[{"product_id":30,"quantity":2}]}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create code exp: %d, got: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	var cart apiv1Cart
+	if err := json.NewDecoder(resp.Body).Decode(&cart); err != nil {
+		t.Fatal(err)
+	}
+
+	uri := fmt.Sprintf("/v1/cart/%d", cart.ID)
+
+	resp = do(http.MethodPut, uri+"/item", `[{"product_id":30,"quantity":3},{"product_id":31,"quantity":1}]`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add code exp: %d, got: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	resp = do(http.MethodGet, uri, "")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("show code exp: %d, got: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&cart); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []apiv1LineItem{
+		{ID: 1, CartID: cart.ID, ProductID: 30, Quantity: 5},
+		{ID: 2, CartID: cart.ID, ProductID: 31, Quantity: 1},
+	}
+
+	if !reflect.DeepEqual(exp, cart.LineItems) {
+		t.Errorf("items do not match\nexp: %+v\ngot: %+v\n", exp, cart.LineItems)
+	}
+
+	etag := resp.Header.Get("ETag")
+
+	resp = do(http.MethodPut, uri+"/item", `[{"product_id":30,"quantity":1}]`, "If-Match", etag)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("matching add code exp: %d, got: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	resp = do(http.MethodPut, uri+"/item", `[{"product_id":30,"quantity":1}]`, "If-Match", etag)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("stale add code exp: %d, got: %d", http.StatusPreconditionFailed, resp.StatusCode)
+	}
+
+	resp = do(http.MethodGet, "/v1/users/15/cart", "")
+	defer resp.Body.Close()
+
+	var active apiv1Cart
+	if err := json.NewDecoder(resp.Body).Decode(&active); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || active.ID != cart.ID || active.Status != CartActive {
+		t.Errorf("active cart exp: %d, got: %d %+v", cart.ID, resp.StatusCode, active)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, srv.URL+uri, nil)
+	r.Header.Set("Authorization", "Bearer other")
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("other user code exp: %d, got: %d", http.StatusForbidden, resp.StatusCode)
+	}
+
+	resp = do(http.MethodGet, "/v1/unknown", "")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown code exp: %d, got: %d", http.StatusNotFound, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("unknown content type exp: %s, got: %s", "application/problem+json", ct)
+	}
+}
+
+func assertProblem(t *testing.T, w *httptest.ResponseRecorder, exp problem) {
+	t.Helper()
+
+	if w.Code != exp.Status {
+		t.Errorf("code exp: %d, got: %d", exp.Status, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("content type exp: %s, got: %s", "application/problem+json", ct)
+	}
+
+	var p problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(exp, p) {
+		t.Errorf("problems do not match\nexp: %+v\ngot: %+v", exp, p)
+	}
 }
 
 func chiRouteContext(t *testing.T, pattern string, uri string) context.Context {
@@ -259,3 +1594,7 @@ func chiRouteContext(t *testing.T, pattern string, uri string) context.Context {
 
 	return context.WithValue(context.Background(), chi.RouteCtxKey, rctx)
 }
+
+func int64p(v int64) *int64 {
+	return &v
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKey is a client supplied key of a mutating request, the response to the request
+// is stored under the key to be replayed on retries.
+type IdempotencyKey struct {
+	UserID      int64  // zero for keys of guests
+	Key         string // keys of guests are hashed along with the guest token or the remote address
+	Fingerprint string // of the request the key has been used with
+	Response    []byte // nil while the request is being processed
+	CreatedAt   time.Time
+	ExpiresAt   time.Time // the key is held for idempotencyLockTTL until the response is stored
+}
+
+const (
+	// idempotencyKeyMaxLen is the maximum length of an Idempotency-Key header.
+	idempotencyKeyMaxLen = 255
+
+	// idempotencyLockTTL is how long a key is held by the request being processed, keys of
+	// requests which have crashed are freed after it.
+	idempotencyLockTTL = time.Minute
+)
+
+// idempotentResponse is a response stored under an idempotency key.
+type idempotentResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// idempotentHeaders are the response headers replayed along with the stored response. The guest
+// token issued with the first cart of a guest is replayed too, so retries get the same cart.
+var idempotentHeaders = []string{"Content-Type", "ETag", "Location", guestTokenHeader, "Set-Cookie"}
+
+// APIv1IdempotencyMiddleware returns a middleware which makes mutating requests with an
+// Idempotency-Key header safe to retry. The response to the first request is stored in st and
+// replayed to retries for ttl; the key can not be reused with a different request.
+// Keys are scoped to the principal, so the middleware must follow APIv1AuthMiddleware. Keys of
+// guests are scoped to the guest token, or to the remote address until they are issued one.
+func APIv1IdempotencyMiddleware(st storer, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > idempotencyKeyMaxLen {
+				p := newProblem(r, http.StatusBadRequest, "invalid-params", "Invalid request parameters")
+				p.InvalidParams = []invalidParam{{Name: "Idempotency-Key", Reason: "too long"}}
+				p.write(w)
+				return
+			}
+
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				newProblem(r, http.StatusUnauthorized, "unauthorized", "Authentication required").write(w)
+				return
+			}
+
+			// Guests are issued their token with their first cart, so retries of it are told apart
+			// by the remote address.
+			switch {
+			case principal.UserID == 0 && principal.GuestToken == "":
+				key = hashGuestToken("addr " + remoteHost(r) + "\n" + key)
+			case principal.UserID == 0:
+				key = hashGuestToken(principal.GuestToken + "\n" + key)
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				p := newProblem(r, http.StatusBadRequest, "malformed-body", "Malformed request body")
+				p.Detail = err.Error()
+				p.write(w)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			k := &IdempotencyKey{
+				UserID:      principal.UserID,
+				Key:         key,
+				Fingerprint: idempotencyFingerprint(r, body),
+				ExpiresAt:   time.Now().Add(idempotencyLockTTL),
+			}
+
+			err = st.IdempotencyKeyCreate(r.Context(), k)
+			if errors.Is(err, ErrConflict) {
+				replayIdempotentResponse(w, r, st, k)
+				return
+			} else if errors.Is(err, ErrUnavailable) {
+				newProblem(r, http.StatusServiceUnavailable, "unavailable", "Service temporarily unavailable").write(w)
+				return
+			} else if err != nil {
+				log.Printf("%s %s: idempotency key: %s", r.Method, r.URL.Path, err)
+				newProblem(r, http.StatusInternalServerError, "internal", "Internal server error").write(w)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// Responses to failed requests are not stored, so the request can be retried. Conflicts
+			// and failed preconditions may be transient, e.g. of a busy DB or a concurrent change.
+			// A new context is used as the request's one may be done already.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if rec.status >= http.StatusInternalServerError || rec.status == http.StatusConflict ||
+				rec.status == http.StatusPreconditionFailed || r.Context().Err() != nil {
+				if err := st.IdempotencyKeyDelete(ctx, k.UserID, k.Key); err != nil {
+					log.Printf("%s %s: idempotency key delete: %s", r.Method, r.URL.Path, err)
+				}
+				return
+			}
+
+			resp := idempotentResponse{Status: rec.status, Header: http.Header{}, Body: rec.body.Bytes()}
+			for _, h := range idempotentHeaders {
+				if v := rec.Header().Get(h); v != "" {
+					resp.Header.Set(h, v)
+				}
+			}
+
+			k.ExpiresAt = time.Now().Add(ttl)
+			if k.Response, err = json.Marshal(resp); err != nil {
+				log.Printf("%s %s: idempotent response: %s", r.Method, r.URL.Path, err)
+				return
+			}
+
+			if err := st.IdempotencyKeyComplete(ctx, k); err != nil {
+				log.Printf("%s %s: idempotency key complete: %s", r.Method, r.URL.Path, err)
+			}
+		})
+	}
+}
+
+// replayIdempotentResponse writes the response stored under the key k has collided with.
+func replayIdempotentResponse(w http.ResponseWriter, r *http.Request, st storer, k *IdempotencyKey) {
+	stored, err := st.IdempotencyKeyByKey(r.Context(), k.UserID, k.Key)
+	switch {
+	case errors.Is(err, ErrIdempotencyKeyNotFound):
+		// The first request has failed or the key has just expired.
+		p := newProblem(r, http.StatusConflict, "idempotency-key-in-use", "Idempotency key is in use")
+		p.Detail = "the request may be retried"
+		p.write(w)
+		return
+	case err != nil:
+		log.Printf("%s %s: idempotency key: %s", r.Method, r.URL.Path, err)
+		newProblem(r, http.StatusInternalServerError, "internal", "Internal server error").write(w)
+		return
+	}
+
+	if stored.Fingerprint != k.Fingerprint {
+		p := newProblem(r, http.StatusUnprocessableEntity, "idempotency-key-reused", "Idempotency key reused")
+		p.Detail = "the key has been used with a different request"
+		p.write(w)
+		return
+	}
+
+	if stored.Response == nil {
+		p := newProblem(r, http.StatusConflict, "idempotency-key-in-use", "Idempotency key is in use")
+		p.Detail = "the request with the key is being processed"
+		p.write(w)
+		return
+	}
+
+	var resp idempotentResponse
+	if err := json.Unmarshal(stored.Response, &resp); err != nil {
+		log.Printf("%s %s: idempotent response: %s", r.Method, r.URL.Path, err)
+		newProblem(r, http.StatusInternalServerError, "internal", "Internal server error").write(w)
+		return
+	}
+
+	for h := range resp.Header {
+		w.Header().Set(h, resp.Header.Get(h))
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.Status)
+	if _, err := w.Write(resp.Body); err != nil {
+		log.Printf("%s %s: replay: %s", r.Method, r.URL.Path, err)
+	}
+}
+
+// remoteHost returns the host of the remote address of the request.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// idempotencyFingerprint identifies a request by its method, URI and body.
+func idempotencyFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// PurgeIdempotencyKeys deletes expired idempotency keys from st every interval until ctx is done.
+func PurgeIdempotencyKeys(ctx context.Context, st storer, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tm := <-t.C:
+			if err := st.IdempotencyKeysPurge(ctx, tm); err != nil && ctx.Err() == nil {
+				log.Println("idempotency keys purge:", err)
+			}
+		}
+	}
+}
+
+// responseRecorder passes a response through and records its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status, rec.wroteHeader = status, true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
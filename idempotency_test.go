@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIv1IdempotencyMiddleware(t *testing.T) {
+	st := NewMemory()
+
+	var (
+		calls int
+		held  time.Time // expiry of the key while the request is processed
+	)
+	h := APIv1IdempotencyMiddleware(st, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "/conflict":
+			w.WriteHeader(http.StatusConflict)
+			return
+		case "/held":
+			k, err := st.IdempotencyKeyByKey(r.Context(), 15, "k5")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			held = k.ExpiresAt
+		case "/guest":
+			w.Header().Set(guestTokenHeader, fmt.Sprintf("issued-%d", calls))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"1"`)
+		w.Header().Set("X-Other", "other")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+
+	as := func(p *Principal, method, uri, key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		r := httptest.NewRequest(method, uri, bytes.NewBufferString(body))
+		r = r.WithContext(withPrincipal(r.Context(), p))
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	do := func(method, uri, key, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		return as(&Principal{UserID: 15}, method, uri, key, body)
+	}
+
+	w := do(http.MethodPost, "/v1/cart", "k1", `{}`)
+	if w.Code != http.StatusCreated || calls != 1 {
+		t.Fatalf("first code exp: %d, got: %d, calls: %d", http.StatusCreated, w.Code, calls)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		w := do(http.MethodPost, "/v1/cart", "k1", `{}`)
+
+		if calls != 1 {
+			t.Errorf("calls exp: %d, got: %d", 1, calls)
+		}
+		if w.Code != http.StatusCreated {
+			t.Errorf("code exp: %d, got: %d", http.StatusCreated, w.Code)
+		}
+		if b := w.Body.String(); b != `{"id":1}` {
+			t.Errorf("body exp: %s, got: %s", `{"id":1}`, b)
+		}
+
+		for h, exp := range map[string]string{"Content-Type": "application/json", "ETag": `"1"`, "X-Other": "", "Idempotent-Replayed": "true"} {
+			if v := w.Header().Get(h); v != exp {
+				t.Errorf("header %s exp: %q, got: %q", h, exp, v)
+			}
+		}
+	})
+
+	t.Run("reused", func(t *testing.T) {
+		w := do(http.MethodPost, "/v1/cart", "k1", `{"user_id":16}`)
+
+		if calls != 1 {
+			t.Errorf("calls exp: %d, got: %d", 1, calls)
+		}
+
+		assertProblem(t, w, problem{
+			Type:     "/problems/idempotency-key-reused",
+			Title:    "Idempotency key reused",
+			Status:   http.StatusUnprocessableEntity,
+			Detail:   "the key has been used with a different request",
+			Instance: "/v1/cart",
+		})
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		k := &IdempotencyKey{UserID: 15, Key: "k2", Fingerprint: idempotencyFingerprint(httptest.NewRequest(http.MethodDelete, "/v1/cart/1", nil), nil), ExpiresAt: time.Now().Add(time.Hour)}
+		if err := st.IdempotencyKeyCreate(context.Background(), k); err != nil {
+			t.Fatal(err)
+		}
+
+		w := do(http.MethodDelete, "/v1/cart/1", "k2", "")
+		if w.Code != http.StatusConflict {
+			t.Errorf("code exp: %d, got: %d", http.StatusConflict, w.Code)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		calls = 0
+
+		do(http.MethodPost, "/fail", "k3", "")
+		do(http.MethodPost, "/fail", "k3", "")
+
+		if calls != 2 {
+			t.Errorf("failed request calls exp: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		calls = 0
+
+		do(http.MethodPost, "/conflict", "k6", "")
+		do(http.MethodPost, "/conflict", "k6", "")
+
+		if calls != 2 {
+			t.Errorf("conflicting request calls exp: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("held", func(t *testing.T) {
+		do(http.MethodPost, "/held", "k5", "")
+
+		if held.IsZero() || held.After(time.Now().Add(idempotencyLockTTL)) {
+			t.Errorf("key held until %s exp, got: %s", time.Now().Add(idempotencyLockTTL), held)
+		}
+
+		k, err := st.IdempotencyKeyByKey(context.Background(), 15, "k5")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.ExpiresAt.Before(time.Now().Add(59 * time.Minute)) {
+			t.Errorf("stored response expiry exp: %s, got: %s", time.Now().Add(time.Hour), k.ExpiresAt)
+		}
+	})
+
+	t.Run("crashed", func(t *testing.T) {
+		calls = 0
+
+		// The request holding the key has not completed within the lock.
+		k := &IdempotencyKey{UserID: 15, Key: "k7", Fingerprint: idempotencyFingerprint(httptest.NewRequest(http.MethodPost, "/v1/cart", nil), nil), ExpiresAt: time.Now().Add(-time.Second)}
+		if err := st.IdempotencyKeyCreate(context.Background(), k); err != nil {
+			t.Fatal(err)
+		}
+
+		if w := do(http.MethodPost, "/v1/cart", "k7", ""); w.Code != http.StatusCreated || calls != 1 {
+			t.Errorf("code exp: %d, got: %d, calls: %d", http.StatusCreated, w.Code, calls)
+		}
+	})
+
+	t.Run("guests", func(t *testing.T) {
+		calls = 0
+
+		guest := &Principal{GuestToken: "tok"}
+		as(guest, http.MethodPost, "/v1/cart", "k1", `{}`)
+		if w := as(guest, http.MethodPost, "/v1/cart", "k1", `{}`); w.Header().Get("Idempotent-Replayed") != "true" {
+			t.Error("replayed response exp")
+		}
+
+		// Keys are scoped to the guest token.
+		as(&Principal{GuestToken: "other"}, http.MethodPost, "/v1/cart", "k1", `{}`)
+
+		if calls != 2 {
+			t.Errorf("calls exp: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("guests without a token", func(t *testing.T) {
+		calls = 0
+
+		first := as(&Principal{}, http.MethodPost, "/guest", "k1", `{}`)
+		retry := as(&Principal{}, http.MethodPost, "/guest", "k1", `{}`)
+		if calls != 1 || retry.Header().Get("Idempotent-Replayed") != "true" {
+			t.Errorf("replayed response exp, calls: %d", calls)
+		}
+		if exp, got := first.Header().Get(guestTokenHeader), retry.Header().Get(guestTokenHeader); exp == "" || got != exp {
+			t.Errorf("guest token exp: %q, got: %q", exp, got)
+		}
+
+		// Keys are scoped to the remote address until the token is issued.
+		r := httptest.NewRequest(http.MethodPost, "/guest", bytes.NewBufferString(`{}`))
+		r = r.WithContext(withPrincipal(r.Context(), &Principal{}))
+		r.Header.Set("Idempotency-Key", "k1")
+		r.RemoteAddr = "198.51.100.7:4321"
+		h.ServeHTTP(httptest.NewRecorder(), r)
+
+		if calls != 2 {
+			t.Errorf("calls exp: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		calls = 0
+
+		do(http.MethodPost, "/v1/cart", "", `{}`)
+		do(http.MethodPost, "/v1/cart", "", `{}`)
+
+		if calls != 2 {
+			t.Errorf("calls exp: %d, got: %d", 2, calls)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		calls = 0
+
+		h := APIv1IdempotencyMiddleware(st, -time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		for j := 0; j < 2; j++ {
+			r := httptest.NewRequest(http.MethodDelete, "/v1/cart/2", nil)
+			r = r.WithContext(withPrincipal(r.Context(), &Principal{UserID: 15}))
+			r.Header.Set("Idempotency-Key", "k4")
+
+			h.ServeHTTP(httptest.NewRecorder(), r)
+		}
+
+		if calls != 2 {
+			t.Errorf("calls exp: %d, got: %d", 2, calls)
+		}
+	})
+}
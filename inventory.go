@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// Inventory keeps stock levels of products and soft reservations of stock by carts. Products
+// the inventory does not track are always available.
+type Inventory interface {
+	// Reserve checks that the quantities of the products are available to the cart, in stock
+	// and not reserved by other carts, and reserves them for the cart until the given time
+	// replacing previous reservations of the products by the cart. Nothing is reserved if until
+	// is zero. Fails with ErrOutOfStock.
+	Reserve(ctx context.Context, cartID int64, quantities map[int64]int64, until time.Time) error
+	// Withdraw takes the quantities of the products of a checked out cart out of stock and
+	// releases the reservations of the cart. Fails with ErrOutOfStock like Reserve.
+	Withdraw(ctx context.Context, cartID int64, quantities map[int64]int64) error
+	// Restock puts withdrawn quantities of the products back in stock, e.g. of an order which
+	// has failed to be stored.
+	Restock(ctx context.Context, quantities map[int64]int64) error
+	// Release releases reservations of the cart, of the given products only if any.
+	Release(ctx context.Context, cartID int64, productIDs ...int64) error
+	// ReleaseExpired releases reservations expired by the given time.
+	ReleaseExpired(ctx context.Context, tm time.Time) error
+}
+
+// ReleaseExpiredReservations releases expired reservations of the inventory every interval
+// until the context is done.
+func ReleaseExpiredReservations(ctx context.Context, inv Inventory, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tm := <-t.C:
+			if err := inv.ReleaseExpired(ctx, tm); err != nil && ctx.Err() == nil {
+				log.Println("reservations release:", err)
+			}
+		}
+	}
+}
+
+// SQLite3Inventory is an inventory kept in the stock and stock_reservations tables of a SQLite
+// DB, see ./migrations/catalog. Products without a stock row are not tracked. Open the DB with
+// _txlock=immediate so concurrent reservations are serialized.
+type SQLite3Inventory struct {
+	db *sql.DB
+}
+
+// NewSQLite3Inventory instantiates SQLite3Inventory.
+func NewSQLite3Inventory(db *sql.DB) *SQLite3Inventory {
+	return &SQLite3Inventory{db: db}
+}
+
+func (inv *SQLite3Inventory) Reserve(ctx context.Context, cartID int64, quantities map[int64]int64, until time.Time) (err error) {
+	tx, err := inv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("tx: %w", sqlite3Error(err))
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	tracked, err := inv.check(ctx, tx, cartID, quantities)
+	if err != nil {
+		return err
+	}
+
+	// Only checked.
+	if until.IsZero() {
+		return tx.Rollback()
+	}
+
+	for _, id := range tracked {
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO stock_reservations(cart_id, product_id, quantity, expires_at) VALUES(?, ?, ?, ?)
+			ON CONFLICT(cart_id, product_id) DO UPDATE SET quantity = excluded.quantity, expires_at = excluded.expires_at`,
+			cartID, id, quantities[id], until.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("reservation: %w", sqlite3Error(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", sqlite3Error(err))
+	}
+	return nil
+}
+
+func (inv *SQLite3Inventory) Withdraw(ctx context.Context, cartID int64, quantities map[int64]int64) (err error) {
+	tx, err := inv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("tx: %w", sqlite3Error(err))
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	tracked, err := inv.check(ctx, tx, cartID, quantities)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range tracked {
+		_, err := tx.ExecContext(
+			ctx,
+			`UPDATE stock SET quantity = quantity - ? WHERE product_id = ?`,
+			quantities[id], id,
+		)
+		if err != nil {
+			return fmt.Errorf("stock: %w", sqlite3Error(err))
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM stock_reservations WHERE cart_id = ?`, cartID); err != nil {
+		return fmt.Errorf("reservations: %w", sqlite3Error(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", sqlite3Error(err))
+	}
+	return nil
+}
+
+func (inv *SQLite3Inventory) Restock(ctx context.Context, quantities map[int64]int64) (err error) {
+	tx, err := inv.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("tx: %w", sqlite3Error(err))
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	for id, q := range quantities {
+		_, err := tx.ExecContext(
+			ctx,
+			`UPDATE stock SET quantity = quantity + ? WHERE product_id = ?`,
+			q, id,
+		)
+		if err != nil {
+			return fmt.Errorf("stock: %w", sqlite3Error(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", sqlite3Error(err))
+	}
+	return nil
+}
+
+func (inv *SQLite3Inventory) Release(ctx context.Context, cartID int64, productIDs ...int64) error {
+	if len(productIDs) == 0 {
+		_, err := inv.db.ExecContext(ctx, `DELETE FROM stock_reservations WHERE cart_id = ?`, cartID)
+		return sqlite3Error(err)
+	}
+
+	for _, id := range productIDs {
+		_, err := inv.db.ExecContext(
+			ctx,
+			`DELETE FROM stock_reservations WHERE cart_id = ? AND product_id = ?`,
+			cartID, id,
+		)
+		if err != nil {
+			return fmt.Errorf("product %d: %w", id, sqlite3Error(err))
+		}
+	}
+	return nil
+}
+
+func (inv *SQLite3Inventory) ReleaseExpired(ctx context.Context, tm time.Time) error {
+	_, err := inv.db.ExecContext(
+		ctx,
+		`DELETE FROM stock_reservations WHERE expires_at <= ?`,
+		tm.UTC(),
+	)
+	return sqlite3Error(err)
+}
+
+// check checks that the quantities of the products are available to the cart, returns IDs of
+// the products tracked by the inventory.
+func (inv *SQLite3Inventory) check(ctx context.Context, tx *sql.Tx, cartID int64, quantities map[int64]int64) ([]int64, error) {
+	ids := make([]int64, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var (
+		tracked []int64
+		now     = time.Now().UTC()
+	)
+	for _, id := range ids {
+		var available int64
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT s.quantity - COALESCE((
+				SELECT SUM(r.quantity)
+				FROM stock_reservations r
+				WHERE r.product_id = s.product_id AND r.cart_id <> ? AND r.expires_at > ?
+			), 0)
+			FROM stock s
+			WHERE s.product_id = ?`,
+			cartID, now, id,
+		).Scan(&available)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("stock query: %w", sqlite3Error(err))
+		case available < quantities[id]:
+			if available < 0 {
+				available = 0
+			}
+			return nil, fmt.Errorf("product %d: %d available: %w", id, available, ErrOutOfStock)
+		}
+
+		tracked = append(tracked, id)
+	}
+	return tracked, nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubInventory is an in-memory Inventory of tests, products without stock are not tracked.
+type stubInventory struct {
+	mu           sync.Mutex
+	stock        map[int64]int64
+	reservations map[[2]int64]stubReservation // by cart and product ID
+}
+
+type stubReservation struct {
+	quantity  int64
+	expiresAt time.Time
+}
+
+func newStubInventory(stock map[int64]int64) *stubInventory {
+	return &stubInventory{stock: stock, reservations: map[[2]int64]stubReservation{}}
+}
+
+func (inv *stubInventory) Reserve(ctx context.Context, cartID int64, quantities map[int64]int64, until time.Time) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if err := inv.check(cartID, quantities); err != nil {
+		return err
+	}
+
+	if until.IsZero() {
+		return nil
+	}
+	for id, q := range quantities {
+		if _, ok := inv.stock[id]; ok {
+			inv.reservations[[2]int64{cartID, id}] = stubReservation{quantity: q, expiresAt: until}
+		}
+	}
+	return nil
+}
+
+func (inv *stubInventory) Withdraw(ctx context.Context, cartID int64, quantities map[int64]int64) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if err := inv.check(cartID, quantities); err != nil {
+		return err
+	}
+
+	for id, q := range quantities {
+		if _, ok := inv.stock[id]; ok {
+			inv.stock[id] -= q
+		}
+	}
+	for k := range inv.reservations {
+		if k[0] == cartID {
+			delete(inv.reservations, k)
+		}
+	}
+	return nil
+}
+
+func (inv *stubInventory) Restock(ctx context.Context, quantities map[int64]int64) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	for id, q := range quantities {
+		if _, ok := inv.stock[id]; ok {
+			inv.stock[id] += q
+		}
+	}
+	return nil
+}
+
+func (inv *stubInventory) Release(ctx context.Context, cartID int64, productIDs ...int64) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	for k := range inv.reservations {
+		if k[0] != cartID {
+			continue
+		}
+
+		release := len(productIDs) == 0
+		for _, id := range productIDs {
+			release = release || k[1] == id
+		}
+		if release {
+			delete(inv.reservations, k)
+		}
+	}
+	return nil
+}
+
+func (inv *stubInventory) ReleaseExpired(ctx context.Context, tm time.Time) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	for k, r := range inv.reservations {
+		if !r.expiresAt.After(tm) {
+			delete(inv.reservations, k)
+		}
+	}
+	return nil
+}
+
+func (inv *stubInventory) check(cartID int64, quantities map[int64]int64) error {
+	now := time.Now()
+	for id, q := range quantities {
+		stock, ok := inv.stock[id]
+		if !ok {
+			continue
+		}
+
+		for k, r := range inv.reservations {
+			if k[0] != cartID && k[1] == id && r.expiresAt.After(now) {
+				stock -= r.quantity
+			}
+		}
+		if stock < q {
+			return fmt.Errorf("product %d: %d available: %w", id, stock, ErrOutOfStock)
+		}
+	}
+	return nil
+}
+
+// reserved returns the quantity of the product reserved for the cart.
+func (inv *stubInventory) reserved(cartID, productID int64) int64 {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	return inv.reservations[[2]int64{cartID, productID}].quantity
+}
+
+func TestSQLite3Inventory(t *testing.T) {
+	db := migrateDB(t, "file:inventory?mode=memory&cache=shared", "./migrations/catalog")
+	defer db.Close()
+
+	if _, err := db.Exec(`INSERT INTO stock(product_id, quantity) VALUES(?, ?), (?, ?)`, 1, 3, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	inv := NewSQLite3Inventory(db)
+	ctx := context.Background()
+	until := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		cartID     int64
+		quantities map[int64]int64
+		until      time.Time
+		err        error
+	}{
+		{"reserve", 10, map[int64]int64{1: 2, 3: 100}, until, nil},
+		{"reserved by another cart", 20, map[int64]int64{1: 2}, until, ErrOutOfStock},
+		{"rest", 20, map[int64]int64{1: 1}, until, nil},
+		{"own reservation replaced", 10, map[int64]int64{1: 2}, until, nil},
+		{"check only", 30, map[int64]int64{1: 1}, time.Time{}, ErrOutOfStock},
+		{"out of stock", 30, map[int64]int64{2: 1}, until, ErrOutOfStock},
+		{"untracked", 30, map[int64]int64{3: 1000}, until, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := inv.Reserve(ctx, tt.cartID, tt.quantities, tt.until); !errors.Is(err, tt.err) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+
+	// Stock of an emptied cart is available again.
+	if err := inv.Release(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Reserve(ctx, 30, map[int64]int64{1: 2}, time.Time{}); err != nil {
+		t.Errorf("release: %v", err)
+	}
+
+	if err := inv.Release(ctx, 20, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Reserve(ctx, 30, map[int64]int64{1: 3}, time.Now().Add(-time.Second)); err != nil {
+		t.Errorf("release product: %v", err)
+	}
+
+	// Withdrawn stock is gone for good.
+	if err := inv.Reserve(ctx, 50, map[int64]int64{1: 1}, until); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Withdraw(ctx, 50, map[int64]int64{1: 1, 2: 1}); !errors.Is(err, ErrOutOfStock) {
+		t.Errorf("err exp: %v, got: %v", ErrOutOfStock, err)
+	}
+	if err := inv.Withdraw(ctx, 50, map[int64]int64{1: 1, 3: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stock int64
+	if err := db.QueryRow(`SELECT quantity FROM stock WHERE product_id = ?`, 1).Scan(&stock); err != nil {
+		t.Fatal(err)
+	}
+	if stock != 2 {
+		t.Errorf("stock exp: %d, got: %d", 2, stock)
+	}
+
+	// Stock of a failed order is given back.
+	if err := inv.Restock(ctx, map[int64]int64{1: 1, 3: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT quantity FROM stock WHERE product_id = ?`, 1).Scan(&stock); err != nil {
+		t.Fatal(err)
+	}
+	if stock != 3 {
+		t.Errorf("restocked exp: %d, got: %d", 3, stock)
+	}
+	if err := inv.Withdraw(ctx, 50, map[int64]int64{1: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired reservations do not hold stock and are purged.
+	if err := inv.Reserve(ctx, 40, map[int64]int64{1: 2}, time.Time{}); err != nil {
+		t.Errorf("expired: %v", err)
+	}
+	if err := inv.ReleaseExpired(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM stock_reservations`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("reservations exp: %d, got: %d", 0, n)
+	}
+}
@@ -2,34 +2,121 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
 	"database/sql"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/pressly/goose"
 )
 
 func main() {
 	var (
-		dsn  = flag.String("dsn", "file:./testdata/db.sqlite3?cache=shared&_loc=UTC&mode=rw", "DSN")
-		addr = flag.String("addr", ":5000", "Address to bind HTTP server")
+		driver = flag.String("driver", "sqlite3", "Storage backend: sqlite3 or postgres")
+		dsn    = flag.String("dsn", "file:./testdata/db.sqlite3?cache=shared&_loc=UTC&_foreign_keys=1&_txlock=immediate&mode=rwc", "DSN, memory:// to keep everything in memory")
+		migs   = flag.String("migrations", "./migrations", "Directory of migrations applied on start, PostgreSQL ones are in its postgres subdirectory, none if empty")
+		addr   = flag.String("addr", ":5000", "Address to bind HTTP server")
+
+		catalog = flag.String("catalog", "./testdata/products.json", "Product catalog: a JSON file (*.json) or a SQLite DSN migrated with the catalog subdirectory of migrations")
+		prices  = flag.String("prices", "", "Path to a JSON price table, unit prices of the catalog are used by default")
+
+		promotions = flag.String("promotions", "./testdata/promotions.json", "Path to a JSON file of promotions redeemed by coupon codes, none if empty")
+		rules      = flag.String("rules", "./testdata/rules.json", "Promotions applied without coupon codes: a JSON file (*.json) or a SQLite DSN migrated as the catalog's, none if empty")
+		taxes      = flag.String("taxes", "./testdata/taxes.json", "Taxes: a JSON tax table (*.json) or the URL of a tax service, none if empty")
+		shipping   = flag.String("shipping", "./testdata/shipping.json", "Path to a JSON table of shipping methods, none if empty")
+
+		inventory      = flag.String("inventory", "", "SQLite DSN of the stock of products migrated as the catalog's, stock is not checked if empty")
+		reservationTTL = flag.Duration("reservation-ttl", 15*time.Minute, "How long stock of cart items is reserved, stock is only checked if 0")
+
+		payments    = flag.String("payments", "", "Base URL of a Stripe-like payments API, carts can not be paid if empty")
+		paymentsKey = flag.String("payments-key", os.Getenv("SHOPPINGCART_PAYMENTS_KEY"), "Secret key of the payments API")
+
+		jwtSecret = flag.String("jwt-secret", os.Getenv("SHOPPINGCART_JWT_SECRET"), "HS256 JWT secret, enables HS256 tokens")
+		jwks      = flag.String("jwks", "", "Path to a JWKS file, enables RS256 tokens")
+		apiKeys   = flag.String("api-keys", "", "Path to a JSON file of principals by API key")
+		guests    = flag.Bool("guests", false, "Serve anonymous requests as guests, who may shop with guest carts")
+
+		idempotencyTTL = flag.Duration("idempotency-ttl", 24*time.Hour, "How long responses are replayed to requests with the same Idempotency-Key")
 	)
 	flag.Parse()
 
-	db, err := sql.Open("sqlite3", *dsn)
+	auth, err := newAuthenticator(*jwtSecret, *jwks, *apiKeys)
 	if err != nil {
-		log.Fatal("db:", db)
+		log.Fatal("auth:", err)
+	}
+
+	st, err := openStorer(*driver, *dsn, *migs)
+	if err != nil {
+		log.Fatal("storage:", err)
+	}
+
+	cat, err := openCatalog(*catalog, *migs)
+	if err != nil {
+		log.Fatal("catalog:", err)
+	}
+
+	var ps PriceSource = CatalogPrices{Catalog: cat}
+	if *prices != "" {
+		if ps, err = LoadPriceTable(*prices); err != nil {
+			log.Fatal("prices:", err)
+		}
+	}
+
+	sc := &ShoppingCart{storage: st, catalog: cat, prices: ps}
+	if *promotions != "" {
+		if sc.promotions, err = LoadJSONPromotions(*promotions); err != nil {
+			log.Fatal("promotions:", err)
+		}
+	}
+	if *rules != "" {
+		if sc.rules, err = openRules(*rules, *migs); err != nil {
+			log.Fatal("rules:", err)
+		}
+	}
+	if *taxes != "" {
+		if sc.taxes, err = openTaxes(*taxes); err != nil {
+			log.Fatal("taxes:", err)
+		}
+	}
+	if *shipping != "" {
+		if sc.shipping, err = LoadShippingTable(*shipping); err != nil {
+			log.Fatal("shipping:", err)
+		}
 	}
 
-	st := &SQLite3{db: db}
-	sc := &ShoppingCart{storage: st}
+	if *inventory != "" {
+		db, err := openCatalogDB(*inventory, *migs)
+		if err != nil {
+			log.Fatal("inventory:", err)
+		}
+		sc.inventory, sc.reservationTTL = NewSQLite3Inventory(db), *reservationTTL
+	}
+
+	if *payments != "" {
+		sc.payments = &HTTPPaymentGateway{URL: *payments, Key: *paymentsKey, Client: &http.Client{Timeout: 30 * time.Second}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go PurgeIdempotencyKeys(ctx, st, *idempotencyTTL)
+	if sc.inventory != nil && sc.reservationTTL > 0 {
+		go ReleaseExpiredReservations(ctx, sc.inventory, sc.reservationTTL)
+	}
 
 	s := &http.Server{
 		Addr:    *addr,
-		Handler: NewAPIv1(sc),
+		Handler: NewAPIv1(sc, auth, *guests, APIv1IdempotencyMiddleware(st, *idempotencyTTL)),
 	}
 
 	idleConnsClosed := make(chan struct{})
@@ -53,3 +140,131 @@ func main() {
 
 	<-idleConnsClosed
 }
+
+// openStorer returns a storer for the driver and DSN, the DB is migrated up first unless
+// migrations is empty.
+func openStorer(driver, dsn, migrations string) (storer, error) {
+	if dsn == "memory://" {
+		return NewMemory(), nil
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		st  storer
+		dir = migrations
+	)
+	switch driver {
+	case "sqlite3":
+		st = &SQLite3{db: db}
+	case "postgres":
+		st, dir = &Postgres{db: db}, filepath.Join(migrations, "postgres")
+	default:
+		return nil, fmt.Errorf("driver %q is not supported", driver)
+	}
+
+	if migrations == "" {
+		return st, nil
+	}
+
+	if err := migrate(db, driver, dir); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// openCatalog returns the catalog of a JSON file or of a SQLite DB.
+func openCatalog(src, migrations string) (Catalog, error) {
+	if strings.HasSuffix(src, ".json") {
+		return LoadJSONCatalog(src)
+	}
+
+	db, err := openCatalogDB(src, migrations)
+	if err != nil {
+		return nil, err
+	}
+	return NewSQLite3Catalog(db), nil
+}
+
+// openRules returns the rules of a JSON file or of a SQLite DB.
+func openRules(src, migrations string) (Rules, error) {
+	if strings.HasSuffix(src, ".json") {
+		return LoadJSONRules(src)
+	}
+
+	db, err := openCatalogDB(src, migrations)
+	if err != nil {
+		return nil, err
+	}
+	return NewSQLite3Rules(db), nil
+}
+
+// openCatalogDB opens a SQLite catalog DB, which holds products, rules and stock. The DB is
+// migrated up with the catalog subdirectory of migrations first unless migrations is empty.
+func openCatalogDB(dsn, migrations string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if migrations == "" {
+		return db, nil
+	}
+
+	if err := migrate(db, "sqlite3", filepath.Join(migrations, "catalog")); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// migrate migrates the DB up with the migrations of the directory.
+func migrate(db *sql.DB, dialect, dir string) error {
+	if err := goose.SetDialect(dialect); err != nil {
+		return err
+	}
+	if err := goose.Up(db, dir); err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+	return nil
+}
+
+// openTaxes returns the tax calculator of a JSON tax table or of a tax service URL.
+func openTaxes(src string) (TaxCalculator, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return &HTTPTaxCalculator{URL: src, Client: &http.Client{Timeout: 5 * time.Second}}, nil
+	}
+
+	return LoadTaxTable(src)
+}
+
+// newAuthenticator returns an authenticator accepting tokens of every configured kind.
+func newAuthenticator(jwtSecret, jwksPath, apiKeysPath string) (Authenticator, error) {
+	var auth Authenticators
+
+	if jwtSecret != "" || jwksPath != "" {
+		var keys map[string]*rsa.PublicKey
+		if jwksPath != "" {
+			var err error
+			if keys, err = LoadJWKS(jwksPath); err != nil {
+				return nil, fmt.Errorf("jwks: %w", err)
+			}
+		}
+		auth = append(auth, NewJWTAuthenticator([]byte(jwtSecret), keys))
+	}
+
+	if apiKeysPath != "" {
+		keys, err := LoadAPIKeys(apiKeysPath)
+		if err != nil {
+			return nil, fmt.Errorf("api keys: %w", err)
+		}
+		auth = append(auth, NewAPIKeyAuthenticator(keys))
+	}
+
+	if len(auth) == 0 {
+		return nil, errors.New("no authentication method configured")
+	}
+	return auth, nil
+}
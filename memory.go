@@ -0,0 +1,745 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory storer, handy for tests and ephemeral deployments.
+//
+// The committed state is never modified in place: writers copy it, apply their changes and
+// publish the copy. Transactions read from the snapshot taken at their beginning and copy it on
+// every write. Writers are serialized, a read-write transaction holds the writer lock from
+// BeginTx until Commit or Rollback.
+type Memory struct {
+	db *memDB
+	tx *memTx
+}
+
+type memDB struct {
+	mu     sync.RWMutex
+	state  *memState
+	writer chan struct{}
+}
+
+type memTx struct {
+	mu       sync.Mutex
+	db       *memDB
+	readOnly bool
+	base     *memState
+	state    *memState // nil until the first write
+	done     chan struct{}
+}
+
+type memState struct {
+	carts           map[int64]Cart
+	lineItems       map[int64]LineItem
+	idempotencyKeys map[idempotencyKeyID]IdempotencyKey
+	orders          map[int64]Order
+	payments        map[int64]Payment
+	cartSeq         int64
+	lineItemSeq     int64
+	orderSeq        int64
+	orderItemSeq    int64
+	paymentSeq      int64
+}
+
+type idempotencyKeyID struct {
+	userID int64
+	key    string
+}
+
+// NewMemory instantiates an empty Memory storer.
+func NewMemory() *Memory {
+	return &Memory{
+		db: &memDB{
+			state: &memState{
+				carts:           map[int64]Cart{},
+				lineItems:       map[int64]LineItem{},
+				idempotencyKeys: map[idempotencyKeyID]IdempotencyKey{},
+				orders:          map[int64]Order{},
+				payments:        map[int64]Payment{},
+			},
+			writer: make(chan struct{}, 1),
+		},
+	}
+}
+
+func (s *Memory) BeginTx(ctx context.Context, opts *sql.TxOptions) (storer, error) {
+	if s.tx != nil {
+		return nil, errors.New("can not begin transaction while in a transaction")
+	}
+
+	tx := &memTx{
+		db:       s.db,
+		readOnly: opts != nil && opts.ReadOnly,
+		done:     make(chan struct{}),
+	}
+
+	if !tx.readOnly {
+		if err := s.db.lock(ctx); err != nil {
+			return nil, err
+		}
+	}
+	tx.base = s.db.snapshot()
+
+	// Roll back once the context is done, the same way database/sql does.
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = tx.finish(false)
+		case <-tx.done:
+		}
+	}()
+
+	return &Memory{db: s.db, tx: tx}, nil
+}
+
+func (s *Memory) Commit() error {
+	if s.tx == nil {
+		return errors.New("not a transaction")
+	}
+	return s.tx.finish(true)
+}
+
+func (s *Memory) Rollback() error {
+	if s.tx == nil {
+		return errors.New("not a transaction")
+	}
+	return s.tx.finish(false)
+}
+
+func (s *Memory) CartCreate(ctx context.Context, cart *Cart) error {
+	tm := time.Now().UTC()
+
+	return s.write(ctx, func(st *memState) error {
+		if cart.GuestToken == "" {
+			for id, c := range st.carts {
+				if c.UserID == cart.UserID && c.Status == CartActive && c.GuestToken == "" {
+					c.Status, c.Version, c.UpdatedAt = CartAbandoned, c.Version+1, tm
+					st.carts[id] = c
+				}
+			}
+		}
+
+		st.cartSeq++
+
+		cart.ID, cart.Version, cart.Status = st.cartSeq, 1, CartActive
+		cart.CreatedAt, cart.UpdatedAt = tm, tm
+
+		st.carts[cart.ID] = Cart{
+			ID:         cart.ID,
+			UserID:     cart.UserID,
+			GuestToken: cart.GuestToken,
+			Version:    cart.Version,
+			Status:     cart.Status,
+			CreatedAt:  tm,
+			UpdatedAt:  tm,
+		}
+		return nil
+	})
+}
+
+func (s *Memory) CartWithItemsByCartID(ctx context.Context, cartID int64) (*Cart, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := st.carts[cartID]
+	if !ok {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+	}
+
+	c.LineItems = st.lineItemsByCartID(cartID)
+	c.Coupons = append([]string(nil), c.Coupons...)
+	c.ShippingAddress = copyAddress(c.ShippingAddress)
+	return &c, nil
+}
+
+func (s *Memory) ActiveCartByUserID(ctx context.Context, userID int64) (*Cart, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range st.carts {
+		if c.UserID == userID && c.Status == CartActive && c.GuestToken == "" {
+			c.LineItems = st.lineItemsByCartID(c.ID)
+			c.Coupons = append([]string(nil), c.Coupons...)
+			c.ShippingAddress = copyAddress(c.ShippingAddress)
+			return &c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("active cart of user %d: %w", userID, ErrCartNotFound)
+}
+
+func (s *Memory) CartsByUserID(ctx context.Context, userID int64, q CartsQuery) ([]*Cart, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var carts []*Cart
+	for _, c := range st.carts {
+		if c.UserID != userID || c.ID <= q.AfterID ||
+			!inTimeRange(c.CreatedAt, q.CreatedFrom, q.CreatedTo) ||
+			!inTimeRange(c.UpdatedAt, q.UpdatedFrom, q.UpdatedTo) {
+			continue
+		}
+
+		c := c
+		carts = append(carts, &c)
+	}
+	sort.Slice(carts, func(a, b int) bool { return carts[a].ID < carts[b].ID })
+
+	if len(carts) > q.Limit {
+		carts = carts[:q.Limit]
+	}
+
+	for _, c := range carts {
+		if !q.WithItems {
+			c.Coupons, c.ShippingAddress, c.ShippingMethod = nil, nil, ""
+			continue
+		}
+
+		c.LineItems = st.lineItemsByCartID(c.ID)
+		c.Coupons = append([]string(nil), c.Coupons...)
+		c.ShippingAddress = copyAddress(c.ShippingAddress)
+	}
+	return carts, nil
+}
+
+func (s *Memory) CartEmpty(ctx context.Context, cartID int64) error {
+	return s.write(ctx, func(st *memState) error {
+		if err := st.cartTouch(cartID); err != nil {
+			return err
+		}
+
+		for id, i := range st.lineItems {
+			if i.CartID == cartID {
+				delete(st.lineItems, id)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Memory) CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) error {
+	return s.write(ctx, func(st *memState) error {
+		if err := st.cartTouch(cartID); err != nil {
+			return err
+		}
+
+		c := st.carts[cartID]
+		c.ShippingAddress = copyAddress(a)
+		st.carts[cartID] = c
+		return nil
+	})
+}
+
+func (s *Memory) CartShippingMethodSet(ctx context.Context, cartID int64, code string) error {
+	return s.write(ctx, func(st *memState) error {
+		if err := st.cartTouch(cartID); err != nil {
+			return err
+		}
+
+		c := st.carts[cartID]
+		c.ShippingMethod = code
+		st.carts[cartID] = c
+		return nil
+	})
+}
+
+func (s *Memory) CartStatusSet(ctx context.Context, cartID int64, status CartStatus) error {
+	return s.write(ctx, func(st *memState) error {
+		if err := st.cartTouch(cartID); err != nil {
+			return err
+		}
+
+		c := st.carts[cartID]
+		c.Status = status
+		st.carts[cartID] = c
+		return nil
+	})
+}
+
+func (s *Memory) OrderCreate(ctx context.Context, o *Order) error {
+	tm := time.Now().UTC()
+
+	return s.write(ctx, func(st *memState) error {
+		if c, ok := st.carts[o.CartID]; ok && c.OrderID != 0 {
+			return fmt.Errorf("cart %d order %d: %w", o.CartID, c.OrderID, ErrConflict)
+		}
+
+		// Checking out is a mutation of the cart.
+		if err := st.cartTouch(o.CartID); err != nil {
+			return err
+		}
+
+		st.orderSeq++
+		o.ID, o.CreatedAt = st.orderSeq, tm
+
+		stored := *o
+		stored.ShippingAddress = copyAddress(o.ShippingAddress)
+		stored.LineItems = make([]*OrderItem, len(o.LineItems))
+		for j, i := range o.LineItems {
+			st.orderItemSeq++
+			i.ID = st.orderItemSeq
+
+			cp := *i
+			stored.LineItems[j] = &cp
+		}
+		st.orders[o.ID] = stored
+
+		c := st.carts[o.CartID]
+		c.OrderID, c.Status = o.ID, CartCheckedOut
+		st.carts[o.CartID] = c
+		return nil
+	})
+}
+
+func (s *Memory) LineItemsUpsert(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) error {
+	return s.write(ctx, func(st *memState) error {
+		if err := st.cartTouch(cartID); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if item == nil {
+				continue
+			}
+
+			tm := time.Now().UTC()
+
+			i, ok := st.lineItemByProductID(cartID, item.ProductID)
+			if !ok {
+				st.lineItemSeq++
+				i = LineItem{
+					ID:        st.lineItemSeq,
+					CartID:    cartID,
+					ProductID: item.ProductID,
+					CreatedAt: tm,
+				}
+			}
+			if mode == UpsertIncrement {
+				// Incremented items keep the price they have been added at.
+				i.Quantity += item.Quantity
+				if i.UnitPrice.Currency == "" {
+					i.UnitPrice = item.UnitPrice
+				}
+			} else {
+				i.Quantity = item.Quantity
+				i.UnitPrice = item.UnitPrice
+			}
+			i.UpdatedAt = tm
+
+			st.lineItems[i.ID] = i
+
+			item.ID = i.ID
+			item.Quantity = i.Quantity
+			item.UnitPrice = i.UnitPrice
+			item.UpdatedAt = tm
+		}
+
+		return nil
+	})
+}
+
+func (s *Memory) LineItemRemove(ctx context.Context, cartID, itemID int64) error {
+	return s.write(ctx, func(st *memState) error {
+		if i, ok := st.lineItems[itemID]; !ok || i.CartID != cartID {
+			return fmt.Errorf("item %d: %w", itemID, ErrLineItemNotFound)
+		}
+
+		delete(st.lineItems, itemID)
+		return st.cartTouch(cartID)
+	})
+}
+
+func (s *Memory) CartCouponAdd(ctx context.Context, cartID int64, code string) error {
+	return s.write(ctx, func(st *memState) error {
+		if err := st.cartTouch(cartID); err != nil {
+			return err
+		}
+
+		c := st.carts[cartID]
+		for _, cc := range c.Coupons {
+			if cc == code {
+				return fmt.Errorf("coupon %q: %w", code, ErrConflict)
+			}
+		}
+
+		// The slice may be shared with other states.
+		c.Coupons = append(c.Coupons[:len(c.Coupons):len(c.Coupons)], code)
+		st.carts[cartID] = c
+		return nil
+	})
+}
+
+func (s *Memory) CartCouponRemove(ctx context.Context, cartID int64, code string) error {
+	return s.write(ctx, func(st *memState) error {
+		c, ok := st.carts[cartID]
+		if !ok {
+			return fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+		}
+
+		coupons := make([]string, 0, len(c.Coupons))
+		for _, cc := range c.Coupons {
+			if cc != code {
+				coupons = append(coupons, cc)
+			}
+		}
+		if len(coupons) == len(c.Coupons) {
+			return fmt.Errorf("coupon %q: %w", code, ErrCouponNotFound)
+		}
+
+		c.Coupons = coupons
+		st.carts[cartID] = c
+		return st.cartTouch(cartID)
+	})
+}
+
+func (s *Memory) CouponUses(ctx context.Context, userID int64, code string) (int, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, c := range st.carts {
+		if c.UserID != userID || c.Status != CartCheckedOut {
+			continue
+		}
+		for _, cc := range c.Coupons {
+			if cc == code {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func (s *Memory) PaymentCreate(ctx context.Context, p *Payment) error {
+	tm := time.Now().UTC()
+
+	return s.write(ctx, func(st *memState) error {
+		if _, ok := st.carts[p.CartID]; !ok {
+			return fmt.Errorf("cart %d: %w", p.CartID, ErrCartNotFound)
+		}
+
+		st.paymentSeq++
+		p.ID = st.paymentSeq
+		p.CreatedAt, p.UpdatedAt = tm, tm
+
+		st.payments[p.ID] = *p
+		return nil
+	})
+}
+
+func (s *Memory) PaymentByCartID(ctx context.Context, cartID int64) (*Payment, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *Payment
+	for _, p := range st.payments {
+		if p.CartID == cartID && (latest == nil || p.ID > latest.ID) {
+			p := p
+			latest = &p
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrPaymentNotFound)
+	}
+
+	return latest, nil
+}
+
+func (s *Memory) PaymentStatusSet(ctx context.Context, paymentID int64, status PaymentStatus) error {
+	return s.write(ctx, func(st *memState) error {
+		p, ok := st.payments[paymentID]
+		if !ok {
+			return fmt.Errorf("payment %d: %w", paymentID, ErrPaymentNotFound)
+		}
+
+		p.Status, p.UpdatedAt = status, time.Now().UTC()
+		st.payments[paymentID] = p
+		return nil
+	})
+}
+
+func (s *Memory) IdempotencyKeyCreate(ctx context.Context, key *IdempotencyKey) error {
+	tm := time.Now().UTC()
+
+	return s.write(ctx, func(st *memState) error {
+		id := idempotencyKeyID{key.UserID, key.Key}
+
+		// An expired key is replaced, a live one is a conflict.
+		if k, ok := st.idempotencyKeys[id]; ok && k.ExpiresAt.After(tm) {
+			return fmt.Errorf("key %q: %w", key.Key, ErrConflict)
+		}
+
+		key.CreatedAt = tm
+
+		k := *key
+		k.Response = nil
+		st.idempotencyKeys[id] = k
+		return nil
+	})
+}
+
+func (s *Memory) IdempotencyKeyByKey(ctx context.Context, userID int64, key string) (*IdempotencyKey, error) {
+	st, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k, ok := st.idempotencyKeys[idempotencyKeyID{userID, key}]
+	if !ok || !k.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("key %q: %w", key, ErrIdempotencyKeyNotFound)
+	}
+
+	return &k, nil
+}
+
+func (s *Memory) IdempotencyKeyComplete(ctx context.Context, key *IdempotencyKey) error {
+	return s.write(ctx, func(st *memState) error {
+		id := idempotencyKeyID{key.UserID, key.Key}
+
+		k, ok := st.idempotencyKeys[id]
+		if !ok {
+			return fmt.Errorf("key %q: %w", key.Key, ErrIdempotencyKeyNotFound)
+		}
+
+		k.Response, k.ExpiresAt = append([]byte(nil), key.Response...), key.ExpiresAt
+		st.idempotencyKeys[id] = k
+		return nil
+	})
+}
+
+func (s *Memory) IdempotencyKeyDelete(ctx context.Context, userID int64, key string) error {
+	return s.write(ctx, func(st *memState) error {
+		delete(st.idempotencyKeys, idempotencyKeyID{userID, key})
+		return nil
+	})
+}
+
+func (s *Memory) IdempotencyKeysPurge(ctx context.Context, before time.Time) error {
+	return s.write(ctx, func(st *memState) error {
+		for id, k := range st.idempotencyKeys {
+			if !k.ExpiresAt.After(before) {
+				delete(st.idempotencyKeys, id)
+			}
+		}
+		return nil
+	})
+}
+
+// read returns the state visible to s.
+func (s *Memory) read(ctx context.Context) (*memState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if s.tx == nil {
+		return s.db.snapshot(), nil
+	}
+
+	s.tx.mu.Lock()
+	defer s.tx.mu.Unlock()
+
+	if s.tx.isDone() {
+		return nil, sql.ErrTxDone
+	}
+	if s.tx.state != nil {
+		return s.tx.state, nil
+	}
+	return s.tx.base, nil
+}
+
+// write applies fn to a copy of the state visible to s. Outside of a transaction the copy is
+// published right away, within a transaction on commit.
+func (s *Memory) write(ctx context.Context, fn func(st *memState) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if s.tx == nil {
+		if err := s.db.lock(ctx); err != nil {
+			return err
+		}
+		defer s.db.unlock()
+
+		st := s.db.snapshot().clone()
+		if err := fn(st); err != nil {
+			return err
+		}
+
+		s.db.publish(st)
+		return nil
+	}
+
+	s.tx.mu.Lock()
+	defer s.tx.mu.Unlock()
+
+	switch {
+	case s.tx.isDone():
+		return sql.ErrTxDone
+	case s.tx.readOnly:
+		return errors.New("write in a read-only transaction")
+	}
+
+	// Always copy so a failed write leaves the transaction state untouched.
+	st := s.tx.base
+	if s.tx.state != nil {
+		st = s.tx.state
+	}
+	st = st.clone()
+
+	if err := fn(st); err != nil {
+		return err
+	}
+
+	s.tx.state = st
+	return nil
+}
+
+func (db *memDB) lock(ctx context.Context) error {
+	select {
+	case db.writer <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (db *memDB) unlock() {
+	<-db.writer
+}
+
+func (db *memDB) snapshot() *memState {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.state
+}
+
+func (db *memDB) publish(st *memState) {
+	db.mu.Lock()
+	db.state = st
+	db.mu.Unlock()
+}
+
+// finish commits or rolls back the transaction.
+func (tx *memTx) finish(commit bool) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.isDone() {
+		return sql.ErrTxDone
+	}
+	close(tx.done)
+
+	if tx.readOnly {
+		return nil
+	}
+
+	if commit && tx.state != nil {
+		tx.db.publish(tx.state)
+	}
+	tx.db.unlock()
+
+	return nil
+}
+
+func (tx *memTx) isDone() bool {
+	select {
+	case <-tx.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (st *memState) clone() *memState {
+	c := &memState{
+		carts:           make(map[int64]Cart, len(st.carts)),
+		lineItems:       make(map[int64]LineItem, len(st.lineItems)),
+		idempotencyKeys: make(map[idempotencyKeyID]IdempotencyKey, len(st.idempotencyKeys)),
+		orders:          make(map[int64]Order, len(st.orders)),
+		payments:        make(map[int64]Payment, len(st.payments)),
+		cartSeq:         st.cartSeq,
+		lineItemSeq:     st.lineItemSeq,
+		orderSeq:        st.orderSeq,
+		orderItemSeq:    st.orderItemSeq,
+		paymentSeq:      st.paymentSeq,
+	}
+	for id, v := range st.carts {
+		c.carts[id] = v
+	}
+	for id, v := range st.lineItems {
+		c.lineItems[id] = v
+	}
+	for id, v := range st.idempotencyKeys {
+		c.idempotencyKeys[id] = v
+	}
+	for id, v := range st.orders {
+		c.orders[id] = v
+	}
+	for id, v := range st.payments {
+		c.payments[id] = v
+	}
+	return c
+}
+
+// cartTouch bumps the version of a cart being mutated.
+func (st *memState) cartTouch(cartID int64) error {
+	c, ok := st.carts[cartID]
+	if !ok {
+		return fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+	}
+
+	c.Version++
+	c.UpdatedAt = time.Now().UTC()
+	st.carts[cartID] = c
+	return nil
+}
+
+// lineItemsByCartID returns copies of the cart items ordered by ID.
+func (st *memState) lineItemsByCartID(cartID int64) []*LineItem {
+	var ii []*LineItem
+	for _, i := range st.lineItems {
+		if i.CartID == cartID {
+			i := i
+			ii = append(ii, &i)
+		}
+	}
+	sort.Slice(ii, func(a, b int) bool { return ii[a].ID < ii[b].ID })
+	return ii
+}
+
+func (st *memState) lineItemByProductID(cartID, productID int64) (LineItem, bool) {
+	for _, i := range st.lineItems {
+		if i.CartID == cartID && i.ProductID == productID {
+			return i, true
+		}
+	}
+	return LineItem{}, false
+}
+
+// copyAddress returns a copy of an address, the stored addresses are shared by states.
+func copyAddress(a *Address) *Address {
+	if a == nil {
+		return nil
+	}
+
+	cp := *a
+	return &cp
+}
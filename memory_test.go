@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemory_Suite(t *testing.T) {
+	RunStorerSuite(t, func(t *testing.T) storer { return NewMemory() })
+}
+
+func TestMemory_BeginTx(t *testing.T) {
+	st := NewMemory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.BeginTx(ctx, nil)
+	if err == nil {
+		t.Error("err exp, got none")
+	}
+}
+
+func TestMemory_Commit(t *testing.T) {
+	st := NewMemory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cart{UserID: 1}
+	if err := tx.CartCreate(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.CartWithItemsByCartID(ctx, c.ID); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("uncommitted err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.CartWithItemsByCartID(ctx, c.ID); err != nil {
+		t.Errorf("committed: %v", err)
+	}
+
+	if err := tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("second commit err exp: %v, got: %v", sql.ErrTxDone, err)
+	}
+}
+
+func TestMemory_Rollback(t *testing.T) {
+	st := NewMemory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cart{UserID: 1}
+	if err := tx.CartCreate(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.CartWithItemsByCartID(ctx, c.ID); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+
+	if err := st.Rollback(); err == nil {
+		t.Error("err exp, got none")
+	}
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		if _, err := st.BeginTx(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+
+		// The writer lock is released by the rollback of the abandoned transaction.
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if err := st.CartCreate(ctx, &Cart{UserID: 1}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestMemory_ReadOnly(t *testing.T) {
+	st := NewMemory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.CartCreate(ctx, &Cart{UserID: 1}); err == nil {
+		t.Error("err exp, got none")
+	}
+}
+
+func TestMemory_CartCreate(t *testing.T) {
+	c := &Cart{
+		UserID: 1,
+	}
+
+	st := NewMemory()
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.ID == 0 {
+		t.Error("id not updated")
+	}
+}
+
+func TestMemory_CartWithItemsByCartID(t *testing.T) {
+	st := NewMemory()
+	c := createMemoryCartWithItems(t, st)
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(c, cart) {
+		t.Errorf("carts do not match\nexp: %+v\ngot: %+v", c, cart)
+	}
+
+	if _, err := st.CartWithItemsByCartID(context.Background(), c.ID+1); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+}
+
+func TestMemory_CartEmpty(t *testing.T) {
+	st := NewMemory()
+	c := createMemoryCartWithItems(t, st)
+
+	if err := st.CartEmpty(context.Background(), c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := len(cart.LineItems); l != 0 {
+		t.Errorf("cart items num exp: %d, got: %d", 0, l)
+	}
+}
+
+func TestMemory_LineItemsUpsert(t *testing.T) {
+	st := NewMemory()
+	c := createMemoryCartWithItems(t, st)
+
+	ii := []*LineItem{
+		{ProductID: 9, Quantity: 5},
+		{ProductID: 1, Quantity: 8},
+	}
+
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, ii...); err != nil {
+		t.Fatal("first:", err)
+	}
+
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, &LineItem{ProductID: 9, Quantity: 3}); err != nil {
+		t.Fatal("second:", err)
+	}
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	if l := len(cart.LineItems); l != 2 {
+		t.Fatalf("cart items num exp: %d, got: %d", 2, l)
+	}
+
+	for _, i := range cart.LineItems {
+		if i.ProductID == 1 && i.Quantity != 8 {
+			t.Errorf("product %d quantity exp: %d got: %d", 1, 8, i.Quantity)
+		} else if i.ProductID == 9 && i.Quantity != 3 {
+			t.Errorf("product %d quantity exp: %d got: %d", 9, 3, i.Quantity)
+		}
+	}
+
+	if err := st.LineItemsUpsert(context.Background(), c.ID+1, UpsertSet, ii...); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+}
+
+func TestMemory_LineItemRemove(t *testing.T) {
+	st := NewMemory()
+	c := createMemoryCartWithItems(t, st)
+
+	if err := st.LineItemRemove(context.Background(), c.ID, c.LineItems[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := len(cart.LineItems); l != 0 {
+		t.Errorf("cart items num exp: %d, got: %d", 0, l)
+	}
+}
+
+func TestMemory_Concurrency(t *testing.T) {
+	st := NewMemory()
+	sc := &ShoppingCart{storage: st}
+	ctx := withPrincipal(context.Background(), &Principal{UserID: 1})
+
+	c := &Cart{UserID: 1}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := sc.LineItemAdd(ctx, c.ID, []*LineItem{{ProductID: 1, Quantity: 1}}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q := cart.LineItems[0].Quantity; q != 50 {
+		t.Errorf("quantity exp: %d, got: %d", 50, q)
+	}
+}
+
+func createMemoryCartWithItems(t *testing.T, st *Memory) *Cart {
+	t.Helper()
+
+	c := &Cart{UserID: 50}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	c.LineItems = []*LineItem{{CartID: c.ID, ProductID: 1, Quantity: 2}}
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, c.LineItems...); err != nil {
+		t.Fatal("item:", err)
+	}
+
+	// LineItemsUpsert doesn't report the creation time and the bumped cart version.
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal("cart:", err)
+	}
+	c.Version, c.UpdatedAt = cart.Version, cart.UpdatedAt
+	c.LineItems[0].CreatedAt = cart.LineItems[0].CreatedAt
+
+	return c
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "user_id" integer NOT NULL,
+  "key" varchar(255) NOT NULL,
+  "fingerprint" varchar(64) NOT NULL,
+  "response" blob,
+  "created_at" datetime NOT NULL,
+  "expires_at" datetime NOT NULL,
+  CONSTRAINT "pk_user_id_key" PRIMARY KEY ("user_id", "key")
+);
+CREATE INDEX IF NOT EXISTS "idempotency_keys_expires_at_idx" ON "idempotency_keys" ("expires_at");
+
+-- +goose Down
+DROP TABLE idempotency_keys;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "line_items" ADD COLUMN "unit_price" integer;
+ALTER TABLE "line_items" ADD COLUMN "currency" varchar(3);
+
+-- +goose Down
+ALTER TABLE line_items DROP COLUMN currency;
+ALTER TABLE line_items DROP COLUMN unit_price;
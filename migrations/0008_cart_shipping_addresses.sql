@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "cart_shipping_addresses" (
+  "cart_id" integer PRIMARY KEY NOT NULL,
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "line1" varchar(255) NOT NULL DEFAULT '',
+  "line2" varchar(255) NOT NULL DEFAULT '',
+  "city" varchar(255) NOT NULL DEFAULT '',
+  "postal_code" varchar(32) NOT NULL DEFAULT '',
+  "region" varchar(8) NOT NULL DEFAULT '',
+  "country" char(2) NOT NULL,
+  "updated_at" datetime NOT NULL,
+  CONSTRAINT "fk_carts_id" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+-- +goose Down
+DROP TABLE cart_shipping_addresses;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "cart_shipping_methods" (
+  "cart_id" integer PRIMARY KEY NOT NULL,
+  "code" varchar(64) NOT NULL,
+  "updated_at" datetime NOT NULL,
+  CONSTRAINT "fk_carts_id" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+-- +goose Down
+DROP TABLE cart_shipping_methods;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "payments" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  "cart_id" integer NOT NULL,
+  "cart_version" integer NOT NULL,
+  "amount" integer NOT NULL,
+  "currency" varchar(3) NOT NULL,
+  "status" varchar(16) NOT NULL,
+  "authorization_id" varchar(255) NOT NULL,
+  "created_at" datetime NOT NULL,
+  "updated_at" datetime NOT NULL,
+  CONSTRAINT "fk_carts_id" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS "payments_cart_id_idx" ON "payments" ("cart_id", "id");
+
+-- +goose Down
+DROP TABLE payments;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE "carts" ADD COLUMN "guest_token" varchar(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE carts DROP COLUMN guest_token;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "products" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  "sku" varchar(64) NOT NULL,
+  "name" varchar(255) NOT NULL,
+  "unit_price" integer NOT NULL,
+  "currency" char(3) NOT NULL,
+  "active" boolean NOT NULL DEFAULT 1,
+  CONSTRAINT "uniq_sku" UNIQUE ("sku")
+);
+
+-- +goose Down
+DROP TABLE products;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE "products" ADD COLUMN "category" varchar(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE products DROP COLUMN category;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "promotion_rules" (
+  "name" varchar(64) PRIMARY KEY NOT NULL,
+  "definition" text NOT NULL,
+  "position" integer NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL DEFAULT 1
+);
+
+-- +goose Down
+DROP TABLE promotion_rules;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE "products" ADD COLUMN "tax_class" varchar(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE products DROP COLUMN tax_class;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "stock" (
+  "product_id" integer PRIMARY KEY NOT NULL,
+  "quantity" integer NOT NULL CHECK ("quantity" >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS "stock_reservations" (
+  "cart_id" integer NOT NULL,
+  "product_id" integer NOT NULL,
+  "quantity" integer NOT NULL,
+  "expires_at" datetime NOT NULL,
+  PRIMARY KEY ("cart_id", "product_id")
+);
+
+CREATE INDEX IF NOT EXISTS "stock_reservations_product_id" ON "stock_reservations" ("product_id", "expires_at");
+
+-- +goose Down
+DROP TABLE stock_reservations;
+DROP TABLE stock;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "carts" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "user_id" bigint,
+  "created_at" timestamptz NOT NULL,
+  "updated_at" timestamptz NOT NULL
+);
+
+-- +goose Down
+DROP TABLE carts;
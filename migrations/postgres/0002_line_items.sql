@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "line_items" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "cart_id" bigint,
+  "product_id" bigint,
+  "quantity" bigint DEFAULT 1,
+  "created_at" timestamptz NOT NULL,
+  "updated_at" timestamptz NOT NULL,
+  CONSTRAINT "fk_carts_id" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "uniq_cart_id_product_id" UNIQUE ("cart_id", "product_id")
+);
+
+-- +goose Down
+DROP TABLE line_items;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS "carts_user_id_idx" ON "carts" ("user_id", "id");
+
+-- +goose Down
+DROP INDEX carts_user_id_idx;
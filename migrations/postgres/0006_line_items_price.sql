@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "line_items" ADD COLUMN "unit_price" bigint;
+ALTER TABLE "line_items" ADD COLUMN "currency" char(3);
+
+-- +goose Down
+ALTER TABLE line_items DROP COLUMN currency;
+ALTER TABLE line_items DROP COLUMN unit_price;
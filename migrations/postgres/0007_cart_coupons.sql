@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "cart_coupons" (
+  "cart_id" bigint NOT NULL,
+  "code" varchar(64) NOT NULL,
+  "created_at" timestamptz NOT NULL,
+  CONSTRAINT "pk_cart_id_code" PRIMARY KEY ("cart_id", "code"),
+  CONSTRAINT "fk_carts_id" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+CREATE INDEX IF NOT EXISTS "cart_coupons_code_idx" ON "cart_coupons" ("code");
+
+-- +goose Down
+DROP TABLE cart_coupons;
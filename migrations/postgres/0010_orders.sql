@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "orders" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "cart_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT '',
+  "subtotal" bigint NOT NULL,
+  "discount_total" bigint NOT NULL,
+  "tax_total" bigint NOT NULL,
+  "shipping_total" bigint NOT NULL,
+  "grand_total" bigint NOT NULL,
+  "shipping_method" varchar(64) NOT NULL DEFAULT '',
+  "shipping_name" varchar(255) NOT NULL DEFAULT '',
+  "shipping_line1" varchar(255) NOT NULL DEFAULT '',
+  "shipping_line2" varchar(255) NOT NULL DEFAULT '',
+  "shipping_city" varchar(255) NOT NULL DEFAULT '',
+  "shipping_postal_code" varchar(32) NOT NULL DEFAULT '',
+  "shipping_region" varchar(8) NOT NULL DEFAULT '',
+  "shipping_country" varchar(2) NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL,
+  CONSTRAINT "fk_carts_id" FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "uniq_orders_cart_id" UNIQUE ("cart_id")
+);
+
+CREATE TABLE IF NOT EXISTS "order_items" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "order_id" bigint NOT NULL,
+  "product_id" bigint NOT NULL,
+  "sku" varchar(64) NOT NULL DEFAULT '',
+  "name" varchar(255) NOT NULL DEFAULT '',
+  "quantity" bigint NOT NULL,
+  "unit_price" bigint NOT NULL,
+  "discount" bigint NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT '',
+  CONSTRAINT "fk_orders_id" FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS "order_items_order_id_idx" ON "order_items" ("order_id");
+
+-- +goose Down
+DROP TABLE order_items;
+DROP TABLE orders;
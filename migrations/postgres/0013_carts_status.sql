@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE "carts" ADD COLUMN "status" varchar(16) NOT NULL DEFAULT 'active';
+
+UPDATE carts SET status = 'checked_out' WHERE id IN (SELECT cart_id FROM orders);
+
+-- Only the latest cart of a user stays active.
+UPDATE carts SET status = 'abandoned'
+WHERE status = 'active' AND guest_token = '' AND id < (
+  SELECT MAX(c.id) FROM carts c WHERE c.user_id = carts.user_id AND c.status = 'active' AND c.guest_token = ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "carts_user_id_active_idx" ON "carts" ("user_id") WHERE status = 'active' AND guest_token = '';
+
+-- +goose Down
+DROP INDEX carts_user_id_active_idx;
+ALTER TABLE carts DROP COLUMN status;
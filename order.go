@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Order is a checked out cart. Orders copy the items, the shipping details and the totals of
+// the priced cart at checkout and never change.
+type Order struct {
+	ID              int64
+	CartID          int64
+	UserID          int64
+	LineItems       []*OrderItem
+	ShippingAddress *Address
+	ShippingMethod  string
+	Totals          Totals
+	CreatedAt       time.Time
+}
+
+// OrderItem is a cart item copied into an order.
+type OrderItem struct {
+	ID        int64
+	ProductID int64
+	SKU       string
+	Name      string
+	Quantity  int64
+	UnitPrice Money
+	Discount  Money // discounts of the item, cart discounts only count towards the order totals
+}
+
+// newOrder copies a priced cart into an order.
+func newOrder(c *Cart) (*Order, error) {
+	if c.Totals == nil {
+		return nil, fmt.Errorf("cart %d is not priced", c.ID)
+	}
+
+	o := &Order{
+		CartID:          c.ID,
+		UserID:          c.UserID,
+		LineItems:       make([]*OrderItem, len(c.LineItems)),
+		ShippingAddress: copyAddress(c.ShippingAddress),
+		ShippingMethod:  c.ShippingMethod,
+		Totals:          *c.Totals,
+	}
+
+	for j, i := range c.LineItems {
+		item := &OrderItem{
+			ProductID: i.ProductID,
+			SKU:       i.SKU,
+			Name:      i.Name,
+			Quantity:  i.Quantity,
+			UnitPrice: i.UnitPrice,
+			Discount:  Money{Currency: i.UnitPrice.Currency},
+		}
+
+		for _, a := range i.Adjustments {
+			if a.Kind != AdjustmentDiscount {
+				continue
+			}
+
+			var err error
+			if item.Discount, err = item.Discount.add(a.Amount); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i.ID, err)
+			}
+		}
+
+		o.LineItems[j] = item
+	}
+
+	return o, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PaymentGateway moves money with a payment provider.
+type PaymentGateway interface {
+	// Authorize holds the amount on the payment method of the source token, returns the ID
+	// of the authorization. Authorizations with the same idempotency key are made once.
+	// Declined payments fail with ErrPaymentDeclined.
+	Authorize(ctx context.Context, amount Money, source, idempotencyKey string) (string, error)
+	// Capture collects up to the authorized amount.
+	Capture(ctx context.Context, authorizationID string, amount Money) error
+	// Void releases the held amount of an authorization which has not been captured.
+	Void(ctx context.Context, authorizationID string) error
+	// Refund pays up to the captured amount back.
+	Refund(ctx context.Context, authorizationID string, amount Money) error
+}
+
+// PaymentStatus is a state of a payment.
+type PaymentStatus string
+
+const (
+	PaymentAuthorized PaymentStatus = "authorized"
+	PaymentCaptured   PaymentStatus = "captured"
+	PaymentVoided     PaymentStatus = "voided"
+	PaymentRefunded   PaymentStatus = "refunded"
+)
+
+// Payment is a payment of a cart.
+type Payment struct {
+	ID              int64
+	CartID          int64
+	CartVersion     int64 // the version of the cart paid for
+	Amount          Money
+	Status          PaymentStatus
+	AuthorizationID string // of the payment gateway
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// HTTPPaymentGateway is a payment gateway of a Stripe-like API. Payments are authorized by
+// creating confirmed payment intents with manual capture:
+//
+//	POST /v1/payment_intents amount=1999&currency=eur&payment_method=pm_card_visa&confirm=true&capture_method=manual
+//	POST /v1/payment_intents/{id}/capture amount_to_capture=1999
+//	POST /v1/payment_intents/{id}/cancel
+//	POST /v1/refunds payment_intent={id}&amount=1999
+//
+// Requests are form encoded and authenticated by the secret key, responses are JSON objects
+// with an id and errors are of the form {"error": {"type": "card_error", "message": "..."}}.
+type HTTPPaymentGateway struct {
+	URL    string // base URL, e.g. https://api.stripe.com
+	Key    string // secret API key
+	Client *http.Client
+}
+
+type httpPaymentResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  *struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (g *HTTPPaymentGateway) Authorize(ctx context.Context, amount Money, source, idempotencyKey string) (string, error) {
+	resp, err := g.post(ctx, "/v1/payment_intents", idempotencyKey, url.Values{
+		"amount":         {strconv.FormatInt(amount.Amount, 10)},
+		"currency":       {strings.ToLower(amount.Currency)},
+		"payment_method": {source},
+		"confirm":        {"true"},
+		"capture_method": {"manual"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if resp.Status != "requires_capture" {
+		return "", fmt.Errorf("payment intent %s: %s: %w", resp.ID, resp.Status, ErrPaymentDeclined)
+	}
+	return resp.ID, nil
+}
+
+func (g *HTTPPaymentGateway) Capture(ctx context.Context, authorizationID string, amount Money) error {
+	_, err := g.post(ctx, "/v1/payment_intents/"+url.PathEscape(authorizationID)+"/capture", "", url.Values{
+		"amount_to_capture": {strconv.FormatInt(amount.Amount, 10)},
+	})
+	return err
+}
+
+func (g *HTTPPaymentGateway) Void(ctx context.Context, authorizationID string) error {
+	_, err := g.post(ctx, "/v1/payment_intents/"+url.PathEscape(authorizationID)+"/cancel", "", url.Values{})
+	return err
+}
+
+func (g *HTTPPaymentGateway) Refund(ctx context.Context, authorizationID string, amount Money) error {
+	_, err := g.post(ctx, "/v1/refunds", "", url.Values{
+		"payment_intent": {authorizationID},
+		"amount":         {strconv.FormatInt(amount.Amount, 10)},
+	})
+	return err
+}
+
+// post POSTs the form to the API, card errors fail with ErrPaymentDeclined.
+func (g *HTTPPaymentGateway) post(ctx context.Context, path, idempotencyKey string, form url.Values) (*httpPaymentResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(g.URL, "/")+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Authorization", "Bearer "+g.Key)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		r.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("payment gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var pr httpPaymentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("payment gateway: %s: json: %w", resp.Status, err)
+	}
+
+	switch {
+	case pr.Error != nil && pr.Error.Type == "card_error":
+		return nil, fmt.Errorf("payment gateway: %s: %w", pr.Error.Message, ErrPaymentDeclined)
+	case pr.Error != nil:
+		return nil, fmt.Errorf("payment gateway: %s: %s", resp.Status, pr.Error.Message)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("payment gateway: %s", resp.Status)
+	}
+
+	return &pr, nil
+}
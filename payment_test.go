@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeGateway is an in-process PaymentGateway of tests, the source "declined" is declined.
+type fakeGateway struct {
+	mu             sync.Mutex
+	seq            int
+	payments       map[string]*fakePayment // by authorization ID
+	idempotencyKey map[string]string       // authorization IDs by idempotency key
+}
+
+type fakePayment struct {
+	amount   Money
+	status   PaymentStatus
+	captured Money
+	refunded Money
+}
+
+func newFakeGateway() *fakeGateway {
+	return &fakeGateway{payments: map[string]*fakePayment{}, idempotencyKey: map[string]string{}}
+}
+
+func (g *fakeGateway) Authorize(ctx context.Context, amount Money, source, idempotencyKey string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id, ok := g.idempotencyKey[idempotencyKey]; ok {
+		return id, nil
+	}
+
+	if source == "declined" {
+		return "", fmt.Errorf("card declined: %w", ErrPaymentDeclined)
+	}
+
+	g.seq++
+	id := fmt.Sprintf("auth_%d", g.seq)
+	g.payments[id] = &fakePayment{amount: amount, status: PaymentAuthorized}
+	g.idempotencyKey[idempotencyKey] = id
+	return id, nil
+}
+
+func (g *fakeGateway) Capture(ctx context.Context, authorizationID string, amount Money) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.payments[authorizationID]
+	if !ok || p.status != PaymentAuthorized || amount.Currency != p.amount.Currency || amount.Amount > p.amount.Amount {
+		return fmt.Errorf("capture %s: %w", authorizationID, ErrInvalidArgument)
+	}
+
+	p.status, p.captured = PaymentCaptured, amount
+	return nil
+}
+
+func (g *fakeGateway) Void(ctx context.Context, authorizationID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.payments[authorizationID]
+	if !ok || p.status != PaymentAuthorized {
+		return fmt.Errorf("void %s: %w", authorizationID, ErrInvalidArgument)
+	}
+
+	p.status = PaymentVoided
+	return nil
+}
+
+func (g *fakeGateway) Refund(ctx context.Context, authorizationID string, amount Money) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.payments[authorizationID]
+	if !ok || p.status != PaymentCaptured || amount.Currency != p.captured.Currency || amount.Amount > p.captured.Amount {
+		return fmt.Errorf("refund %s: %w", authorizationID, ErrInvalidArgument)
+	}
+
+	p.status, p.refunded = PaymentRefunded, amount
+	return nil
+}
+
+// status returns the status of an authorization.
+func (g *fakeGateway) status(authorizationID string) PaymentStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if p, ok := g.payments[authorizationID]; ok {
+		return p.status
+	}
+	return ""
+}
+
+func TestHTTPPaymentGateway(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer sk_test" {
+			t.Errorf("authorization exp: %s, got: %s", "Bearer sk_test", auth)
+		}
+
+		requests = append(requests, r.URL.Path+"?"+r.PostForm.Encode())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/payment_intents" && r.PostForm.Get("payment_method") == "pm_card_chargeDeclined":
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error":{"type":"card_error","code":"card_declined","message":"Your card was declined."}}`))
+		case r.URL.Path == "/v1/payment_intents" && r.PostForm.Get("payment_method") == "pm_card_authenticationRequired":
+			_, _ = w.Write([]byte(`{"id":"pi_2","status":"requires_action"}`))
+		case r.URL.Path == "/v1/payment_intents":
+			if key := r.Header.Get("Idempotency-Key"); key != "cart-1-1" {
+				t.Errorf("idempotency key exp: %s, got: %s", "cart-1-1", key)
+			}
+			_, _ = w.Write([]byte(`{"id":"pi_1","status":"requires_capture"}`))
+		case r.URL.Path == "/v1/payment_intents/pi_1/capture":
+			_, _ = w.Write([]byte(`{"id":"pi_1","status":"succeeded"}`))
+		case r.URL.Path == "/v1/payment_intents/pi_1/cancel":
+			_, _ = w.Write([]byte(`{"id":"pi_1","status":"canceled"}`))
+		case r.URL.Path == "/v1/refunds":
+			_, _ = w.Write([]byte(`{"id":"re_1","status":"succeeded"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"No such payment_intent"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	g := &HTTPPaymentGateway{URL: srv.URL, Key: "sk_test"}
+	ctx := context.Background()
+	eur := Money{Amount: 1999, Currency: "EUR"}
+
+	id, err := g.Authorize(ctx, eur, "pm_card_visa", "cart-1-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "pi_1" {
+		t.Errorf("authorization exp: %s, got: %s", "pi_1", id)
+	}
+
+	if err := g.Capture(ctx, id, eur); err != nil {
+		t.Error("capture:", err)
+	}
+	if err := g.Refund(ctx, id, eur); err != nil {
+		t.Error("refund:", err)
+	}
+	if err := g.Void(ctx, id); err != nil {
+		t.Error("void:", err)
+	}
+
+	exp := []string{
+		"/v1/payment_intents?amount=1999&capture_method=manual&confirm=true&currency=eur&payment_method=pm_card_visa",
+		"/v1/payment_intents/pi_1/capture?amount_to_capture=1999",
+		"/v1/refunds?amount=1999&payment_intent=pi_1",
+		"/v1/payment_intents/pi_1/cancel?",
+	}
+	if fmt.Sprint(exp) != fmt.Sprint(requests) {
+		t.Errorf("requests do not match\nexp: %v\ngot: %v", exp, requests)
+	}
+
+	for _, source := range []string{"pm_card_chargeDeclined", "pm_card_authenticationRequired"} {
+		if _, err := g.Authorize(ctx, eur, source, ""); !errors.Is(err, ErrPaymentDeclined) {
+			t.Errorf("%s err exp: %v, got: %v", source, ErrPaymentDeclined, err)
+		}
+	}
+
+	if err := g.Void(ctx, "pi_unknown"); err == nil || errors.Is(err, ErrPaymentDeclined) {
+		t.Errorf("gateway error exp, got: %v", err)
+	}
+}
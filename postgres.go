@@ -0,0 +1,825 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Postgres holds functions to mutate objects state in a PostgreSQL DB.
+type Postgres struct {
+	db   db
+	lock bool // lock carts read within a read-write transaction
+}
+
+func (s *Postgres) BeginTx(ctx context.Context, opts *sql.TxOptions) (storer, error) {
+	db, ok := s.db.(*sql.DB)
+	if !ok {
+		return nil, errors.New("can not begin transaction while in a transaction")
+	}
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, postgresError(err)
+	}
+
+	return &Postgres{db: tx, lock: opts == nil || !opts.ReadOnly}, nil
+}
+
+func (s *Postgres) Commit() error {
+	tx, ok := s.db.(*sql.Tx)
+	if !ok {
+		return errors.New("not a transaction")
+	}
+	return postgresError(tx.Commit())
+}
+
+func (s *Postgres) Rollback() error {
+	tx, ok := s.db.(*sql.Tx)
+	if !ok {
+		return errors.New("not a transaction")
+	}
+	return tx.Rollback()
+}
+
+func (s *Postgres) CartCreate(ctx context.Context, cart *Cart) error {
+	tm := time.Now().UTC()
+
+	if cart.GuestToken == "" {
+		_, err := s.db.ExecContext(
+			ctx,
+			`UPDATE carts SET status = $1, version = version + 1, updated_at = $2 WHERE user_id = $3 AND status = $4 AND guest_token = ''`,
+			CartAbandoned, tm, cart.UserID, CartActive,
+		)
+		if err != nil {
+			return fmt.Errorf("abandon carts: %w", postgresError(err))
+		}
+	}
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`INSERT INTO carts(user_id, guest_token, status, version, created_at, updated_at) VALUES($1, $2, $3, 1, $4, $5) RETURNING id`,
+		cart.UserID, cart.GuestToken, CartActive, tm, tm,
+	).Scan(&cart.ID)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	cart.Version, cart.Status = 1, CartActive
+	cart.CreatedAt, cart.UpdatedAt = tm, tm
+	return nil
+}
+
+func (s *Postgres) CartWithItemsByCartID(ctx context.Context, cartID int64) (*Cart, error) {
+	c := &Cart{}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT user_id, guest_token, status, version, created_at, updated_at, COALESCE((SELECT id FROM orders WHERE cart_id = carts.id), 0)
+		FROM carts
+		WHERE id = $1`+s.forUpdate(),
+		cartID,
+	).Scan(
+		&c.UserID,
+		&c.GuestToken,
+		&c.Status,
+		&c.Version,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.OrderID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("cart query: %w", postgresError(err))
+	}
+
+	c.ID = cartID
+
+	items, err := s.lineItemsByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, err := s.couponsByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.shippingAddressesByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.shippingMethodsByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.LineItems, c.Coupons = items[cartID], coupons[cartID]
+	c.ShippingAddress, c.ShippingMethod = addresses[cartID], methods[cartID]
+	return c, nil
+}
+
+func (s *Postgres) ActiveCartByUserID(ctx context.Context, userID int64) (*Cart, error) {
+	var cartID int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id FROM carts WHERE user_id = $1 AND status = $2 AND guest_token = ''`,
+		userID, CartActive,
+	).Scan(&cartID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("active cart of user %d: %w", userID, ErrCartNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("cart query: %w", postgresError(err))
+	}
+
+	return s.CartWithItemsByCartID(ctx, cartID)
+}
+
+func (s *Postgres) CartsByUserID(ctx context.Context, userID int64, q CartsQuery) ([]*Cart, error) {
+	where, args := []string{"user_id = $1", "id > $2"}, []interface{}{userID, q.AfterID}
+	for _, f := range []struct {
+		cond string
+		tm   time.Time
+	}{
+		{"created_at >= ", q.CreatedFrom},
+		{"created_at < ", q.CreatedTo},
+		{"updated_at >= ", q.UpdatedFrom},
+		{"updated_at < ", q.UpdatedTo},
+	} {
+		if !f.tm.IsZero() {
+			args = append(args, f.tm.UTC())
+			where = append(where, f.cond+fmt.Sprintf("$%d", len(args)))
+		}
+	}
+	args = append(args, q.Limit)
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, guest_token, status, version, created_at, updated_at, COALESCE((SELECT id FROM orders WHERE cart_id = carts.id), 0)
+		FROM carts
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id
+		LIMIT `+fmt.Sprintf("$%d", len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cart query: %w", postgresError(err))
+	}
+	defer rows.Close()
+
+	var (
+		carts []*Cart
+		ids   []int64
+	)
+	for rows.Next() {
+		c := &Cart{UserID: userID}
+		if err := rows.Scan(&c.ID, &c.GuestToken, &c.Status, &c.Version, &c.CreatedAt, &c.UpdatedAt, &c.OrderID); err != nil {
+			return nil, fmt.Errorf("cart scan: %w", err)
+		}
+
+		carts = append(carts, c)
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cart rows: %w", err)
+	}
+
+	if !q.WithItems || len(carts) == 0 {
+		return carts, nil
+	}
+
+	items, err := s.lineItemsByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, err := s.couponsByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.shippingAddressesByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.shippingMethodsByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range carts {
+		c.LineItems, c.Coupons = items[c.ID], coupons[c.ID]
+		c.ShippingAddress, c.ShippingMethod = addresses[c.ID], methods[c.ID]
+	}
+	return carts, nil
+}
+
+func (s *Postgres) CartEmpty(ctx context.Context, cartID int64) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM line_items WHERE cart_id = $1`,
+		cartID,
+	)
+	return postgresError(err)
+}
+
+func (s *Postgres) CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	if a == nil {
+		_, err := s.db.ExecContext(
+			ctx,
+			`DELETE FROM cart_shipping_addresses WHERE cart_id = $1`,
+			cartID,
+		)
+		return postgresError(err)
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO cart_shipping_addresses(cart_id, name, line1, line2, city, postal_code, region, country, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT(cart_id) DO UPDATE SET name = excluded.name, line1 = excluded.line1, line2 = excluded.line2,
+		city = excluded.city, postal_code = excluded.postal_code, region = excluded.region, country = excluded.country,
+		updated_at = excluded.updated_at`,
+		cartID, a.Name, a.Line1, a.Line2, a.City, a.PostalCode, a.Region, a.Country, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("shipping address: %w", postgresError(err))
+	}
+
+	return nil
+}
+
+func (s *Postgres) CartShippingMethodSet(ctx context.Context, cartID int64, code string) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	if code == "" {
+		_, err := s.db.ExecContext(
+			ctx,
+			`DELETE FROM cart_shipping_methods WHERE cart_id = $1`,
+			cartID,
+		)
+		return postgresError(err)
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO cart_shipping_methods(cart_id, code, updated_at) VALUES($1, $2, $3)
+		ON CONFLICT(cart_id) DO UPDATE SET code = excluded.code, updated_at = excluded.updated_at`,
+		cartID, code, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("shipping method: %w", postgresError(err))
+	}
+
+	return nil
+}
+
+func (s *Postgres) CartStatusSet(ctx context.Context, cartID int64, status CartStatus) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE carts SET status = $1 WHERE id = $2`,
+		status, cartID,
+	)
+	return postgresError(err)
+}
+
+func (s *Postgres) OrderCreate(ctx context.Context, o *Order) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tm := time.Now().UTC()
+
+	a, t := o.ShippingAddress, o.Totals
+	if a == nil {
+		a = &Address{}
+	}
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`INSERT INTO orders(cart_id, user_id, currency, subtotal, discount_total, tax_total, shipping_total, grand_total, shipping_method,
+		shipping_name, shipping_line1, shipping_line2, shipping_city, shipping_postal_code, shipping_region, shipping_country, created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id`,
+		o.CartID, o.UserID, t.GrandTotal.Currency, t.Subtotal.Amount, t.DiscountTotal.Amount, t.TaxTotal.Amount, t.ShippingTotal.Amount,
+		t.GrandTotal.Amount, o.ShippingMethod, a.Name, a.Line1, a.Line2, a.City, a.PostalCode, a.Region, a.Country, tm,
+	).Scan(&o.ID)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	for _, i := range o.LineItems {
+		err := s.db.QueryRowContext(
+			ctx,
+			`INSERT INTO order_items(order_id, product_id, sku, name, quantity, unit_price, discount, currency)
+			VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			o.ID, i.ProductID, i.SKU, i.Name, i.Quantity, i.UnitPrice.Amount, i.Discount.Amount, i.UnitPrice.Currency,
+		).Scan(&i.ID)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i.ProductID, postgresError(err))
+		}
+	}
+
+	o.CreatedAt = tm
+
+	return s.CartStatusSet(ctx, o.CartID, CartCheckedOut)
+}
+
+func (s *Postgres) LineItemsUpsert(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+
+		tm := time.Now().UTC()
+
+		// Incremented items keep the price they have been added at.
+		set := "quantity = EXCLUDED.quantity, unit_price = EXCLUDED.unit_price, currency = EXCLUDED.currency"
+		if mode == UpsertIncrement {
+			set = "quantity = line_items.quantity + EXCLUDED.quantity, " +
+				"unit_price = COALESCE(line_items.unit_price, EXCLUDED.unit_price), currency = COALESCE(line_items.currency, EXCLUDED.currency)"
+		}
+
+		price, currency := priceArgs(item.UnitPrice)
+		err := s.db.QueryRowContext(
+			ctx,
+			`INSERT INTO line_items(cart_id, product_id, quantity, unit_price, currency, created_at, updated_at)
+			VALUES($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT(cart_id, product_id) DO UPDATE SET `+set+`, updated_at = EXCLUDED.updated_at
+			RETURNING id, quantity, unit_price, currency`,
+			cartID, item.ProductID, item.Quantity, price, currency, tm, tm,
+		).Scan(&item.ID, &item.Quantity, &price, &currency)
+		if err != nil {
+			return fmt.Errorf("exec %d: %w", item.ProductID, postgresError(err))
+		}
+
+		item.UnitPrice = Money{Amount: price.Int64, Currency: currency.String}
+
+		item.UpdatedAt = tm
+	}
+
+	return nil
+}
+
+func (s *Postgres) LineItemRemove(ctx context.Context, cartID, itemID int64) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM line_items WHERE cart_id = $1 AND id = $2`,
+		cartID, itemID,
+	)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("item %d: %w", itemID, ErrLineItemNotFound)
+	}
+
+	return s.cartTouch(ctx, cartID)
+}
+
+func (s *Postgres) CartCouponAdd(ctx context.Context, cartID int64, code string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO cart_coupons(cart_id, code, created_at) VALUES($1, $2, $3)`,
+		cartID, code, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("coupon %q: %w", code, postgresError(err))
+	}
+
+	return s.cartTouch(ctx, cartID)
+}
+
+func (s *Postgres) CartCouponRemove(ctx context.Context, cartID int64, code string) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM cart_coupons WHERE cart_id = $1 AND code = $2`,
+		cartID, code,
+	)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("coupon %q: %w", code, ErrCouponNotFound)
+	}
+
+	return s.cartTouch(ctx, cartID)
+}
+
+func (s *Postgres) CouponUses(ctx context.Context, userID int64, code string) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*)
+		FROM cart_coupons cc
+		JOIN carts c ON c.id = cc.cart_id
+		WHERE c.user_id = $1 AND c.status = $2 AND cc.code = $3`,
+		userID, CartCheckedOut, code,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("coupon uses: %w", postgresError(err))
+	}
+
+	return n, nil
+}
+
+func (s *Postgres) PaymentCreate(ctx context.Context, p *Payment) error {
+	tm := time.Now().UTC()
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`INSERT INTO payments(cart_id, cart_version, amount, currency, status, authorization_id, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		p.CartID, p.CartVersion, p.Amount.Amount, p.Amount.Currency, p.Status, p.AuthorizationID, tm, tm,
+	).Scan(&p.ID)
+	if err != nil {
+		return fmt.Errorf("payment: %w", postgresError(err))
+	}
+
+	p.CreatedAt, p.UpdatedAt = tm, tm
+	return nil
+}
+
+func (s *Postgres) PaymentByCartID(ctx context.Context, cartID int64) (*Payment, error) {
+	p := &Payment{CartID: cartID}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, cart_version, amount, currency, status, authorization_id, created_at, updated_at
+		FROM payments
+		WHERE cart_id = $1
+		ORDER BY id DESC
+		LIMIT 1`,
+		cartID,
+	).Scan(
+		&p.ID,
+		&p.CartVersion,
+		&p.Amount.Amount,
+		&p.Amount.Currency,
+		&p.Status,
+		&p.AuthorizationID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrPaymentNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("payment query: %w", postgresError(err))
+	}
+
+	return p, nil
+}
+
+func (s *Postgres) PaymentStatusSet(ctx context.Context, paymentID int64, status PaymentStatus) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE payments SET status = $1, updated_at = $2 WHERE id = $3`,
+		status, time.Now().UTC(), paymentID,
+	)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("payment %d: %w", paymentID, ErrPaymentNotFound)
+	}
+
+	return nil
+}
+
+func (s *Postgres) IdempotencyKeyCreate(ctx context.Context, key *IdempotencyKey) error {
+	tm := time.Now().UTC()
+
+	// An expired key is replaced, a live one is a conflict.
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND expires_at <= $3`,
+		key.UserID, key.Key, tm,
+	)
+	if err != nil {
+		return fmt.Errorf("expired: %w", postgresError(err))
+	}
+
+	_, err = s.db.ExecContext(
+		ctx,
+		`INSERT INTO idempotency_keys(user_id, key, fingerprint, created_at, expires_at) VALUES($1, $2, $3, $4, $5)`,
+		key.UserID, key.Key, key.Fingerprint, tm, key.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	key.CreatedAt = tm
+	return nil
+}
+
+func (s *Postgres) IdempotencyKeyByKey(ctx context.Context, userID int64, key string) (*IdempotencyKey, error) {
+	k := &IdempotencyKey{UserID: userID, Key: key}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT fingerprint, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2 AND expires_at > $3`,
+		userID, key, time.Now().UTC(),
+	).Scan(
+		&k.Fingerprint,
+		&k.Response,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("key %q: %w", key, ErrIdempotencyKeyNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("key query: %w", postgresError(err))
+	}
+
+	return k, nil
+}
+
+func (s *Postgres) IdempotencyKeyComplete(ctx context.Context, key *IdempotencyKey) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE idempotency_keys SET response = $1, expires_at = $2 WHERE user_id = $3 AND key = $4`,
+		key.Response, key.ExpiresAt.UTC(), key.UserID, key.Key,
+	)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("key %q: %w", key.Key, ErrIdempotencyKeyNotFound)
+	}
+
+	return nil
+}
+
+func (s *Postgres) IdempotencyKeyDelete(ctx context.Context, userID int64, key string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2`,
+		userID, key,
+	)
+	return postgresError(err)
+}
+
+func (s *Postgres) IdempotencyKeysPurge(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM idempotency_keys WHERE expires_at <= $1`,
+		before.UTC(),
+	)
+	return postgresError(err)
+}
+
+// cartTouch bumps the version of a cart being mutated.
+func (s *Postgres) cartTouch(ctx context.Context, cartID int64) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE carts SET version = version + 1, updated_at = $1 WHERE id = $2`,
+		time.Now().UTC(), cartID,
+	)
+	if err != nil {
+		return postgresError(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+	}
+
+	return nil
+}
+
+// forUpdate locks the selected carts until the end of a read-write transaction, so the carts
+// do not change between being read and being mutated.
+func (s *Postgres) forUpdate() string {
+	if s.lock {
+		return " FOR UPDATE"
+	}
+	return ""
+}
+
+// lineItemsByCartIDs returns items of the carts by cart ID, items are ordered by ID.
+func (s *Postgres) lineItemsByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64][]*LineItem, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = fmt.Sprintf("$%d", j+1), id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, cart_id, product_id, quantity, unit_price, currency, created_at, updated_at
+		FROM line_items
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)
+		ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("item query: %w", postgresError(err))
+	}
+	defer rows.Close()
+
+	items := make(map[int64][]*LineItem, len(cartIDs))
+	for rows.Next() {
+		var (
+			i        = &LineItem{}
+			price    sql.NullInt64
+			currency sql.NullString
+		)
+		err := rows.Scan(
+			&i.ID,
+			&i.CartID,
+			&i.ProductID,
+			&i.Quantity,
+			&price,
+			&currency,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("item scan: %w", err)
+		}
+
+		i.UnitPrice = Money{Amount: price.Int64, Currency: currency.String}
+
+		items[i.CartID] = append(items[i.CartID], i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("item rows: %w", err)
+	}
+
+	return items, nil
+}
+
+// couponsByCartIDs returns codes of the coupons applied to the carts by cart ID, in the order
+// of application.
+func (s *Postgres) couponsByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64][]string, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = fmt.Sprintf("$%d", j+1), id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT cart_id, code
+		FROM cart_coupons
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)
+		ORDER BY created_at, code`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("coupon query: %w", postgresError(err))
+	}
+	defer rows.Close()
+
+	coupons := make(map[int64][]string, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID int64
+			code   string
+		)
+		if err := rows.Scan(&cartID, &code); err != nil {
+			return nil, fmt.Errorf("coupon scan: %w", err)
+		}
+
+		coupons[cartID] = append(coupons[cartID], code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("coupon rows: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// shippingAddressesByCartIDs returns shipping addresses of the carts by cart ID.
+func (s *Postgres) shippingAddressesByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64]*Address, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = fmt.Sprintf("$%d", j+1), id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT cart_id, name, line1, line2, city, postal_code, region, country
+		FROM cart_shipping_addresses
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("shipping address query: %w", postgresError(err))
+	}
+	defer rows.Close()
+
+	addresses := make(map[int64]*Address, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID int64
+			a      Address
+		)
+		if err := rows.Scan(&cartID, &a.Name, &a.Line1, &a.Line2, &a.City, &a.PostalCode, &a.Region, &a.Country); err != nil {
+			return nil, fmt.Errorf("shipping address scan: %w", err)
+		}
+
+		addresses[cartID] = &a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("shipping address rows: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// shippingMethodsByCartIDs returns codes of the shipping methods of the carts by cart ID.
+func (s *Postgres) shippingMethodsByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64]string, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = fmt.Sprintf("$%d", j+1), id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT cart_id, code
+		FROM cart_shipping_methods
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("shipping method query: %w", postgresError(err))
+	}
+	defer rows.Close()
+
+	methods := make(map[int64]string, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID int64
+			code   string
+		)
+		if err := rows.Scan(&cartID, &code); err != nil {
+			return nil, fmt.Errorf("shipping method scan: %w", err)
+		}
+
+		methods[cartID] = code
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("shipping method rows: %w", err)
+	}
+
+	return methods, nil
+}
+
+// postgresError translates PostgreSQL errors into domain errors.
+func postgresError(err error) error {
+	var perr *pq.Error
+	if !errors.As(err, &perr) {
+		return err
+	}
+
+	switch perr.Code {
+	case "23503": // foreign_key_violation
+		if perr.Constraint == "fk_carts_id" {
+			return fmt.Errorf("%v: %w", err, ErrCartNotFound)
+		}
+	case "23505": // unique_violation
+		return fmt.Errorf("%v: %w", err, ErrConflict)
+	case "40001", "40P01", "55P03": // serialization_failure, deadlock_detected, lock_not_available
+		return fmt.Errorf("%v: %w", err, ErrUnavailable)
+	}
+
+	return err
+}
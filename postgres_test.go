@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/pressly/goose"
+)
+
+func TestPostgres_Suite(t *testing.T) {
+	RunStorerSuite(t, func(t *testing.T) storer { return &Postgres{db: connectPostgres(t)} })
+}
+
+func TestPostgres_BeginTx(t *testing.T) {
+	st := &Postgres{db: connectPostgres(t)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.BeginTx(ctx, nil)
+	if err == nil {
+		t.Error("err exp, got none")
+	}
+}
+
+func TestPostgres_Rollback(t *testing.T) {
+	st := &Postgres{db: connectPostgres(t)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cart{UserID: 1}
+	if err := tx.CartCreate(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.CartWithItemsByCartID(ctx, c.ID); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+}
+
+func TestPostgres_CartCreate(t *testing.T) {
+	c := &Cart{
+		UserID: 1,
+	}
+
+	st := &Postgres{db: connectPostgres(t)}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.ID == 0 {
+		t.Error("id not updated")
+	}
+}
+
+func TestPostgres_CartWithItemsByCartID(t *testing.T) {
+	st := &Postgres{db: connectPostgres(t)}
+	c := &Cart{UserID: 50}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	c.LineItems = []*LineItem{{CartID: c.ID, ProductID: 1, Quantity: 2}}
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, c.LineItems...); err != nil {
+		t.Fatal("items:", err)
+	}
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cart.UserID != c.UserID || len(cart.LineItems) != 1 {
+		t.Fatalf("carts do not match\nexp: %+v\ngot: %+v", c, cart)
+	}
+
+	exp, got := *c.LineItems[0], *cart.LineItems[0]
+	exp.CreatedAt, exp.UpdatedAt, got.CreatedAt, got.UpdatedAt = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("items do not match\nexp: %+v\ngot: %+v", exp, got)
+	}
+}
+
+func TestPostgres_LineItemsUpsert(t *testing.T) {
+	st := &Postgres{db: connectPostgres(t)}
+	c := &Cart{UserID: 50}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	ii := []*LineItem{
+		{ProductID: 9, Quantity: 5},
+		{ProductID: 1, Quantity: 8},
+	}
+
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, ii...); err != nil {
+		t.Fatal("first:", err)
+	}
+
+	i := &LineItem{ProductID: 9, Quantity: 3}
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, i); err != nil {
+		t.Fatal("second:", err)
+	}
+
+	if i.ID != ii[0].ID {
+		t.Errorf("item id exp: %d, got: %d", ii[0].ID, i.ID)
+	}
+
+	cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+	if err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	if l := len(cart.LineItems); l != 2 {
+		t.Fatalf("cart items num exp: %d, got: %d", 2, l)
+	}
+
+	for _, i := range cart.LineItems {
+		if i.ProductID == 1 && i.Quantity != 8 {
+			t.Errorf("product %d quantity exp: %d got: %d", 1, 8, i.Quantity)
+		} else if i.ProductID == 9 && i.Quantity != 3 {
+			t.Errorf("product %d quantity exp: %d got: %d", 9, 3, i.Quantity)
+		}
+	}
+}
+
+func TestPostgres_LineItemRemove(t *testing.T) {
+	st := &Postgres{db: connectPostgres(t)}
+	c := &Cart{UserID: 50}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	i := &LineItem{ProductID: 1, Quantity: 2}
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, i); err != nil {
+		t.Fatal("items:", err)
+	}
+
+	if err := st.LineItemRemove(context.Background(), c.ID, i.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// connectPostgres connects to the DB from SHOPPINGCART_POSTGRES_DSN and migrates it,
+// skips the test if the variable is not set.
+func connectPostgres(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("SHOPPINGCART_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("SHOPPINGCART_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goose.SetLogger(log.New(ioutil.Discard, "", 0))
+
+	if err := goose.SetDialect("postgres"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := goose.Up(db, "./migrations/postgres"); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+)
+
+// Money is an amount in minor units of an ISO 4217 currency, e.g. cents of EUR.
+// Amounts are never floats.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// add returns m + o, both must be in the same currency unless one of them is zero.
+func (m Money) add(o Money) (Money, error) {
+	switch {
+	case o.Amount == 0 && o.Currency == "":
+		return m, nil
+	case m.Amount == 0 && m.Currency == "":
+		return o, nil
+	case m.Currency != o.Currency:
+		return Money{}, fmt.Errorf("%s and %s: %w", m.Currency, o.Currency, ErrCurrencyMismatch)
+	case (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) || (o.Amount < 0 && m.Amount < math.MinInt64-o.Amount):
+		return Money{}, fmt.Errorf("%d + %d overflows: %w", m.Amount, o.Amount, ErrInvalidQuantity)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// mul returns m multiplied by a non-negative quantity.
+func (m Money) mul(q int64) (Money, error) {
+	if q < 0 || (q > 0 && (m.Amount > math.MaxInt64/q || m.Amount < math.MinInt64/q)) {
+		return Money{}, fmt.Errorf("%d * %d overflows: %w", m.Amount, q, ErrInvalidQuantity)
+	}
+	return Money{Amount: m.Amount * q, Currency: m.Currency}, nil
+}
+
+// AdjustmentKind is a kind of a cart price adjustment.
+type AdjustmentKind string
+
+const (
+	AdjustmentDiscount AdjustmentKind = "discount"
+	AdjustmentTax      AdjustmentKind = "tax"
+	AdjustmentShipping AdjustmentKind = "shipping"
+)
+
+// Adjustment is a discount, tax or shipping charge applied to a cart. Amounts are positive,
+// discounts are subtracted from the subtotal.
+type Adjustment struct {
+	Kind     AdjustmentKind
+	Label    string
+	Amount   Money
+	Included bool // a tax already included in the unit prices, not added to the grand total
+	Shipping bool // a discount of the shipping charges rather than of the items
+}
+
+// Totals are the totals of a cart, all of them are in the currency of the cart.
+type Totals struct {
+	Subtotal      Money
+	DiscountTotal Money
+	TaxTotal      Money
+	ShippingTotal Money
+	GrandTotal    Money
+}
+
+// CartTotals computes totals of the cart from unit prices of its items and adjustments of the
+// cart and its items.
+// Items without a unit price are not counted. Discounts never bring the grand total below
+// zero.
+func CartTotals(c *Cart) (Totals, error) {
+	var (
+		t   Totals
+		err error
+	)
+
+	for _, i := range c.LineItems {
+		if i.UnitPrice.Currency == "" {
+			continue
+		}
+
+		amount, err := i.UnitPrice.mul(i.Quantity)
+		if err != nil {
+			return Totals{}, fmt.Errorf("item %d: %w", i.ID, err)
+		}
+
+		if t.Subtotal, err = t.Subtotal.add(amount); err != nil {
+			return Totals{}, fmt.Errorf("item %d: %w", i.ID, err)
+		}
+	}
+
+	adjustments := c.Adjustments
+	for _, i := range c.LineItems {
+		adjustments = append(adjustments[:len(adjustments):len(adjustments)], i.Adjustments...)
+	}
+
+	var included, shippingDiscount Money
+	for _, a := range adjustments {
+		total := &t.DiscountTotal
+		switch {
+		case a.Kind == AdjustmentDiscount && a.Shipping:
+			total = &shippingDiscount
+		case a.Kind == AdjustmentTax:
+			total = &t.TaxTotal
+			if a.Included {
+				if included, err = included.add(a.Amount); err != nil {
+					return Totals{}, fmt.Errorf("%s %q: %w", a.Kind, a.Label, err)
+				}
+			}
+		case a.Kind == AdjustmentShipping:
+			total = &t.ShippingTotal
+		}
+
+		if *total, err = total.add(a.Amount); err != nil {
+			return Totals{}, fmt.Errorf("%s %q: %w", a.Kind, a.Label, err)
+		}
+	}
+
+	// Items and shipping are discounted at most down to zero each.
+	if t.DiscountTotal.Amount > t.Subtotal.Amount {
+		t.DiscountTotal.Amount = t.Subtotal.Amount
+	}
+	if shippingDiscount.Amount > t.ShippingTotal.Amount {
+		shippingDiscount.Amount = t.ShippingTotal.Amount
+	}
+	if t.DiscountTotal, err = t.DiscountTotal.add(shippingDiscount); err != nil {
+		return Totals{}, fmt.Errorf("shipping discount: %w", err)
+	}
+
+	// Included taxes are part of the subtotal already.
+	discount := Money{Amount: -t.DiscountTotal.Amount, Currency: t.DiscountTotal.Currency}
+	excluded := Money{Amount: t.TaxTotal.Amount - included.Amount, Currency: t.TaxTotal.Currency}
+	for _, m := range []Money{t.Subtotal, discount, excluded, t.ShippingTotal} {
+		if t.GrandTotal, err = t.GrandTotal.add(m); err != nil {
+			return Totals{}, fmt.Errorf("grand total: %w", err)
+		}
+	}
+
+	// Every total is in the currency of the cart, even when it is zero.
+	currency := t.GrandTotal.Currency
+	for _, m := range []*Money{&t.Subtotal, &t.DiscountTotal, &t.TaxTotal, &t.ShippingTotal, &t.GrandTotal} {
+		m.Currency = currency
+	}
+
+	return t, nil
+}
+
+// PriceSource looks unit prices of products up.
+type PriceSource interface {
+	// UnitPrices returns unit prices by product ID, products without a price are omitted.
+	UnitPrices(ctx context.Context, productIDs ...int64) (map[int64]Money, error)
+}
+
+// PriceTable is a price source of fixed unit prices.
+type PriceTable map[int64]Money
+
+func (t PriceTable) UnitPrices(ctx context.Context, productIDs ...int64) (map[int64]Money, error) {
+	prices := make(map[int64]Money, len(productIDs))
+	for _, id := range productIDs {
+		if p, ok := t[id]; ok {
+			prices[id] = p
+		}
+	}
+	return prices, nil
+}
+
+// LoadPriceTable reads a price table from a JSON file of the form
+// {"<product id>": {"amount": 499, "currency": "EUR"}}.
+func LoadPriceTable(path string) (PriceTable, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pp map[string]struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal(b, &pp); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	t := make(PriceTable, len(pp))
+	for id, p := range pp {
+		productID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("product id %q: %w", id, err)
+		}
+		t[productID] = Money{Amount: p.Amount, Currency: p.Currency}
+	}
+	return t, nil
+}
+
+// CatalogPrices is a price source of unit prices of catalog products.
+type CatalogPrices struct {
+	Catalog Catalog
+}
+
+func (c CatalogPrices) UnitPrices(ctx context.Context, productIDs ...int64) (map[int64]Money, error) {
+	products, err := c.Catalog.Products(ctx, productIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[int64]Money, len(products))
+	for id, p := range products {
+		prices[id] = p.UnitPrice
+	}
+	return prices, nil
+}
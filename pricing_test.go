@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCartTotals(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	tests := []struct {
+		name string
+		cart Cart
+		exp  Totals
+		err  error
+	}{
+		{"empty", Cart{}, Totals{}, nil},
+		{
+			"items",
+			Cart{LineItems: []*LineItem{
+				{ProductID: 1, Quantity: 2, UnitPrice: eur(499)},
+				{ProductID: 2, Quantity: 1, UnitPrice: eur(1250)},
+				// Unpriced items are not counted.
+				{ProductID: 3, Quantity: 5},
+			}},
+			Totals{eur(2248), eur(0), eur(0), eur(0), eur(2248)},
+			nil,
+		},
+		{
+			"adjustments",
+			Cart{
+				LineItems: []*LineItem{{ProductID: 1, Quantity: 2, UnitPrice: eur(1000)}},
+				Adjustments: []Adjustment{
+					{Kind: AdjustmentDiscount, Label: "10% off", Amount: eur(200)},
+					{Kind: AdjustmentTax, Label: "VAT", Amount: eur(342)},
+					{Kind: AdjustmentShipping, Label: "Standard", Amount: eur(490)},
+					{Kind: AdjustmentDiscount, Label: "Welcome", Amount: eur(100)},
+				},
+			},
+			Totals{eur(2000), eur(300), eur(342), eur(490), eur(2532)},
+			nil,
+		},
+		{
+			"item adjustments",
+			Cart{
+				LineItems: []*LineItem{
+					{ProductID: 1, Quantity: 3, UnitPrice: eur(500), Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Label: "B2G1", Amount: eur(500)}}},
+					{ProductID: 2, Quantity: 1, UnitPrice: eur(1000)},
+				},
+				Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Label: "WELCOME10", Amount: eur(250)}},
+			},
+			Totals{eur(2500), eur(750), eur(0), eur(0), eur(1750)},
+			nil,
+		},
+		{
+			"included taxes",
+			Cart{
+				LineItems: []*LineItem{{ProductID: 1, Quantity: 1, UnitPrice: eur(1190)}},
+				Adjustments: []Adjustment{
+					{Kind: AdjustmentTax, Label: "VAT 19%", Amount: eur(190), Included: true},
+					{Kind: AdjustmentTax, Label: "Levy", Amount: eur(10)},
+				},
+			},
+			Totals{eur(1190), eur(0), eur(200), eur(0), eur(1200)},
+			nil,
+		},
+		{
+			"discount capped",
+			Cart{
+				LineItems:   []*LineItem{{ProductID: 1, Quantity: 1, UnitPrice: eur(500)}},
+				Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Label: "Gift card", Amount: eur(800)}},
+			},
+			Totals{eur(500), eur(500), eur(0), eur(0), eur(0)},
+			nil,
+		},
+		{
+			"shipping discount capped separately",
+			Cart{
+				LineItems: []*LineItem{{ProductID: 1, Quantity: 1, UnitPrice: eur(500)}},
+				Adjustments: []Adjustment{
+					{Kind: AdjustmentDiscount, Label: "Gift card", Amount: eur(800)},
+					{Kind: AdjustmentShipping, Label: "Standard", Amount: eur(490)},
+					{Kind: AdjustmentDiscount, Label: "FREESHIP", Amount: eur(600), Shipping: true},
+				},
+			},
+			Totals{eur(500), eur(990), eur(0), eur(490), eur(0)},
+			nil,
+		},
+		{
+			"currency mismatch",
+			Cart{LineItems: []*LineItem{
+				{ProductID: 1, Quantity: 1, UnitPrice: eur(499)},
+				{ProductID: 2, Quantity: 1, UnitPrice: Money{Amount: 499, Currency: "USD"}},
+			}},
+			Totals{},
+			ErrCurrencyMismatch,
+		},
+		{
+			"adjustment currency mismatch",
+			Cart{
+				LineItems:   []*LineItem{{ProductID: 1, Quantity: 1, UnitPrice: eur(499)}},
+				Adjustments: []Adjustment{{Kind: AdjustmentShipping, Amount: Money{Amount: 490, Currency: "USD"}}},
+			},
+			Totals{},
+			ErrCurrencyMismatch,
+		},
+		{
+			"item overflow",
+			Cart{LineItems: []*LineItem{{ProductID: 1, Quantity: 3, UnitPrice: eur(math.MaxInt64 / 2)}}},
+			Totals{},
+			ErrInvalidQuantity,
+		},
+		{
+			"subtotal overflow",
+			Cart{LineItems: []*LineItem{
+				{ProductID: 1, Quantity: 1, UnitPrice: eur(math.MaxInt64)},
+				{ProductID: 2, Quantity: 1, UnitPrice: eur(1)},
+			}},
+			Totals{},
+			ErrInvalidQuantity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totals, err := CartTotals(&tt.cart)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err exp: %v, got: %v", tt.err, err)
+			}
+
+			if !reflect.DeepEqual(tt.exp, totals) {
+				t.Errorf("totals do not match\nexp: %+v\ngot: %+v", tt.exp, totals)
+			}
+		})
+	}
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	path := writeTestFile(t, "prices.json", `{"1": {"amount": 499, "currency": "EUR"}, "2": {"amount": 1250, "currency": "EUR"}}`)
+
+	pt, err := LoadPriceTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices, err := pt.UnitPrices(context.Background(), 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := map[int64]Money{1: {Amount: 499, Currency: "EUR"}}; !reflect.DeepEqual(exp, prices) {
+		t.Errorf("prices do not match\nexp: %+v\ngot: %+v", exp, prices)
+	}
+
+	if _, err := LoadPriceTable(writeTestFile(t, "prices.json", `{"one": {"amount": 499, "currency": "EUR"}}`)); err == nil {
+		t.Error("invalid product id err exp")
+	}
+}
+
+func TestCatalogPrices_UnitPrices(t *testing.T) {
+	cat := &JSONCatalog{products: map[int64]*Product{
+		1: {ID: 1, UnitPrice: Money{Amount: 499, Currency: "EUR"}},
+		2: {ID: 2, UnitPrice: Money{Amount: 1250, Currency: "EUR"}},
+	}}
+
+	prices, err := CatalogPrices{Catalog: cat}.UnitPrices(context.Background(), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := map[int64]Money{2: {Amount: 1250, Currency: "EUR"}}; !reflect.DeepEqual(exp, prices) {
+		t.Errorf("prices do not match\nexp: %+v\ngot: %+v", exp, prices)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// problem is an RFC 7807 problem details object.
+type problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []invalidParam `json:"invalid_params,omitempty"`
+}
+
+// invalidParam describes a request parameter which failed validation.
+type invalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// newProblem returns a problem which occurred while serving r, its type is derived from code.
+func newProblem(r *http.Request, status int, code, title string) problem {
+	return problem{
+		Type:     "/problems/" + code,
+		Title:    title,
+		Status:   status,
+		Instance: r.URL.RequestURI(),
+	}
+}
+
+// write writes the problem as the response.
+func (p problem) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("problem Encode(%+v): %s", p, err)
+	}
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// PromotionKind is a kind of a discount rule.
+type PromotionKind string
+
+const (
+	PromotionPercentOff   PromotionKind = "percent_off"   // percent off the items or the subtotal
+	PromotionAmountOff    PromotionKind = "amount_off"    // fixed amount off the subtotal
+	PromotionBuyXGetY     PromotionKind = "buy_x_get_y"   // units of an item for free
+	PromotionFreeShipping PromotionKind = "free_shipping" // shipping charges discounted
+	PromotionTiered       PromotionKind = "tiered"        // percent off items by their quantity
+)
+
+// Promotion is a discount rule redeemed by a coupon code or, if it has no code, applied
+// automatically by name.
+type Promotion struct {
+	Code        string
+	Name        string
+	Description string // explains the promotion to customers
+
+	Kind        PromotionKind
+	Percent     int64           // of PromotionPercentOff, 1..100
+	Amount      Money           // of PromotionAmountOff
+	BuyQuantity int64           // of PromotionBuyXGetY, every BuyQuantity units of an item ...
+	GetQuantity int64           // ... get GetQuantity more units for free
+	Tiers       []PromotionTier // of PromotionTiered, ordered by MinQuantity
+
+	// Items matched by the promotion, all if both are empty. Discounts of matching items are
+	// per item, other discounts are of the cart.
+	ProductIDs []int64
+	Category   string
+
+	MinQuantity    int64     // total quantity of matching items, zero if there is no minimum
+	MinSubtotal    Money     // zero if there is no minimum
+	StartsAt       time.Time // the validity window, zero bounds are ignored
+	EndsAt         time.Time
+	MaxUsesPerUser int // carts of a user the coupon may be applied to, 0 is unlimited
+}
+
+// PromotionTier is a volume discount of items of at least MinQuantity units.
+type PromotionTier struct {
+	MinQuantity int64
+	Percent     int64
+}
+
+// Promotions looks promotions up.
+type Promotions interface {
+	// PromotionByCode returns the promotion of a coupon code, ErrCouponNotFound if there is none.
+	PromotionByCode(ctx context.Context, code string) (*Promotion, error)
+}
+
+// normalizeCouponCode returns the canonical form of a coupon code, codes are case-insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validate checks the promotion is well-formed.
+func (p *Promotion) validate() error {
+	switch {
+	case p.Code == "" && p.Name == "":
+		return fmt.Errorf("neither code nor name: %w", ErrInvalidArgument)
+	case p.Kind == PromotionPercentOff && (p.Percent < 1 || p.Percent > 100):
+		return fmt.Errorf("percent %d is out of 1..100: %w", p.Percent, ErrInvalidArgument)
+	case p.Kind == PromotionAmountOff && (p.Amount.Amount <= 0 || p.Amount.Currency == ""):
+		return fmt.Errorf("amount %d %s: %w", p.Amount.Amount, p.Amount.Currency, ErrInvalidArgument)
+	case p.Kind == PromotionBuyXGetY && (p.BuyQuantity <= 0 || p.GetQuantity <= 0):
+		return fmt.Errorf("buy %d get %d: %w", p.BuyQuantity, p.GetQuantity, ErrInvalidArgument)
+	case p.MinSubtotal.Amount > 0 && p.MinSubtotal.Currency == "":
+		return fmt.Errorf("min subtotal %d has no currency: %w", p.MinSubtotal.Amount, ErrInvalidArgument)
+	case p.Kind == PromotionTiered && len(p.Tiers) == 0:
+		return fmt.Errorf("no tiers: %w", ErrInvalidArgument)
+	case p.Kind != PromotionPercentOff && p.Kind != PromotionAmountOff && p.Kind != PromotionBuyXGetY &&
+		p.Kind != PromotionFreeShipping && p.Kind != PromotionTiered:
+		return fmt.Errorf("kind %q: %w", p.Kind, ErrInvalidArgument)
+	}
+
+	for j, t := range p.Tiers {
+		switch {
+		case t.MinQuantity <= 0 || t.Percent < 1 || t.Percent > 100:
+			return fmt.Errorf("tier %d: min quantity %d, percent %d: %w", j, t.MinQuantity, t.Percent, ErrInvalidArgument)
+		case j > 0 && p.Tiers[j-1].MinQuantity >= t.MinQuantity:
+			return fmt.Errorf("tier %d is out of order: %w", j, ErrInvalidArgument)
+		}
+	}
+
+	return nil
+}
+
+// label is the label of the discounts of the promotion.
+func (p *Promotion) label() string {
+	if p.Code != "" {
+		return p.Code
+	}
+	return p.Name
+}
+
+// applicable checks the promotion may be applied to the cart at tm. Usage limits are up to
+// the caller.
+func (p *Promotion) applicable(c *Cart, tm time.Time) error {
+	if !inTimeRange(tm, p.StartsAt, p.EndsAt) {
+		return fmt.Errorf("promotion %q is not valid at %s: %w", p.label(), tm.UTC().Format(time.RFC3339), ErrCouponNotApplicable)
+	}
+
+	if p.MinQuantity > 0 {
+		var quantity int64
+		for _, i := range c.LineItems {
+			if p.matches(i) {
+				quantity += i.Quantity
+			}
+		}
+
+		if quantity < p.MinQuantity {
+			return fmt.Errorf("promotion %q requires %d items: %w", p.label(), p.MinQuantity, ErrCouponNotApplicable)
+		}
+	}
+
+	if p.MinSubtotal.Amount <= 0 && p.Amount.Currency == "" {
+		return nil
+	}
+
+	t, err := CartTotals(c)
+	if err != nil {
+		return err
+	}
+
+	// A discount in another currency could not be totaled with the cart.
+	if p.Amount.Currency != "" && p.Amount.Currency != t.Subtotal.Currency {
+		return fmt.Errorf("promotion %q is in %s: %w", p.label(), p.Amount.Currency, ErrCouponNotApplicable)
+	}
+
+	if p.MinSubtotal.Amount > 0 && (t.Subtotal.Currency != p.MinSubtotal.Currency || t.Subtotal.Amount < p.MinSubtotal.Amount) {
+		return fmt.Errorf("promotion %q requires a subtotal of %d %s: %w",
+			p.label(), p.MinSubtotal.Amount, p.MinSubtotal.Currency, ErrCouponNotApplicable)
+	}
+
+	return nil
+}
+
+// inTimeRange reports whether tm is within [from, to), zero bounds are ignored.
+func inTimeRange(tm, from, to time.Time) bool {
+	return (from.IsZero() || !tm.Before(from)) && (to.IsZero() || tm.Before(to))
+}
+
+// apply adds discounts of the promotion to the cart and its items. Unpriced items are not
+// discounted.
+func (p *Promotion) apply(c *Cart) error {
+	discount := func(amount Money) Adjustment {
+		return Adjustment{Kind: AdjustmentDiscount, Label: p.label(), Amount: amount}
+	}
+
+	switch p.Kind {
+	case PromotionPercentOff:
+		if len(p.ProductIDs) == 0 && p.Category == "" {
+			t, err := CartTotals(c)
+			if err != nil {
+				return err
+			}
+
+			amount, err := percentOf(t.Subtotal, p.Percent)
+			if err != nil {
+				return err
+			}
+
+			c.Adjustments = append(c.Adjustments, discount(amount))
+			return nil
+		}
+
+		for _, i := range c.LineItems {
+			if !p.discounts(i) {
+				continue
+			}
+
+			total, err := i.UnitPrice.mul(i.Quantity)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i.ID, err)
+			}
+
+			amount, err := percentOf(total, p.Percent)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i.ID, err)
+			}
+
+			i.Adjustments = append(i.Adjustments, discount(amount))
+		}
+
+	case PromotionAmountOff:
+		c.Adjustments = append(c.Adjustments, discount(p.Amount))
+
+	case PromotionBuyXGetY:
+		for _, i := range c.LineItems {
+			free := i.Quantity / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+			if free == 0 || !p.discounts(i) {
+				continue
+			}
+
+			amount, err := i.UnitPrice.mul(free)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i.ID, err)
+			}
+
+			i.Adjustments = append(i.Adjustments, discount(amount))
+		}
+
+	case PromotionFreeShipping:
+		var (
+			amount Money
+			err    error
+		)
+		for _, a := range c.Adjustments {
+			if a.Kind != AdjustmentShipping {
+				continue
+			}
+			if amount, err = amount.add(a.Amount); err != nil {
+				return err
+			}
+		}
+
+		d := discount(amount)
+		d.Shipping = true
+		c.Adjustments = append(c.Adjustments, d)
+
+	case PromotionTiered:
+		for _, i := range c.LineItems {
+			var percent int64
+			for _, t := range p.Tiers {
+				if i.Quantity >= t.MinQuantity {
+					percent = t.Percent
+				}
+			}
+			if percent == 0 || !p.discounts(i) {
+				continue
+			}
+
+			total, err := i.UnitPrice.mul(i.Quantity)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i.ID, err)
+			}
+
+			amount, err := percentOf(total, percent)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i.ID, err)
+			}
+
+			i.Adjustments = append(i.Adjustments, discount(amount))
+		}
+
+	default:
+		return fmt.Errorf("kind %q: %w", p.Kind, ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+// discounts reports whether the promotion discounts the item.
+func (p *Promotion) discounts(i *LineItem) bool {
+	return i.UnitPrice.Currency != "" && p.matches(i)
+}
+
+// matches reports whether the item is of the products or the category of the promotion.
+func (p *Promotion) matches(i *LineItem) bool {
+	if p.Category != "" && p.Category != i.Category {
+		return false
+	}
+
+	if len(p.ProductIDs) == 0 {
+		return true
+	}
+	for _, id := range p.ProductIDs {
+		if id == i.ProductID {
+			return true
+		}
+	}
+	return false
+}
+
+// percentOf returns percent of m rounded down.
+func percentOf(m Money, percent int64) (Money, error) {
+	amount, err := m.mul(percent)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount.Amount /= 100
+	return amount, nil
+}
+
+// jsonPromotion is the JSON form of a promotion, amounts are in minor units of the currency.
+type jsonPromotion struct {
+	Code        string        `json:"code"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Kind        PromotionKind `json:"kind"`
+	Percent     int64         `json:"percent"`
+	Amount      int64         `json:"amount"`
+	Currency    string        `json:"currency"`
+	BuyQuantity int64         `json:"buy_quantity"`
+	GetQuantity int64         `json:"get_quantity"`
+	Tiers       []struct {
+		MinQuantity int64 `json:"min_quantity"`
+		Percent     int64 `json:"percent"`
+	} `json:"tiers"`
+	ProductIDs     []int64   `json:"product_ids"`
+	Category       string    `json:"category"`
+	MinQuantity    int64     `json:"min_quantity"`
+	MinSubtotal    int64     `json:"min_subtotal"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	MaxUsesPerUser int       `json:"max_uses_per_user"`
+}
+
+// promotion returns the valid promotion of p.
+func (p *jsonPromotion) promotion() (*Promotion, error) {
+	money := func(amount int64) Money {
+		if amount == 0 {
+			return Money{}
+		}
+		return Money{Amount: amount, Currency: p.Currency}
+	}
+
+	promotion := &Promotion{
+		Code:           normalizeCouponCode(p.Code),
+		Name:           p.Name,
+		Description:    p.Description,
+		Kind:           p.Kind,
+		Percent:        p.Percent,
+		Amount:         money(p.Amount),
+		BuyQuantity:    p.BuyQuantity,
+		GetQuantity:    p.GetQuantity,
+		ProductIDs:     p.ProductIDs,
+		Category:       p.Category,
+		MinQuantity:    p.MinQuantity,
+		MinSubtotal:    money(p.MinSubtotal),
+		StartsAt:       p.StartsAt,
+		EndsAt:         p.EndsAt,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+	}
+	for _, t := range p.Tiers {
+		promotion.Tiers = append(promotion.Tiers, PromotionTier{MinQuantity: t.MinQuantity, Percent: t.Percent})
+	}
+
+	if err := promotion.validate(); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// JSONPromotions are promotions loaded from a JSON file.
+type JSONPromotions struct {
+	promotions map[string]*Promotion
+}
+
+// LoadJSONPromotions reads promotions from a JSON file of the form
+// [{"code": "WELCOME10", "kind": "percent_off", "percent": 10, "min_subtotal": 2000, "currency": "EUR",
+// "starts_at": "2020-01-01T00:00:00Z", "ends_at": "2021-01-01T00:00:00Z", "max_uses_per_user": 1}].
+// Amounts are in minor units of the currency.
+func LoadJSONPromotions(path string) (*JSONPromotions, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pp []jsonPromotion
+	if err := json.Unmarshal(b, &pp); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	ps := &JSONPromotions{promotions: make(map[string]*Promotion, len(pp))}
+	for j, p := range pp {
+		if p.Code == "" {
+			return nil, fmt.Errorf("promotion %d: code is empty: %w", j, ErrInvalidArgument)
+		}
+
+		promotion, err := p.promotion()
+		if err != nil {
+			return nil, fmt.Errorf("promotion %d: %w", j, err)
+		}
+
+		ps.promotions[promotion.Code] = promotion
+	}
+	return ps, nil
+}
+
+func (ps *JSONPromotions) PromotionByCode(ctx context.Context, code string) (*Promotion, error) {
+	p, ok := ps.promotions[normalizeCouponCode(code)]
+	if !ok {
+		return nil, fmt.Errorf("coupon %q: %w", code, ErrCouponNotFound)
+	}
+
+	cp := *p
+	return &cp, nil
+}
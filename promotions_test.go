@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPromotion_apply(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+	discount := func(code string, amount int64) []Adjustment {
+		return []Adjustment{{Kind: AdjustmentDiscount, Label: code, Amount: eur(amount)}}
+	}
+
+	tests := []struct {
+		name string
+		p    Promotion
+		exp  Cart // exp discounts of the cart and its items
+	}{
+		{
+			"percent off subtotal",
+			Promotion{Code: "P10", Kind: PromotionPercentOff, Percent: 10},
+			Cart{Adjustments: discount("P10", 449)},
+		},
+		{
+			"percent off products",
+			Promotion{Code: "P10", Kind: PromotionPercentOff, Percent: 10, ProductIDs: []int64{2}},
+			Cart{LineItems: []*LineItem{nil, {Adjustments: discount("P10", 300)}, nil}},
+		},
+		{
+			"percent off category",
+			Promotion{Name: "Tea", Kind: PromotionPercentOff, Percent: 10, Category: "tea"},
+			Cart{LineItems: []*LineItem{{Adjustments: discount("Tea", 149)}, nil, nil}},
+		},
+		{
+			"amount off",
+			Promotion{Code: "A5", Kind: PromotionAmountOff, Amount: eur(500)},
+			Cart{Adjustments: discount("A5", 500)},
+		},
+		{
+			"buy 2 get 1",
+			Promotion{Code: "B2G1", Kind: PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+			Cart{LineItems: []*LineItem{{Adjustments: discount("B2G1", 499)}, nil, nil}},
+		},
+		{
+			"free shipping",
+			Promotion{Code: "SHIP", Kind: PromotionFreeShipping},
+			Cart{Adjustments: []Adjustment{{Kind: AdjustmentShipping, Amount: eur(490)}, {Kind: AdjustmentDiscount, Label: "SHIP", Amount: eur(490), Shipping: true}}},
+		},
+		{
+			"tiered",
+			Promotion{Name: "Volume", Kind: PromotionTiered, Tiers: []PromotionTier{{MinQuantity: 2, Percent: 5}, {MinQuantity: 3, Percent: 10}}},
+			Cart{LineItems: []*LineItem{{Adjustments: discount("Volume", 149)}, {Adjustments: discount("Volume", 150)}, nil}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Cart{
+				LineItems: []*LineItem{
+					{ProductID: 1, Category: "tea", Quantity: 3, UnitPrice: eur(499)},
+					{ProductID: 2, Quantity: 2, UnitPrice: eur(1500)},
+					// Unpriced items are not discounted.
+					{ProductID: 3, Quantity: 9},
+				},
+			}
+			if tt.p.Kind == PromotionFreeShipping {
+				c.Adjustments = []Adjustment{{Kind: AdjustmentShipping, Amount: eur(490)}}
+			}
+
+			if err := tt.p.apply(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tt.exp.Adjustments, c.Adjustments) {
+				t.Errorf("cart discounts do not match\nexp: %+v\ngot: %+v", tt.exp.Adjustments, c.Adjustments)
+			}
+
+			for j, i := range c.LineItems {
+				var exp []Adjustment
+				if j < len(tt.exp.LineItems) && tt.exp.LineItems[j] != nil {
+					exp = tt.exp.LineItems[j].Adjustments
+				}
+
+				if !reflect.DeepEqual(exp, i.Adjustments) {
+					t.Errorf("item %d discounts do not match\nexp: %+v\ngot: %+v", j, exp, i.Adjustments)
+				}
+			}
+		})
+	}
+}
+
+func TestPromotion_applicable(t *testing.T) {
+	tm := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := &Cart{LineItems: []*LineItem{{ProductID: 1, Quantity: 2, UnitPrice: Money{Amount: 1000, Currency: "EUR"}}}}
+
+	tests := []struct {
+		name string
+		p    Promotion
+		err  error
+	}{
+		{"no restrictions", Promotion{}, nil},
+		{"within window", Promotion{StartsAt: tm.Add(-time.Hour), EndsAt: tm.Add(time.Hour)}, nil},
+		{"not started", Promotion{StartsAt: tm.Add(time.Hour)}, ErrCouponNotApplicable},
+		{"expired", Promotion{EndsAt: tm}, ErrCouponNotApplicable},
+		{"min subtotal reached", Promotion{MinSubtotal: Money{Amount: 2000, Currency: "EUR"}}, nil},
+		{"min subtotal not reached", Promotion{MinSubtotal: Money{Amount: 2001, Currency: "EUR"}}, ErrCouponNotApplicable},
+		{"min quantity reached", Promotion{MinQuantity: 2}, nil},
+		{"min quantity not reached", Promotion{MinQuantity: 3}, ErrCouponNotApplicable},
+		{"min quantity of category", Promotion{MinQuantity: 1, Category: "tea"}, ErrCouponNotApplicable},
+		{"min subtotal in other currency", Promotion{MinSubtotal: Money{Amount: 100, Currency: "USD"}}, ErrCouponNotApplicable},
+		{"amount off", Promotion{Kind: PromotionAmountOff, Amount: Money{Amount: 500, Currency: "EUR"}}, nil},
+		{"amount off in other currency", Promotion{Kind: PromotionAmountOff, Amount: Money{Amount: 500, Currency: "USD"}}, ErrCouponNotApplicable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.applicable(c, tm)
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Errorf("err exp: %v, got: %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestLoadJSONPromotions(t *testing.T) {
+	path := writeTestFile(t, "promotions.json", `[
+		{"code":"welcome10","kind":"percent_off","percent":10,"min_subtotal":2000,"currency":"EUR","ends_at":"2021-01-01T00:00:00Z","max_uses_per_user":1},
+		{"code":"B2G1","kind":"buy_x_get_y","buy_quantity":2,"get_quantity":1,"product_ids":[1,2]}
+	]`)
+
+	ps, err := LoadJSONPromotions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ps.PromotionByCode(context.Background(), " Welcome10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &Promotion{
+		Code:           "WELCOME10",
+		Kind:           PromotionPercentOff,
+		Percent:        10,
+		MinSubtotal:    Money{Amount: 2000, Currency: "EUR"},
+		EndsAt:         time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaxUsesPerUser: 1,
+	}
+	if !reflect.DeepEqual(exp, p) {
+		t.Errorf("promotions do not match\nexp: %+v\ngot: %+v", exp, p)
+	}
+
+	if _, err := ps.PromotionByCode(context.Background(), "UNKNOWN"); !errors.Is(err, ErrCouponNotFound) {
+		t.Errorf("err exp: %v, got: %v", ErrCouponNotFound, err)
+	}
+
+	for _, invalid := range []string{
+		`[{"code":"P","kind":"percent_off","percent":101}]`,
+		`[{"code":"A","kind":"amount_off","amount":500}]`,
+		`[{"code":"B","kind":"buy_x_get_y","buy_quantity":2}]`,
+		`[{"code":"X","kind":"unknown"}]`,
+		`[{"kind":"free_shipping"}]`,
+		`[{"code":"T","kind":"tiered"}]`,
+		`[{"code":"T","kind":"tiered","tiers":[{"min_quantity":5,"percent":10},{"min_quantity":2,"percent":5}]}]`,
+	} {
+		if _, err := LoadJSONPromotions(writeTestFile(t, "promotions.json", invalid)); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s err exp: %v, got: %v", invalid, ErrInvalidArgument, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Rules list promotions applied to every cart which qualifies for them, without coupons.
+type Rules interface {
+	// Rules returns the rules in the order they are applied in.
+	Rules(ctx context.Context) ([]*Promotion, error)
+}
+
+// FiredRule explains a rule applied to a cart.
+type FiredRule struct {
+	Name        string
+	Description string
+}
+
+// JSONRules are rules loaded from a JSON file.
+type JSONRules struct {
+	rules []*Promotion
+}
+
+// LoadJSONRules reads rules from a JSON file of the form
+// [{"name": "tea-3-10", "description": "10% off 3 or more teas", "kind": "percent_off", "percent": 10,
+// "category": "tea", "min_quantity": 3}], rules are promotions with a name instead of a code,
+// see LoadJSONPromotions.
+func LoadJSONRules(path string) (*JSONRules, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pp []jsonPromotion
+	if err := json.Unmarshal(b, &pp); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	rules := &JSONRules{rules: make([]*Promotion, len(pp))}
+	for j, p := range pp {
+		if rules.rules[j], err = ruleOf(p); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", j, err)
+		}
+	}
+	return rules, nil
+}
+
+func (r *JSONRules) Rules(ctx context.Context) ([]*Promotion, error) {
+	rules := make([]*Promotion, len(r.rules))
+	for j, p := range r.rules {
+		cp := *p
+		rules[j] = &cp
+	}
+	return rules, nil
+}
+
+// SQLite3Rules are rules kept in the promotion_rules table of a SQLite DB, see
+// ./migrations/catalog. Rules are defined in the JSON form of LoadJSONRules.
+type SQLite3Rules struct {
+	db *sql.DB
+}
+
+// NewSQLite3Rules instantiates SQLite3Rules.
+func NewSQLite3Rules(db *sql.DB) *SQLite3Rules {
+	return &SQLite3Rules{db: db}
+}
+
+func (r *SQLite3Rules) Rules(ctx context.Context) ([]*Promotion, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT name, definition
+		FROM promotion_rules
+		WHERE active
+		ORDER BY position, name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("rule query: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*Promotion
+	for rows.Next() {
+		var (
+			name       string
+			definition []byte
+		)
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("rule scan: %w", err)
+		}
+
+		var p jsonPromotion
+		if err := json.Unmarshal(definition, &p); err != nil {
+			return nil, fmt.Errorf("rule %q: json: %w", name, err)
+		}
+		p.Name = name
+
+		rule, err := ruleOf(p)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", name, err)
+		}
+
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rule rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ruleOf returns the rule of a JSON promotion.
+func ruleOf(p jsonPromotion) (*Promotion, error) {
+	switch {
+	case p.Name == "":
+		return nil, fmt.Errorf("name is empty: %w", ErrInvalidArgument)
+	case p.Code != "":
+		return nil, fmt.Errorf("rules have no codes: %w", ErrInvalidArgument)
+	}
+
+	return p.promotion()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoadJSONRules(t *testing.T) {
+	path := writeTestFile(t, "rules.json", `[
+		{"name":"tea-3-10","description":"10% off 3 or more teas","kind":"percent_off","percent":10,"category":"tea","min_quantity":3},
+		{"name":"volume","kind":"tiered","tiers":[{"min_quantity":4,"percent":5},{"min_quantity":10,"percent":10}]}
+	]`)
+
+	r, err := LoadJSONRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := r.Rules(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Promotion{
+		{Name: "tea-3-10", Description: "10% off 3 or more teas", Kind: PromotionPercentOff, Percent: 10, Category: "tea", MinQuantity: 3},
+		{Name: "volume", Kind: PromotionTiered, Tiers: []PromotionTier{{MinQuantity: 4, Percent: 5}, {MinQuantity: 10, Percent: 10}}},
+	}
+	if !reflect.DeepEqual(exp, rules) {
+		t.Errorf("rules do not match\nexp: %+v\ngot: %+v", exp, rules)
+	}
+
+	// Callers get copies.
+	rules[0].Percent = 50
+	if rules, _ := r.Rules(context.Background()); rules[0].Percent != 10 {
+		t.Errorf("percent exp: %d, got: %d", 10, rules[0].Percent)
+	}
+
+	for _, invalid := range []string{
+		`[{"kind":"percent_off","percent":10}]`,
+		`[{"name":"coupon","code":"C","kind":"percent_off","percent":10}]`,
+		`[{"name":"P","kind":"percent_off","percent":101}]`,
+	} {
+		if _, err := LoadJSONRules(writeTestFile(t, "rules.json", invalid)); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s err exp: %v, got: %v", invalid, ErrInvalidArgument, err)
+		}
+	}
+}
+
+func TestSQLite3Rules_Rules(t *testing.T) {
+	db := migrateDB(t, "file:rules?mode=memory&cache=shared", "./migrations/catalog")
+	defer db.Close()
+
+	_, err := db.Exec(
+		`INSERT INTO promotion_rules(name, definition, position, active) VALUES(?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?)`,
+		"tea-3-10", `{"description":"10% off 3 or more teas","kind":"percent_off","percent":10,"category":"tea","min_quantity":3}`, 2, true,
+		"five-off", `{"kind":"amount_off","amount":500,"currency":"EUR","min_subtotal":5000}`, 1, true,
+		"inactive", `{"kind":"free_shipping"}`, 0, false,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := NewSQLite3Rules(db).Rules(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*Promotion{
+		{
+			Name:        "five-off",
+			Kind:        PromotionAmountOff,
+			Amount:      Money{Amount: 500, Currency: "EUR"},
+			MinSubtotal: Money{Amount: 5000, Currency: "EUR"},
+		},
+		{Name: "tea-3-10", Description: "10% off 3 or more teas", Kind: PromotionPercentOff, Percent: 10, Category: "tea", MinQuantity: 3},
+	}
+	if !reflect.DeepEqual(exp, rules) {
+		t.Errorf("rules do not match\nexp: %+v\ngot: %+v", exp, rules)
+	}
+
+	if _, err := db.Exec(`UPDATE promotion_rules SET definition = ? WHERE name = ?`, `{"kind":"unknown"}`, "five-off"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSQLite3Rules(db).Rules(context.Background()); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("err exp: %v, got: %v", ErrInvalidArgument, err)
+	}
+}
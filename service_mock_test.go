@@ -17,35 +17,107 @@ import (
 type ServiceMock struct {
 	t minimock.Tester
 
+	funcActiveCart          func(ctx context.Context, userID int64) (cp1 *Cart, err error)
+	inspectFuncActiveCart   func(ctx context.Context, userID int64)
+	afterActiveCartCounter  uint64
+	beforeActiveCartCounter uint64
+	ActiveCartMock          mServiceMockActiveCart
+
 	funcCartCreate          func(ctx context.Context, userID int64, items []*LineItem) (cp1 *Cart, err error)
 	inspectFuncCartCreate   func(ctx context.Context, userID int64, items []*LineItem)
 	afterCartCreateCounter  uint64
 	beforeCartCreateCounter uint64
 	CartCreateMock          mServiceMockCartCreate
 
-	funcCartEmpty          func(ctx context.Context, cartID int64) (err error)
+	funcCartEmpty          func(ctx context.Context, cartID int64) (i1 int64, err error)
 	inspectFuncCartEmpty   func(ctx context.Context, cartID int64)
 	afterCartEmptyCounter  uint64
 	beforeCartEmptyCounter uint64
 	CartEmptyMock          mServiceMockCartEmpty
 
+	funcCartMerge          func(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy) (cp1 *Cart, err error)
+	inspectFuncCartMerge   func(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy)
+	afterCartMergeCounter  uint64
+	beforeCartMergeCounter uint64
+	CartMergeMock          mServiceMockCartMerge
+
+	funcCartPay          func(ctx context.Context, cartID int64, source string) (pp1 *Payment, b1 bool, err error)
+	inspectFuncCartPay   func(ctx context.Context, cartID int64, source string)
+	afterCartPayCounter  uint64
+	beforeCartPayCounter uint64
+	CartPayMock          mServiceMockCartPay
+
+	funcCartReprice          func(ctx context.Context, cartID int64) (cp1 *Cart, err error)
+	inspectFuncCartReprice   func(ctx context.Context, cartID int64)
+	afterCartRepriceCounter  uint64
+	beforeCartRepriceCounter uint64
+	CartRepriceMock          mServiceMockCartReprice
+
+	funcCartShippingAddressSet          func(ctx context.Context, cartID int64, a *Address) (cp1 *Cart, err error)
+	inspectFuncCartShippingAddressSet   func(ctx context.Context, cartID int64, a *Address)
+	afterCartShippingAddressSetCounter  uint64
+	beforeCartShippingAddressSetCounter uint64
+	CartShippingAddressSetMock          mServiceMockCartShippingAddressSet
+
+	funcCartShippingMethodSet          func(ctx context.Context, cartID int64, code string) (cp1 *Cart, err error)
+	inspectFuncCartShippingMethodSet   func(ctx context.Context, cartID int64, code string)
+	afterCartShippingMethodSetCounter  uint64
+	beforeCartShippingMethodSetCounter uint64
+	CartShippingMethodSetMock          mServiceMockCartShippingMethodSet
+
 	funcCartShow          func(ctx context.Context, cartID int64) (cp1 *Cart, err error)
 	inspectFuncCartShow   func(ctx context.Context, cartID int64)
 	afterCartShowCounter  uint64
 	beforeCartShowCounter uint64
 	CartShowMock          mServiceMockCartShow
 
-	funcLineItemAdd          func(ctx context.Context, cartID int64, items []*LineItem) (lpa1 []*LineItem, err error)
+	funcCartsByUser          func(ctx context.Context, userID int64, q CartsQuery) (cpa1 []*Cart, i1 int64, err error)
+	inspectFuncCartsByUser   func(ctx context.Context, userID int64, q CartsQuery)
+	afterCartsByUserCounter  uint64
+	beforeCartsByUserCounter uint64
+	CartsByUserMock          mServiceMockCartsByUser
+
+	funcCheckout          func(ctx context.Context, cartID int64) (i1 int64, err error)
+	inspectFuncCheckout   func(ctx context.Context, cartID int64)
+	afterCheckoutCounter  uint64
+	beforeCheckoutCounter uint64
+	CheckoutMock          mServiceMockCheckout
+
+	funcCouponApply          func(ctx context.Context, cartID int64, code string) (cp1 *Cart, err error)
+	inspectFuncCouponApply   func(ctx context.Context, cartID int64, code string)
+	afterCouponApplyCounter  uint64
+	beforeCouponApplyCounter uint64
+	CouponApplyMock          mServiceMockCouponApply
+
+	funcCouponRemove          func(ctx context.Context, cartID int64, code string) (i1 int64, err error)
+	inspectFuncCouponRemove   func(ctx context.Context, cartID int64, code string)
+	afterCouponRemoveCounter  uint64
+	beforeCouponRemoveCounter uint64
+	CouponRemoveMock          mServiceMockCouponRemove
+
+	funcLineItemAdd          func(ctx context.Context, cartID int64, items []*LineItem) (lpa1 []*LineItem, i1 int64, err error)
 	inspectFuncLineItemAdd   func(ctx context.Context, cartID int64, items []*LineItem)
 	afterLineItemAddCounter  uint64
 	beforeLineItemAddCounter uint64
 	LineItemAddMock          mServiceMockLineItemAdd
 
-	funcLineItemRemove          func(ctx context.Context, cartID int64, itemID int64) (err error)
+	funcLineItemRemove          func(ctx context.Context, cartID int64, itemID int64) (i1 int64, err error)
 	inspectFuncLineItemRemove   func(ctx context.Context, cartID int64, itemID int64)
 	afterLineItemRemoveCounter  uint64
 	beforeLineItemRemoveCounter uint64
 	LineItemRemoveMock          mServiceMockLineItemRemove
+
+	funcLineItemSetQuantity          func(ctx context.Context, cartID int64, itemID int64, quantity int64) (lp1 *LineItem, i1 int64, err error)
+	inspectFuncLineItemSetQuantity   func(ctx context.Context, cartID int64, itemID int64, quantity int64)
+	afterLineItemSetQuantityCounter  uint64
+	beforeLineItemSetQuantityCounter uint64
+	LineItemSetQuantityMock          mServiceMockLineItemSetQuantity
+
+	funcShippingMethods          func(ctx context.Context, cartID int64) (sa1 []ShippingRate, err error)
+	inspectFuncShippingMethods   func(ctx context.Context, cartID int64)
+	afterShippingMethodsCounter  uint64
+	beforeShippingMethodsCounter uint64
+	ShippingMethodsMock          mServiceMockShippingMethods
 }
 
 // NewServiceMock returns a mock for service
@@ -55,24 +127,277 @@ func NewServiceMock(t minimock.Tester) *ServiceMock {
 		controller.RegisterMocker(m)
 	}
 
+	m.ActiveCartMock = mServiceMockActiveCart{mock: m}
+	m.ActiveCartMock.callArgs = []*ServiceMockActiveCartParams{}
+
 	m.CartCreateMock = mServiceMockCartCreate{mock: m}
 	m.CartCreateMock.callArgs = []*ServiceMockCartCreateParams{}
 
 	m.CartEmptyMock = mServiceMockCartEmpty{mock: m}
 	m.CartEmptyMock.callArgs = []*ServiceMockCartEmptyParams{}
 
+	m.CartMergeMock = mServiceMockCartMerge{mock: m}
+	m.CartMergeMock.callArgs = []*ServiceMockCartMergeParams{}
+
+	m.CartPayMock = mServiceMockCartPay{mock: m}
+	m.CartPayMock.callArgs = []*ServiceMockCartPayParams{}
+
+	m.CartRepriceMock = mServiceMockCartReprice{mock: m}
+	m.CartRepriceMock.callArgs = []*ServiceMockCartRepriceParams{}
+
+	m.CartShippingAddressSetMock = mServiceMockCartShippingAddressSet{mock: m}
+	m.CartShippingAddressSetMock.callArgs = []*ServiceMockCartShippingAddressSetParams{}
+
+	m.CartShippingMethodSetMock = mServiceMockCartShippingMethodSet{mock: m}
+	m.CartShippingMethodSetMock.callArgs = []*ServiceMockCartShippingMethodSetParams{}
+
 	m.CartShowMock = mServiceMockCartShow{mock: m}
 	m.CartShowMock.callArgs = []*ServiceMockCartShowParams{}
 
+	m.CartsByUserMock = mServiceMockCartsByUser{mock: m}
+	m.CartsByUserMock.callArgs = []*ServiceMockCartsByUserParams{}
+
+	m.CheckoutMock = mServiceMockCheckout{mock: m}
+	m.CheckoutMock.callArgs = []*ServiceMockCheckoutParams{}
+
+	m.CouponApplyMock = mServiceMockCouponApply{mock: m}
+	m.CouponApplyMock.callArgs = []*ServiceMockCouponApplyParams{}
+
+	m.CouponRemoveMock = mServiceMockCouponRemove{mock: m}
+	m.CouponRemoveMock.callArgs = []*ServiceMockCouponRemoveParams{}
+
 	m.LineItemAddMock = mServiceMockLineItemAdd{mock: m}
 	m.LineItemAddMock.callArgs = []*ServiceMockLineItemAddParams{}
 
 	m.LineItemRemoveMock = mServiceMockLineItemRemove{mock: m}
 	m.LineItemRemoveMock.callArgs = []*ServiceMockLineItemRemoveParams{}
 
+	m.LineItemSetQuantityMock = mServiceMockLineItemSetQuantity{mock: m}
+	m.LineItemSetQuantityMock.callArgs = []*ServiceMockLineItemSetQuantityParams{}
+
+	m.ShippingMethodsMock = mServiceMockShippingMethods{mock: m}
+	m.ShippingMethodsMock.callArgs = []*ServiceMockShippingMethodsParams{}
+
 	return m
 }
 
+type mServiceMockActiveCart struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockActiveCartExpectation
+	expectations       []*ServiceMockActiveCartExpectation
+
+	callArgs []*ServiceMockActiveCartParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockActiveCartExpectation specifies expectation struct of the service.ActiveCart
+type ServiceMockActiveCartExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockActiveCartParams
+	results *ServiceMockActiveCartResults
+	Counter uint64
+}
+
+// ServiceMockActiveCartParams contains parameters of the service.ActiveCart
+type ServiceMockActiveCartParams struct {
+	ctx    context.Context
+	userID int64
+}
+
+// ServiceMockActiveCartResults contains results of the service.ActiveCart
+type ServiceMockActiveCartResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for service.ActiveCart
+func (mmActiveCart *mServiceMockActiveCart) Expect(ctx context.Context, userID int64) *mServiceMockActiveCart {
+	if mmActiveCart.mock.funcActiveCart != nil {
+		mmActiveCart.mock.t.Fatalf("ServiceMock.ActiveCart mock is already set by Set")
+	}
+
+	if mmActiveCart.defaultExpectation == nil {
+		mmActiveCart.defaultExpectation = &ServiceMockActiveCartExpectation{}
+	}
+
+	mmActiveCart.defaultExpectation.params = &ServiceMockActiveCartParams{ctx, userID}
+	for _, e := range mmActiveCart.expectations {
+		if minimock.Equal(e.params, mmActiveCart.defaultExpectation.params) {
+			mmActiveCart.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmActiveCart.defaultExpectation.params)
+		}
+	}
+
+	return mmActiveCart
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.ActiveCart
+func (mmActiveCart *mServiceMockActiveCart) Inspect(f func(ctx context.Context, userID int64)) *mServiceMockActiveCart {
+	if mmActiveCart.mock.inspectFuncActiveCart != nil {
+		mmActiveCart.mock.t.Fatalf("Inspect function is already set for ServiceMock.ActiveCart")
+	}
+
+	mmActiveCart.mock.inspectFuncActiveCart = f
+
+	return mmActiveCart
+}
+
+// Return sets up results that will be returned by service.ActiveCart
+func (mmActiveCart *mServiceMockActiveCart) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmActiveCart.mock.funcActiveCart != nil {
+		mmActiveCart.mock.t.Fatalf("ServiceMock.ActiveCart mock is already set by Set")
+	}
+
+	if mmActiveCart.defaultExpectation == nil {
+		mmActiveCart.defaultExpectation = &ServiceMockActiveCartExpectation{mock: mmActiveCart.mock}
+	}
+	mmActiveCart.defaultExpectation.results = &ServiceMockActiveCartResults{cp1, err}
+	return mmActiveCart.mock
+}
+
+//Set uses given function f to mock the service.ActiveCart method
+func (mmActiveCart *mServiceMockActiveCart) Set(f func(ctx context.Context, userID int64) (cp1 *Cart, err error)) *ServiceMock {
+	if mmActiveCart.defaultExpectation != nil {
+		mmActiveCart.mock.t.Fatalf("Default expectation is already set for the service.ActiveCart method")
+	}
+
+	if len(mmActiveCart.expectations) > 0 {
+		mmActiveCart.mock.t.Fatalf("Some expectations are already set for the service.ActiveCart method")
+	}
+
+	mmActiveCart.mock.funcActiveCart = f
+	return mmActiveCart.mock
+}
+
+// When sets expectation for the service.ActiveCart which will trigger the result defined by the following
+// Then helper
+func (mmActiveCart *mServiceMockActiveCart) When(ctx context.Context, userID int64) *ServiceMockActiveCartExpectation {
+	if mmActiveCart.mock.funcActiveCart != nil {
+		mmActiveCart.mock.t.Fatalf("ServiceMock.ActiveCart mock is already set by Set")
+	}
+
+	expectation := &ServiceMockActiveCartExpectation{
+		mock:   mmActiveCart.mock,
+		params: &ServiceMockActiveCartParams{ctx, userID},
+	}
+	mmActiveCart.expectations = append(mmActiveCart.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.ActiveCart return parameters for the expectation previously defined by the When method
+func (e *ServiceMockActiveCartExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockActiveCartResults{cp1, err}
+	return e.mock
+}
+
+// ActiveCart implements service
+func (mmActiveCart *ServiceMock) ActiveCart(ctx context.Context, userID int64) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmActiveCart.beforeActiveCartCounter, 1)
+	defer mm_atomic.AddUint64(&mmActiveCart.afterActiveCartCounter, 1)
+
+	if mmActiveCart.inspectFuncActiveCart != nil {
+		mmActiveCart.inspectFuncActiveCart(ctx, userID)
+	}
+
+	mm_params := &ServiceMockActiveCartParams{ctx, userID}
+
+	// Record call args
+	mmActiveCart.ActiveCartMock.mutex.Lock()
+	mmActiveCart.ActiveCartMock.callArgs = append(mmActiveCart.ActiveCartMock.callArgs, mm_params)
+	mmActiveCart.ActiveCartMock.mutex.Unlock()
+
+	for _, e := range mmActiveCart.ActiveCartMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmActiveCart.ActiveCartMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmActiveCart.ActiveCartMock.defaultExpectation.Counter, 1)
+		mm_want := mmActiveCart.ActiveCartMock.defaultExpectation.params
+		mm_got := ServiceMockActiveCartParams{ctx, userID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmActiveCart.t.Errorf("ServiceMock.ActiveCart got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmActiveCart.ActiveCartMock.defaultExpectation.results
+		if mm_results == nil {
+			mmActiveCart.t.Fatal("No results are set for the ServiceMock.ActiveCart")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmActiveCart.funcActiveCart != nil {
+		return mmActiveCart.funcActiveCart(ctx, userID)
+	}
+	mmActiveCart.t.Fatalf("Unexpected call to ServiceMock.ActiveCart. %v %v", ctx, userID)
+	return
+}
+
+// ActiveCartAfterCounter returns a count of finished ServiceMock.ActiveCart invocations
+func (mmActiveCart *ServiceMock) ActiveCartAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmActiveCart.afterActiveCartCounter)
+}
+
+// ActiveCartBeforeCounter returns a count of ServiceMock.ActiveCart invocations
+func (mmActiveCart *ServiceMock) ActiveCartBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmActiveCart.beforeActiveCartCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.ActiveCart.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmActiveCart *mServiceMockActiveCart) Calls() []*ServiceMockActiveCartParams {
+	mmActiveCart.mutex.RLock()
+
+	argCopy := make([]*ServiceMockActiveCartParams, len(mmActiveCart.callArgs))
+	copy(argCopy, mmActiveCart.callArgs)
+
+	mmActiveCart.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockActiveCartDone returns true if the count of the ActiveCart invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockActiveCartDone() bool {
+	for _, e := range m.ActiveCartMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ActiveCartMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterActiveCartCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcActiveCart != nil && mm_atomic.LoadUint64(&m.afterActiveCartCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockActiveCartInspect logs each unmet expectation
+func (m *ServiceMock) MinimockActiveCartInspect() {
+	for _, e := range m.ActiveCartMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.ActiveCart with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ActiveCartMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterActiveCartCounter) < 1 {
+		if m.ActiveCartMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.ActiveCart")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.ActiveCart with params: %#v", *m.ActiveCartMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcActiveCart != nil && mm_atomic.LoadUint64(&m.afterActiveCartCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.ActiveCart")
+	}
+}
+
 type mServiceMockCartCreate struct {
 	mock               *ServiceMock
 	defaultExpectation *ServiceMockCartCreateExpectation
@@ -316,6 +641,7 @@ type ServiceMockCartEmptyParams struct {
 
 // ServiceMockCartEmptyResults contains results of the service.CartEmpty
 type ServiceMockCartEmptyResults struct {
+	i1  int64
 	err error
 }
 
@@ -351,7 +677,7 @@ func (mmCartEmpty *mServiceMockCartEmpty) Inspect(f func(ctx context.Context, ca
 }
 
 // Return sets up results that will be returned by service.CartEmpty
-func (mmCartEmpty *mServiceMockCartEmpty) Return(err error) *ServiceMock {
+func (mmCartEmpty *mServiceMockCartEmpty) Return(i1 int64, err error) *ServiceMock {
 	if mmCartEmpty.mock.funcCartEmpty != nil {
 		mmCartEmpty.mock.t.Fatalf("ServiceMock.CartEmpty mock is already set by Set")
 	}
@@ -359,12 +685,12 @@ func (mmCartEmpty *mServiceMockCartEmpty) Return(err error) *ServiceMock {
 	if mmCartEmpty.defaultExpectation == nil {
 		mmCartEmpty.defaultExpectation = &ServiceMockCartEmptyExpectation{mock: mmCartEmpty.mock}
 	}
-	mmCartEmpty.defaultExpectation.results = &ServiceMockCartEmptyResults{err}
+	mmCartEmpty.defaultExpectation.results = &ServiceMockCartEmptyResults{i1, err}
 	return mmCartEmpty.mock
 }
 
 //Set uses given function f to mock the service.CartEmpty method
-func (mmCartEmpty *mServiceMockCartEmpty) Set(f func(ctx context.Context, cartID int64) (err error)) *ServiceMock {
+func (mmCartEmpty *mServiceMockCartEmpty) Set(f func(ctx context.Context, cartID int64) (i1 int64, err error)) *ServiceMock {
 	if mmCartEmpty.defaultExpectation != nil {
 		mmCartEmpty.mock.t.Fatalf("Default expectation is already set for the service.CartEmpty method")
 	}
@@ -393,13 +719,13 @@ func (mmCartEmpty *mServiceMockCartEmpty) When(ctx context.Context, cartID int64
 }
 
 // Then sets up service.CartEmpty return parameters for the expectation previously defined by the When method
-func (e *ServiceMockCartEmptyExpectation) Then(err error) *ServiceMock {
-	e.results = &ServiceMockCartEmptyResults{err}
+func (e *ServiceMockCartEmptyExpectation) Then(i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockCartEmptyResults{i1, err}
 	return e.mock
 }
 
 // CartEmpty implements service
-func (mmCartEmpty *ServiceMock) CartEmpty(ctx context.Context, cartID int64) (err error) {
+func (mmCartEmpty *ServiceMock) CartEmpty(ctx context.Context, cartID int64) (i1 int64, err error) {
 	mm_atomic.AddUint64(&mmCartEmpty.beforeCartEmptyCounter, 1)
 	defer mm_atomic.AddUint64(&mmCartEmpty.afterCartEmptyCounter, 1)
 
@@ -417,7 +743,7 @@ func (mmCartEmpty *ServiceMock) CartEmpty(ctx context.Context, cartID int64) (er
 	for _, e := range mmCartEmpty.CartEmptyMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.err
+			return e.results.i1, e.results.err
 		}
 	}
 
@@ -433,7 +759,7 @@ func (mmCartEmpty *ServiceMock) CartEmpty(ctx context.Context, cartID int64) (er
 		if mm_results == nil {
 			mmCartEmpty.t.Fatal("No results are set for the ServiceMock.CartEmpty")
 		}
-		return (*mm_results).err
+		return (*mm_results).i1, (*mm_results).err
 	}
 	if mmCartEmpty.funcCartEmpty != nil {
 		return mmCartEmpty.funcCartEmpty(ctx, cartID)
@@ -507,260 +833,2224 @@ func (m *ServiceMock) MinimockCartEmptyInspect() {
 	}
 }
 
-type mServiceMockCartShow struct {
+type mServiceMockCartMerge struct {
 	mock               *ServiceMock
-	defaultExpectation *ServiceMockCartShowExpectation
-	expectations       []*ServiceMockCartShowExpectation
+	defaultExpectation *ServiceMockCartMergeExpectation
+	expectations       []*ServiceMockCartMergeExpectation
 
-	callArgs []*ServiceMockCartShowParams
+	callArgs []*ServiceMockCartMergeParams
 	mutex    sync.RWMutex
 }
 
-// ServiceMockCartShowExpectation specifies expectation struct of the service.CartShow
-type ServiceMockCartShowExpectation struct {
+// ServiceMockCartMergeExpectation specifies expectation struct of the service.CartMerge
+type ServiceMockCartMergeExpectation struct {
 	mock    *ServiceMock
-	params  *ServiceMockCartShowParams
-	results *ServiceMockCartShowResults
+	params  *ServiceMockCartMergeParams
+	results *ServiceMockCartMergeResults
 	Counter uint64
 }
 
-// ServiceMockCartShowParams contains parameters of the service.CartShow
-type ServiceMockCartShowParams struct {
-	ctx    context.Context
-	cartID int64
+// ServiceMockCartMergeParams contains parameters of the service.CartMerge
+type ServiceMockCartMergeParams struct {
+	ctx         context.Context
+	cartID      int64
+	guestCartID int64
+	strategy    MergeStrategy
 }
 
-// ServiceMockCartShowResults contains results of the service.CartShow
-type ServiceMockCartShowResults struct {
+// ServiceMockCartMergeResults contains results of the service.CartMerge
+type ServiceMockCartMergeResults struct {
 	cp1 *Cart
 	err error
 }
 
-// Expect sets up expected params for service.CartShow
-func (mmCartShow *mServiceMockCartShow) Expect(ctx context.Context, cartID int64) *mServiceMockCartShow {
-	if mmCartShow.mock.funcCartShow != nil {
-		mmCartShow.mock.t.Fatalf("ServiceMock.CartShow mock is already set by Set")
+// Expect sets up expected params for service.CartMerge
+func (mmCartMerge *mServiceMockCartMerge) Expect(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy) *mServiceMockCartMerge {
+	if mmCartMerge.mock.funcCartMerge != nil {
+		mmCartMerge.mock.t.Fatalf("ServiceMock.CartMerge mock is already set by Set")
 	}
 
-	if mmCartShow.defaultExpectation == nil {
-		mmCartShow.defaultExpectation = &ServiceMockCartShowExpectation{}
+	if mmCartMerge.defaultExpectation == nil {
+		mmCartMerge.defaultExpectation = &ServiceMockCartMergeExpectation{}
 	}
 
-	mmCartShow.defaultExpectation.params = &ServiceMockCartShowParams{ctx, cartID}
-	for _, e := range mmCartShow.expectations {
-		if minimock.Equal(e.params, mmCartShow.defaultExpectation.params) {
-			mmCartShow.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartShow.defaultExpectation.params)
+	mmCartMerge.defaultExpectation.params = &ServiceMockCartMergeParams{ctx, cartID, guestCartID, strategy}
+	for _, e := range mmCartMerge.expectations {
+		if minimock.Equal(e.params, mmCartMerge.defaultExpectation.params) {
+			mmCartMerge.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartMerge.defaultExpectation.params)
 		}
 	}
 
-	return mmCartShow
+	return mmCartMerge
 }
 
-// Inspect accepts an inspector function that has same arguments as the service.CartShow
-func (mmCartShow *mServiceMockCartShow) Inspect(f func(ctx context.Context, cartID int64)) *mServiceMockCartShow {
-	if mmCartShow.mock.inspectFuncCartShow != nil {
-		mmCartShow.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartShow")
+// Inspect accepts an inspector function that has same arguments as the service.CartMerge
+func (mmCartMerge *mServiceMockCartMerge) Inspect(f func(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy)) *mServiceMockCartMerge {
+	if mmCartMerge.mock.inspectFuncCartMerge != nil {
+		mmCartMerge.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartMerge")
 	}
 
-	mmCartShow.mock.inspectFuncCartShow = f
+	mmCartMerge.mock.inspectFuncCartMerge = f
 
-	return mmCartShow
+	return mmCartMerge
 }
 
-// Return sets up results that will be returned by service.CartShow
-func (mmCartShow *mServiceMockCartShow) Return(cp1 *Cart, err error) *ServiceMock {
-	if mmCartShow.mock.funcCartShow != nil {
-		mmCartShow.mock.t.Fatalf("ServiceMock.CartShow mock is already set by Set")
+// Return sets up results that will be returned by service.CartMerge
+func (mmCartMerge *mServiceMockCartMerge) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmCartMerge.mock.funcCartMerge != nil {
+		mmCartMerge.mock.t.Fatalf("ServiceMock.CartMerge mock is already set by Set")
 	}
 
-	if mmCartShow.defaultExpectation == nil {
-		mmCartShow.defaultExpectation = &ServiceMockCartShowExpectation{mock: mmCartShow.mock}
+	if mmCartMerge.defaultExpectation == nil {
+		mmCartMerge.defaultExpectation = &ServiceMockCartMergeExpectation{mock: mmCartMerge.mock}
 	}
-	mmCartShow.defaultExpectation.results = &ServiceMockCartShowResults{cp1, err}
-	return mmCartShow.mock
+	mmCartMerge.defaultExpectation.results = &ServiceMockCartMergeResults{cp1, err}
+	return mmCartMerge.mock
 }
 
-//Set uses given function f to mock the service.CartShow method
-func (mmCartShow *mServiceMockCartShow) Set(f func(ctx context.Context, cartID int64) (cp1 *Cart, err error)) *ServiceMock {
-	if mmCartShow.defaultExpectation != nil {
-		mmCartShow.mock.t.Fatalf("Default expectation is already set for the service.CartShow method")
+//Set uses given function f to mock the service.CartMerge method
+func (mmCartMerge *mServiceMockCartMerge) Set(f func(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy) (cp1 *Cart, err error)) *ServiceMock {
+	if mmCartMerge.defaultExpectation != nil {
+		mmCartMerge.mock.t.Fatalf("Default expectation is already set for the service.CartMerge method")
 	}
 
-	if len(mmCartShow.expectations) > 0 {
-		mmCartShow.mock.t.Fatalf("Some expectations are already set for the service.CartShow method")
+	if len(mmCartMerge.expectations) > 0 {
+		mmCartMerge.mock.t.Fatalf("Some expectations are already set for the service.CartMerge method")
 	}
 
-	mmCartShow.mock.funcCartShow = f
-	return mmCartShow.mock
+	mmCartMerge.mock.funcCartMerge = f
+	return mmCartMerge.mock
 }
 
-// When sets expectation for the service.CartShow which will trigger the result defined by the following
+// When sets expectation for the service.CartMerge which will trigger the result defined by the following
 // Then helper
-func (mmCartShow *mServiceMockCartShow) When(ctx context.Context, cartID int64) *ServiceMockCartShowExpectation {
-	if mmCartShow.mock.funcCartShow != nil {
-		mmCartShow.mock.t.Fatalf("ServiceMock.CartShow mock is already set by Set")
+func (mmCartMerge *mServiceMockCartMerge) When(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy) *ServiceMockCartMergeExpectation {
+	if mmCartMerge.mock.funcCartMerge != nil {
+		mmCartMerge.mock.t.Fatalf("ServiceMock.CartMerge mock is already set by Set")
 	}
 
-	expectation := &ServiceMockCartShowExpectation{
-		mock:   mmCartShow.mock,
-		params: &ServiceMockCartShowParams{ctx, cartID},
+	expectation := &ServiceMockCartMergeExpectation{
+		mock:   mmCartMerge.mock,
+		params: &ServiceMockCartMergeParams{ctx, cartID, guestCartID, strategy},
 	}
-	mmCartShow.expectations = append(mmCartShow.expectations, expectation)
+	mmCartMerge.expectations = append(mmCartMerge.expectations, expectation)
 	return expectation
 }
 
-// Then sets up service.CartShow return parameters for the expectation previously defined by the When method
-func (e *ServiceMockCartShowExpectation) Then(cp1 *Cart, err error) *ServiceMock {
-	e.results = &ServiceMockCartShowResults{cp1, err}
+// Then sets up service.CartMerge return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartMergeExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockCartMergeResults{cp1, err}
 	return e.mock
 }
 
-// CartShow implements service
-func (mmCartShow *ServiceMock) CartShow(ctx context.Context, cartID int64) (cp1 *Cart, err error) {
-	mm_atomic.AddUint64(&mmCartShow.beforeCartShowCounter, 1)
-	defer mm_atomic.AddUint64(&mmCartShow.afterCartShowCounter, 1)
+// CartMerge implements service
+func (mmCartMerge *ServiceMock) CartMerge(ctx context.Context, cartID int64, guestCartID int64, strategy MergeStrategy) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCartMerge.beforeCartMergeCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartMerge.afterCartMergeCounter, 1)
 
-	if mmCartShow.inspectFuncCartShow != nil {
-		mmCartShow.inspectFuncCartShow(ctx, cartID)
+	if mmCartMerge.inspectFuncCartMerge != nil {
+		mmCartMerge.inspectFuncCartMerge(ctx, cartID, guestCartID, strategy)
 	}
 
-	mm_params := &ServiceMockCartShowParams{ctx, cartID}
+	mm_params := &ServiceMockCartMergeParams{ctx, cartID, guestCartID, strategy}
 
 	// Record call args
-	mmCartShow.CartShowMock.mutex.Lock()
-	mmCartShow.CartShowMock.callArgs = append(mmCartShow.CartShowMock.callArgs, mm_params)
-	mmCartShow.CartShowMock.mutex.Unlock()
+	mmCartMerge.CartMergeMock.mutex.Lock()
+	mmCartMerge.CartMergeMock.callArgs = append(mmCartMerge.CartMergeMock.callArgs, mm_params)
+	mmCartMerge.CartMergeMock.mutex.Unlock()
 
-	for _, e := range mmCartShow.CartShowMock.expectations {
+	for _, e := range mmCartMerge.CartMergeMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.cp1, e.results.err
 		}
 	}
 
-	if mmCartShow.CartShowMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmCartShow.CartShowMock.defaultExpectation.Counter, 1)
-		mm_want := mmCartShow.CartShowMock.defaultExpectation.params
-		mm_got := ServiceMockCartShowParams{ctx, cartID}
+	if mmCartMerge.CartMergeMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartMerge.CartMergeMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartMerge.CartMergeMock.defaultExpectation.params
+		mm_got := ServiceMockCartMergeParams{ctx, cartID, guestCartID, strategy}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmCartShow.t.Errorf("ServiceMock.CartShow got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmCartMerge.t.Errorf("ServiceMock.CartMerge got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmCartShow.CartShowMock.defaultExpectation.results
+		mm_results := mmCartMerge.CartMergeMock.defaultExpectation.results
 		if mm_results == nil {
-			mmCartShow.t.Fatal("No results are set for the ServiceMock.CartShow")
+			mmCartMerge.t.Fatal("No results are set for the ServiceMock.CartMerge")
 		}
 		return (*mm_results).cp1, (*mm_results).err
 	}
-	if mmCartShow.funcCartShow != nil {
-		return mmCartShow.funcCartShow(ctx, cartID)
+	if mmCartMerge.funcCartMerge != nil {
+		return mmCartMerge.funcCartMerge(ctx, cartID, guestCartID, strategy)
 	}
-	mmCartShow.t.Fatalf("Unexpected call to ServiceMock.CartShow. %v %v", ctx, cartID)
+	mmCartMerge.t.Fatalf("Unexpected call to ServiceMock.CartMerge. %v %v %v %v", ctx, cartID, guestCartID, strategy)
 	return
 }
 
-// CartShowAfterCounter returns a count of finished ServiceMock.CartShow invocations
-func (mmCartShow *ServiceMock) CartShowAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartShow.afterCartShowCounter)
+// CartMergeAfterCounter returns a count of finished ServiceMock.CartMerge invocations
+func (mmCartMerge *ServiceMock) CartMergeAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartMerge.afterCartMergeCounter)
 }
 
-// CartShowBeforeCounter returns a count of ServiceMock.CartShow invocations
-func (mmCartShow *ServiceMock) CartShowBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartShow.beforeCartShowCounter)
+// CartMergeBeforeCounter returns a count of ServiceMock.CartMerge invocations
+func (mmCartMerge *ServiceMock) CartMergeBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartMerge.beforeCartMergeCounter)
 }
 
-// Calls returns a list of arguments used in each call to ServiceMock.CartShow.
+// Calls returns a list of arguments used in each call to ServiceMock.CartMerge.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmCartShow *mServiceMockCartShow) Calls() []*ServiceMockCartShowParams {
-	mmCartShow.mutex.RLock()
+func (mmCartMerge *mServiceMockCartMerge) Calls() []*ServiceMockCartMergeParams {
+	mmCartMerge.mutex.RLock()
 
-	argCopy := make([]*ServiceMockCartShowParams, len(mmCartShow.callArgs))
-	copy(argCopy, mmCartShow.callArgs)
+	argCopy := make([]*ServiceMockCartMergeParams, len(mmCartMerge.callArgs))
+	copy(argCopy, mmCartMerge.callArgs)
 
-	mmCartShow.mutex.RUnlock()
+	mmCartMerge.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockCartShowDone returns true if the count of the CartShow invocations corresponds
+// MinimockCartMergeDone returns true if the count of the CartMerge invocations corresponds
 // the number of defined expectations
-func (m *ServiceMock) MinimockCartShowDone() bool {
-	for _, e := range m.CartShowMock.expectations {
+func (m *ServiceMock) MinimockCartMergeDone() bool {
+	for _, e := range m.CartMergeMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartShowMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
+	if m.CartMergeMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartMergeCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartShow != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
+	if m.funcCartMerge != nil && mm_atomic.LoadUint64(&m.afterCartMergeCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockCartShowInspect logs each unmet expectation
-func (m *ServiceMock) MinimockCartShowInspect() {
-	for _, e := range m.CartShowMock.expectations {
+// MinimockCartMergeInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartMergeInspect() {
+	for _, e := range m.CartMergeMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to ServiceMock.CartShow with params: %#v", *e.params)
+			m.t.Errorf("Expected call to ServiceMock.CartMerge with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartShowMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
-		if m.CartShowMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to ServiceMock.CartShow")
+	if m.CartMergeMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartMergeCounter) < 1 {
+		if m.CartMergeMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartMerge")
 		} else {
-			m.t.Errorf("Expected call to ServiceMock.CartShow with params: %#v", *m.CartShowMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to ServiceMock.CartMerge with params: %#v", *m.CartMergeMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartShow != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
-		m.t.Error("Expected call to ServiceMock.CartShow")
+	if m.funcCartMerge != nil && mm_atomic.LoadUint64(&m.afterCartMergeCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartMerge")
 	}
 }
 
-type mServiceMockLineItemAdd struct {
+type mServiceMockCartPay struct {
 	mock               *ServiceMock
-	defaultExpectation *ServiceMockLineItemAddExpectation
-	expectations       []*ServiceMockLineItemAddExpectation
+	defaultExpectation *ServiceMockCartPayExpectation
+	expectations       []*ServiceMockCartPayExpectation
 
-	callArgs []*ServiceMockLineItemAddParams
+	callArgs []*ServiceMockCartPayParams
 	mutex    sync.RWMutex
 }
 
-// ServiceMockLineItemAddExpectation specifies expectation struct of the service.LineItemAdd
-type ServiceMockLineItemAddExpectation struct {
+// ServiceMockCartPayExpectation specifies expectation struct of the service.CartPay
+type ServiceMockCartPayExpectation struct {
 	mock    *ServiceMock
-	params  *ServiceMockLineItemAddParams
-	results *ServiceMockLineItemAddResults
+	params  *ServiceMockCartPayParams
+	results *ServiceMockCartPayResults
 	Counter uint64
 }
 
-// ServiceMockLineItemAddParams contains parameters of the service.LineItemAdd
-type ServiceMockLineItemAddParams struct {
+// ServiceMockCartPayParams contains parameters of the service.CartPay
+type ServiceMockCartPayParams struct {
 	ctx    context.Context
 	cartID int64
-	items  []*LineItem
+	source string
 }
 
-// ServiceMockLineItemAddResults contains results of the service.LineItemAdd
-type ServiceMockLineItemAddResults struct {
-	lpa1 []*LineItem
-	err  error
+// ServiceMockCartPayResults contains results of the service.CartPay
+type ServiceMockCartPayResults struct {
+	pp1 *Payment
+	b1  bool
+	err error
 }
 
-// Expect sets up expected params for service.LineItemAdd
-func (mmLineItemAdd *mServiceMockLineItemAdd) Expect(ctx context.Context, cartID int64, items []*LineItem) *mServiceMockLineItemAdd {
-	if mmLineItemAdd.mock.funcLineItemAdd != nil {
-		mmLineItemAdd.mock.t.Fatalf("ServiceMock.LineItemAdd mock is already set by Set")
+// Expect sets up expected params for service.CartPay
+func (mmCartPay *mServiceMockCartPay) Expect(ctx context.Context, cartID int64, source string) *mServiceMockCartPay {
+	if mmCartPay.mock.funcCartPay != nil {
+		mmCartPay.mock.t.Fatalf("ServiceMock.CartPay mock is already set by Set")
 	}
 
-	if mmLineItemAdd.defaultExpectation == nil {
+	if mmCartPay.defaultExpectation == nil {
+		mmCartPay.defaultExpectation = &ServiceMockCartPayExpectation{}
+	}
+
+	mmCartPay.defaultExpectation.params = &ServiceMockCartPayParams{ctx, cartID, source}
+	for _, e := range mmCartPay.expectations {
+		if minimock.Equal(e.params, mmCartPay.defaultExpectation.params) {
+			mmCartPay.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartPay.defaultExpectation.params)
+		}
+	}
+
+	return mmCartPay
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CartPay
+func (mmCartPay *mServiceMockCartPay) Inspect(f func(ctx context.Context, cartID int64, source string)) *mServiceMockCartPay {
+	if mmCartPay.mock.inspectFuncCartPay != nil {
+		mmCartPay.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartPay")
+	}
+
+	mmCartPay.mock.inspectFuncCartPay = f
+
+	return mmCartPay
+}
+
+// Return sets up results that will be returned by service.CartPay
+func (mmCartPay *mServiceMockCartPay) Return(pp1 *Payment, b1 bool, err error) *ServiceMock {
+	if mmCartPay.mock.funcCartPay != nil {
+		mmCartPay.mock.t.Fatalf("ServiceMock.CartPay mock is already set by Set")
+	}
+
+	if mmCartPay.defaultExpectation == nil {
+		mmCartPay.defaultExpectation = &ServiceMockCartPayExpectation{mock: mmCartPay.mock}
+	}
+	mmCartPay.defaultExpectation.results = &ServiceMockCartPayResults{pp1, b1, err}
+	return mmCartPay.mock
+}
+
+//Set uses given function f to mock the service.CartPay method
+func (mmCartPay *mServiceMockCartPay) Set(f func(ctx context.Context, cartID int64, source string) (pp1 *Payment, b1 bool, err error)) *ServiceMock {
+	if mmCartPay.defaultExpectation != nil {
+		mmCartPay.mock.t.Fatalf("Default expectation is already set for the service.CartPay method")
+	}
+
+	if len(mmCartPay.expectations) > 0 {
+		mmCartPay.mock.t.Fatalf("Some expectations are already set for the service.CartPay method")
+	}
+
+	mmCartPay.mock.funcCartPay = f
+	return mmCartPay.mock
+}
+
+// When sets expectation for the service.CartPay which will trigger the result defined by the following
+// Then helper
+func (mmCartPay *mServiceMockCartPay) When(ctx context.Context, cartID int64, source string) *ServiceMockCartPayExpectation {
+	if mmCartPay.mock.funcCartPay != nil {
+		mmCartPay.mock.t.Fatalf("ServiceMock.CartPay mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCartPayExpectation{
+		mock:   mmCartPay.mock,
+		params: &ServiceMockCartPayParams{ctx, cartID, source},
+	}
+	mmCartPay.expectations = append(mmCartPay.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CartPay return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartPayExpectation) Then(pp1 *Payment, b1 bool, err error) *ServiceMock {
+	e.results = &ServiceMockCartPayResults{pp1, b1, err}
+	return e.mock
+}
+
+// CartPay implements service
+func (mmCartPay *ServiceMock) CartPay(ctx context.Context, cartID int64, source string) (pp1 *Payment, b1 bool, err error) {
+	mm_atomic.AddUint64(&mmCartPay.beforeCartPayCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartPay.afterCartPayCounter, 1)
+
+	if mmCartPay.inspectFuncCartPay != nil {
+		mmCartPay.inspectFuncCartPay(ctx, cartID, source)
+	}
+
+	mm_params := &ServiceMockCartPayParams{ctx, cartID, source}
+
+	// Record call args
+	mmCartPay.CartPayMock.mutex.Lock()
+	mmCartPay.CartPayMock.callArgs = append(mmCartPay.CartPayMock.callArgs, mm_params)
+	mmCartPay.CartPayMock.mutex.Unlock()
+
+	for _, e := range mmCartPay.CartPayMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.pp1, e.results.b1, e.results.err
+		}
+	}
+
+	if mmCartPay.CartPayMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartPay.CartPayMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartPay.CartPayMock.defaultExpectation.params
+		mm_got := ServiceMockCartPayParams{ctx, cartID, source}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartPay.t.Errorf("ServiceMock.CartPay got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartPay.CartPayMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartPay.t.Fatal("No results are set for the ServiceMock.CartPay")
+		}
+		return (*mm_results).pp1, (*mm_results).b1, (*mm_results).err
+	}
+	if mmCartPay.funcCartPay != nil {
+		return mmCartPay.funcCartPay(ctx, cartID, source)
+	}
+	mmCartPay.t.Fatalf("Unexpected call to ServiceMock.CartPay. %v %v %v", ctx, cartID, source)
+	return
+}
+
+// CartPayAfterCounter returns a count of finished ServiceMock.CartPay invocations
+func (mmCartPay *ServiceMock) CartPayAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartPay.afterCartPayCounter)
+}
+
+// CartPayBeforeCounter returns a count of ServiceMock.CartPay invocations
+func (mmCartPay *ServiceMock) CartPayBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartPay.beforeCartPayCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CartPay.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartPay *mServiceMockCartPay) Calls() []*ServiceMockCartPayParams {
+	mmCartPay.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCartPayParams, len(mmCartPay.callArgs))
+	copy(argCopy, mmCartPay.callArgs)
+
+	mmCartPay.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartPayDone returns true if the count of the CartPay invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCartPayDone() bool {
+	for _, e := range m.CartPayMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartPayMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartPayCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartPay != nil && mm_atomic.LoadUint64(&m.afterCartPayCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartPayInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartPayInspect() {
+	for _, e := range m.CartPayMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CartPay with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartPayMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartPayCounter) < 1 {
+		if m.CartPayMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartPay")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CartPay with params: %#v", *m.CartPayMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartPay != nil && mm_atomic.LoadUint64(&m.afterCartPayCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartPay")
+	}
+}
+
+type mServiceMockCartReprice struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCartRepriceExpectation
+	expectations       []*ServiceMockCartRepriceExpectation
+
+	callArgs []*ServiceMockCartRepriceParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCartRepriceExpectation specifies expectation struct of the service.CartReprice
+type ServiceMockCartRepriceExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCartRepriceParams
+	results *ServiceMockCartRepriceResults
+	Counter uint64
+}
+
+// ServiceMockCartRepriceParams contains parameters of the service.CartReprice
+type ServiceMockCartRepriceParams struct {
+	ctx    context.Context
+	cartID int64
+}
+
+// ServiceMockCartRepriceResults contains results of the service.CartReprice
+type ServiceMockCartRepriceResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for service.CartReprice
+func (mmCartReprice *mServiceMockCartReprice) Expect(ctx context.Context, cartID int64) *mServiceMockCartReprice {
+	if mmCartReprice.mock.funcCartReprice != nil {
+		mmCartReprice.mock.t.Fatalf("ServiceMock.CartReprice mock is already set by Set")
+	}
+
+	if mmCartReprice.defaultExpectation == nil {
+		mmCartReprice.defaultExpectation = &ServiceMockCartRepriceExpectation{}
+	}
+
+	mmCartReprice.defaultExpectation.params = &ServiceMockCartRepriceParams{ctx, cartID}
+	for _, e := range mmCartReprice.expectations {
+		if minimock.Equal(e.params, mmCartReprice.defaultExpectation.params) {
+			mmCartReprice.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartReprice.defaultExpectation.params)
+		}
+	}
+
+	return mmCartReprice
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CartReprice
+func (mmCartReprice *mServiceMockCartReprice) Inspect(f func(ctx context.Context, cartID int64)) *mServiceMockCartReprice {
+	if mmCartReprice.mock.inspectFuncCartReprice != nil {
+		mmCartReprice.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartReprice")
+	}
+
+	mmCartReprice.mock.inspectFuncCartReprice = f
+
+	return mmCartReprice
+}
+
+// Return sets up results that will be returned by service.CartReprice
+func (mmCartReprice *mServiceMockCartReprice) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmCartReprice.mock.funcCartReprice != nil {
+		mmCartReprice.mock.t.Fatalf("ServiceMock.CartReprice mock is already set by Set")
+	}
+
+	if mmCartReprice.defaultExpectation == nil {
+		mmCartReprice.defaultExpectation = &ServiceMockCartRepriceExpectation{mock: mmCartReprice.mock}
+	}
+	mmCartReprice.defaultExpectation.results = &ServiceMockCartRepriceResults{cp1, err}
+	return mmCartReprice.mock
+}
+
+//Set uses given function f to mock the service.CartReprice method
+func (mmCartReprice *mServiceMockCartReprice) Set(f func(ctx context.Context, cartID int64) (cp1 *Cart, err error)) *ServiceMock {
+	if mmCartReprice.defaultExpectation != nil {
+		mmCartReprice.mock.t.Fatalf("Default expectation is already set for the service.CartReprice method")
+	}
+
+	if len(mmCartReprice.expectations) > 0 {
+		mmCartReprice.mock.t.Fatalf("Some expectations are already set for the service.CartReprice method")
+	}
+
+	mmCartReprice.mock.funcCartReprice = f
+	return mmCartReprice.mock
+}
+
+// When sets expectation for the service.CartReprice which will trigger the result defined by the following
+// Then helper
+func (mmCartReprice *mServiceMockCartReprice) When(ctx context.Context, cartID int64) *ServiceMockCartRepriceExpectation {
+	if mmCartReprice.mock.funcCartReprice != nil {
+		mmCartReprice.mock.t.Fatalf("ServiceMock.CartReprice mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCartRepriceExpectation{
+		mock:   mmCartReprice.mock,
+		params: &ServiceMockCartRepriceParams{ctx, cartID},
+	}
+	mmCartReprice.expectations = append(mmCartReprice.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CartReprice return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartRepriceExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockCartRepriceResults{cp1, err}
+	return e.mock
+}
+
+// CartReprice implements service
+func (mmCartReprice *ServiceMock) CartReprice(ctx context.Context, cartID int64) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCartReprice.beforeCartRepriceCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartReprice.afterCartRepriceCounter, 1)
+
+	if mmCartReprice.inspectFuncCartReprice != nil {
+		mmCartReprice.inspectFuncCartReprice(ctx, cartID)
+	}
+
+	mm_params := &ServiceMockCartRepriceParams{ctx, cartID}
+
+	// Record call args
+	mmCartReprice.CartRepriceMock.mutex.Lock()
+	mmCartReprice.CartRepriceMock.callArgs = append(mmCartReprice.CartRepriceMock.callArgs, mm_params)
+	mmCartReprice.CartRepriceMock.mutex.Unlock()
+
+	for _, e := range mmCartReprice.CartRepriceMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmCartReprice.CartRepriceMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartReprice.CartRepriceMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartReprice.CartRepriceMock.defaultExpectation.params
+		mm_got := ServiceMockCartRepriceParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartReprice.t.Errorf("ServiceMock.CartReprice got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartReprice.CartRepriceMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartReprice.t.Fatal("No results are set for the ServiceMock.CartReprice")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmCartReprice.funcCartReprice != nil {
+		return mmCartReprice.funcCartReprice(ctx, cartID)
+	}
+	mmCartReprice.t.Fatalf("Unexpected call to ServiceMock.CartReprice. %v %v", ctx, cartID)
+	return
+}
+
+// CartRepriceAfterCounter returns a count of finished ServiceMock.CartReprice invocations
+func (mmCartReprice *ServiceMock) CartRepriceAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartReprice.afterCartRepriceCounter)
+}
+
+// CartRepriceBeforeCounter returns a count of ServiceMock.CartReprice invocations
+func (mmCartReprice *ServiceMock) CartRepriceBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartReprice.beforeCartRepriceCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CartReprice.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartReprice *mServiceMockCartReprice) Calls() []*ServiceMockCartRepriceParams {
+	mmCartReprice.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCartRepriceParams, len(mmCartReprice.callArgs))
+	copy(argCopy, mmCartReprice.callArgs)
+
+	mmCartReprice.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartRepriceDone returns true if the count of the CartReprice invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCartRepriceDone() bool {
+	for _, e := range m.CartRepriceMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartRepriceMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartRepriceCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartReprice != nil && mm_atomic.LoadUint64(&m.afterCartRepriceCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartRepriceInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartRepriceInspect() {
+	for _, e := range m.CartRepriceMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CartReprice with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartRepriceMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartRepriceCounter) < 1 {
+		if m.CartRepriceMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartReprice")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CartReprice with params: %#v", *m.CartRepriceMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartReprice != nil && mm_atomic.LoadUint64(&m.afterCartRepriceCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartReprice")
+	}
+}
+
+type mServiceMockCartShippingAddressSet struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCartShippingAddressSetExpectation
+	expectations       []*ServiceMockCartShippingAddressSetExpectation
+
+	callArgs []*ServiceMockCartShippingAddressSetParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCartShippingAddressSetExpectation specifies expectation struct of the service.CartShippingAddressSet
+type ServiceMockCartShippingAddressSetExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCartShippingAddressSetParams
+	results *ServiceMockCartShippingAddressSetResults
+	Counter uint64
+}
+
+// ServiceMockCartShippingAddressSetParams contains parameters of the service.CartShippingAddressSet
+type ServiceMockCartShippingAddressSetParams struct {
+	ctx    context.Context
+	cartID int64
+	a      *Address
+}
+
+// ServiceMockCartShippingAddressSetResults contains results of the service.CartShippingAddressSet
+type ServiceMockCartShippingAddressSetResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for service.CartShippingAddressSet
+func (mmCartShippingAddressSet *mServiceMockCartShippingAddressSet) Expect(ctx context.Context, cartID int64, a *Address) *mServiceMockCartShippingAddressSet {
+	if mmCartShippingAddressSet.mock.funcCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("ServiceMock.CartShippingAddressSet mock is already set by Set")
+	}
+
+	if mmCartShippingAddressSet.defaultExpectation == nil {
+		mmCartShippingAddressSet.defaultExpectation = &ServiceMockCartShippingAddressSetExpectation{}
+	}
+
+	mmCartShippingAddressSet.defaultExpectation.params = &ServiceMockCartShippingAddressSetParams{ctx, cartID, a}
+	for _, e := range mmCartShippingAddressSet.expectations {
+		if minimock.Equal(e.params, mmCartShippingAddressSet.defaultExpectation.params) {
+			mmCartShippingAddressSet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartShippingAddressSet.defaultExpectation.params)
+		}
+	}
+
+	return mmCartShippingAddressSet
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CartShippingAddressSet
+func (mmCartShippingAddressSet *mServiceMockCartShippingAddressSet) Inspect(f func(ctx context.Context, cartID int64, a *Address)) *mServiceMockCartShippingAddressSet {
+	if mmCartShippingAddressSet.mock.inspectFuncCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartShippingAddressSet")
+	}
+
+	mmCartShippingAddressSet.mock.inspectFuncCartShippingAddressSet = f
+
+	return mmCartShippingAddressSet
+}
+
+// Return sets up results that will be returned by service.CartShippingAddressSet
+func (mmCartShippingAddressSet *mServiceMockCartShippingAddressSet) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmCartShippingAddressSet.mock.funcCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("ServiceMock.CartShippingAddressSet mock is already set by Set")
+	}
+
+	if mmCartShippingAddressSet.defaultExpectation == nil {
+		mmCartShippingAddressSet.defaultExpectation = &ServiceMockCartShippingAddressSetExpectation{mock: mmCartShippingAddressSet.mock}
+	}
+	mmCartShippingAddressSet.defaultExpectation.results = &ServiceMockCartShippingAddressSetResults{cp1, err}
+	return mmCartShippingAddressSet.mock
+}
+
+//Set uses given function f to mock the service.CartShippingAddressSet method
+func (mmCartShippingAddressSet *mServiceMockCartShippingAddressSet) Set(f func(ctx context.Context, cartID int64, a *Address) (cp1 *Cart, err error)) *ServiceMock {
+	if mmCartShippingAddressSet.defaultExpectation != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("Default expectation is already set for the service.CartShippingAddressSet method")
+	}
+
+	if len(mmCartShippingAddressSet.expectations) > 0 {
+		mmCartShippingAddressSet.mock.t.Fatalf("Some expectations are already set for the service.CartShippingAddressSet method")
+	}
+
+	mmCartShippingAddressSet.mock.funcCartShippingAddressSet = f
+	return mmCartShippingAddressSet.mock
+}
+
+// When sets expectation for the service.CartShippingAddressSet which will trigger the result defined by the following
+// Then helper
+func (mmCartShippingAddressSet *mServiceMockCartShippingAddressSet) When(ctx context.Context, cartID int64, a *Address) *ServiceMockCartShippingAddressSetExpectation {
+	if mmCartShippingAddressSet.mock.funcCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("ServiceMock.CartShippingAddressSet mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCartShippingAddressSetExpectation{
+		mock:   mmCartShippingAddressSet.mock,
+		params: &ServiceMockCartShippingAddressSetParams{ctx, cartID, a},
+	}
+	mmCartShippingAddressSet.expectations = append(mmCartShippingAddressSet.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CartShippingAddressSet return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartShippingAddressSetExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockCartShippingAddressSetResults{cp1, err}
+	return e.mock
+}
+
+// CartShippingAddressSet implements service
+func (mmCartShippingAddressSet *ServiceMock) CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCartShippingAddressSet.beforeCartShippingAddressSetCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartShippingAddressSet.afterCartShippingAddressSetCounter, 1)
+
+	if mmCartShippingAddressSet.inspectFuncCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.inspectFuncCartShippingAddressSet(ctx, cartID, a)
+	}
+
+	mm_params := &ServiceMockCartShippingAddressSetParams{ctx, cartID, a}
+
+	// Record call args
+	mmCartShippingAddressSet.CartShippingAddressSetMock.mutex.Lock()
+	mmCartShippingAddressSet.CartShippingAddressSetMock.callArgs = append(mmCartShippingAddressSet.CartShippingAddressSetMock.callArgs, mm_params)
+	mmCartShippingAddressSet.CartShippingAddressSetMock.mutex.Unlock()
+
+	for _, e := range mmCartShippingAddressSet.CartShippingAddressSetMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation.params
+		mm_got := ServiceMockCartShippingAddressSetParams{ctx, cartID, a}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartShippingAddressSet.t.Errorf("ServiceMock.CartShippingAddressSet got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartShippingAddressSet.t.Fatal("No results are set for the ServiceMock.CartShippingAddressSet")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmCartShippingAddressSet.funcCartShippingAddressSet != nil {
+		return mmCartShippingAddressSet.funcCartShippingAddressSet(ctx, cartID, a)
+	}
+	mmCartShippingAddressSet.t.Fatalf("Unexpected call to ServiceMock.CartShippingAddressSet. %v %v %v", ctx, cartID, a)
+	return
+}
+
+// CartShippingAddressSetAfterCounter returns a count of finished ServiceMock.CartShippingAddressSet invocations
+func (mmCartShippingAddressSet *ServiceMock) CartShippingAddressSetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingAddressSet.afterCartShippingAddressSetCounter)
+}
+
+// CartShippingAddressSetBeforeCounter returns a count of ServiceMock.CartShippingAddressSet invocations
+func (mmCartShippingAddressSet *ServiceMock) CartShippingAddressSetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingAddressSet.beforeCartShippingAddressSetCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CartShippingAddressSet.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartShippingAddressSet *mServiceMockCartShippingAddressSet) Calls() []*ServiceMockCartShippingAddressSetParams {
+	mmCartShippingAddressSet.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCartShippingAddressSetParams, len(mmCartShippingAddressSet.callArgs))
+	copy(argCopy, mmCartShippingAddressSet.callArgs)
+
+	mmCartShippingAddressSet.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartShippingAddressSetDone returns true if the count of the CartShippingAddressSet invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCartShippingAddressSetDone() bool {
+	for _, e := range m.CartShippingAddressSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingAddressSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingAddressSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartShippingAddressSetInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartShippingAddressSetInspect() {
+	for _, e := range m.CartShippingAddressSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CartShippingAddressSet with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingAddressSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		if m.CartShippingAddressSetMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartShippingAddressSet")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CartShippingAddressSet with params: %#v", *m.CartShippingAddressSetMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingAddressSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartShippingAddressSet")
+	}
+}
+
+type mServiceMockCartShippingMethodSet struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCartShippingMethodSetExpectation
+	expectations       []*ServiceMockCartShippingMethodSetExpectation
+
+	callArgs []*ServiceMockCartShippingMethodSetParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCartShippingMethodSetExpectation specifies expectation struct of the service.CartShippingMethodSet
+type ServiceMockCartShippingMethodSetExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCartShippingMethodSetParams
+	results *ServiceMockCartShippingMethodSetResults
+	Counter uint64
+}
+
+// ServiceMockCartShippingMethodSetParams contains parameters of the service.CartShippingMethodSet
+type ServiceMockCartShippingMethodSetParams struct {
+	ctx    context.Context
+	cartID int64
+	code   string
+}
+
+// ServiceMockCartShippingMethodSetResults contains results of the service.CartShippingMethodSet
+type ServiceMockCartShippingMethodSetResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for service.CartShippingMethodSet
+func (mmCartShippingMethodSet *mServiceMockCartShippingMethodSet) Expect(ctx context.Context, cartID int64, code string) *mServiceMockCartShippingMethodSet {
+	if mmCartShippingMethodSet.mock.funcCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("ServiceMock.CartShippingMethodSet mock is already set by Set")
+	}
+
+	if mmCartShippingMethodSet.defaultExpectation == nil {
+		mmCartShippingMethodSet.defaultExpectation = &ServiceMockCartShippingMethodSetExpectation{}
+	}
+
+	mmCartShippingMethodSet.defaultExpectation.params = &ServiceMockCartShippingMethodSetParams{ctx, cartID, code}
+	for _, e := range mmCartShippingMethodSet.expectations {
+		if minimock.Equal(e.params, mmCartShippingMethodSet.defaultExpectation.params) {
+			mmCartShippingMethodSet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartShippingMethodSet.defaultExpectation.params)
+		}
+	}
+
+	return mmCartShippingMethodSet
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CartShippingMethodSet
+func (mmCartShippingMethodSet *mServiceMockCartShippingMethodSet) Inspect(f func(ctx context.Context, cartID int64, code string)) *mServiceMockCartShippingMethodSet {
+	if mmCartShippingMethodSet.mock.inspectFuncCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartShippingMethodSet")
+	}
+
+	mmCartShippingMethodSet.mock.inspectFuncCartShippingMethodSet = f
+
+	return mmCartShippingMethodSet
+}
+
+// Return sets up results that will be returned by service.CartShippingMethodSet
+func (mmCartShippingMethodSet *mServiceMockCartShippingMethodSet) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmCartShippingMethodSet.mock.funcCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("ServiceMock.CartShippingMethodSet mock is already set by Set")
+	}
+
+	if mmCartShippingMethodSet.defaultExpectation == nil {
+		mmCartShippingMethodSet.defaultExpectation = &ServiceMockCartShippingMethodSetExpectation{mock: mmCartShippingMethodSet.mock}
+	}
+	mmCartShippingMethodSet.defaultExpectation.results = &ServiceMockCartShippingMethodSetResults{cp1, err}
+	return mmCartShippingMethodSet.mock
+}
+
+//Set uses given function f to mock the service.CartShippingMethodSet method
+func (mmCartShippingMethodSet *mServiceMockCartShippingMethodSet) Set(f func(ctx context.Context, cartID int64, code string) (cp1 *Cart, err error)) *ServiceMock {
+	if mmCartShippingMethodSet.defaultExpectation != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("Default expectation is already set for the service.CartShippingMethodSet method")
+	}
+
+	if len(mmCartShippingMethodSet.expectations) > 0 {
+		mmCartShippingMethodSet.mock.t.Fatalf("Some expectations are already set for the service.CartShippingMethodSet method")
+	}
+
+	mmCartShippingMethodSet.mock.funcCartShippingMethodSet = f
+	return mmCartShippingMethodSet.mock
+}
+
+// When sets expectation for the service.CartShippingMethodSet which will trigger the result defined by the following
+// Then helper
+func (mmCartShippingMethodSet *mServiceMockCartShippingMethodSet) When(ctx context.Context, cartID int64, code string) *ServiceMockCartShippingMethodSetExpectation {
+	if mmCartShippingMethodSet.mock.funcCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("ServiceMock.CartShippingMethodSet mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCartShippingMethodSetExpectation{
+		mock:   mmCartShippingMethodSet.mock,
+		params: &ServiceMockCartShippingMethodSetParams{ctx, cartID, code},
+	}
+	mmCartShippingMethodSet.expectations = append(mmCartShippingMethodSet.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CartShippingMethodSet return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartShippingMethodSetExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockCartShippingMethodSetResults{cp1, err}
+	return e.mock
+}
+
+// CartShippingMethodSet implements service
+func (mmCartShippingMethodSet *ServiceMock) CartShippingMethodSet(ctx context.Context, cartID int64, code string) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCartShippingMethodSet.beforeCartShippingMethodSetCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartShippingMethodSet.afterCartShippingMethodSetCounter, 1)
+
+	if mmCartShippingMethodSet.inspectFuncCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.inspectFuncCartShippingMethodSet(ctx, cartID, code)
+	}
+
+	mm_params := &ServiceMockCartShippingMethodSetParams{ctx, cartID, code}
+
+	// Record call args
+	mmCartShippingMethodSet.CartShippingMethodSetMock.mutex.Lock()
+	mmCartShippingMethodSet.CartShippingMethodSetMock.callArgs = append(mmCartShippingMethodSet.CartShippingMethodSetMock.callArgs, mm_params)
+	mmCartShippingMethodSet.CartShippingMethodSetMock.mutex.Unlock()
+
+	for _, e := range mmCartShippingMethodSet.CartShippingMethodSetMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation.params
+		mm_got := ServiceMockCartShippingMethodSetParams{ctx, cartID, code}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartShippingMethodSet.t.Errorf("ServiceMock.CartShippingMethodSet got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartShippingMethodSet.t.Fatal("No results are set for the ServiceMock.CartShippingMethodSet")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmCartShippingMethodSet.funcCartShippingMethodSet != nil {
+		return mmCartShippingMethodSet.funcCartShippingMethodSet(ctx, cartID, code)
+	}
+	mmCartShippingMethodSet.t.Fatalf("Unexpected call to ServiceMock.CartShippingMethodSet. %v %v %v", ctx, cartID, code)
+	return
+}
+
+// CartShippingMethodSetAfterCounter returns a count of finished ServiceMock.CartShippingMethodSet invocations
+func (mmCartShippingMethodSet *ServiceMock) CartShippingMethodSetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingMethodSet.afterCartShippingMethodSetCounter)
+}
+
+// CartShippingMethodSetBeforeCounter returns a count of ServiceMock.CartShippingMethodSet invocations
+func (mmCartShippingMethodSet *ServiceMock) CartShippingMethodSetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingMethodSet.beforeCartShippingMethodSetCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CartShippingMethodSet.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartShippingMethodSet *mServiceMockCartShippingMethodSet) Calls() []*ServiceMockCartShippingMethodSetParams {
+	mmCartShippingMethodSet.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCartShippingMethodSetParams, len(mmCartShippingMethodSet.callArgs))
+	copy(argCopy, mmCartShippingMethodSet.callArgs)
+
+	mmCartShippingMethodSet.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartShippingMethodSetDone returns true if the count of the CartShippingMethodSet invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCartShippingMethodSetDone() bool {
+	for _, e := range m.CartShippingMethodSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingMethodSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingMethodSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartShippingMethodSetInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartShippingMethodSetInspect() {
+	for _, e := range m.CartShippingMethodSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CartShippingMethodSet with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingMethodSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		if m.CartShippingMethodSetMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartShippingMethodSet")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CartShippingMethodSet with params: %#v", *m.CartShippingMethodSetMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingMethodSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartShippingMethodSet")
+	}
+}
+
+type mServiceMockCartShow struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCartShowExpectation
+	expectations       []*ServiceMockCartShowExpectation
+
+	callArgs []*ServiceMockCartShowParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCartShowExpectation specifies expectation struct of the service.CartShow
+type ServiceMockCartShowExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCartShowParams
+	results *ServiceMockCartShowResults
+	Counter uint64
+}
+
+// ServiceMockCartShowParams contains parameters of the service.CartShow
+type ServiceMockCartShowParams struct {
+	ctx    context.Context
+	cartID int64
+}
+
+// ServiceMockCartShowResults contains results of the service.CartShow
+type ServiceMockCartShowResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for service.CartShow
+func (mmCartShow *mServiceMockCartShow) Expect(ctx context.Context, cartID int64) *mServiceMockCartShow {
+	if mmCartShow.mock.funcCartShow != nil {
+		mmCartShow.mock.t.Fatalf("ServiceMock.CartShow mock is already set by Set")
+	}
+
+	if mmCartShow.defaultExpectation == nil {
+		mmCartShow.defaultExpectation = &ServiceMockCartShowExpectation{}
+	}
+
+	mmCartShow.defaultExpectation.params = &ServiceMockCartShowParams{ctx, cartID}
+	for _, e := range mmCartShow.expectations {
+		if minimock.Equal(e.params, mmCartShow.defaultExpectation.params) {
+			mmCartShow.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartShow.defaultExpectation.params)
+		}
+	}
+
+	return mmCartShow
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CartShow
+func (mmCartShow *mServiceMockCartShow) Inspect(f func(ctx context.Context, cartID int64)) *mServiceMockCartShow {
+	if mmCartShow.mock.inspectFuncCartShow != nil {
+		mmCartShow.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartShow")
+	}
+
+	mmCartShow.mock.inspectFuncCartShow = f
+
+	return mmCartShow
+}
+
+// Return sets up results that will be returned by service.CartShow
+func (mmCartShow *mServiceMockCartShow) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmCartShow.mock.funcCartShow != nil {
+		mmCartShow.mock.t.Fatalf("ServiceMock.CartShow mock is already set by Set")
+	}
+
+	if mmCartShow.defaultExpectation == nil {
+		mmCartShow.defaultExpectation = &ServiceMockCartShowExpectation{mock: mmCartShow.mock}
+	}
+	mmCartShow.defaultExpectation.results = &ServiceMockCartShowResults{cp1, err}
+	return mmCartShow.mock
+}
+
+//Set uses given function f to mock the service.CartShow method
+func (mmCartShow *mServiceMockCartShow) Set(f func(ctx context.Context, cartID int64) (cp1 *Cart, err error)) *ServiceMock {
+	if mmCartShow.defaultExpectation != nil {
+		mmCartShow.mock.t.Fatalf("Default expectation is already set for the service.CartShow method")
+	}
+
+	if len(mmCartShow.expectations) > 0 {
+		mmCartShow.mock.t.Fatalf("Some expectations are already set for the service.CartShow method")
+	}
+
+	mmCartShow.mock.funcCartShow = f
+	return mmCartShow.mock
+}
+
+// When sets expectation for the service.CartShow which will trigger the result defined by the following
+// Then helper
+func (mmCartShow *mServiceMockCartShow) When(ctx context.Context, cartID int64) *ServiceMockCartShowExpectation {
+	if mmCartShow.mock.funcCartShow != nil {
+		mmCartShow.mock.t.Fatalf("ServiceMock.CartShow mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCartShowExpectation{
+		mock:   mmCartShow.mock,
+		params: &ServiceMockCartShowParams{ctx, cartID},
+	}
+	mmCartShow.expectations = append(mmCartShow.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CartShow return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartShowExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockCartShowResults{cp1, err}
+	return e.mock
+}
+
+// CartShow implements service
+func (mmCartShow *ServiceMock) CartShow(ctx context.Context, cartID int64) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCartShow.beforeCartShowCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartShow.afterCartShowCounter, 1)
+
+	if mmCartShow.inspectFuncCartShow != nil {
+		mmCartShow.inspectFuncCartShow(ctx, cartID)
+	}
+
+	mm_params := &ServiceMockCartShowParams{ctx, cartID}
+
+	// Record call args
+	mmCartShow.CartShowMock.mutex.Lock()
+	mmCartShow.CartShowMock.callArgs = append(mmCartShow.CartShowMock.callArgs, mm_params)
+	mmCartShow.CartShowMock.mutex.Unlock()
+
+	for _, e := range mmCartShow.CartShowMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmCartShow.CartShowMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartShow.CartShowMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartShow.CartShowMock.defaultExpectation.params
+		mm_got := ServiceMockCartShowParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartShow.t.Errorf("ServiceMock.CartShow got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartShow.CartShowMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartShow.t.Fatal("No results are set for the ServiceMock.CartShow")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmCartShow.funcCartShow != nil {
+		return mmCartShow.funcCartShow(ctx, cartID)
+	}
+	mmCartShow.t.Fatalf("Unexpected call to ServiceMock.CartShow. %v %v", ctx, cartID)
+	return
+}
+
+// CartShowAfterCounter returns a count of finished ServiceMock.CartShow invocations
+func (mmCartShow *ServiceMock) CartShowAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShow.afterCartShowCounter)
+}
+
+// CartShowBeforeCounter returns a count of ServiceMock.CartShow invocations
+func (mmCartShow *ServiceMock) CartShowBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShow.beforeCartShowCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CartShow.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartShow *mServiceMockCartShow) Calls() []*ServiceMockCartShowParams {
+	mmCartShow.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCartShowParams, len(mmCartShow.callArgs))
+	copy(argCopy, mmCartShow.callArgs)
+
+	mmCartShow.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartShowDone returns true if the count of the CartShow invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCartShowDone() bool {
+	for _, e := range m.CartShowMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShowMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShow != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartShowInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartShowInspect() {
+	for _, e := range m.CartShowMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CartShow with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShowMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
+		if m.CartShowMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartShow")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CartShow with params: %#v", *m.CartShowMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShow != nil && mm_atomic.LoadUint64(&m.afterCartShowCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartShow")
+	}
+}
+
+type mServiceMockCartsByUser struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCartsByUserExpectation
+	expectations       []*ServiceMockCartsByUserExpectation
+
+	callArgs []*ServiceMockCartsByUserParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCartsByUserExpectation specifies expectation struct of the service.CartsByUser
+type ServiceMockCartsByUserExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCartsByUserParams
+	results *ServiceMockCartsByUserResults
+	Counter uint64
+}
+
+// ServiceMockCartsByUserParams contains parameters of the service.CartsByUser
+type ServiceMockCartsByUserParams struct {
+	ctx    context.Context
+	userID int64
+	q      CartsQuery
+}
+
+// ServiceMockCartsByUserResults contains results of the service.CartsByUser
+type ServiceMockCartsByUserResults struct {
+	cpa1 []*Cart
+	i1   int64
+	err  error
+}
+
+// Expect sets up expected params for service.CartsByUser
+func (mmCartsByUser *mServiceMockCartsByUser) Expect(ctx context.Context, userID int64, q CartsQuery) *mServiceMockCartsByUser {
+	if mmCartsByUser.mock.funcCartsByUser != nil {
+		mmCartsByUser.mock.t.Fatalf("ServiceMock.CartsByUser mock is already set by Set")
+	}
+
+	if mmCartsByUser.defaultExpectation == nil {
+		mmCartsByUser.defaultExpectation = &ServiceMockCartsByUserExpectation{}
+	}
+
+	mmCartsByUser.defaultExpectation.params = &ServiceMockCartsByUserParams{ctx, userID, q}
+	for _, e := range mmCartsByUser.expectations {
+		if minimock.Equal(e.params, mmCartsByUser.defaultExpectation.params) {
+			mmCartsByUser.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartsByUser.defaultExpectation.params)
+		}
+	}
+
+	return mmCartsByUser
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CartsByUser
+func (mmCartsByUser *mServiceMockCartsByUser) Inspect(f func(ctx context.Context, userID int64, q CartsQuery)) *mServiceMockCartsByUser {
+	if mmCartsByUser.mock.inspectFuncCartsByUser != nil {
+		mmCartsByUser.mock.t.Fatalf("Inspect function is already set for ServiceMock.CartsByUser")
+	}
+
+	mmCartsByUser.mock.inspectFuncCartsByUser = f
+
+	return mmCartsByUser
+}
+
+// Return sets up results that will be returned by service.CartsByUser
+func (mmCartsByUser *mServiceMockCartsByUser) Return(cpa1 []*Cart, i1 int64, err error) *ServiceMock {
+	if mmCartsByUser.mock.funcCartsByUser != nil {
+		mmCartsByUser.mock.t.Fatalf("ServiceMock.CartsByUser mock is already set by Set")
+	}
+
+	if mmCartsByUser.defaultExpectation == nil {
+		mmCartsByUser.defaultExpectation = &ServiceMockCartsByUserExpectation{mock: mmCartsByUser.mock}
+	}
+	mmCartsByUser.defaultExpectation.results = &ServiceMockCartsByUserResults{cpa1, i1, err}
+	return mmCartsByUser.mock
+}
+
+//Set uses given function f to mock the service.CartsByUser method
+func (mmCartsByUser *mServiceMockCartsByUser) Set(f func(ctx context.Context, userID int64, q CartsQuery) (cpa1 []*Cart, i1 int64, err error)) *ServiceMock {
+	if mmCartsByUser.defaultExpectation != nil {
+		mmCartsByUser.mock.t.Fatalf("Default expectation is already set for the service.CartsByUser method")
+	}
+
+	if len(mmCartsByUser.expectations) > 0 {
+		mmCartsByUser.mock.t.Fatalf("Some expectations are already set for the service.CartsByUser method")
+	}
+
+	mmCartsByUser.mock.funcCartsByUser = f
+	return mmCartsByUser.mock
+}
+
+// When sets expectation for the service.CartsByUser which will trigger the result defined by the following
+// Then helper
+func (mmCartsByUser *mServiceMockCartsByUser) When(ctx context.Context, userID int64, q CartsQuery) *ServiceMockCartsByUserExpectation {
+	if mmCartsByUser.mock.funcCartsByUser != nil {
+		mmCartsByUser.mock.t.Fatalf("ServiceMock.CartsByUser mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCartsByUserExpectation{
+		mock:   mmCartsByUser.mock,
+		params: &ServiceMockCartsByUserParams{ctx, userID, q},
+	}
+	mmCartsByUser.expectations = append(mmCartsByUser.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CartsByUser return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCartsByUserExpectation) Then(cpa1 []*Cart, i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockCartsByUserResults{cpa1, i1, err}
+	return e.mock
+}
+
+// CartsByUser implements service
+func (mmCartsByUser *ServiceMock) CartsByUser(ctx context.Context, userID int64, q CartsQuery) (cpa1 []*Cart, i1 int64, err error) {
+	mm_atomic.AddUint64(&mmCartsByUser.beforeCartsByUserCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartsByUser.afterCartsByUserCounter, 1)
+
+	if mmCartsByUser.inspectFuncCartsByUser != nil {
+		mmCartsByUser.inspectFuncCartsByUser(ctx, userID, q)
+	}
+
+	mm_params := &ServiceMockCartsByUserParams{ctx, userID, q}
+
+	// Record call args
+	mmCartsByUser.CartsByUserMock.mutex.Lock()
+	mmCartsByUser.CartsByUserMock.callArgs = append(mmCartsByUser.CartsByUserMock.callArgs, mm_params)
+	mmCartsByUser.CartsByUserMock.mutex.Unlock()
+
+	for _, e := range mmCartsByUser.CartsByUserMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cpa1, e.results.i1, e.results.err
+		}
+	}
+
+	if mmCartsByUser.CartsByUserMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartsByUser.CartsByUserMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartsByUser.CartsByUserMock.defaultExpectation.params
+		mm_got := ServiceMockCartsByUserParams{ctx, userID, q}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartsByUser.t.Errorf("ServiceMock.CartsByUser got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartsByUser.CartsByUserMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartsByUser.t.Fatal("No results are set for the ServiceMock.CartsByUser")
+		}
+		return (*mm_results).cpa1, (*mm_results).i1, (*mm_results).err
+	}
+	if mmCartsByUser.funcCartsByUser != nil {
+		return mmCartsByUser.funcCartsByUser(ctx, userID, q)
+	}
+	mmCartsByUser.t.Fatalf("Unexpected call to ServiceMock.CartsByUser. %v %v %v", ctx, userID, q)
+	return
+}
+
+// CartsByUserAfterCounter returns a count of finished ServiceMock.CartsByUser invocations
+func (mmCartsByUser *ServiceMock) CartsByUserAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartsByUser.afterCartsByUserCounter)
+}
+
+// CartsByUserBeforeCounter returns a count of ServiceMock.CartsByUser invocations
+func (mmCartsByUser *ServiceMock) CartsByUserBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartsByUser.beforeCartsByUserCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CartsByUser.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartsByUser *mServiceMockCartsByUser) Calls() []*ServiceMockCartsByUserParams {
+	mmCartsByUser.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCartsByUserParams, len(mmCartsByUser.callArgs))
+	copy(argCopy, mmCartsByUser.callArgs)
+
+	mmCartsByUser.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartsByUserDone returns true if the count of the CartsByUser invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCartsByUserDone() bool {
+	for _, e := range m.CartsByUserMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartsByUserMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartsByUserCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartsByUser != nil && mm_atomic.LoadUint64(&m.afterCartsByUserCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartsByUserInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCartsByUserInspect() {
+	for _, e := range m.CartsByUserMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CartsByUser with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartsByUserMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartsByUserCounter) < 1 {
+		if m.CartsByUserMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CartsByUser")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CartsByUser with params: %#v", *m.CartsByUserMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartsByUser != nil && mm_atomic.LoadUint64(&m.afterCartsByUserCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CartsByUser")
+	}
+}
+
+type mServiceMockCheckout struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCheckoutExpectation
+	expectations       []*ServiceMockCheckoutExpectation
+
+	callArgs []*ServiceMockCheckoutParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCheckoutExpectation specifies expectation struct of the service.Checkout
+type ServiceMockCheckoutExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCheckoutParams
+	results *ServiceMockCheckoutResults
+	Counter uint64
+}
+
+// ServiceMockCheckoutParams contains parameters of the service.Checkout
+type ServiceMockCheckoutParams struct {
+	ctx    context.Context
+	cartID int64
+}
+
+// ServiceMockCheckoutResults contains results of the service.Checkout
+type ServiceMockCheckoutResults struct {
+	i1  int64
+	err error
+}
+
+// Expect sets up expected params for service.Checkout
+func (mmCheckout *mServiceMockCheckout) Expect(ctx context.Context, cartID int64) *mServiceMockCheckout {
+	if mmCheckout.mock.funcCheckout != nil {
+		mmCheckout.mock.t.Fatalf("ServiceMock.Checkout mock is already set by Set")
+	}
+
+	if mmCheckout.defaultExpectation == nil {
+		mmCheckout.defaultExpectation = &ServiceMockCheckoutExpectation{}
+	}
+
+	mmCheckout.defaultExpectation.params = &ServiceMockCheckoutParams{ctx, cartID}
+	for _, e := range mmCheckout.expectations {
+		if minimock.Equal(e.params, mmCheckout.defaultExpectation.params) {
+			mmCheckout.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCheckout.defaultExpectation.params)
+		}
+	}
+
+	return mmCheckout
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.Checkout
+func (mmCheckout *mServiceMockCheckout) Inspect(f func(ctx context.Context, cartID int64)) *mServiceMockCheckout {
+	if mmCheckout.mock.inspectFuncCheckout != nil {
+		mmCheckout.mock.t.Fatalf("Inspect function is already set for ServiceMock.Checkout")
+	}
+
+	mmCheckout.mock.inspectFuncCheckout = f
+
+	return mmCheckout
+}
+
+// Return sets up results that will be returned by service.Checkout
+func (mmCheckout *mServiceMockCheckout) Return(i1 int64, err error) *ServiceMock {
+	if mmCheckout.mock.funcCheckout != nil {
+		mmCheckout.mock.t.Fatalf("ServiceMock.Checkout mock is already set by Set")
+	}
+
+	if mmCheckout.defaultExpectation == nil {
+		mmCheckout.defaultExpectation = &ServiceMockCheckoutExpectation{mock: mmCheckout.mock}
+	}
+	mmCheckout.defaultExpectation.results = &ServiceMockCheckoutResults{i1, err}
+	return mmCheckout.mock
+}
+
+//Set uses given function f to mock the service.Checkout method
+func (mmCheckout *mServiceMockCheckout) Set(f func(ctx context.Context, cartID int64) (i1 int64, err error)) *ServiceMock {
+	if mmCheckout.defaultExpectation != nil {
+		mmCheckout.mock.t.Fatalf("Default expectation is already set for the service.Checkout method")
+	}
+
+	if len(mmCheckout.expectations) > 0 {
+		mmCheckout.mock.t.Fatalf("Some expectations are already set for the service.Checkout method")
+	}
+
+	mmCheckout.mock.funcCheckout = f
+	return mmCheckout.mock
+}
+
+// When sets expectation for the service.Checkout which will trigger the result defined by the following
+// Then helper
+func (mmCheckout *mServiceMockCheckout) When(ctx context.Context, cartID int64) *ServiceMockCheckoutExpectation {
+	if mmCheckout.mock.funcCheckout != nil {
+		mmCheckout.mock.t.Fatalf("ServiceMock.Checkout mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCheckoutExpectation{
+		mock:   mmCheckout.mock,
+		params: &ServiceMockCheckoutParams{ctx, cartID},
+	}
+	mmCheckout.expectations = append(mmCheckout.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.Checkout return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCheckoutExpectation) Then(i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockCheckoutResults{i1, err}
+	return e.mock
+}
+
+// Checkout implements service
+func (mmCheckout *ServiceMock) Checkout(ctx context.Context, cartID int64) (i1 int64, err error) {
+	mm_atomic.AddUint64(&mmCheckout.beforeCheckoutCounter, 1)
+	defer mm_atomic.AddUint64(&mmCheckout.afterCheckoutCounter, 1)
+
+	if mmCheckout.inspectFuncCheckout != nil {
+		mmCheckout.inspectFuncCheckout(ctx, cartID)
+	}
+
+	mm_params := &ServiceMockCheckoutParams{ctx, cartID}
+
+	// Record call args
+	mmCheckout.CheckoutMock.mutex.Lock()
+	mmCheckout.CheckoutMock.callArgs = append(mmCheckout.CheckoutMock.callArgs, mm_params)
+	mmCheckout.CheckoutMock.mutex.Unlock()
+
+	for _, e := range mmCheckout.CheckoutMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.i1, e.results.err
+		}
+	}
+
+	if mmCheckout.CheckoutMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCheckout.CheckoutMock.defaultExpectation.Counter, 1)
+		mm_want := mmCheckout.CheckoutMock.defaultExpectation.params
+		mm_got := ServiceMockCheckoutParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCheckout.t.Errorf("ServiceMock.Checkout got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCheckout.CheckoutMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCheckout.t.Fatal("No results are set for the ServiceMock.Checkout")
+		}
+		return (*mm_results).i1, (*mm_results).err
+	}
+	if mmCheckout.funcCheckout != nil {
+		return mmCheckout.funcCheckout(ctx, cartID)
+	}
+	mmCheckout.t.Fatalf("Unexpected call to ServiceMock.Checkout. %v %v", ctx, cartID)
+	return
+}
+
+// CheckoutAfterCounter returns a count of finished ServiceMock.Checkout invocations
+func (mmCheckout *ServiceMock) CheckoutAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCheckout.afterCheckoutCounter)
+}
+
+// CheckoutBeforeCounter returns a count of ServiceMock.Checkout invocations
+func (mmCheckout *ServiceMock) CheckoutBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCheckout.beforeCheckoutCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.Checkout.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCheckout *mServiceMockCheckout) Calls() []*ServiceMockCheckoutParams {
+	mmCheckout.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCheckoutParams, len(mmCheckout.callArgs))
+	copy(argCopy, mmCheckout.callArgs)
+
+	mmCheckout.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCheckoutDone returns true if the count of the Checkout invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCheckoutDone() bool {
+	for _, e := range m.CheckoutMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CheckoutMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCheckoutCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCheckout != nil && mm_atomic.LoadUint64(&m.afterCheckoutCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCheckoutInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCheckoutInspect() {
+	for _, e := range m.CheckoutMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.Checkout with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CheckoutMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCheckoutCounter) < 1 {
+		if m.CheckoutMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.Checkout")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.Checkout with params: %#v", *m.CheckoutMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCheckout != nil && mm_atomic.LoadUint64(&m.afterCheckoutCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.Checkout")
+	}
+}
+
+type mServiceMockCouponApply struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCouponApplyExpectation
+	expectations       []*ServiceMockCouponApplyExpectation
+
+	callArgs []*ServiceMockCouponApplyParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCouponApplyExpectation specifies expectation struct of the service.CouponApply
+type ServiceMockCouponApplyExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCouponApplyParams
+	results *ServiceMockCouponApplyResults
+	Counter uint64
+}
+
+// ServiceMockCouponApplyParams contains parameters of the service.CouponApply
+type ServiceMockCouponApplyParams struct {
+	ctx    context.Context
+	cartID int64
+	code   string
+}
+
+// ServiceMockCouponApplyResults contains results of the service.CouponApply
+type ServiceMockCouponApplyResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for service.CouponApply
+func (mmCouponApply *mServiceMockCouponApply) Expect(ctx context.Context, cartID int64, code string) *mServiceMockCouponApply {
+	if mmCouponApply.mock.funcCouponApply != nil {
+		mmCouponApply.mock.t.Fatalf("ServiceMock.CouponApply mock is already set by Set")
+	}
+
+	if mmCouponApply.defaultExpectation == nil {
+		mmCouponApply.defaultExpectation = &ServiceMockCouponApplyExpectation{}
+	}
+
+	mmCouponApply.defaultExpectation.params = &ServiceMockCouponApplyParams{ctx, cartID, code}
+	for _, e := range mmCouponApply.expectations {
+		if minimock.Equal(e.params, mmCouponApply.defaultExpectation.params) {
+			mmCouponApply.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCouponApply.defaultExpectation.params)
+		}
+	}
+
+	return mmCouponApply
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CouponApply
+func (mmCouponApply *mServiceMockCouponApply) Inspect(f func(ctx context.Context, cartID int64, code string)) *mServiceMockCouponApply {
+	if mmCouponApply.mock.inspectFuncCouponApply != nil {
+		mmCouponApply.mock.t.Fatalf("Inspect function is already set for ServiceMock.CouponApply")
+	}
+
+	mmCouponApply.mock.inspectFuncCouponApply = f
+
+	return mmCouponApply
+}
+
+// Return sets up results that will be returned by service.CouponApply
+func (mmCouponApply *mServiceMockCouponApply) Return(cp1 *Cart, err error) *ServiceMock {
+	if mmCouponApply.mock.funcCouponApply != nil {
+		mmCouponApply.mock.t.Fatalf("ServiceMock.CouponApply mock is already set by Set")
+	}
+
+	if mmCouponApply.defaultExpectation == nil {
+		mmCouponApply.defaultExpectation = &ServiceMockCouponApplyExpectation{mock: mmCouponApply.mock}
+	}
+	mmCouponApply.defaultExpectation.results = &ServiceMockCouponApplyResults{cp1, err}
+	return mmCouponApply.mock
+}
+
+//Set uses given function f to mock the service.CouponApply method
+func (mmCouponApply *mServiceMockCouponApply) Set(f func(ctx context.Context, cartID int64, code string) (cp1 *Cart, err error)) *ServiceMock {
+	if mmCouponApply.defaultExpectation != nil {
+		mmCouponApply.mock.t.Fatalf("Default expectation is already set for the service.CouponApply method")
+	}
+
+	if len(mmCouponApply.expectations) > 0 {
+		mmCouponApply.mock.t.Fatalf("Some expectations are already set for the service.CouponApply method")
+	}
+
+	mmCouponApply.mock.funcCouponApply = f
+	return mmCouponApply.mock
+}
+
+// When sets expectation for the service.CouponApply which will trigger the result defined by the following
+// Then helper
+func (mmCouponApply *mServiceMockCouponApply) When(ctx context.Context, cartID int64, code string) *ServiceMockCouponApplyExpectation {
+	if mmCouponApply.mock.funcCouponApply != nil {
+		mmCouponApply.mock.t.Fatalf("ServiceMock.CouponApply mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCouponApplyExpectation{
+		mock:   mmCouponApply.mock,
+		params: &ServiceMockCouponApplyParams{ctx, cartID, code},
+	}
+	mmCouponApply.expectations = append(mmCouponApply.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CouponApply return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCouponApplyExpectation) Then(cp1 *Cart, err error) *ServiceMock {
+	e.results = &ServiceMockCouponApplyResults{cp1, err}
+	return e.mock
+}
+
+// CouponApply implements service
+func (mmCouponApply *ServiceMock) CouponApply(ctx context.Context, cartID int64, code string) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCouponApply.beforeCouponApplyCounter, 1)
+	defer mm_atomic.AddUint64(&mmCouponApply.afterCouponApplyCounter, 1)
+
+	if mmCouponApply.inspectFuncCouponApply != nil {
+		mmCouponApply.inspectFuncCouponApply(ctx, cartID, code)
+	}
+
+	mm_params := &ServiceMockCouponApplyParams{ctx, cartID, code}
+
+	// Record call args
+	mmCouponApply.CouponApplyMock.mutex.Lock()
+	mmCouponApply.CouponApplyMock.callArgs = append(mmCouponApply.CouponApplyMock.callArgs, mm_params)
+	mmCouponApply.CouponApplyMock.mutex.Unlock()
+
+	for _, e := range mmCouponApply.CouponApplyMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmCouponApply.CouponApplyMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCouponApply.CouponApplyMock.defaultExpectation.Counter, 1)
+		mm_want := mmCouponApply.CouponApplyMock.defaultExpectation.params
+		mm_got := ServiceMockCouponApplyParams{ctx, cartID, code}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCouponApply.t.Errorf("ServiceMock.CouponApply got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCouponApply.CouponApplyMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCouponApply.t.Fatal("No results are set for the ServiceMock.CouponApply")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmCouponApply.funcCouponApply != nil {
+		return mmCouponApply.funcCouponApply(ctx, cartID, code)
+	}
+	mmCouponApply.t.Fatalf("Unexpected call to ServiceMock.CouponApply. %v %v %v", ctx, cartID, code)
+	return
+}
+
+// CouponApplyAfterCounter returns a count of finished ServiceMock.CouponApply invocations
+func (mmCouponApply *ServiceMock) CouponApplyAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCouponApply.afterCouponApplyCounter)
+}
+
+// CouponApplyBeforeCounter returns a count of ServiceMock.CouponApply invocations
+func (mmCouponApply *ServiceMock) CouponApplyBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCouponApply.beforeCouponApplyCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CouponApply.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCouponApply *mServiceMockCouponApply) Calls() []*ServiceMockCouponApplyParams {
+	mmCouponApply.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCouponApplyParams, len(mmCouponApply.callArgs))
+	copy(argCopy, mmCouponApply.callArgs)
+
+	mmCouponApply.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCouponApplyDone returns true if the count of the CouponApply invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCouponApplyDone() bool {
+	for _, e := range m.CouponApplyMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CouponApplyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCouponApplyCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCouponApply != nil && mm_atomic.LoadUint64(&m.afterCouponApplyCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCouponApplyInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCouponApplyInspect() {
+	for _, e := range m.CouponApplyMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CouponApply with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CouponApplyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCouponApplyCounter) < 1 {
+		if m.CouponApplyMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CouponApply")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CouponApply with params: %#v", *m.CouponApplyMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCouponApply != nil && mm_atomic.LoadUint64(&m.afterCouponApplyCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CouponApply")
+	}
+}
+
+type mServiceMockCouponRemove struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockCouponRemoveExpectation
+	expectations       []*ServiceMockCouponRemoveExpectation
+
+	callArgs []*ServiceMockCouponRemoveParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockCouponRemoveExpectation specifies expectation struct of the service.CouponRemove
+type ServiceMockCouponRemoveExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockCouponRemoveParams
+	results *ServiceMockCouponRemoveResults
+	Counter uint64
+}
+
+// ServiceMockCouponRemoveParams contains parameters of the service.CouponRemove
+type ServiceMockCouponRemoveParams struct {
+	ctx    context.Context
+	cartID int64
+	code   string
+}
+
+// ServiceMockCouponRemoveResults contains results of the service.CouponRemove
+type ServiceMockCouponRemoveResults struct {
+	i1  int64
+	err error
+}
+
+// Expect sets up expected params for service.CouponRemove
+func (mmCouponRemove *mServiceMockCouponRemove) Expect(ctx context.Context, cartID int64, code string) *mServiceMockCouponRemove {
+	if mmCouponRemove.mock.funcCouponRemove != nil {
+		mmCouponRemove.mock.t.Fatalf("ServiceMock.CouponRemove mock is already set by Set")
+	}
+
+	if mmCouponRemove.defaultExpectation == nil {
+		mmCouponRemove.defaultExpectation = &ServiceMockCouponRemoveExpectation{}
+	}
+
+	mmCouponRemove.defaultExpectation.params = &ServiceMockCouponRemoveParams{ctx, cartID, code}
+	for _, e := range mmCouponRemove.expectations {
+		if minimock.Equal(e.params, mmCouponRemove.defaultExpectation.params) {
+			mmCouponRemove.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCouponRemove.defaultExpectation.params)
+		}
+	}
+
+	return mmCouponRemove
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.CouponRemove
+func (mmCouponRemove *mServiceMockCouponRemove) Inspect(f func(ctx context.Context, cartID int64, code string)) *mServiceMockCouponRemove {
+	if mmCouponRemove.mock.inspectFuncCouponRemove != nil {
+		mmCouponRemove.mock.t.Fatalf("Inspect function is already set for ServiceMock.CouponRemove")
+	}
+
+	mmCouponRemove.mock.inspectFuncCouponRemove = f
+
+	return mmCouponRemove
+}
+
+// Return sets up results that will be returned by service.CouponRemove
+func (mmCouponRemove *mServiceMockCouponRemove) Return(i1 int64, err error) *ServiceMock {
+	if mmCouponRemove.mock.funcCouponRemove != nil {
+		mmCouponRemove.mock.t.Fatalf("ServiceMock.CouponRemove mock is already set by Set")
+	}
+
+	if mmCouponRemove.defaultExpectation == nil {
+		mmCouponRemove.defaultExpectation = &ServiceMockCouponRemoveExpectation{mock: mmCouponRemove.mock}
+	}
+	mmCouponRemove.defaultExpectation.results = &ServiceMockCouponRemoveResults{i1, err}
+	return mmCouponRemove.mock
+}
+
+//Set uses given function f to mock the service.CouponRemove method
+func (mmCouponRemove *mServiceMockCouponRemove) Set(f func(ctx context.Context, cartID int64, code string) (i1 int64, err error)) *ServiceMock {
+	if mmCouponRemove.defaultExpectation != nil {
+		mmCouponRemove.mock.t.Fatalf("Default expectation is already set for the service.CouponRemove method")
+	}
+
+	if len(mmCouponRemove.expectations) > 0 {
+		mmCouponRemove.mock.t.Fatalf("Some expectations are already set for the service.CouponRemove method")
+	}
+
+	mmCouponRemove.mock.funcCouponRemove = f
+	return mmCouponRemove.mock
+}
+
+// When sets expectation for the service.CouponRemove which will trigger the result defined by the following
+// Then helper
+func (mmCouponRemove *mServiceMockCouponRemove) When(ctx context.Context, cartID int64, code string) *ServiceMockCouponRemoveExpectation {
+	if mmCouponRemove.mock.funcCouponRemove != nil {
+		mmCouponRemove.mock.t.Fatalf("ServiceMock.CouponRemove mock is already set by Set")
+	}
+
+	expectation := &ServiceMockCouponRemoveExpectation{
+		mock:   mmCouponRemove.mock,
+		params: &ServiceMockCouponRemoveParams{ctx, cartID, code},
+	}
+	mmCouponRemove.expectations = append(mmCouponRemove.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.CouponRemove return parameters for the expectation previously defined by the When method
+func (e *ServiceMockCouponRemoveExpectation) Then(i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockCouponRemoveResults{i1, err}
+	return e.mock
+}
+
+// CouponRemove implements service
+func (mmCouponRemove *ServiceMock) CouponRemove(ctx context.Context, cartID int64, code string) (i1 int64, err error) {
+	mm_atomic.AddUint64(&mmCouponRemove.beforeCouponRemoveCounter, 1)
+	defer mm_atomic.AddUint64(&mmCouponRemove.afterCouponRemoveCounter, 1)
+
+	if mmCouponRemove.inspectFuncCouponRemove != nil {
+		mmCouponRemove.inspectFuncCouponRemove(ctx, cartID, code)
+	}
+
+	mm_params := &ServiceMockCouponRemoveParams{ctx, cartID, code}
+
+	// Record call args
+	mmCouponRemove.CouponRemoveMock.mutex.Lock()
+	mmCouponRemove.CouponRemoveMock.callArgs = append(mmCouponRemove.CouponRemoveMock.callArgs, mm_params)
+	mmCouponRemove.CouponRemoveMock.mutex.Unlock()
+
+	for _, e := range mmCouponRemove.CouponRemoveMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.i1, e.results.err
+		}
+	}
+
+	if mmCouponRemove.CouponRemoveMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCouponRemove.CouponRemoveMock.defaultExpectation.Counter, 1)
+		mm_want := mmCouponRemove.CouponRemoveMock.defaultExpectation.params
+		mm_got := ServiceMockCouponRemoveParams{ctx, cartID, code}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCouponRemove.t.Errorf("ServiceMock.CouponRemove got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCouponRemove.CouponRemoveMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCouponRemove.t.Fatal("No results are set for the ServiceMock.CouponRemove")
+		}
+		return (*mm_results).i1, (*mm_results).err
+	}
+	if mmCouponRemove.funcCouponRemove != nil {
+		return mmCouponRemove.funcCouponRemove(ctx, cartID, code)
+	}
+	mmCouponRemove.t.Fatalf("Unexpected call to ServiceMock.CouponRemove. %v %v %v", ctx, cartID, code)
+	return
+}
+
+// CouponRemoveAfterCounter returns a count of finished ServiceMock.CouponRemove invocations
+func (mmCouponRemove *ServiceMock) CouponRemoveAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCouponRemove.afterCouponRemoveCounter)
+}
+
+// CouponRemoveBeforeCounter returns a count of ServiceMock.CouponRemove invocations
+func (mmCouponRemove *ServiceMock) CouponRemoveBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCouponRemove.beforeCouponRemoveCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.CouponRemove.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCouponRemove *mServiceMockCouponRemove) Calls() []*ServiceMockCouponRemoveParams {
+	mmCouponRemove.mutex.RLock()
+
+	argCopy := make([]*ServiceMockCouponRemoveParams, len(mmCouponRemove.callArgs))
+	copy(argCopy, mmCouponRemove.callArgs)
+
+	mmCouponRemove.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCouponRemoveDone returns true if the count of the CouponRemove invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockCouponRemoveDone() bool {
+	for _, e := range m.CouponRemoveMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CouponRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCouponRemoveCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCouponRemove != nil && mm_atomic.LoadUint64(&m.afterCouponRemoveCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCouponRemoveInspect logs each unmet expectation
+func (m *ServiceMock) MinimockCouponRemoveInspect() {
+	for _, e := range m.CouponRemoveMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.CouponRemove with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CouponRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCouponRemoveCounter) < 1 {
+		if m.CouponRemoveMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.CouponRemove")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.CouponRemove with params: %#v", *m.CouponRemoveMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCouponRemove != nil && mm_atomic.LoadUint64(&m.afterCouponRemoveCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.CouponRemove")
+	}
+}
+
+type mServiceMockLineItemAdd struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockLineItemAddExpectation
+	expectations       []*ServiceMockLineItemAddExpectation
+
+	callArgs []*ServiceMockLineItemAddParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockLineItemAddExpectation specifies expectation struct of the service.LineItemAdd
+type ServiceMockLineItemAddExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockLineItemAddParams
+	results *ServiceMockLineItemAddResults
+	Counter uint64
+}
+
+// ServiceMockLineItemAddParams contains parameters of the service.LineItemAdd
+type ServiceMockLineItemAddParams struct {
+	ctx    context.Context
+	cartID int64
+	items  []*LineItem
+}
+
+// ServiceMockLineItemAddResults contains results of the service.LineItemAdd
+type ServiceMockLineItemAddResults struct {
+	lpa1 []*LineItem
+	i1   int64
+	err  error
+}
+
+// Expect sets up expected params for service.LineItemAdd
+func (mmLineItemAdd *mServiceMockLineItemAdd) Expect(ctx context.Context, cartID int64, items []*LineItem) *mServiceMockLineItemAdd {
+	if mmLineItemAdd.mock.funcLineItemAdd != nil {
+		mmLineItemAdd.mock.t.Fatalf("ServiceMock.LineItemAdd mock is already set by Set")
+	}
+
+	if mmLineItemAdd.defaultExpectation == nil {
 		mmLineItemAdd.defaultExpectation = &ServiceMockLineItemAddExpectation{}
 	}
 
@@ -786,7 +3076,7 @@ func (mmLineItemAdd *mServiceMockLineItemAdd) Inspect(f func(ctx context.Context
 }
 
 // Return sets up results that will be returned by service.LineItemAdd
-func (mmLineItemAdd *mServiceMockLineItemAdd) Return(lpa1 []*LineItem, err error) *ServiceMock {
+func (mmLineItemAdd *mServiceMockLineItemAdd) Return(lpa1 []*LineItem, i1 int64, err error) *ServiceMock {
 	if mmLineItemAdd.mock.funcLineItemAdd != nil {
 		mmLineItemAdd.mock.t.Fatalf("ServiceMock.LineItemAdd mock is already set by Set")
 	}
@@ -794,12 +3084,12 @@ func (mmLineItemAdd *mServiceMockLineItemAdd) Return(lpa1 []*LineItem, err error
 	if mmLineItemAdd.defaultExpectation == nil {
 		mmLineItemAdd.defaultExpectation = &ServiceMockLineItemAddExpectation{mock: mmLineItemAdd.mock}
 	}
-	mmLineItemAdd.defaultExpectation.results = &ServiceMockLineItemAddResults{lpa1, err}
+	mmLineItemAdd.defaultExpectation.results = &ServiceMockLineItemAddResults{lpa1, i1, err}
 	return mmLineItemAdd.mock
 }
 
 //Set uses given function f to mock the service.LineItemAdd method
-func (mmLineItemAdd *mServiceMockLineItemAdd) Set(f func(ctx context.Context, cartID int64, items []*LineItem) (lpa1 []*LineItem, err error)) *ServiceMock {
+func (mmLineItemAdd *mServiceMockLineItemAdd) Set(f func(ctx context.Context, cartID int64, items []*LineItem) (lpa1 []*LineItem, i1 int64, err error)) *ServiceMock {
 	if mmLineItemAdd.defaultExpectation != nil {
 		mmLineItemAdd.mock.t.Fatalf("Default expectation is already set for the service.LineItemAdd method")
 	}
@@ -828,13 +3118,13 @@ func (mmLineItemAdd *mServiceMockLineItemAdd) When(ctx context.Context, cartID i
 }
 
 // Then sets up service.LineItemAdd return parameters for the expectation previously defined by the When method
-func (e *ServiceMockLineItemAddExpectation) Then(lpa1 []*LineItem, err error) *ServiceMock {
-	e.results = &ServiceMockLineItemAddResults{lpa1, err}
+func (e *ServiceMockLineItemAddExpectation) Then(lpa1 []*LineItem, i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockLineItemAddResults{lpa1, i1, err}
 	return e.mock
 }
 
 // LineItemAdd implements service
-func (mmLineItemAdd *ServiceMock) LineItemAdd(ctx context.Context, cartID int64, items []*LineItem) (lpa1 []*LineItem, err error) {
+func (mmLineItemAdd *ServiceMock) LineItemAdd(ctx context.Context, cartID int64, items []*LineItem) (lpa1 []*LineItem, i1 int64, err error) {
 	mm_atomic.AddUint64(&mmLineItemAdd.beforeLineItemAddCounter, 1)
 	defer mm_atomic.AddUint64(&mmLineItemAdd.afterLineItemAddCounter, 1)
 
@@ -852,7 +3142,7 @@ func (mmLineItemAdd *ServiceMock) LineItemAdd(ctx context.Context, cartID int64,
 	for _, e := range mmLineItemAdd.LineItemAddMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.lpa1, e.results.err
+			return e.results.lpa1, e.results.i1, e.results.err
 		}
 	}
 
@@ -868,7 +3158,7 @@ func (mmLineItemAdd *ServiceMock) LineItemAdd(ctx context.Context, cartID int64,
 		if mm_results == nil {
 			mmLineItemAdd.t.Fatal("No results are set for the ServiceMock.LineItemAdd")
 		}
-		return (*mm_results).lpa1, (*mm_results).err
+		return (*mm_results).lpa1, (*mm_results).i1, (*mm_results).err
 	}
 	if mmLineItemAdd.funcLineItemAdd != nil {
 		return mmLineItemAdd.funcLineItemAdd(ctx, cartID, items)
@@ -968,6 +3258,7 @@ type ServiceMockLineItemRemoveParams struct {
 
 // ServiceMockLineItemRemoveResults contains results of the service.LineItemRemove
 type ServiceMockLineItemRemoveResults struct {
+	i1  int64
 	err error
 }
 
@@ -1003,7 +3294,7 @@ func (mmLineItemRemove *mServiceMockLineItemRemove) Inspect(f func(ctx context.C
 }
 
 // Return sets up results that will be returned by service.LineItemRemove
-func (mmLineItemRemove *mServiceMockLineItemRemove) Return(err error) *ServiceMock {
+func (mmLineItemRemove *mServiceMockLineItemRemove) Return(i1 int64, err error) *ServiceMock {
 	if mmLineItemRemove.mock.funcLineItemRemove != nil {
 		mmLineItemRemove.mock.t.Fatalf("ServiceMock.LineItemRemove mock is already set by Set")
 	}
@@ -1011,12 +3302,12 @@ func (mmLineItemRemove *mServiceMockLineItemRemove) Return(err error) *ServiceMo
 	if mmLineItemRemove.defaultExpectation == nil {
 		mmLineItemRemove.defaultExpectation = &ServiceMockLineItemRemoveExpectation{mock: mmLineItemRemove.mock}
 	}
-	mmLineItemRemove.defaultExpectation.results = &ServiceMockLineItemRemoveResults{err}
+	mmLineItemRemove.defaultExpectation.results = &ServiceMockLineItemRemoveResults{i1, err}
 	return mmLineItemRemove.mock
 }
 
 //Set uses given function f to mock the service.LineItemRemove method
-func (mmLineItemRemove *mServiceMockLineItemRemove) Set(f func(ctx context.Context, cartID int64, itemID int64) (err error)) *ServiceMock {
+func (mmLineItemRemove *mServiceMockLineItemRemove) Set(f func(ctx context.Context, cartID int64, itemID int64) (i1 int64, err error)) *ServiceMock {
 	if mmLineItemRemove.defaultExpectation != nil {
 		mmLineItemRemove.mock.t.Fatalf("Default expectation is already set for the service.LineItemRemove method")
 	}
@@ -1045,13 +3336,13 @@ func (mmLineItemRemove *mServiceMockLineItemRemove) When(ctx context.Context, ca
 }
 
 // Then sets up service.LineItemRemove return parameters for the expectation previously defined by the When method
-func (e *ServiceMockLineItemRemoveExpectation) Then(err error) *ServiceMock {
-	e.results = &ServiceMockLineItemRemoveResults{err}
+func (e *ServiceMockLineItemRemoveExpectation) Then(i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockLineItemRemoveResults{i1, err}
 	return e.mock
 }
 
 // LineItemRemove implements service
-func (mmLineItemRemove *ServiceMock) LineItemRemove(ctx context.Context, cartID int64, itemID int64) (err error) {
+func (mmLineItemRemove *ServiceMock) LineItemRemove(ctx context.Context, cartID int64, itemID int64) (i1 int64, err error) {
 	mm_atomic.AddUint64(&mmLineItemRemove.beforeLineItemRemoveCounter, 1)
 	defer mm_atomic.AddUint64(&mmLineItemRemove.afterLineItemRemoveCounter, 1)
 
@@ -1069,7 +3360,7 @@ func (mmLineItemRemove *ServiceMock) LineItemRemove(ctx context.Context, cartID
 	for _, e := range mmLineItemRemove.LineItemRemoveMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.err
+			return e.results.i1, e.results.err
 		}
 	}
 
@@ -1085,7 +3376,7 @@ func (mmLineItemRemove *ServiceMock) LineItemRemove(ctx context.Context, cartID
 		if mm_results == nil {
 			mmLineItemRemove.t.Fatal("No results are set for the ServiceMock.LineItemRemove")
 		}
-		return (*mm_results).err
+		return (*mm_results).i1, (*mm_results).err
 	}
 	if mmLineItemRemove.funcLineItemRemove != nil {
 		return mmLineItemRemove.funcLineItemRemove(ctx, cartID, itemID)
@@ -1159,18 +3450,479 @@ func (m *ServiceMock) MinimockLineItemRemoveInspect() {
 	}
 }
 
+type mServiceMockLineItemSetQuantity struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockLineItemSetQuantityExpectation
+	expectations       []*ServiceMockLineItemSetQuantityExpectation
+
+	callArgs []*ServiceMockLineItemSetQuantityParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockLineItemSetQuantityExpectation specifies expectation struct of the service.LineItemSetQuantity
+type ServiceMockLineItemSetQuantityExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockLineItemSetQuantityParams
+	results *ServiceMockLineItemSetQuantityResults
+	Counter uint64
+}
+
+// ServiceMockLineItemSetQuantityParams contains parameters of the service.LineItemSetQuantity
+type ServiceMockLineItemSetQuantityParams struct {
+	ctx      context.Context
+	cartID   int64
+	itemID   int64
+	quantity int64
+}
+
+// ServiceMockLineItemSetQuantityResults contains results of the service.LineItemSetQuantity
+type ServiceMockLineItemSetQuantityResults struct {
+	lp1 *LineItem
+	i1  int64
+	err error
+}
+
+// Expect sets up expected params for service.LineItemSetQuantity
+func (mmLineItemSetQuantity *mServiceMockLineItemSetQuantity) Expect(ctx context.Context, cartID int64, itemID int64, quantity int64) *mServiceMockLineItemSetQuantity {
+	if mmLineItemSetQuantity.mock.funcLineItemSetQuantity != nil {
+		mmLineItemSetQuantity.mock.t.Fatalf("ServiceMock.LineItemSetQuantity mock is already set by Set")
+	}
+
+	if mmLineItemSetQuantity.defaultExpectation == nil {
+		mmLineItemSetQuantity.defaultExpectation = &ServiceMockLineItemSetQuantityExpectation{}
+	}
+
+	mmLineItemSetQuantity.defaultExpectation.params = &ServiceMockLineItemSetQuantityParams{ctx, cartID, itemID, quantity}
+	for _, e := range mmLineItemSetQuantity.expectations {
+		if minimock.Equal(e.params, mmLineItemSetQuantity.defaultExpectation.params) {
+			mmLineItemSetQuantity.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmLineItemSetQuantity.defaultExpectation.params)
+		}
+	}
+
+	return mmLineItemSetQuantity
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.LineItemSetQuantity
+func (mmLineItemSetQuantity *mServiceMockLineItemSetQuantity) Inspect(f func(ctx context.Context, cartID int64, itemID int64, quantity int64)) *mServiceMockLineItemSetQuantity {
+	if mmLineItemSetQuantity.mock.inspectFuncLineItemSetQuantity != nil {
+		mmLineItemSetQuantity.mock.t.Fatalf("Inspect function is already set for ServiceMock.LineItemSetQuantity")
+	}
+
+	mmLineItemSetQuantity.mock.inspectFuncLineItemSetQuantity = f
+
+	return mmLineItemSetQuantity
+}
+
+// Return sets up results that will be returned by service.LineItemSetQuantity
+func (mmLineItemSetQuantity *mServiceMockLineItemSetQuantity) Return(lp1 *LineItem, i1 int64, err error) *ServiceMock {
+	if mmLineItemSetQuantity.mock.funcLineItemSetQuantity != nil {
+		mmLineItemSetQuantity.mock.t.Fatalf("ServiceMock.LineItemSetQuantity mock is already set by Set")
+	}
+
+	if mmLineItemSetQuantity.defaultExpectation == nil {
+		mmLineItemSetQuantity.defaultExpectation = &ServiceMockLineItemSetQuantityExpectation{mock: mmLineItemSetQuantity.mock}
+	}
+	mmLineItemSetQuantity.defaultExpectation.results = &ServiceMockLineItemSetQuantityResults{lp1, i1, err}
+	return mmLineItemSetQuantity.mock
+}
+
+//Set uses given function f to mock the service.LineItemSetQuantity method
+func (mmLineItemSetQuantity *mServiceMockLineItemSetQuantity) Set(f func(ctx context.Context, cartID int64, itemID int64, quantity int64) (lp1 *LineItem, i1 int64, err error)) *ServiceMock {
+	if mmLineItemSetQuantity.defaultExpectation != nil {
+		mmLineItemSetQuantity.mock.t.Fatalf("Default expectation is already set for the service.LineItemSetQuantity method")
+	}
+
+	if len(mmLineItemSetQuantity.expectations) > 0 {
+		mmLineItemSetQuantity.mock.t.Fatalf("Some expectations are already set for the service.LineItemSetQuantity method")
+	}
+
+	mmLineItemSetQuantity.mock.funcLineItemSetQuantity = f
+	return mmLineItemSetQuantity.mock
+}
+
+// When sets expectation for the service.LineItemSetQuantity which will trigger the result defined by the following
+// Then helper
+func (mmLineItemSetQuantity *mServiceMockLineItemSetQuantity) When(ctx context.Context, cartID int64, itemID int64, quantity int64) *ServiceMockLineItemSetQuantityExpectation {
+	if mmLineItemSetQuantity.mock.funcLineItemSetQuantity != nil {
+		mmLineItemSetQuantity.mock.t.Fatalf("ServiceMock.LineItemSetQuantity mock is already set by Set")
+	}
+
+	expectation := &ServiceMockLineItemSetQuantityExpectation{
+		mock:   mmLineItemSetQuantity.mock,
+		params: &ServiceMockLineItemSetQuantityParams{ctx, cartID, itemID, quantity},
+	}
+	mmLineItemSetQuantity.expectations = append(mmLineItemSetQuantity.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.LineItemSetQuantity return parameters for the expectation previously defined by the When method
+func (e *ServiceMockLineItemSetQuantityExpectation) Then(lp1 *LineItem, i1 int64, err error) *ServiceMock {
+	e.results = &ServiceMockLineItemSetQuantityResults{lp1, i1, err}
+	return e.mock
+}
+
+// LineItemSetQuantity implements service
+func (mmLineItemSetQuantity *ServiceMock) LineItemSetQuantity(ctx context.Context, cartID int64, itemID int64, quantity int64) (lp1 *LineItem, i1 int64, err error) {
+	mm_atomic.AddUint64(&mmLineItemSetQuantity.beforeLineItemSetQuantityCounter, 1)
+	defer mm_atomic.AddUint64(&mmLineItemSetQuantity.afterLineItemSetQuantityCounter, 1)
+
+	if mmLineItemSetQuantity.inspectFuncLineItemSetQuantity != nil {
+		mmLineItemSetQuantity.inspectFuncLineItemSetQuantity(ctx, cartID, itemID, quantity)
+	}
+
+	mm_params := &ServiceMockLineItemSetQuantityParams{ctx, cartID, itemID, quantity}
+
+	// Record call args
+	mmLineItemSetQuantity.LineItemSetQuantityMock.mutex.Lock()
+	mmLineItemSetQuantity.LineItemSetQuantityMock.callArgs = append(mmLineItemSetQuantity.LineItemSetQuantityMock.callArgs, mm_params)
+	mmLineItemSetQuantity.LineItemSetQuantityMock.mutex.Unlock()
+
+	for _, e := range mmLineItemSetQuantity.LineItemSetQuantityMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.lp1, e.results.i1, e.results.err
+		}
+	}
+
+	if mmLineItemSetQuantity.LineItemSetQuantityMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmLineItemSetQuantity.LineItemSetQuantityMock.defaultExpectation.Counter, 1)
+		mm_want := mmLineItemSetQuantity.LineItemSetQuantityMock.defaultExpectation.params
+		mm_got := ServiceMockLineItemSetQuantityParams{ctx, cartID, itemID, quantity}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmLineItemSetQuantity.t.Errorf("ServiceMock.LineItemSetQuantity got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmLineItemSetQuantity.LineItemSetQuantityMock.defaultExpectation.results
+		if mm_results == nil {
+			mmLineItemSetQuantity.t.Fatal("No results are set for the ServiceMock.LineItemSetQuantity")
+		}
+		return (*mm_results).lp1, (*mm_results).i1, (*mm_results).err
+	}
+	if mmLineItemSetQuantity.funcLineItemSetQuantity != nil {
+		return mmLineItemSetQuantity.funcLineItemSetQuantity(ctx, cartID, itemID, quantity)
+	}
+	mmLineItemSetQuantity.t.Fatalf("Unexpected call to ServiceMock.LineItemSetQuantity. %v %v %v %v", ctx, cartID, itemID, quantity)
+	return
+}
+
+// LineItemSetQuantityAfterCounter returns a count of finished ServiceMock.LineItemSetQuantity invocations
+func (mmLineItemSetQuantity *ServiceMock) LineItemSetQuantityAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmLineItemSetQuantity.afterLineItemSetQuantityCounter)
+}
+
+// LineItemSetQuantityBeforeCounter returns a count of ServiceMock.LineItemSetQuantity invocations
+func (mmLineItemSetQuantity *ServiceMock) LineItemSetQuantityBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmLineItemSetQuantity.beforeLineItemSetQuantityCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.LineItemSetQuantity.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmLineItemSetQuantity *mServiceMockLineItemSetQuantity) Calls() []*ServiceMockLineItemSetQuantityParams {
+	mmLineItemSetQuantity.mutex.RLock()
+
+	argCopy := make([]*ServiceMockLineItemSetQuantityParams, len(mmLineItemSetQuantity.callArgs))
+	copy(argCopy, mmLineItemSetQuantity.callArgs)
+
+	mmLineItemSetQuantity.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockLineItemSetQuantityDone returns true if the count of the LineItemSetQuantity invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockLineItemSetQuantityDone() bool {
+	for _, e := range m.LineItemSetQuantityMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.LineItemSetQuantityMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemSetQuantityCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcLineItemSetQuantity != nil && mm_atomic.LoadUint64(&m.afterLineItemSetQuantityCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockLineItemSetQuantityInspect logs each unmet expectation
+func (m *ServiceMock) MinimockLineItemSetQuantityInspect() {
+	for _, e := range m.LineItemSetQuantityMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.LineItemSetQuantity with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.LineItemSetQuantityMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemSetQuantityCounter) < 1 {
+		if m.LineItemSetQuantityMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.LineItemSetQuantity")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.LineItemSetQuantity with params: %#v", *m.LineItemSetQuantityMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcLineItemSetQuantity != nil && mm_atomic.LoadUint64(&m.afterLineItemSetQuantityCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.LineItemSetQuantity")
+	}
+}
+
+type mServiceMockShippingMethods struct {
+	mock               *ServiceMock
+	defaultExpectation *ServiceMockShippingMethodsExpectation
+	expectations       []*ServiceMockShippingMethodsExpectation
+
+	callArgs []*ServiceMockShippingMethodsParams
+	mutex    sync.RWMutex
+}
+
+// ServiceMockShippingMethodsExpectation specifies expectation struct of the service.ShippingMethods
+type ServiceMockShippingMethodsExpectation struct {
+	mock    *ServiceMock
+	params  *ServiceMockShippingMethodsParams
+	results *ServiceMockShippingMethodsResults
+	Counter uint64
+}
+
+// ServiceMockShippingMethodsParams contains parameters of the service.ShippingMethods
+type ServiceMockShippingMethodsParams struct {
+	ctx    context.Context
+	cartID int64
+}
+
+// ServiceMockShippingMethodsResults contains results of the service.ShippingMethods
+type ServiceMockShippingMethodsResults struct {
+	sa1 []ShippingRate
+	err error
+}
+
+// Expect sets up expected params for service.ShippingMethods
+func (mmShippingMethods *mServiceMockShippingMethods) Expect(ctx context.Context, cartID int64) *mServiceMockShippingMethods {
+	if mmShippingMethods.mock.funcShippingMethods != nil {
+		mmShippingMethods.mock.t.Fatalf("ServiceMock.ShippingMethods mock is already set by Set")
+	}
+
+	if mmShippingMethods.defaultExpectation == nil {
+		mmShippingMethods.defaultExpectation = &ServiceMockShippingMethodsExpectation{}
+	}
+
+	mmShippingMethods.defaultExpectation.params = &ServiceMockShippingMethodsParams{ctx, cartID}
+	for _, e := range mmShippingMethods.expectations {
+		if minimock.Equal(e.params, mmShippingMethods.defaultExpectation.params) {
+			mmShippingMethods.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmShippingMethods.defaultExpectation.params)
+		}
+	}
+
+	return mmShippingMethods
+}
+
+// Inspect accepts an inspector function that has same arguments as the service.ShippingMethods
+func (mmShippingMethods *mServiceMockShippingMethods) Inspect(f func(ctx context.Context, cartID int64)) *mServiceMockShippingMethods {
+	if mmShippingMethods.mock.inspectFuncShippingMethods != nil {
+		mmShippingMethods.mock.t.Fatalf("Inspect function is already set for ServiceMock.ShippingMethods")
+	}
+
+	mmShippingMethods.mock.inspectFuncShippingMethods = f
+
+	return mmShippingMethods
+}
+
+// Return sets up results that will be returned by service.ShippingMethods
+func (mmShippingMethods *mServiceMockShippingMethods) Return(sa1 []ShippingRate, err error) *ServiceMock {
+	if mmShippingMethods.mock.funcShippingMethods != nil {
+		mmShippingMethods.mock.t.Fatalf("ServiceMock.ShippingMethods mock is already set by Set")
+	}
+
+	if mmShippingMethods.defaultExpectation == nil {
+		mmShippingMethods.defaultExpectation = &ServiceMockShippingMethodsExpectation{mock: mmShippingMethods.mock}
+	}
+	mmShippingMethods.defaultExpectation.results = &ServiceMockShippingMethodsResults{sa1, err}
+	return mmShippingMethods.mock
+}
+
+//Set uses given function f to mock the service.ShippingMethods method
+func (mmShippingMethods *mServiceMockShippingMethods) Set(f func(ctx context.Context, cartID int64) (sa1 []ShippingRate, err error)) *ServiceMock {
+	if mmShippingMethods.defaultExpectation != nil {
+		mmShippingMethods.mock.t.Fatalf("Default expectation is already set for the service.ShippingMethods method")
+	}
+
+	if len(mmShippingMethods.expectations) > 0 {
+		mmShippingMethods.mock.t.Fatalf("Some expectations are already set for the service.ShippingMethods method")
+	}
+
+	mmShippingMethods.mock.funcShippingMethods = f
+	return mmShippingMethods.mock
+}
+
+// When sets expectation for the service.ShippingMethods which will trigger the result defined by the following
+// Then helper
+func (mmShippingMethods *mServiceMockShippingMethods) When(ctx context.Context, cartID int64) *ServiceMockShippingMethodsExpectation {
+	if mmShippingMethods.mock.funcShippingMethods != nil {
+		mmShippingMethods.mock.t.Fatalf("ServiceMock.ShippingMethods mock is already set by Set")
+	}
+
+	expectation := &ServiceMockShippingMethodsExpectation{
+		mock:   mmShippingMethods.mock,
+		params: &ServiceMockShippingMethodsParams{ctx, cartID},
+	}
+	mmShippingMethods.expectations = append(mmShippingMethods.expectations, expectation)
+	return expectation
+}
+
+// Then sets up service.ShippingMethods return parameters for the expectation previously defined by the When method
+func (e *ServiceMockShippingMethodsExpectation) Then(sa1 []ShippingRate, err error) *ServiceMock {
+	e.results = &ServiceMockShippingMethodsResults{sa1, err}
+	return e.mock
+}
+
+// ShippingMethods implements service
+func (mmShippingMethods *ServiceMock) ShippingMethods(ctx context.Context, cartID int64) (sa1 []ShippingRate, err error) {
+	mm_atomic.AddUint64(&mmShippingMethods.beforeShippingMethodsCounter, 1)
+	defer mm_atomic.AddUint64(&mmShippingMethods.afterShippingMethodsCounter, 1)
+
+	if mmShippingMethods.inspectFuncShippingMethods != nil {
+		mmShippingMethods.inspectFuncShippingMethods(ctx, cartID)
+	}
+
+	mm_params := &ServiceMockShippingMethodsParams{ctx, cartID}
+
+	// Record call args
+	mmShippingMethods.ShippingMethodsMock.mutex.Lock()
+	mmShippingMethods.ShippingMethodsMock.callArgs = append(mmShippingMethods.ShippingMethodsMock.callArgs, mm_params)
+	mmShippingMethods.ShippingMethodsMock.mutex.Unlock()
+
+	for _, e := range mmShippingMethods.ShippingMethodsMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.sa1, e.results.err
+		}
+	}
+
+	if mmShippingMethods.ShippingMethodsMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmShippingMethods.ShippingMethodsMock.defaultExpectation.Counter, 1)
+		mm_want := mmShippingMethods.ShippingMethodsMock.defaultExpectation.params
+		mm_got := ServiceMockShippingMethodsParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmShippingMethods.t.Errorf("ServiceMock.ShippingMethods got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmShippingMethods.ShippingMethodsMock.defaultExpectation.results
+		if mm_results == nil {
+			mmShippingMethods.t.Fatal("No results are set for the ServiceMock.ShippingMethods")
+		}
+		return (*mm_results).sa1, (*mm_results).err
+	}
+	if mmShippingMethods.funcShippingMethods != nil {
+		return mmShippingMethods.funcShippingMethods(ctx, cartID)
+	}
+	mmShippingMethods.t.Fatalf("Unexpected call to ServiceMock.ShippingMethods. %v %v", ctx, cartID)
+	return
+}
+
+// ShippingMethodsAfterCounter returns a count of finished ServiceMock.ShippingMethods invocations
+func (mmShippingMethods *ServiceMock) ShippingMethodsAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmShippingMethods.afterShippingMethodsCounter)
+}
+
+// ShippingMethodsBeforeCounter returns a count of ServiceMock.ShippingMethods invocations
+func (mmShippingMethods *ServiceMock) ShippingMethodsBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmShippingMethods.beforeShippingMethodsCounter)
+}
+
+// Calls returns a list of arguments used in each call to ServiceMock.ShippingMethods.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmShippingMethods *mServiceMockShippingMethods) Calls() []*ServiceMockShippingMethodsParams {
+	mmShippingMethods.mutex.RLock()
+
+	argCopy := make([]*ServiceMockShippingMethodsParams, len(mmShippingMethods.callArgs))
+	copy(argCopy, mmShippingMethods.callArgs)
+
+	mmShippingMethods.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockShippingMethodsDone returns true if the count of the ShippingMethods invocations corresponds
+// the number of defined expectations
+func (m *ServiceMock) MinimockShippingMethodsDone() bool {
+	for _, e := range m.ShippingMethodsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ShippingMethodsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterShippingMethodsCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcShippingMethods != nil && mm_atomic.LoadUint64(&m.afterShippingMethodsCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockShippingMethodsInspect logs each unmet expectation
+func (m *ServiceMock) MinimockShippingMethodsInspect() {
+	for _, e := range m.ShippingMethodsMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to ServiceMock.ShippingMethods with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ShippingMethodsMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterShippingMethodsCounter) < 1 {
+		if m.ShippingMethodsMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to ServiceMock.ShippingMethods")
+		} else {
+			m.t.Errorf("Expected call to ServiceMock.ShippingMethods with params: %#v", *m.ShippingMethodsMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcShippingMethods != nil && mm_atomic.LoadUint64(&m.afterShippingMethodsCounter) < 1 {
+		m.t.Error("Expected call to ServiceMock.ShippingMethods")
+	}
+}
+
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *ServiceMock) MinimockFinish() {
 	if !m.minimockDone() {
+		m.MinimockActiveCartInspect()
+
 		m.MinimockCartCreateInspect()
 
 		m.MinimockCartEmptyInspect()
 
+		m.MinimockCartMergeInspect()
+
+		m.MinimockCartPayInspect()
+
+		m.MinimockCartRepriceInspect()
+
+		m.MinimockCartShippingAddressSetInspect()
+
+		m.MinimockCartShippingMethodSetInspect()
+
 		m.MinimockCartShowInspect()
 
+		m.MinimockCartsByUserInspect()
+
+		m.MinimockCheckoutInspect()
+
+		m.MinimockCouponApplyInspect()
+
+		m.MinimockCouponRemoveInspect()
+
 		m.MinimockLineItemAddInspect()
 
 		m.MinimockLineItemRemoveInspect()
+
+		m.MinimockLineItemSetQuantityInspect()
+
+		m.MinimockShippingMethodsInspect()
 		m.t.FailNow()
 	}
 }
@@ -1194,9 +3946,21 @@ func (m *ServiceMock) MinimockWait(timeout mm_time.Duration) {
 func (m *ServiceMock) minimockDone() bool {
 	done := true
 	return done &&
+		m.MinimockActiveCartDone() &&
 		m.MinimockCartCreateDone() &&
 		m.MinimockCartEmptyDone() &&
+		m.MinimockCartMergeDone() &&
+		m.MinimockCartPayDone() &&
+		m.MinimockCartRepriceDone() &&
+		m.MinimockCartShippingAddressSetDone() &&
+		m.MinimockCartShippingMethodSetDone() &&
 		m.MinimockCartShowDone() &&
+		m.MinimockCartsByUserDone() &&
+		m.MinimockCheckoutDone() &&
+		m.MinimockCouponApplyDone() &&
+		m.MinimockCouponRemoveDone() &&
 		m.MinimockLineItemAddDone() &&
-		m.MinimockLineItemRemoveDone()
+		m.MinimockLineItemRemoveDone() &&
+		m.MinimockLineItemSetQuantityDone() &&
+		m.MinimockShippingMethodsDone()
 }
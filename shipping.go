@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ShippingRater rates shipping of carts.
+type ShippingRater interface {
+	// ShippingRates returns the shipping methods available for the enriched items and the
+	// shipping address of the cart, with their costs.
+	ShippingRates(ctx context.Context, c *Cart) ([]ShippingRate, error)
+}
+
+// ShippingRate is the cost of shipping a cart by a shipping method.
+type ShippingRate struct {
+	Code   string
+	Name   string
+	Amount Money
+}
+
+// ShippingMethod is a shipping method of a shipping table.
+type ShippingMethod struct {
+	Code        string
+	Name        string
+	Countries   []string // every country if empty
+	Amount      Money    // per shipment
+	PerItem     Money    // per unit of the items
+	MaxQuantity int64    // of all the items, unlimited if 0
+}
+
+// ShippingTable is a shipping rater of fixed shipping methods. Methods are available for carts
+// with priced items in the currency of the method and a shipping address in one of the
+// countries of the method.
+type ShippingTable []ShippingMethod
+
+// LoadShippingTable reads a shipping table from a JSON file of the form
+// [{"code": "standard", "name": "Standard", "countries": ["DE", "AT"], "amount": 490, "per_item": 0,
+// "currency": "EUR", "max_quantity": 0}].
+func LoadShippingTable(path string) (ShippingTable, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mm []struct {
+		Code        string   `json:"code"`
+		Name        string   `json:"name"`
+		Countries   []string `json:"countries"`
+		Amount      int64    `json:"amount"`
+		PerItem     int64    `json:"per_item"`
+		Currency    string   `json:"currency"`
+		MaxQuantity int64    `json:"max_quantity"`
+	}
+	if err := json.Unmarshal(b, &mm); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	t := make(ShippingTable, len(mm))
+	codes := make(map[string]bool, len(mm))
+	for j, m := range mm {
+		switch {
+		case m.Code == "":
+			return nil, fmt.Errorf("method %d: code is empty: %w", j, ErrInvalidArgument)
+		case codes[m.Code]:
+			return nil, fmt.Errorf("method %d: code %q is not unique: %w", j, m.Code, ErrInvalidArgument)
+		case m.Currency == "":
+			return nil, fmt.Errorf("method %q: currency is empty: %w", m.Code, ErrInvalidArgument)
+		case m.Amount < 0, m.PerItem < 0, m.MaxQuantity < 0:
+			return nil, fmt.Errorf("method %q: negative amount or quantity: %w", m.Code, ErrInvalidArgument)
+		}
+		codes[m.Code] = true
+
+		method := ShippingMethod{
+			Code:        m.Code,
+			Name:        m.Name,
+			Amount:      Money{Amount: m.Amount, Currency: m.Currency},
+			PerItem:     Money{Amount: m.PerItem, Currency: m.Currency},
+			MaxQuantity: m.MaxQuantity,
+		}
+		for _, c := range m.Countries {
+			method.Countries = append(method.Countries, strings.ToUpper(c))
+		}
+		if method.Name == "" {
+			method.Name = method.Code
+		}
+
+		t[j] = method
+	}
+	return t, nil
+}
+
+func (t ShippingTable) ShippingRates(ctx context.Context, c *Cart) ([]ShippingRate, error) {
+	if c.ShippingAddress == nil {
+		return nil, nil
+	}
+
+	var (
+		currency string
+		quantity int64
+	)
+	for _, i := range c.LineItems {
+		if i.UnitPrice.Currency != "" {
+			currency = i.UnitPrice.Currency
+		}
+		quantity += i.Quantity
+	}
+
+	// Nothing to ship.
+	if currency == "" {
+		return nil, nil
+	}
+
+	var rates []ShippingRate
+	for _, m := range t {
+		if m.Amount.Currency != currency || !m.shipsTo(c.ShippingAddress) ||
+			(m.MaxQuantity > 0 && quantity > m.MaxQuantity) {
+			continue
+		}
+
+		perItem, err := m.PerItem.mul(quantity)
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %w", m.Code, err)
+		}
+
+		amount, err := m.Amount.add(perItem)
+		if err != nil {
+			return nil, fmt.Errorf("method %q: %w", m.Code, err)
+		}
+
+		rates = append(rates, ShippingRate{Code: m.Code, Name: m.Name, Amount: amount})
+	}
+	return rates, nil
+}
+
+// shipsTo tells whether the method ships to the address.
+func (m *ShippingMethod) shipsTo(a *Address) bool {
+	if len(m.Countries) == 0 {
+		return true
+	}
+
+	for _, c := range m.Countries {
+		if strings.EqualFold(c, a.Country) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestShippingTable_ShippingRates(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	table := ShippingTable{
+		{Code: "standard", Name: "Standard", Countries: []string{"DE", "AT"}, Amount: eur(490)},
+		{Code: "express", Name: "Express", Countries: []string{"DE"}, Amount: eur(990), PerItem: eur(100)},
+		{Code: "letter", Name: "Letter", Amount: eur(190), MaxQuantity: 2},
+		{Code: "usps", Name: "USPS", Countries: []string{"US"}, Amount: Money{Amount: 500, Currency: "USD"}},
+	}
+
+	tests := []struct {
+		name     string
+		address  *Address
+		quantity int64
+		exp      []ShippingRate
+	}{
+		{"no address", nil, 1, nil},
+		{
+			"every method",
+			&Address{Country: "de"},
+			2,
+			[]ShippingRate{
+				{Code: "standard", Name: "Standard", Amount: eur(490)},
+				{Code: "express", Name: "Express", Amount: eur(1190)},
+				{Code: "letter", Name: "Letter", Amount: eur(190)},
+			},
+		},
+		{
+			"over max quantity",
+			&Address{Country: "AT"},
+			3,
+			[]ShippingRate{{Code: "standard", Name: "Standard", Amount: eur(490)}},
+		},
+		{"other currency", &Address{Country: "US"}, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Cart{
+				ShippingAddress: tt.address,
+				LineItems:       []*LineItem{{ProductID: 1, Quantity: tt.quantity, UnitPrice: eur(500)}},
+			}
+
+			rates, err := table.ShippingRates(context.Background(), c)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tt.exp, rates) {
+				t.Errorf("rates do not match\nexp: %+v\ngot: %+v", tt.exp, rates)
+			}
+		})
+	}
+
+	// Nothing to ship.
+	if rates, err := table.ShippingRates(context.Background(), &Cart{ShippingAddress: &Address{Country: "DE"}}); err != nil || rates != nil {
+		t.Errorf("no rates exp, got: %+v, %v", rates, err)
+	}
+}
+
+func TestLoadShippingTable(t *testing.T) {
+	path := writeTestFile(t, "shipping.json", `[
+		{"code":"standard","name":"Standard","countries":["de","AT"],"amount":490,"currency":"EUR"},
+		{"code":"letter","amount":190,"per_item":10,"currency":"EUR","max_quantity":2}
+	]`)
+
+	table, err := LoadShippingTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := ShippingTable{
+		{Code: "standard", Name: "Standard", Countries: []string{"DE", "AT"}, Amount: Money{Amount: 490, Currency: "EUR"}, PerItem: Money{Currency: "EUR"}},
+		{Code: "letter", Name: "letter", Amount: Money{Amount: 190, Currency: "EUR"}, PerItem: Money{Amount: 10, Currency: "EUR"}, MaxQuantity: 2},
+	}
+	if !reflect.DeepEqual(exp, table) {
+		t.Errorf("shipping tables do not match\nexp: %+v\ngot: %+v", exp, table)
+	}
+
+	for _, invalid := range []string{
+		`[{"amount":490,"currency":"EUR"}]`,
+		`[{"code":"a","amount":490,"currency":"EUR"},{"code":"a","amount":490,"currency":"EUR"}]`,
+		`[{"code":"a","amount":490}]`,
+		`[{"code":"a","amount":-1,"currency":"EUR"}]`,
+	} {
+		if _, err := LoadShippingTable(writeTestFile(t, "shipping.json", invalid)); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s err exp: %v, got: %v", invalid, ErrInvalidArgument, err)
+		}
+	}
+}
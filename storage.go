@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/mattn/go-sqlite3"
 )
 
 type db interface {
@@ -27,7 +30,7 @@ func (s *SQLite3) BeginTx(ctx context.Context, opts *sql.TxOptions) (storer, err
 
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, sqlite3Error(err)
 	}
 
 	return &SQLite3{db: tx}, nil
@@ -38,25 +41,45 @@ func (s *SQLite3) Commit() error {
 	if !ok {
 		return errors.New("not a transaction")
 	}
-	return tx.Commit()
+	return sqlite3Error(tx.Commit())
+}
+
+func (s *SQLite3) Rollback() error {
+	tx, ok := s.db.(*sql.Tx)
+	if !ok {
+		return errors.New("not a transaction")
+	}
+	return tx.Rollback()
 }
 
 func (s *SQLite3) CartCreate(ctx context.Context, cart *Cart) error {
 	tm := time.Now().UTC()
 
+	if cart.GuestToken == "" {
+		_, err := s.db.ExecContext(
+			ctx,
+			`UPDATE carts SET status = ?, version = version + 1, updated_at = ? WHERE user_id = ? AND status = ? AND guest_token = ''`,
+			CartAbandoned, tm, cart.UserID, CartActive,
+		)
+		if err != nil {
+			return fmt.Errorf("abandon carts: %w", sqlite3Error(err))
+		}
+	}
+
 	res, err := s.db.ExecContext(
 		ctx,
-		`INSERT INTO carts(user_id, created_at, updated_at) VALUES(?, ?, ?)`,
-		cart.UserID, tm, tm,
+		`INSERT INTO carts(user_id, guest_token, status, version, created_at, updated_at) VALUES(?, ?, ?, 1, ?, ?)`,
+		cart.UserID, cart.GuestToken, CartActive, tm, tm,
 	)
 	if err != nil {
-		return err
+		return sqlite3Error(err)
 	}
 
 	if cart.ID, err = res.LastInsertId(); err != nil {
 		return err
 	}
 
+	cart.Version, cart.Status = 1, CartActive
 	cart.CreatedAt, cart.UpdatedAt = tm, tm
 	return nil
 }
@@ -65,68 +88,287 @@ func (s *SQLite3) CartWithItemsByCartID(ctx context.Context, cartID int64) (*Car
 	c := &Cart{}
 	err := s.db.QueryRowContext(
 		ctx,
-		`SELECT user_id, created_at, updated_at
+		`SELECT user_id, guest_token, status, version, created_at, updated_at, COALESCE((SELECT id FROM orders WHERE cart_id = carts.id), 0)
 		FROM carts
 		WHERE id = ?`,
 		cartID,
 	).Scan(
 		&c.UserID,
+		&c.GuestToken,
+		&c.Status,
+		&c.Version,
 		&c.CreatedAt,
 		&c.UpdatedAt,
+		&c.OrderID,
 	)
-	if err != nil {
-		return nil, fmt.Errorf("cart query: %w", err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("cart query: %w", sqlite3Error(err))
 	}
 
 	c.ID = cartID
 
-	var ii []*LineItem
+	items, err := s.lineItemsByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, err := s.couponsByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.shippingAddressesByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.shippingMethodsByCartIDs(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.LineItems, c.Coupons = items[cartID], coupons[cartID]
+	c.ShippingAddress, c.ShippingMethod = addresses[cartID], methods[cartID]
+	return c, nil
+}
+
+func (s *SQLite3) ActiveCartByUserID(ctx context.Context, userID int64) (*Cart, error) {
+	var cartID int64
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id FROM carts WHERE user_id = ? AND status = ? AND guest_token = ''`,
+		userID, CartActive,
+	).Scan(&cartID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("active cart of user %d: %w", userID, ErrCartNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("cart query: %w", sqlite3Error(err))
+	}
+
+	return s.CartWithItemsByCartID(ctx, cartID)
+}
+
+func (s *SQLite3) CartsByUserID(ctx context.Context, userID int64, q CartsQuery) ([]*Cart, error) {
+	where, args := []string{"user_id = ?", "id > ?"}, []interface{}{userID, q.AfterID}
+	for _, f := range []struct {
+		cond string
+		tm   time.Time
+	}{
+		{"created_at >= ", q.CreatedFrom},
+		{"created_at < ", q.CreatedTo},
+		{"updated_at >= ", q.UpdatedFrom},
+		{"updated_at < ", q.UpdatedTo},
+	} {
+		if !f.tm.IsZero() {
+			args = append(args, f.tm.UTC())
+			where = append(where, f.cond+"?")
+		}
+	}
+	args = append(args, q.Limit)
+
 	rows, err := s.db.QueryContext(
 		ctx,
-		`SELECT id, product_id, quantity, created_at, updated_at
-		FROM line_items
-		WHERE cart_id = ?`,
-		cartID,
+		`SELECT id, guest_token, status, version, created_at, updated_at, COALESCE((SELECT id FROM orders WHERE cart_id = carts.id), 0)
+		FROM carts
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id
+		LIMIT ?`,
+		args...,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("item query: %w", err)
+		return nil, fmt.Errorf("cart query: %w", sqlite3Error(err))
 	}
 	defer rows.Close()
 
+	var (
+		carts []*Cart
+		ids   []int64
+	)
 	for rows.Next() {
-		i := &LineItem{}
-		err := rows.Scan(
-			&i.ID,
-			&i.ProductID,
-			&i.Quantity,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("item scan: %w", err)
+		c := &Cart{UserID: userID}
+		if err := rows.Scan(&c.ID, &c.GuestToken, &c.Status, &c.Version, &c.CreatedAt, &c.UpdatedAt, &c.OrderID); err != nil {
+			return nil, fmt.Errorf("cart scan: %w", err)
 		}
 
-		i.CartID = cartID
-		ii = append(ii, i)
+		carts = append(carts, c)
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cart rows: %w", err)
 	}
 
-	c.LineItems = ii
-	return c, nil
+	if !q.WithItems || len(carts) == 0 {
+		return carts, nil
+	}
+
+	items, err := s.lineItemsByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, err := s.couponsByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.shippingAddressesByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.shippingMethodsByCartIDs(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range carts {
+		c.LineItems, c.Coupons = items[c.ID], coupons[c.ID]
+		c.ShippingAddress, c.ShippingMethod = addresses[c.ID], methods[c.ID]
+	}
+	return carts, nil
 }
 
 func (s *SQLite3) CartEmpty(ctx context.Context, cartID int64) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
 	_, err := s.db.ExecContext(
 		ctx,
 		`DELETE FROM line_items WHERE cart_id = ?`,
 		cartID,
 	)
-	return err
+	return sqlite3Error(err)
 }
 
-func (s *SQLite3) LineItemsUpsert(ctx context.Context, cartID int64, items ...*LineItem) error {
+func (s *SQLite3) CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	if a == nil {
+		_, err := s.db.ExecContext(
+			ctx,
+			`DELETE FROM cart_shipping_addresses WHERE cart_id = ?`,
+			cartID,
+		)
+		return sqlite3Error(err)
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO cart_shipping_addresses(cart_id, name, line1, line2, city, postal_code, region, country, updated_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(cart_id) DO UPDATE SET name = excluded.name, line1 = excluded.line1, line2 = excluded.line2,
+		city = excluded.city, postal_code = excluded.postal_code, region = excluded.region, country = excluded.country,
+		updated_at = excluded.updated_at`,
+		cartID, a.Name, a.Line1, a.Line2, a.City, a.PostalCode, a.Region, a.Country, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("shipping address: %w", sqlite3CartError(err))
+	}
+
+	return nil
+}
+
+func (s *SQLite3) CartShippingMethodSet(ctx context.Context, cartID int64, code string) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	if code == "" {
+		_, err := s.db.ExecContext(
+			ctx,
+			`DELETE FROM cart_shipping_methods WHERE cart_id = ?`,
+			cartID,
+		)
+		return sqlite3Error(err)
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO cart_shipping_methods(cart_id, code, updated_at) VALUES(?, ?, ?)
+		ON CONFLICT(cart_id) DO UPDATE SET code = excluded.code, updated_at = excluded.updated_at`,
+		cartID, code, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("shipping method: %w", sqlite3CartError(err))
+	}
+
+	return nil
+}
+
+func (s *SQLite3) CartStatusSet(ctx context.Context, cartID int64, status CartStatus) error {
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE carts SET status = ? WHERE id = ?`,
+		status, cartID,
+	)
+	return sqlite3Error(err)
+}
+
+func (s *SQLite3) OrderCreate(ctx context.Context, o *Order) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	tm := time.Now().UTC()
+
+	a, t := o.ShippingAddress, o.Totals
+	if a == nil {
+		a = &Address{}
+	}
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO orders(cart_id, user_id, currency, subtotal, discount_total, tax_total, shipping_total, grand_total, shipping_method,
+		shipping_name, shipping_line1, shipping_line2, shipping_city, shipping_postal_code, shipping_region, shipping_country, created_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		o.CartID, o.UserID, t.GrandTotal.Currency, t.Subtotal.Amount, t.DiscountTotal.Amount, t.TaxTotal.Amount, t.ShippingTotal.Amount,
+		t.GrandTotal.Amount, o.ShippingMethod, a.Name, a.Line1, a.Line2, a.City, a.PostalCode, a.Region, a.Country, tm,
+	)
+	if err != nil {
+		return sqlite3CartError(err)
+	}
+
+	if o.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+
+	for _, i := range o.LineItems {
+		res, err := s.db.ExecContext(
+			ctx,
+			`INSERT INTO order_items(order_id, product_id, sku, name, quantity, unit_price, discount, currency)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+			o.ID, i.ProductID, i.SKU, i.Name, i.Quantity, i.UnitPrice.Amount, i.Discount.Amount, i.UnitPrice.Currency,
+		)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i.ProductID, sqlite3Error(err))
+		}
+
+		if i.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	o.CreatedAt = tm
+
+	return s.CartStatusSet(ctx, o.CartID, CartCheckedOut)
+}
+
+func (s *SQLite3) LineItemsUpsert(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := s.cartTouch(ctx, cartID); err != nil {
+		return err
+	}
+
 	for _, item := range items {
 		if item == nil {
 			continue
@@ -134,34 +376,486 @@ func (s *SQLite3) LineItemsUpsert(ctx context.Context, cartID int64, items ...*L
 
 		tm := time.Now().UTC()
 
-		res, err := s.db.ExecContext(
+		// Incremented items keep the price they have been added at.
+		set := "quantity = excluded.quantity, unit_price = excluded.unit_price, currency = excluded.currency"
+		if mode == UpsertIncrement {
+			set = "quantity = quantity + excluded.quantity, unit_price = COALESCE(unit_price, excluded.unit_price), currency = COALESCE(currency, excluded.currency)"
+		}
+
+		price, currency := priceArgs(item.UnitPrice)
+		_, err := s.db.ExecContext(
 			ctx,
-			`INSERT INTO line_items(cart_id, product_id, quantity, created_at, updated_at)
-			VALUES(?, ?, ?, ?, ?)
-			ON CONFLICT(cart_id, product_id) DO UPDATE SET quantity = ?, updated_at = ?`,
-			cartID, item.ProductID, item.Quantity, tm, tm,
-			item.Quantity, tm,
+			`INSERT INTO line_items(cart_id, product_id, quantity, unit_price, currency, created_at, updated_at)
+			VALUES(?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(cart_id, product_id) DO UPDATE SET `+set+`, updated_at = excluded.updated_at`,
+			cartID, item.ProductID, item.Quantity, price, currency, tm, tm,
 		)
 		if err != nil {
-			return fmt.Errorf("exec %d: %w", item.ProductID, err)
+			return fmt.Errorf("exec %d: %w", item.ProductID, sqlite3CartError(err))
 		}
 
-		item.UpdatedAt = time.Now().UTC()
+		item.UpdatedAt = tm
 
-		if item.ID, err = res.LastInsertId(); err != nil {
+		// LastInsertId is not reliable when the conflicting row gets updated.
+		err = s.db.QueryRowContext(
+			ctx,
+			`SELECT id, quantity, unit_price, currency FROM line_items WHERE cart_id = ? AND product_id = ?`,
+			cartID, item.ProductID,
+		).Scan(&item.ID, &item.Quantity, &price, &currency)
+		if err != nil {
 			return fmt.Errorf("id %d: %w", item.ProductID, err)
 		}
+
+		item.UnitPrice = Money{Amount: price.Int64, Currency: currency.String}
 	}
 
 	return nil
 }
 
 func (s *SQLite3) LineItemRemove(ctx context.Context, cartID, itemID int64) error {
-	fmt.Println(cartID, itemID)
-	_, err := s.db.ExecContext(
+	res, err := s.db.ExecContext(
 		ctx,
 		`DELETE FROM line_items WHERE cart_id = ? AND id = ?`,
 		cartID, itemID,
 	)
+	if err != nil {
+		return sqlite3Error(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("item %d: %w", itemID, ErrLineItemNotFound)
+	}
+
+	return s.cartTouch(ctx, cartID)
+}
+
+func (s *SQLite3) CartCouponAdd(ctx context.Context, cartID int64, code string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO cart_coupons(cart_id, code, created_at) VALUES(?, ?, ?)`,
+		cartID, code, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("coupon %q: %w", code, sqlite3CartError(err))
+	}
+
+	return s.cartTouch(ctx, cartID)
+}
+
+func (s *SQLite3) CartCouponRemove(ctx context.Context, cartID int64, code string) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM cart_coupons WHERE cart_id = ? AND code = ?`,
+		cartID, code,
+	)
+	if err != nil {
+		return sqlite3Error(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("coupon %q: %w", code, ErrCouponNotFound)
+	}
+
+	return s.cartTouch(ctx, cartID)
+}
+
+func (s *SQLite3) CouponUses(ctx context.Context, userID int64, code string) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*)
+		FROM cart_coupons cc
+		JOIN carts c ON c.id = cc.cart_id
+		WHERE c.user_id = ? AND c.status = ? AND cc.code = ?`,
+		userID, CartCheckedOut, code,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("coupon uses: %w", sqlite3Error(err))
+	}
+
+	return n, nil
+}
+
+func (s *SQLite3) PaymentCreate(ctx context.Context, p *Payment) error {
+	tm := time.Now().UTC()
+
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO payments(cart_id, cart_version, amount, currency, status, authorization_id, created_at, updated_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.CartID, p.CartVersion, p.Amount.Amount, p.Amount.Currency, p.Status, p.AuthorizationID, tm, tm,
+	)
+	if err != nil {
+		return fmt.Errorf("payment: %w", sqlite3CartError(err))
+	}
+
+	if p.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+
+	p.CreatedAt, p.UpdatedAt = tm, tm
+	return nil
+}
+
+func (s *SQLite3) PaymentByCartID(ctx context.Context, cartID int64) (*Payment, error) {
+	p := &Payment{CartID: cartID}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, cart_version, amount, currency, status, authorization_id, created_at, updated_at
+		FROM payments
+		WHERE cart_id = ?
+		ORDER BY id DESC
+		LIMIT 1`,
+		cartID,
+	).Scan(
+		&p.ID,
+		&p.CartVersion,
+		&p.Amount.Amount,
+		&p.Amount.Currency,
+		&p.Status,
+		&p.AuthorizationID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cart %d: %w", cartID, ErrPaymentNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("payment query: %w", sqlite3Error(err))
+	}
+
+	return p, nil
+}
+
+func (s *SQLite3) PaymentStatusSet(ctx context.Context, paymentID int64, status PaymentStatus) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE payments SET status = ?, updated_at = ? WHERE id = ?`,
+		status, time.Now().UTC(), paymentID,
+	)
+	if err != nil {
+		return sqlite3Error(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("payment %d: %w", paymentID, ErrPaymentNotFound)
+	}
+
+	return nil
+}
+
+func (s *SQLite3) IdempotencyKeyCreate(ctx context.Context, key *IdempotencyKey) error {
+	tm := time.Now().UTC()
+
+	// An expired key is replaced, a live one is a conflict.
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = ? AND key = ? AND expires_at <= ?`,
+		key.UserID, key.Key, tm,
+	)
+	if err != nil {
+		return fmt.Errorf("expired: %w", sqlite3Error(err))
+	}
+
+	_, err = s.db.ExecContext(
+		ctx,
+		`INSERT INTO idempotency_keys(user_id, key, fingerprint, created_at, expires_at) VALUES(?, ?, ?, ?, ?)`,
+		key.UserID, key.Key, key.Fingerprint, tm, key.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return sqlite3Error(err)
+	}
+
+	key.CreatedAt = tm
+	return nil
+}
+
+func (s *SQLite3) IdempotencyKeyByKey(ctx context.Context, userID int64, key string) (*IdempotencyKey, error) {
+	k := &IdempotencyKey{UserID: userID, Key: key}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT fingerprint, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = ? AND key = ? AND expires_at > ?`,
+		userID, key, time.Now().UTC(),
+	).Scan(
+		&k.Fingerprint,
+		&k.Response,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("key %q: %w", key, ErrIdempotencyKeyNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("key query: %w", sqlite3Error(err))
+	}
+
+	return k, nil
+}
+
+func (s *SQLite3) IdempotencyKeyComplete(ctx context.Context, key *IdempotencyKey) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE idempotency_keys SET response = ?, expires_at = ? WHERE user_id = ? AND key = ?`,
+		key.Response, key.ExpiresAt.UTC(), key.UserID, key.Key,
+	)
+	if err != nil {
+		return sqlite3Error(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("key %q: %w", key.Key, ErrIdempotencyKeyNotFound)
+	}
+
+	return nil
+}
+
+func (s *SQLite3) IdempotencyKeyDelete(ctx context.Context, userID int64, key string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = ? AND key = ?`,
+		userID, key,
+	)
+	return sqlite3Error(err)
+}
+
+func (s *SQLite3) IdempotencyKeysPurge(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM idempotency_keys WHERE expires_at <= ?`,
+		before.UTC(),
+	)
+	return sqlite3Error(err)
+}
+
+// cartTouch bumps the version of a cart being mutated.
+func (s *SQLite3) cartTouch(ctx context.Context, cartID int64) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`UPDATE carts SET version = version + 1, updated_at = ? WHERE id = ?`,
+		time.Now().UTC(), cartID,
+	)
+	if err != nil {
+		return sqlite3Error(err)
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("cart %d: %w", cartID, ErrCartNotFound)
+	}
+
+	return nil
+}
+
+// lineItemsByCartIDs returns items of the carts by cart ID, items are ordered by ID.
+func (s *SQLite3) lineItemsByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64][]*LineItem, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = "?", id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, cart_id, product_id, quantity, unit_price, currency, created_at, updated_at
+		FROM line_items
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)
+		ORDER BY id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("item query: %w", sqlite3Error(err))
+	}
+	defer rows.Close()
+
+	items := make(map[int64][]*LineItem, len(cartIDs))
+	for rows.Next() {
+		var (
+			i        = &LineItem{}
+			price    sql.NullInt64
+			currency sql.NullString
+		)
+		err := rows.Scan(
+			&i.ID,
+			&i.CartID,
+			&i.ProductID,
+			&i.Quantity,
+			&price,
+			&currency,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("item scan: %w", err)
+		}
+
+		i.UnitPrice = Money{Amount: price.Int64, Currency: currency.String}
+
+		items[i.CartID] = append(items[i.CartID], i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("item rows: %w", err)
+	}
+
+	return items, nil
+}
+
+// couponsByCartIDs returns codes of the coupons applied to the carts by cart ID, in the order
+// of application.
+func (s *SQLite3) couponsByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64][]string, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = "?", id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT cart_id, code
+		FROM cart_coupons
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)
+		ORDER BY created_at, code`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("coupon query: %w", sqlite3Error(err))
+	}
+	defer rows.Close()
+
+	coupons := make(map[int64][]string, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID int64
+			code   string
+		)
+		if err := rows.Scan(&cartID, &code); err != nil {
+			return nil, fmt.Errorf("coupon scan: %w", err)
+		}
+
+		coupons[cartID] = append(coupons[cartID], code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("coupon rows: %w", err)
+	}
+
+	return coupons, nil
+}
+
+// shippingAddressesByCartIDs returns shipping addresses of the carts by cart ID.
+func (s *SQLite3) shippingAddressesByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64]*Address, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = "?", id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT cart_id, name, line1, line2, city, postal_code, region, country
+		FROM cart_shipping_addresses
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("shipping address query: %w", sqlite3Error(err))
+	}
+	defer rows.Close()
+
+	addresses := make(map[int64]*Address, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID int64
+			a      Address
+		)
+		if err := rows.Scan(&cartID, &a.Name, &a.Line1, &a.Line2, &a.City, &a.PostalCode, &a.Region, &a.Country); err != nil {
+			return nil, fmt.Errorf("shipping address scan: %w", err)
+		}
+
+		addresses[cartID] = &a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("shipping address rows: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// shippingMethodsByCartIDs returns codes of the shipping methods of the carts by cart ID.
+func (s *SQLite3) shippingMethodsByCartIDs(ctx context.Context, cartIDs ...int64) (map[int64]string, error) {
+	ph, args := make([]string, len(cartIDs)), make([]interface{}, len(cartIDs))
+	for j, id := range cartIDs {
+		ph[j], args[j] = "?", id
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT cart_id, code
+		FROM cart_shipping_methods
+		WHERE cart_id IN (`+strings.Join(ph, ", ")+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("shipping method query: %w", sqlite3Error(err))
+	}
+	defer rows.Close()
+
+	methods := make(map[int64]string, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID int64
+			code   string
+		)
+		if err := rows.Scan(&cartID, &code); err != nil {
+			return nil, fmt.Errorf("shipping method scan: %w", err)
+		}
+
+		methods[cartID] = code
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("shipping method rows: %w", err)
+	}
+
+	return methods, nil
+}
+
+// priceArgs returns the unit_price and currency column values of a unit price,
+// NULLs if the price is unknown.
+func priceArgs(m Money) (sql.NullInt64, sql.NullString) {
+	if m.Currency == "" {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	return sql.NullInt64{Int64: m.Amount, Valid: true}, sql.NullString{String: m.Currency, Valid: true}
+}
+
+// sqlite3Error translates SQLite errors into domain errors. Violated foreign keys are not
+// translated as SQLite does not name them, see sqlite3CartError.
+func sqlite3Error(err error) error {
+	var serr sqlite3.Error
+	if !errors.As(err, &serr) {
+		return err
+	}
+
+	switch {
+	case serr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey, serr.ExtendedCode == sqlite3.ErrConstraintUnique:
+		return fmt.Errorf("%v: %w", err, ErrConflict)
+	case serr.Code == sqlite3.ErrBusy, serr.Code == sqlite3.ErrLocked:
+		return fmt.Errorf("%v: %w", err, ErrUnavailable)
+	}
+
 	return err
 }
+
+// sqlite3CartError translates SQLite errors of statements inserting rows of a cart, whose only
+// foreign key references the cart.
+func sqlite3CartError(err error) error {
+	var serr sqlite3.Error
+	if errors.As(err, &serr) && serr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+		return fmt.Errorf("%v: %w", err, ErrCartNotFound)
+	}
+	return sqlite3Error(err)
+}
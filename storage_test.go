@@ -3,16 +3,34 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/pressly/goose"
 )
 
+func TestSQLite3_Suite(t *testing.T) {
+	RunStorerSuite(t, func(t *testing.T) storer {
+		dir, err := ioutil.TempDir("", "shoppingcart")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.RemoveAll(dir) })
+
+		db := migrateDB(t, "file:"+filepath.Join(dir, "db.sqlite3")+"?_loc=UTC&_foreign_keys=1&_busy_timeout=5000&_txlock=immediate", "./migrations")
+		t.Cleanup(func() { db.Close() })
+
+		return &SQLite3{db: db}
+	})
+}
+
 func TestSQLite3_BeginTx(t *testing.T) {
 	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
 	if err != nil {
@@ -39,6 +57,35 @@ func TestSQLite3_Commit(t *testing.T) {
 	t.Skip("🤷")
 }
 
+func TestSQLite3_Rollback(t *testing.T) {
+	st := &SQLite3{db: connectDB(t)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx, err := st.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Cart{UserID: 1}
+	if err := tx.CartCreate(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.CartWithItemsByCartID(ctx, c.ID); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+
+	if err := st.Rollback(); err == nil {
+		t.Error("err exp, got none")
+	}
+}
+
 func TestSQLite3_CartCreate(t *testing.T) {
 	c := &Cart{
 		UserID: 1,
@@ -89,11 +136,11 @@ func TestSQLite3_LineItemsUpsert(t *testing.T) {
 		{ProductID: 1, Quantity: 8},
 	}
 
-	if err := st.LineItemsUpsert(context.Background(), c.ID, ii...); err != nil {
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, ii...); err != nil {
 		t.Fatal("first:", err)
 	}
 
-	if err := st.LineItemsUpsert(context.Background(), c.ID, &LineItem{ProductID: 9, Quantity: 3}); err != nil {
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, &LineItem{ProductID: 9, Quantity: 3}); err != nil {
 		t.Fatal("second:", err)
 	}
 
@@ -125,10 +172,45 @@ func TestSQLite3_LineItemRemove(t *testing.T) {
 	}
 }
 
+func TestSQLite3Error(t *testing.T) {
+	fk := sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey}
+
+	tests := []struct {
+		name string
+		err  error
+		exp  error
+	}{
+		{"unique", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}, ErrConflict},
+		{"busy", sqlite3.Error{Code: sqlite3.ErrBusy}, ErrUnavailable},
+		{"locked", sqlite3.Error{Code: sqlite3.ErrLocked}, ErrUnavailable},
+		{"foreign key", fk, nil},
+		{"other", errors.New("other"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sqlite3Error(tt.err)
+			for _, e := range []error{ErrConflict, ErrUnavailable, ErrCartNotFound} {
+				if errors.Is(err, e) != (e == tt.exp) {
+					t.Errorf("err exp: %v, got: %v", tt.exp, err)
+				}
+			}
+		})
+	}
+
+	if err := sqlite3CartError(fk); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("cart err exp: %v, got: %v", ErrCartNotFound, err)
+	}
+}
+
 func connectDB(t *testing.T) *sql.DB {
 	t.Helper()
+	return migrateDB(t, "file::memory:?cache=shared", "./migrations")
+}
 
-	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+func migrateDB(t *testing.T, dsn, dir string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -139,7 +221,7 @@ func connectDB(t *testing.T) *sql.DB {
 		t.Fatal(err)
 	}
 
-	if err := goose.Up(db, "./migrations"); err != nil {
+	if err := goose.Up(db, dir); err != nil {
 		t.Fatal(err)
 	}
 
@@ -151,9 +233,12 @@ func createCartWithItems(t *testing.T, db *sql.DB) *Cart {
 
 	cartID := time.Now().UnixNano()
 
+	// A user of its own, users have a single active cart.
 	c := &Cart{
-		ID:     cartID,
-		UserID: 50,
+		ID:      cartID,
+		UserID:  cartID,
+		Version: 1,
+		Status:  CartActive,
 		LineItems: []*LineItem{
 			{ID: time.Now().UnixNano(), CartID: cartID, ProductID: 1, Quantity: 2, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()},
 		},
@@ -162,8 +247,8 @@ func createCartWithItems(t *testing.T, db *sql.DB) *Cart {
 	}
 
 	_, err := db.Exec(
-		`INSERT INTO carts(id, user_id, created_at, updated_at) VALUES(?, ?, ?, ?)`,
-		c.ID, c.UserID, c.CreatedAt, c.UpdatedAt,
+		`INSERT INTO carts(id, user_id, status, version, created_at, updated_at) VALUES(?, ?, ?, ?, ?, ?)`,
+		c.ID, c.UserID, c.Status, c.Version, c.CreatedAt, c.UpdatedAt,
 	)
 	if err != nil {
 		t.Fatal("cart:", err)
@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"sync"
 	mm_atomic "sync/atomic"
+	"time"
 	mm_time "time"
 
 	"github.com/gojuno/minimock/v3"
@@ -18,12 +19,30 @@ import (
 type StorerMock struct {
 	t minimock.Tester
 
+	funcActiveCartByUserID          func(ctx context.Context, userID int64) (cp1 *Cart, err error)
+	inspectFuncActiveCartByUserID   func(ctx context.Context, userID int64)
+	afterActiveCartByUserIDCounter  uint64
+	beforeActiveCartByUserIDCounter uint64
+	ActiveCartByUserIDMock          mStorerMockActiveCartByUserID
+
 	funcBeginTx          func(ctx context.Context, opts *sql.TxOptions) (s1 storer, err error)
 	inspectFuncBeginTx   func(ctx context.Context, opts *sql.TxOptions)
 	afterBeginTxCounter  uint64
 	beforeBeginTxCounter uint64
 	BeginTxMock          mStorerMockBeginTx
 
+	funcCartCouponAdd          func(ctx context.Context, cartID int64, code string) (err error)
+	inspectFuncCartCouponAdd   func(ctx context.Context, cartID int64, code string)
+	afterCartCouponAddCounter  uint64
+	beforeCartCouponAddCounter uint64
+	CartCouponAddMock          mStorerMockCartCouponAdd
+
+	funcCartCouponRemove          func(ctx context.Context, cartID int64, code string) (err error)
+	inspectFuncCartCouponRemove   func(ctx context.Context, cartID int64, code string)
+	afterCartCouponRemoveCounter  uint64
+	beforeCartCouponRemoveCounter uint64
+	CartCouponRemoveMock          mStorerMockCartCouponRemove
+
 	funcCartCreate          func(ctx context.Context, cart *Cart) (err error)
 	inspectFuncCartCreate   func(ctx context.Context, cart *Cart)
 	afterCartCreateCounter  uint64
@@ -36,29 +55,119 @@ type StorerMock struct {
 	beforeCartEmptyCounter uint64
 	CartEmptyMock          mStorerMockCartEmpty
 
+	funcCartShippingAddressSet          func(ctx context.Context, cartID int64, a *Address) (err error)
+	inspectFuncCartShippingAddressSet   func(ctx context.Context, cartID int64, a *Address)
+	afterCartShippingAddressSetCounter  uint64
+	beforeCartShippingAddressSetCounter uint64
+	CartShippingAddressSetMock          mStorerMockCartShippingAddressSet
+
+	funcCartShippingMethodSet          func(ctx context.Context, cartID int64, code string) (err error)
+	inspectFuncCartShippingMethodSet   func(ctx context.Context, cartID int64, code string)
+	afterCartShippingMethodSetCounter  uint64
+	beforeCartShippingMethodSetCounter uint64
+	CartShippingMethodSetMock          mStorerMockCartShippingMethodSet
+
+	funcCartStatusSet          func(ctx context.Context, cartID int64, status CartStatus) (err error)
+	inspectFuncCartStatusSet   func(ctx context.Context, cartID int64, status CartStatus)
+	afterCartStatusSetCounter  uint64
+	beforeCartStatusSetCounter uint64
+	CartStatusSetMock          mStorerMockCartStatusSet
+
 	funcCartWithItemsByCartID          func(ctx context.Context, cartID int64) (cp1 *Cart, err error)
 	inspectFuncCartWithItemsByCartID   func(ctx context.Context, cartID int64)
 	afterCartWithItemsByCartIDCounter  uint64
 	beforeCartWithItemsByCartIDCounter uint64
 	CartWithItemsByCartIDMock          mStorerMockCartWithItemsByCartID
 
+	funcCartsByUserID          func(ctx context.Context, userID int64, q CartsQuery) (cpa1 []*Cart, err error)
+	inspectFuncCartsByUserID   func(ctx context.Context, userID int64, q CartsQuery)
+	afterCartsByUserIDCounter  uint64
+	beforeCartsByUserIDCounter uint64
+	CartsByUserIDMock          mStorerMockCartsByUserID
+
 	funcCommit          func() (err error)
 	inspectFuncCommit   func()
 	afterCommitCounter  uint64
 	beforeCommitCounter uint64
 	CommitMock          mStorerMockCommit
 
+	funcCouponUses          func(ctx context.Context, userID int64, code string) (i1 int, err error)
+	inspectFuncCouponUses   func(ctx context.Context, userID int64, code string)
+	afterCouponUsesCounter  uint64
+	beforeCouponUsesCounter uint64
+	CouponUsesMock          mStorerMockCouponUses
+
+	funcIdempotencyKeyByKey          func(ctx context.Context, userID int64, key string) (ip1 *IdempotencyKey, err error)
+	inspectFuncIdempotencyKeyByKey   func(ctx context.Context, userID int64, key string)
+	afterIdempotencyKeyByKeyCounter  uint64
+	beforeIdempotencyKeyByKeyCounter uint64
+	IdempotencyKeyByKeyMock          mStorerMockIdempotencyKeyByKey
+
+	funcIdempotencyKeyComplete          func(ctx context.Context, key *IdempotencyKey) (err error)
+	inspectFuncIdempotencyKeyComplete   func(ctx context.Context, key *IdempotencyKey)
+	afterIdempotencyKeyCompleteCounter  uint64
+	beforeIdempotencyKeyCompleteCounter uint64
+	IdempotencyKeyCompleteMock          mStorerMockIdempotencyKeyComplete
+
+	funcIdempotencyKeyCreate          func(ctx context.Context, key *IdempotencyKey) (err error)
+	inspectFuncIdempotencyKeyCreate   func(ctx context.Context, key *IdempotencyKey)
+	afterIdempotencyKeyCreateCounter  uint64
+	beforeIdempotencyKeyCreateCounter uint64
+	IdempotencyKeyCreateMock          mStorerMockIdempotencyKeyCreate
+
+	funcIdempotencyKeyDelete          func(ctx context.Context, userID int64, key string) (err error)
+	inspectFuncIdempotencyKeyDelete   func(ctx context.Context, userID int64, key string)
+	afterIdempotencyKeyDeleteCounter  uint64
+	beforeIdempotencyKeyDeleteCounter uint64
+	IdempotencyKeyDeleteMock          mStorerMockIdempotencyKeyDelete
+
+	funcIdempotencyKeysPurge          func(ctx context.Context, before time.Time) (err error)
+	inspectFuncIdempotencyKeysPurge   func(ctx context.Context, before time.Time)
+	afterIdempotencyKeysPurgeCounter  uint64
+	beforeIdempotencyKeysPurgeCounter uint64
+	IdempotencyKeysPurgeMock          mStorerMockIdempotencyKeysPurge
+
 	funcLineItemRemove          func(ctx context.Context, cartID int64, itemID int64) (err error)
 	inspectFuncLineItemRemove   func(ctx context.Context, cartID int64, itemID int64)
 	afterLineItemRemoveCounter  uint64
 	beforeLineItemRemoveCounter uint64
 	LineItemRemoveMock          mStorerMockLineItemRemove
 
-	funcLineItemsUpsert          func(ctx context.Context, cartID int64, items ...*LineItem) (err error)
-	inspectFuncLineItemsUpsert   func(ctx context.Context, cartID int64, items ...*LineItem)
+	funcLineItemsUpsert          func(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) (err error)
+	inspectFuncLineItemsUpsert   func(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem)
 	afterLineItemsUpsertCounter  uint64
 	beforeLineItemsUpsertCounter uint64
 	LineItemsUpsertMock          mStorerMockLineItemsUpsert
+
+	funcOrderCreate          func(ctx context.Context, o *Order) (err error)
+	inspectFuncOrderCreate   func(ctx context.Context, o *Order)
+	afterOrderCreateCounter  uint64
+	beforeOrderCreateCounter uint64
+	OrderCreateMock          mStorerMockOrderCreate
+
+	funcPaymentByCartID          func(ctx context.Context, cartID int64) (pp1 *Payment, err error)
+	inspectFuncPaymentByCartID   func(ctx context.Context, cartID int64)
+	afterPaymentByCartIDCounter  uint64
+	beforePaymentByCartIDCounter uint64
+	PaymentByCartIDMock          mStorerMockPaymentByCartID
+
+	funcPaymentCreate          func(ctx context.Context, p *Payment) (err error)
+	inspectFuncPaymentCreate   func(ctx context.Context, p *Payment)
+	afterPaymentCreateCounter  uint64
+	beforePaymentCreateCounter uint64
+	PaymentCreateMock          mStorerMockPaymentCreate
+
+	funcPaymentStatusSet          func(ctx context.Context, paymentID int64, status PaymentStatus) (err error)
+	inspectFuncPaymentStatusSet   func(ctx context.Context, paymentID int64, status PaymentStatus)
+	afterPaymentStatusSetCounter  uint64
+	beforePaymentStatusSetCounter uint64
+	PaymentStatusSetMock          mStorerMockPaymentStatusSet
+
+	funcRollback          func() (err error)
+	inspectFuncRollback   func()
+	afterRollbackCounter  uint64
+	beforeRollbackCounter uint64
+	RollbackMock          mStorerMockRollback
 }
 
 // NewStorerMock returns a mock for storer
@@ -68,29 +177,299 @@ func NewStorerMock(t minimock.Tester) *StorerMock {
 		controller.RegisterMocker(m)
 	}
 
+	m.ActiveCartByUserIDMock = mStorerMockActiveCartByUserID{mock: m}
+	m.ActiveCartByUserIDMock.callArgs = []*StorerMockActiveCartByUserIDParams{}
+
 	m.BeginTxMock = mStorerMockBeginTx{mock: m}
 	m.BeginTxMock.callArgs = []*StorerMockBeginTxParams{}
 
+	m.CartCouponAddMock = mStorerMockCartCouponAdd{mock: m}
+	m.CartCouponAddMock.callArgs = []*StorerMockCartCouponAddParams{}
+
+	m.CartCouponRemoveMock = mStorerMockCartCouponRemove{mock: m}
+	m.CartCouponRemoveMock.callArgs = []*StorerMockCartCouponRemoveParams{}
+
 	m.CartCreateMock = mStorerMockCartCreate{mock: m}
 	m.CartCreateMock.callArgs = []*StorerMockCartCreateParams{}
 
 	m.CartEmptyMock = mStorerMockCartEmpty{mock: m}
 	m.CartEmptyMock.callArgs = []*StorerMockCartEmptyParams{}
 
+	m.CartShippingAddressSetMock = mStorerMockCartShippingAddressSet{mock: m}
+	m.CartShippingAddressSetMock.callArgs = []*StorerMockCartShippingAddressSetParams{}
+
+	m.CartShippingMethodSetMock = mStorerMockCartShippingMethodSet{mock: m}
+	m.CartShippingMethodSetMock.callArgs = []*StorerMockCartShippingMethodSetParams{}
+
+	m.CartStatusSetMock = mStorerMockCartStatusSet{mock: m}
+	m.CartStatusSetMock.callArgs = []*StorerMockCartStatusSetParams{}
+
 	m.CartWithItemsByCartIDMock = mStorerMockCartWithItemsByCartID{mock: m}
 	m.CartWithItemsByCartIDMock.callArgs = []*StorerMockCartWithItemsByCartIDParams{}
 
+	m.CartsByUserIDMock = mStorerMockCartsByUserID{mock: m}
+	m.CartsByUserIDMock.callArgs = []*StorerMockCartsByUserIDParams{}
+
 	m.CommitMock = mStorerMockCommit{mock: m}
 
+	m.CouponUsesMock = mStorerMockCouponUses{mock: m}
+	m.CouponUsesMock.callArgs = []*StorerMockCouponUsesParams{}
+
+	m.IdempotencyKeyByKeyMock = mStorerMockIdempotencyKeyByKey{mock: m}
+	m.IdempotencyKeyByKeyMock.callArgs = []*StorerMockIdempotencyKeyByKeyParams{}
+
+	m.IdempotencyKeyCompleteMock = mStorerMockIdempotencyKeyComplete{mock: m}
+	m.IdempotencyKeyCompleteMock.callArgs = []*StorerMockIdempotencyKeyCompleteParams{}
+
+	m.IdempotencyKeyCreateMock = mStorerMockIdempotencyKeyCreate{mock: m}
+	m.IdempotencyKeyCreateMock.callArgs = []*StorerMockIdempotencyKeyCreateParams{}
+
+	m.IdempotencyKeyDeleteMock = mStorerMockIdempotencyKeyDelete{mock: m}
+	m.IdempotencyKeyDeleteMock.callArgs = []*StorerMockIdempotencyKeyDeleteParams{}
+
+	m.IdempotencyKeysPurgeMock = mStorerMockIdempotencyKeysPurge{mock: m}
+	m.IdempotencyKeysPurgeMock.callArgs = []*StorerMockIdempotencyKeysPurgeParams{}
+
 	m.LineItemRemoveMock = mStorerMockLineItemRemove{mock: m}
 	m.LineItemRemoveMock.callArgs = []*StorerMockLineItemRemoveParams{}
 
 	m.LineItemsUpsertMock = mStorerMockLineItemsUpsert{mock: m}
 	m.LineItemsUpsertMock.callArgs = []*StorerMockLineItemsUpsertParams{}
 
+	m.OrderCreateMock = mStorerMockOrderCreate{mock: m}
+	m.OrderCreateMock.callArgs = []*StorerMockOrderCreateParams{}
+
+	m.PaymentByCartIDMock = mStorerMockPaymentByCartID{mock: m}
+	m.PaymentByCartIDMock.callArgs = []*StorerMockPaymentByCartIDParams{}
+
+	m.PaymentCreateMock = mStorerMockPaymentCreate{mock: m}
+	m.PaymentCreateMock.callArgs = []*StorerMockPaymentCreateParams{}
+
+	m.PaymentStatusSetMock = mStorerMockPaymentStatusSet{mock: m}
+	m.PaymentStatusSetMock.callArgs = []*StorerMockPaymentStatusSetParams{}
+
+	m.RollbackMock = mStorerMockRollback{mock: m}
+
 	return m
 }
 
+type mStorerMockActiveCartByUserID struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockActiveCartByUserIDExpectation
+	expectations       []*StorerMockActiveCartByUserIDExpectation
+
+	callArgs []*StorerMockActiveCartByUserIDParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockActiveCartByUserIDExpectation specifies expectation struct of the storer.ActiveCartByUserID
+type StorerMockActiveCartByUserIDExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockActiveCartByUserIDParams
+	results *StorerMockActiveCartByUserIDResults
+	Counter uint64
+}
+
+// StorerMockActiveCartByUserIDParams contains parameters of the storer.ActiveCartByUserID
+type StorerMockActiveCartByUserIDParams struct {
+	ctx    context.Context
+	userID int64
+}
+
+// StorerMockActiveCartByUserIDResults contains results of the storer.ActiveCartByUserID
+type StorerMockActiveCartByUserIDResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for storer.ActiveCartByUserID
+func (mmActiveCartByUserID *mStorerMockActiveCartByUserID) Expect(ctx context.Context, userID int64) *mStorerMockActiveCartByUserID {
+	if mmActiveCartByUserID.mock.funcActiveCartByUserID != nil {
+		mmActiveCartByUserID.mock.t.Fatalf("StorerMock.ActiveCartByUserID mock is already set by Set")
+	}
+
+	if mmActiveCartByUserID.defaultExpectation == nil {
+		mmActiveCartByUserID.defaultExpectation = &StorerMockActiveCartByUserIDExpectation{}
+	}
+
+	mmActiveCartByUserID.defaultExpectation.params = &StorerMockActiveCartByUserIDParams{ctx, userID}
+	for _, e := range mmActiveCartByUserID.expectations {
+		if minimock.Equal(e.params, mmActiveCartByUserID.defaultExpectation.params) {
+			mmActiveCartByUserID.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmActiveCartByUserID.defaultExpectation.params)
+		}
+	}
+
+	return mmActiveCartByUserID
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.ActiveCartByUserID
+func (mmActiveCartByUserID *mStorerMockActiveCartByUserID) Inspect(f func(ctx context.Context, userID int64)) *mStorerMockActiveCartByUserID {
+	if mmActiveCartByUserID.mock.inspectFuncActiveCartByUserID != nil {
+		mmActiveCartByUserID.mock.t.Fatalf("Inspect function is already set for StorerMock.ActiveCartByUserID")
+	}
+
+	mmActiveCartByUserID.mock.inspectFuncActiveCartByUserID = f
+
+	return mmActiveCartByUserID
+}
+
+// Return sets up results that will be returned by storer.ActiveCartByUserID
+func (mmActiveCartByUserID *mStorerMockActiveCartByUserID) Return(cp1 *Cart, err error) *StorerMock {
+	if mmActiveCartByUserID.mock.funcActiveCartByUserID != nil {
+		mmActiveCartByUserID.mock.t.Fatalf("StorerMock.ActiveCartByUserID mock is already set by Set")
+	}
+
+	if mmActiveCartByUserID.defaultExpectation == nil {
+		mmActiveCartByUserID.defaultExpectation = &StorerMockActiveCartByUserIDExpectation{mock: mmActiveCartByUserID.mock}
+	}
+	mmActiveCartByUserID.defaultExpectation.results = &StorerMockActiveCartByUserIDResults{cp1, err}
+	return mmActiveCartByUserID.mock
+}
+
+//Set uses given function f to mock the storer.ActiveCartByUserID method
+func (mmActiveCartByUserID *mStorerMockActiveCartByUserID) Set(f func(ctx context.Context, userID int64) (cp1 *Cart, err error)) *StorerMock {
+	if mmActiveCartByUserID.defaultExpectation != nil {
+		mmActiveCartByUserID.mock.t.Fatalf("Default expectation is already set for the storer.ActiveCartByUserID method")
+	}
+
+	if len(mmActiveCartByUserID.expectations) > 0 {
+		mmActiveCartByUserID.mock.t.Fatalf("Some expectations are already set for the storer.ActiveCartByUserID method")
+	}
+
+	mmActiveCartByUserID.mock.funcActiveCartByUserID = f
+	return mmActiveCartByUserID.mock
+}
+
+// When sets expectation for the storer.ActiveCartByUserID which will trigger the result defined by the following
+// Then helper
+func (mmActiveCartByUserID *mStorerMockActiveCartByUserID) When(ctx context.Context, userID int64) *StorerMockActiveCartByUserIDExpectation {
+	if mmActiveCartByUserID.mock.funcActiveCartByUserID != nil {
+		mmActiveCartByUserID.mock.t.Fatalf("StorerMock.ActiveCartByUserID mock is already set by Set")
+	}
+
+	expectation := &StorerMockActiveCartByUserIDExpectation{
+		mock:   mmActiveCartByUserID.mock,
+		params: &StorerMockActiveCartByUserIDParams{ctx, userID},
+	}
+	mmActiveCartByUserID.expectations = append(mmActiveCartByUserID.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.ActiveCartByUserID return parameters for the expectation previously defined by the When method
+func (e *StorerMockActiveCartByUserIDExpectation) Then(cp1 *Cart, err error) *StorerMock {
+	e.results = &StorerMockActiveCartByUserIDResults{cp1, err}
+	return e.mock
+}
+
+// ActiveCartByUserID implements storer
+func (mmActiveCartByUserID *StorerMock) ActiveCartByUserID(ctx context.Context, userID int64) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmActiveCartByUserID.beforeActiveCartByUserIDCounter, 1)
+	defer mm_atomic.AddUint64(&mmActiveCartByUserID.afterActiveCartByUserIDCounter, 1)
+
+	if mmActiveCartByUserID.inspectFuncActiveCartByUserID != nil {
+		mmActiveCartByUserID.inspectFuncActiveCartByUserID(ctx, userID)
+	}
+
+	mm_params := &StorerMockActiveCartByUserIDParams{ctx, userID}
+
+	// Record call args
+	mmActiveCartByUserID.ActiveCartByUserIDMock.mutex.Lock()
+	mmActiveCartByUserID.ActiveCartByUserIDMock.callArgs = append(mmActiveCartByUserID.ActiveCartByUserIDMock.callArgs, mm_params)
+	mmActiveCartByUserID.ActiveCartByUserIDMock.mutex.Unlock()
+
+	for _, e := range mmActiveCartByUserID.ActiveCartByUserIDMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmActiveCartByUserID.ActiveCartByUserIDMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmActiveCartByUserID.ActiveCartByUserIDMock.defaultExpectation.Counter, 1)
+		mm_want := mmActiveCartByUserID.ActiveCartByUserIDMock.defaultExpectation.params
+		mm_got := StorerMockActiveCartByUserIDParams{ctx, userID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmActiveCartByUserID.t.Errorf("StorerMock.ActiveCartByUserID got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmActiveCartByUserID.ActiveCartByUserIDMock.defaultExpectation.results
+		if mm_results == nil {
+			mmActiveCartByUserID.t.Fatal("No results are set for the StorerMock.ActiveCartByUserID")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmActiveCartByUserID.funcActiveCartByUserID != nil {
+		return mmActiveCartByUserID.funcActiveCartByUserID(ctx, userID)
+	}
+	mmActiveCartByUserID.t.Fatalf("Unexpected call to StorerMock.ActiveCartByUserID. %v %v", ctx, userID)
+	return
+}
+
+// ActiveCartByUserIDAfterCounter returns a count of finished StorerMock.ActiveCartByUserID invocations
+func (mmActiveCartByUserID *StorerMock) ActiveCartByUserIDAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmActiveCartByUserID.afterActiveCartByUserIDCounter)
+}
+
+// ActiveCartByUserIDBeforeCounter returns a count of StorerMock.ActiveCartByUserID invocations
+func (mmActiveCartByUserID *StorerMock) ActiveCartByUserIDBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmActiveCartByUserID.beforeActiveCartByUserIDCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.ActiveCartByUserID.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmActiveCartByUserID *mStorerMockActiveCartByUserID) Calls() []*StorerMockActiveCartByUserIDParams {
+	mmActiveCartByUserID.mutex.RLock()
+
+	argCopy := make([]*StorerMockActiveCartByUserIDParams, len(mmActiveCartByUserID.callArgs))
+	copy(argCopy, mmActiveCartByUserID.callArgs)
+
+	mmActiveCartByUserID.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockActiveCartByUserIDDone returns true if the count of the ActiveCartByUserID invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockActiveCartByUserIDDone() bool {
+	for _, e := range m.ActiveCartByUserIDMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ActiveCartByUserIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterActiveCartByUserIDCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcActiveCartByUserID != nil && mm_atomic.LoadUint64(&m.afterActiveCartByUserIDCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockActiveCartByUserIDInspect logs each unmet expectation
+func (m *StorerMock) MinimockActiveCartByUserIDInspect() {
+	for _, e := range m.ActiveCartByUserIDMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.ActiveCartByUserID with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ActiveCartByUserIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterActiveCartByUserIDCounter) < 1 {
+		if m.ActiveCartByUserIDMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.ActiveCartByUserID")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.ActiveCartByUserID with params: %#v", *m.ActiveCartByUserIDMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcActiveCartByUserID != nil && mm_atomic.LoadUint64(&m.afterActiveCartByUserIDCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.ActiveCartByUserID")
+	}
+}
+
 type mStorerMockBeginTx struct {
 	mock               *StorerMock
 	defaultExpectation *StorerMockBeginTxExpectation
@@ -308,1248 +687,4898 @@ func (m *StorerMock) MinimockBeginTxInspect() {
 	}
 }
 
-type mStorerMockCartCreate struct {
+type mStorerMockCartCouponAdd struct {
 	mock               *StorerMock
-	defaultExpectation *StorerMockCartCreateExpectation
-	expectations       []*StorerMockCartCreateExpectation
+	defaultExpectation *StorerMockCartCouponAddExpectation
+	expectations       []*StorerMockCartCouponAddExpectation
 
-	callArgs []*StorerMockCartCreateParams
+	callArgs []*StorerMockCartCouponAddParams
 	mutex    sync.RWMutex
 }
 
-// StorerMockCartCreateExpectation specifies expectation struct of the storer.CartCreate
-type StorerMockCartCreateExpectation struct {
+// StorerMockCartCouponAddExpectation specifies expectation struct of the storer.CartCouponAdd
+type StorerMockCartCouponAddExpectation struct {
 	mock    *StorerMock
-	params  *StorerMockCartCreateParams
-	results *StorerMockCartCreateResults
+	params  *StorerMockCartCouponAddParams
+	results *StorerMockCartCouponAddResults
 	Counter uint64
 }
 
-// StorerMockCartCreateParams contains parameters of the storer.CartCreate
-type StorerMockCartCreateParams struct {
-	ctx  context.Context
-	cart *Cart
+// StorerMockCartCouponAddParams contains parameters of the storer.CartCouponAdd
+type StorerMockCartCouponAddParams struct {
+	ctx    context.Context
+	cartID int64
+	code   string
 }
 
-// StorerMockCartCreateResults contains results of the storer.CartCreate
-type StorerMockCartCreateResults struct {
+// StorerMockCartCouponAddResults contains results of the storer.CartCouponAdd
+type StorerMockCartCouponAddResults struct {
 	err error
 }
 
-// Expect sets up expected params for storer.CartCreate
-func (mmCartCreate *mStorerMockCartCreate) Expect(ctx context.Context, cart *Cart) *mStorerMockCartCreate {
-	if mmCartCreate.mock.funcCartCreate != nil {
-		mmCartCreate.mock.t.Fatalf("StorerMock.CartCreate mock is already set by Set")
+// Expect sets up expected params for storer.CartCouponAdd
+func (mmCartCouponAdd *mStorerMockCartCouponAdd) Expect(ctx context.Context, cartID int64, code string) *mStorerMockCartCouponAdd {
+	if mmCartCouponAdd.mock.funcCartCouponAdd != nil {
+		mmCartCouponAdd.mock.t.Fatalf("StorerMock.CartCouponAdd mock is already set by Set")
 	}
 
-	if mmCartCreate.defaultExpectation == nil {
-		mmCartCreate.defaultExpectation = &StorerMockCartCreateExpectation{}
+	if mmCartCouponAdd.defaultExpectation == nil {
+		mmCartCouponAdd.defaultExpectation = &StorerMockCartCouponAddExpectation{}
 	}
 
-	mmCartCreate.defaultExpectation.params = &StorerMockCartCreateParams{ctx, cart}
-	for _, e := range mmCartCreate.expectations {
-		if minimock.Equal(e.params, mmCartCreate.defaultExpectation.params) {
-			mmCartCreate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartCreate.defaultExpectation.params)
+	mmCartCouponAdd.defaultExpectation.params = &StorerMockCartCouponAddParams{ctx, cartID, code}
+	for _, e := range mmCartCouponAdd.expectations {
+		if minimock.Equal(e.params, mmCartCouponAdd.defaultExpectation.params) {
+			mmCartCouponAdd.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartCouponAdd.defaultExpectation.params)
 		}
 	}
 
-	return mmCartCreate
+	return mmCartCouponAdd
 }
 
-// Inspect accepts an inspector function that has same arguments as the storer.CartCreate
-func (mmCartCreate *mStorerMockCartCreate) Inspect(f func(ctx context.Context, cart *Cart)) *mStorerMockCartCreate {
-	if mmCartCreate.mock.inspectFuncCartCreate != nil {
-		mmCartCreate.mock.t.Fatalf("Inspect function is already set for StorerMock.CartCreate")
+// Inspect accepts an inspector function that has same arguments as the storer.CartCouponAdd
+func (mmCartCouponAdd *mStorerMockCartCouponAdd) Inspect(f func(ctx context.Context, cartID int64, code string)) *mStorerMockCartCouponAdd {
+	if mmCartCouponAdd.mock.inspectFuncCartCouponAdd != nil {
+		mmCartCouponAdd.mock.t.Fatalf("Inspect function is already set for StorerMock.CartCouponAdd")
 	}
 
-	mmCartCreate.mock.inspectFuncCartCreate = f
+	mmCartCouponAdd.mock.inspectFuncCartCouponAdd = f
 
-	return mmCartCreate
+	return mmCartCouponAdd
 }
 
-// Return sets up results that will be returned by storer.CartCreate
-func (mmCartCreate *mStorerMockCartCreate) Return(err error) *StorerMock {
-	if mmCartCreate.mock.funcCartCreate != nil {
-		mmCartCreate.mock.t.Fatalf("StorerMock.CartCreate mock is already set by Set")
+// Return sets up results that will be returned by storer.CartCouponAdd
+func (mmCartCouponAdd *mStorerMockCartCouponAdd) Return(err error) *StorerMock {
+	if mmCartCouponAdd.mock.funcCartCouponAdd != nil {
+		mmCartCouponAdd.mock.t.Fatalf("StorerMock.CartCouponAdd mock is already set by Set")
 	}
 
-	if mmCartCreate.defaultExpectation == nil {
-		mmCartCreate.defaultExpectation = &StorerMockCartCreateExpectation{mock: mmCartCreate.mock}
+	if mmCartCouponAdd.defaultExpectation == nil {
+		mmCartCouponAdd.defaultExpectation = &StorerMockCartCouponAddExpectation{mock: mmCartCouponAdd.mock}
 	}
-	mmCartCreate.defaultExpectation.results = &StorerMockCartCreateResults{err}
-	return mmCartCreate.mock
+	mmCartCouponAdd.defaultExpectation.results = &StorerMockCartCouponAddResults{err}
+	return mmCartCouponAdd.mock
 }
 
-//Set uses given function f to mock the storer.CartCreate method
-func (mmCartCreate *mStorerMockCartCreate) Set(f func(ctx context.Context, cart *Cart) (err error)) *StorerMock {
-	if mmCartCreate.defaultExpectation != nil {
-		mmCartCreate.mock.t.Fatalf("Default expectation is already set for the storer.CartCreate method")
+//Set uses given function f to mock the storer.CartCouponAdd method
+func (mmCartCouponAdd *mStorerMockCartCouponAdd) Set(f func(ctx context.Context, cartID int64, code string) (err error)) *StorerMock {
+	if mmCartCouponAdd.defaultExpectation != nil {
+		mmCartCouponAdd.mock.t.Fatalf("Default expectation is already set for the storer.CartCouponAdd method")
 	}
 
-	if len(mmCartCreate.expectations) > 0 {
-		mmCartCreate.mock.t.Fatalf("Some expectations are already set for the storer.CartCreate method")
+	if len(mmCartCouponAdd.expectations) > 0 {
+		mmCartCouponAdd.mock.t.Fatalf("Some expectations are already set for the storer.CartCouponAdd method")
 	}
 
-	mmCartCreate.mock.funcCartCreate = f
-	return mmCartCreate.mock
+	mmCartCouponAdd.mock.funcCartCouponAdd = f
+	return mmCartCouponAdd.mock
 }
 
-// When sets expectation for the storer.CartCreate which will trigger the result defined by the following
+// When sets expectation for the storer.CartCouponAdd which will trigger the result defined by the following
 // Then helper
-func (mmCartCreate *mStorerMockCartCreate) When(ctx context.Context, cart *Cart) *StorerMockCartCreateExpectation {
-	if mmCartCreate.mock.funcCartCreate != nil {
-		mmCartCreate.mock.t.Fatalf("StorerMock.CartCreate mock is already set by Set")
+func (mmCartCouponAdd *mStorerMockCartCouponAdd) When(ctx context.Context, cartID int64, code string) *StorerMockCartCouponAddExpectation {
+	if mmCartCouponAdd.mock.funcCartCouponAdd != nil {
+		mmCartCouponAdd.mock.t.Fatalf("StorerMock.CartCouponAdd mock is already set by Set")
 	}
 
-	expectation := &StorerMockCartCreateExpectation{
-		mock:   mmCartCreate.mock,
-		params: &StorerMockCartCreateParams{ctx, cart},
+	expectation := &StorerMockCartCouponAddExpectation{
+		mock:   mmCartCouponAdd.mock,
+		params: &StorerMockCartCouponAddParams{ctx, cartID, code},
 	}
-	mmCartCreate.expectations = append(mmCartCreate.expectations, expectation)
+	mmCartCouponAdd.expectations = append(mmCartCouponAdd.expectations, expectation)
 	return expectation
 }
 
-// Then sets up storer.CartCreate return parameters for the expectation previously defined by the When method
-func (e *StorerMockCartCreateExpectation) Then(err error) *StorerMock {
-	e.results = &StorerMockCartCreateResults{err}
+// Then sets up storer.CartCouponAdd return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartCouponAddExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartCouponAddResults{err}
 	return e.mock
 }
 
-// CartCreate implements storer
-func (mmCartCreate *StorerMock) CartCreate(ctx context.Context, cart *Cart) (err error) {
-	mm_atomic.AddUint64(&mmCartCreate.beforeCartCreateCounter, 1)
-	defer mm_atomic.AddUint64(&mmCartCreate.afterCartCreateCounter, 1)
+// CartCouponAdd implements storer
+func (mmCartCouponAdd *StorerMock) CartCouponAdd(ctx context.Context, cartID int64, code string) (err error) {
+	mm_atomic.AddUint64(&mmCartCouponAdd.beforeCartCouponAddCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartCouponAdd.afterCartCouponAddCounter, 1)
 
-	if mmCartCreate.inspectFuncCartCreate != nil {
-		mmCartCreate.inspectFuncCartCreate(ctx, cart)
+	if mmCartCouponAdd.inspectFuncCartCouponAdd != nil {
+		mmCartCouponAdd.inspectFuncCartCouponAdd(ctx, cartID, code)
 	}
 
-	mm_params := &StorerMockCartCreateParams{ctx, cart}
+	mm_params := &StorerMockCartCouponAddParams{ctx, cartID, code}
 
 	// Record call args
-	mmCartCreate.CartCreateMock.mutex.Lock()
-	mmCartCreate.CartCreateMock.callArgs = append(mmCartCreate.CartCreateMock.callArgs, mm_params)
-	mmCartCreate.CartCreateMock.mutex.Unlock()
+	mmCartCouponAdd.CartCouponAddMock.mutex.Lock()
+	mmCartCouponAdd.CartCouponAddMock.callArgs = append(mmCartCouponAdd.CartCouponAddMock.callArgs, mm_params)
+	mmCartCouponAdd.CartCouponAddMock.mutex.Unlock()
 
-	for _, e := range mmCartCreate.CartCreateMock.expectations {
+	for _, e := range mmCartCouponAdd.CartCouponAddMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.err
 		}
 	}
 
-	if mmCartCreate.CartCreateMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmCartCreate.CartCreateMock.defaultExpectation.Counter, 1)
-		mm_want := mmCartCreate.CartCreateMock.defaultExpectation.params
-		mm_got := StorerMockCartCreateParams{ctx, cart}
+	if mmCartCouponAdd.CartCouponAddMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartCouponAdd.CartCouponAddMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartCouponAdd.CartCouponAddMock.defaultExpectation.params
+		mm_got := StorerMockCartCouponAddParams{ctx, cartID, code}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmCartCreate.t.Errorf("StorerMock.CartCreate got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmCartCouponAdd.t.Errorf("StorerMock.CartCouponAdd got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmCartCreate.CartCreateMock.defaultExpectation.results
+		mm_results := mmCartCouponAdd.CartCouponAddMock.defaultExpectation.results
 		if mm_results == nil {
-			mmCartCreate.t.Fatal("No results are set for the StorerMock.CartCreate")
+			mmCartCouponAdd.t.Fatal("No results are set for the StorerMock.CartCouponAdd")
 		}
 		return (*mm_results).err
 	}
-	if mmCartCreate.funcCartCreate != nil {
-		return mmCartCreate.funcCartCreate(ctx, cart)
+	if mmCartCouponAdd.funcCartCouponAdd != nil {
+		return mmCartCouponAdd.funcCartCouponAdd(ctx, cartID, code)
 	}
-	mmCartCreate.t.Fatalf("Unexpected call to StorerMock.CartCreate. %v %v", ctx, cart)
+	mmCartCouponAdd.t.Fatalf("Unexpected call to StorerMock.CartCouponAdd. %v %v %v", ctx, cartID, code)
 	return
 }
 
-// CartCreateAfterCounter returns a count of finished StorerMock.CartCreate invocations
-func (mmCartCreate *StorerMock) CartCreateAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartCreate.afterCartCreateCounter)
+// CartCouponAddAfterCounter returns a count of finished StorerMock.CartCouponAdd invocations
+func (mmCartCouponAdd *StorerMock) CartCouponAddAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartCouponAdd.afterCartCouponAddCounter)
 }
 
-// CartCreateBeforeCounter returns a count of StorerMock.CartCreate invocations
-func (mmCartCreate *StorerMock) CartCreateBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartCreate.beforeCartCreateCounter)
+// CartCouponAddBeforeCounter returns a count of StorerMock.CartCouponAdd invocations
+func (mmCartCouponAdd *StorerMock) CartCouponAddBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartCouponAdd.beforeCartCouponAddCounter)
 }
 
-// Calls returns a list of arguments used in each call to StorerMock.CartCreate.
+// Calls returns a list of arguments used in each call to StorerMock.CartCouponAdd.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmCartCreate *mStorerMockCartCreate) Calls() []*StorerMockCartCreateParams {
-	mmCartCreate.mutex.RLock()
+func (mmCartCouponAdd *mStorerMockCartCouponAdd) Calls() []*StorerMockCartCouponAddParams {
+	mmCartCouponAdd.mutex.RLock()
 
-	argCopy := make([]*StorerMockCartCreateParams, len(mmCartCreate.callArgs))
-	copy(argCopy, mmCartCreate.callArgs)
+	argCopy := make([]*StorerMockCartCouponAddParams, len(mmCartCouponAdd.callArgs))
+	copy(argCopy, mmCartCouponAdd.callArgs)
 
-	mmCartCreate.mutex.RUnlock()
+	mmCartCouponAdd.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockCartCreateDone returns true if the count of the CartCreate invocations corresponds
+// MinimockCartCouponAddDone returns true if the count of the CartCouponAdd invocations corresponds
 // the number of defined expectations
-func (m *StorerMock) MinimockCartCreateDone() bool {
-	for _, e := range m.CartCreateMock.expectations {
+func (m *StorerMock) MinimockCartCouponAddDone() bool {
+	for _, e := range m.CartCouponAddMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
+	if m.CartCouponAddMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCouponAddCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartCreate != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
+	if m.funcCartCouponAdd != nil && mm_atomic.LoadUint64(&m.afterCartCouponAddCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockCartCreateInspect logs each unmet expectation
-func (m *StorerMock) MinimockCartCreateInspect() {
-	for _, e := range m.CartCreateMock.expectations {
+// MinimockCartCouponAddInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartCouponAddInspect() {
+	for _, e := range m.CartCouponAddMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to StorerMock.CartCreate with params: %#v", *e.params)
+			m.t.Errorf("Expected call to StorerMock.CartCouponAdd with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
-		if m.CartCreateMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to StorerMock.CartCreate")
+	if m.CartCouponAddMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCouponAddCounter) < 1 {
+		if m.CartCouponAddMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartCouponAdd")
 		} else {
-			m.t.Errorf("Expected call to StorerMock.CartCreate with params: %#v", *m.CartCreateMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to StorerMock.CartCouponAdd with params: %#v", *m.CartCouponAddMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartCreate != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.CartCreate")
+	if m.funcCartCouponAdd != nil && mm_atomic.LoadUint64(&m.afterCartCouponAddCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartCouponAdd")
 	}
 }
 
-type mStorerMockCartEmpty struct {
+type mStorerMockCartCouponRemove struct {
 	mock               *StorerMock
-	defaultExpectation *StorerMockCartEmptyExpectation
-	expectations       []*StorerMockCartEmptyExpectation
+	defaultExpectation *StorerMockCartCouponRemoveExpectation
+	expectations       []*StorerMockCartCouponRemoveExpectation
 
-	callArgs []*StorerMockCartEmptyParams
+	callArgs []*StorerMockCartCouponRemoveParams
 	mutex    sync.RWMutex
 }
 
-// StorerMockCartEmptyExpectation specifies expectation struct of the storer.CartEmpty
-type StorerMockCartEmptyExpectation struct {
+// StorerMockCartCouponRemoveExpectation specifies expectation struct of the storer.CartCouponRemove
+type StorerMockCartCouponRemoveExpectation struct {
 	mock    *StorerMock
-	params  *StorerMockCartEmptyParams
-	results *StorerMockCartEmptyResults
+	params  *StorerMockCartCouponRemoveParams
+	results *StorerMockCartCouponRemoveResults
 	Counter uint64
 }
 
-// StorerMockCartEmptyParams contains parameters of the storer.CartEmpty
+// StorerMockCartCouponRemoveParams contains parameters of the storer.CartCouponRemove
+type StorerMockCartCouponRemoveParams struct {
+	ctx    context.Context
+	cartID int64
+	code   string
+}
+
+// StorerMockCartCouponRemoveResults contains results of the storer.CartCouponRemove
+type StorerMockCartCouponRemoveResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.CartCouponRemove
+func (mmCartCouponRemove *mStorerMockCartCouponRemove) Expect(ctx context.Context, cartID int64, code string) *mStorerMockCartCouponRemove {
+	if mmCartCouponRemove.mock.funcCartCouponRemove != nil {
+		mmCartCouponRemove.mock.t.Fatalf("StorerMock.CartCouponRemove mock is already set by Set")
+	}
+
+	if mmCartCouponRemove.defaultExpectation == nil {
+		mmCartCouponRemove.defaultExpectation = &StorerMockCartCouponRemoveExpectation{}
+	}
+
+	mmCartCouponRemove.defaultExpectation.params = &StorerMockCartCouponRemoveParams{ctx, cartID, code}
+	for _, e := range mmCartCouponRemove.expectations {
+		if minimock.Equal(e.params, mmCartCouponRemove.defaultExpectation.params) {
+			mmCartCouponRemove.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartCouponRemove.defaultExpectation.params)
+		}
+	}
+
+	return mmCartCouponRemove
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartCouponRemove
+func (mmCartCouponRemove *mStorerMockCartCouponRemove) Inspect(f func(ctx context.Context, cartID int64, code string)) *mStorerMockCartCouponRemove {
+	if mmCartCouponRemove.mock.inspectFuncCartCouponRemove != nil {
+		mmCartCouponRemove.mock.t.Fatalf("Inspect function is already set for StorerMock.CartCouponRemove")
+	}
+
+	mmCartCouponRemove.mock.inspectFuncCartCouponRemove = f
+
+	return mmCartCouponRemove
+}
+
+// Return sets up results that will be returned by storer.CartCouponRemove
+func (mmCartCouponRemove *mStorerMockCartCouponRemove) Return(err error) *StorerMock {
+	if mmCartCouponRemove.mock.funcCartCouponRemove != nil {
+		mmCartCouponRemove.mock.t.Fatalf("StorerMock.CartCouponRemove mock is already set by Set")
+	}
+
+	if mmCartCouponRemove.defaultExpectation == nil {
+		mmCartCouponRemove.defaultExpectation = &StorerMockCartCouponRemoveExpectation{mock: mmCartCouponRemove.mock}
+	}
+	mmCartCouponRemove.defaultExpectation.results = &StorerMockCartCouponRemoveResults{err}
+	return mmCartCouponRemove.mock
+}
+
+//Set uses given function f to mock the storer.CartCouponRemove method
+func (mmCartCouponRemove *mStorerMockCartCouponRemove) Set(f func(ctx context.Context, cartID int64, code string) (err error)) *StorerMock {
+	if mmCartCouponRemove.defaultExpectation != nil {
+		mmCartCouponRemove.mock.t.Fatalf("Default expectation is already set for the storer.CartCouponRemove method")
+	}
+
+	if len(mmCartCouponRemove.expectations) > 0 {
+		mmCartCouponRemove.mock.t.Fatalf("Some expectations are already set for the storer.CartCouponRemove method")
+	}
+
+	mmCartCouponRemove.mock.funcCartCouponRemove = f
+	return mmCartCouponRemove.mock
+}
+
+// When sets expectation for the storer.CartCouponRemove which will trigger the result defined by the following
+// Then helper
+func (mmCartCouponRemove *mStorerMockCartCouponRemove) When(ctx context.Context, cartID int64, code string) *StorerMockCartCouponRemoveExpectation {
+	if mmCartCouponRemove.mock.funcCartCouponRemove != nil {
+		mmCartCouponRemove.mock.t.Fatalf("StorerMock.CartCouponRemove mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartCouponRemoveExpectation{
+		mock:   mmCartCouponRemove.mock,
+		params: &StorerMockCartCouponRemoveParams{ctx, cartID, code},
+	}
+	mmCartCouponRemove.expectations = append(mmCartCouponRemove.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartCouponRemove return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartCouponRemoveExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartCouponRemoveResults{err}
+	return e.mock
+}
+
+// CartCouponRemove implements storer
+func (mmCartCouponRemove *StorerMock) CartCouponRemove(ctx context.Context, cartID int64, code string) (err error) {
+	mm_atomic.AddUint64(&mmCartCouponRemove.beforeCartCouponRemoveCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartCouponRemove.afterCartCouponRemoveCounter, 1)
+
+	if mmCartCouponRemove.inspectFuncCartCouponRemove != nil {
+		mmCartCouponRemove.inspectFuncCartCouponRemove(ctx, cartID, code)
+	}
+
+	mm_params := &StorerMockCartCouponRemoveParams{ctx, cartID, code}
+
+	// Record call args
+	mmCartCouponRemove.CartCouponRemoveMock.mutex.Lock()
+	mmCartCouponRemove.CartCouponRemoveMock.callArgs = append(mmCartCouponRemove.CartCouponRemoveMock.callArgs, mm_params)
+	mmCartCouponRemove.CartCouponRemoveMock.mutex.Unlock()
+
+	for _, e := range mmCartCouponRemove.CartCouponRemoveMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmCartCouponRemove.CartCouponRemoveMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartCouponRemove.CartCouponRemoveMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartCouponRemove.CartCouponRemoveMock.defaultExpectation.params
+		mm_got := StorerMockCartCouponRemoveParams{ctx, cartID, code}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartCouponRemove.t.Errorf("StorerMock.CartCouponRemove got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartCouponRemove.CartCouponRemoveMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartCouponRemove.t.Fatal("No results are set for the StorerMock.CartCouponRemove")
+		}
+		return (*mm_results).err
+	}
+	if mmCartCouponRemove.funcCartCouponRemove != nil {
+		return mmCartCouponRemove.funcCartCouponRemove(ctx, cartID, code)
+	}
+	mmCartCouponRemove.t.Fatalf("Unexpected call to StorerMock.CartCouponRemove. %v %v %v", ctx, cartID, code)
+	return
+}
+
+// CartCouponRemoveAfterCounter returns a count of finished StorerMock.CartCouponRemove invocations
+func (mmCartCouponRemove *StorerMock) CartCouponRemoveAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartCouponRemove.afterCartCouponRemoveCounter)
+}
+
+// CartCouponRemoveBeforeCounter returns a count of StorerMock.CartCouponRemove invocations
+func (mmCartCouponRemove *StorerMock) CartCouponRemoveBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartCouponRemove.beforeCartCouponRemoveCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartCouponRemove.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartCouponRemove *mStorerMockCartCouponRemove) Calls() []*StorerMockCartCouponRemoveParams {
+	mmCartCouponRemove.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartCouponRemoveParams, len(mmCartCouponRemove.callArgs))
+	copy(argCopy, mmCartCouponRemove.callArgs)
+
+	mmCartCouponRemove.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartCouponRemoveDone returns true if the count of the CartCouponRemove invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartCouponRemoveDone() bool {
+	for _, e := range m.CartCouponRemoveMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartCouponRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCouponRemoveCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartCouponRemove != nil && mm_atomic.LoadUint64(&m.afterCartCouponRemoveCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartCouponRemoveInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartCouponRemoveInspect() {
+	for _, e := range m.CartCouponRemoveMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartCouponRemove with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartCouponRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCouponRemoveCounter) < 1 {
+		if m.CartCouponRemoveMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartCouponRemove")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartCouponRemove with params: %#v", *m.CartCouponRemoveMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartCouponRemove != nil && mm_atomic.LoadUint64(&m.afterCartCouponRemoveCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartCouponRemove")
+	}
+}
+
+type mStorerMockCartCreate struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartCreateExpectation
+	expectations       []*StorerMockCartCreateExpectation
+
+	callArgs []*StorerMockCartCreateParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartCreateExpectation specifies expectation struct of the storer.CartCreate
+type StorerMockCartCreateExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartCreateParams
+	results *StorerMockCartCreateResults
+	Counter uint64
+}
+
+// StorerMockCartCreateParams contains parameters of the storer.CartCreate
+type StorerMockCartCreateParams struct {
+	ctx  context.Context
+	cart *Cart
+}
+
+// StorerMockCartCreateResults contains results of the storer.CartCreate
+type StorerMockCartCreateResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.CartCreate
+func (mmCartCreate *mStorerMockCartCreate) Expect(ctx context.Context, cart *Cart) *mStorerMockCartCreate {
+	if mmCartCreate.mock.funcCartCreate != nil {
+		mmCartCreate.mock.t.Fatalf("StorerMock.CartCreate mock is already set by Set")
+	}
+
+	if mmCartCreate.defaultExpectation == nil {
+		mmCartCreate.defaultExpectation = &StorerMockCartCreateExpectation{}
+	}
+
+	mmCartCreate.defaultExpectation.params = &StorerMockCartCreateParams{ctx, cart}
+	for _, e := range mmCartCreate.expectations {
+		if minimock.Equal(e.params, mmCartCreate.defaultExpectation.params) {
+			mmCartCreate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartCreate.defaultExpectation.params)
+		}
+	}
+
+	return mmCartCreate
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartCreate
+func (mmCartCreate *mStorerMockCartCreate) Inspect(f func(ctx context.Context, cart *Cart)) *mStorerMockCartCreate {
+	if mmCartCreate.mock.inspectFuncCartCreate != nil {
+		mmCartCreate.mock.t.Fatalf("Inspect function is already set for StorerMock.CartCreate")
+	}
+
+	mmCartCreate.mock.inspectFuncCartCreate = f
+
+	return mmCartCreate
+}
+
+// Return sets up results that will be returned by storer.CartCreate
+func (mmCartCreate *mStorerMockCartCreate) Return(err error) *StorerMock {
+	if mmCartCreate.mock.funcCartCreate != nil {
+		mmCartCreate.mock.t.Fatalf("StorerMock.CartCreate mock is already set by Set")
+	}
+
+	if mmCartCreate.defaultExpectation == nil {
+		mmCartCreate.defaultExpectation = &StorerMockCartCreateExpectation{mock: mmCartCreate.mock}
+	}
+	mmCartCreate.defaultExpectation.results = &StorerMockCartCreateResults{err}
+	return mmCartCreate.mock
+}
+
+//Set uses given function f to mock the storer.CartCreate method
+func (mmCartCreate *mStorerMockCartCreate) Set(f func(ctx context.Context, cart *Cart) (err error)) *StorerMock {
+	if mmCartCreate.defaultExpectation != nil {
+		mmCartCreate.mock.t.Fatalf("Default expectation is already set for the storer.CartCreate method")
+	}
+
+	if len(mmCartCreate.expectations) > 0 {
+		mmCartCreate.mock.t.Fatalf("Some expectations are already set for the storer.CartCreate method")
+	}
+
+	mmCartCreate.mock.funcCartCreate = f
+	return mmCartCreate.mock
+}
+
+// When sets expectation for the storer.CartCreate which will trigger the result defined by the following
+// Then helper
+func (mmCartCreate *mStorerMockCartCreate) When(ctx context.Context, cart *Cart) *StorerMockCartCreateExpectation {
+	if mmCartCreate.mock.funcCartCreate != nil {
+		mmCartCreate.mock.t.Fatalf("StorerMock.CartCreate mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartCreateExpectation{
+		mock:   mmCartCreate.mock,
+		params: &StorerMockCartCreateParams{ctx, cart},
+	}
+	mmCartCreate.expectations = append(mmCartCreate.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartCreate return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartCreateExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartCreateResults{err}
+	return e.mock
+}
+
+// CartCreate implements storer
+func (mmCartCreate *StorerMock) CartCreate(ctx context.Context, cart *Cart) (err error) {
+	mm_atomic.AddUint64(&mmCartCreate.beforeCartCreateCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartCreate.afterCartCreateCounter, 1)
+
+	if mmCartCreate.inspectFuncCartCreate != nil {
+		mmCartCreate.inspectFuncCartCreate(ctx, cart)
+	}
+
+	mm_params := &StorerMockCartCreateParams{ctx, cart}
+
+	// Record call args
+	mmCartCreate.CartCreateMock.mutex.Lock()
+	mmCartCreate.CartCreateMock.callArgs = append(mmCartCreate.CartCreateMock.callArgs, mm_params)
+	mmCartCreate.CartCreateMock.mutex.Unlock()
+
+	for _, e := range mmCartCreate.CartCreateMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmCartCreate.CartCreateMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartCreate.CartCreateMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartCreate.CartCreateMock.defaultExpectation.params
+		mm_got := StorerMockCartCreateParams{ctx, cart}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartCreate.t.Errorf("StorerMock.CartCreate got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartCreate.CartCreateMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartCreate.t.Fatal("No results are set for the StorerMock.CartCreate")
+		}
+		return (*mm_results).err
+	}
+	if mmCartCreate.funcCartCreate != nil {
+		return mmCartCreate.funcCartCreate(ctx, cart)
+	}
+	mmCartCreate.t.Fatalf("Unexpected call to StorerMock.CartCreate. %v %v", ctx, cart)
+	return
+}
+
+// CartCreateAfterCounter returns a count of finished StorerMock.CartCreate invocations
+func (mmCartCreate *StorerMock) CartCreateAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartCreate.afterCartCreateCounter)
+}
+
+// CartCreateBeforeCounter returns a count of StorerMock.CartCreate invocations
+func (mmCartCreate *StorerMock) CartCreateBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartCreate.beforeCartCreateCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartCreate.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartCreate *mStorerMockCartCreate) Calls() []*StorerMockCartCreateParams {
+	mmCartCreate.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartCreateParams, len(mmCartCreate.callArgs))
+	copy(argCopy, mmCartCreate.callArgs)
+
+	mmCartCreate.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartCreateDone returns true if the count of the CartCreate invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartCreateDone() bool {
+	for _, e := range m.CartCreateMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartCreate != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartCreateInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartCreateInspect() {
+	for _, e := range m.CartCreateMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartCreate with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
+		if m.CartCreateMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartCreate")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartCreate with params: %#v", *m.CartCreateMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartCreate != nil && mm_atomic.LoadUint64(&m.afterCartCreateCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartCreate")
+	}
+}
+
+type mStorerMockCartEmpty struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartEmptyExpectation
+	expectations       []*StorerMockCartEmptyExpectation
+
+	callArgs []*StorerMockCartEmptyParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartEmptyExpectation specifies expectation struct of the storer.CartEmpty
+type StorerMockCartEmptyExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartEmptyParams
+	results *StorerMockCartEmptyResults
+	Counter uint64
+}
+
+// StorerMockCartEmptyParams contains parameters of the storer.CartEmpty
 type StorerMockCartEmptyParams struct {
 	ctx    context.Context
 	cartID int64
 }
 
-// StorerMockCartEmptyResults contains results of the storer.CartEmpty
-type StorerMockCartEmptyResults struct {
+// StorerMockCartEmptyResults contains results of the storer.CartEmpty
+type StorerMockCartEmptyResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.CartEmpty
+func (mmCartEmpty *mStorerMockCartEmpty) Expect(ctx context.Context, cartID int64) *mStorerMockCartEmpty {
+	if mmCartEmpty.mock.funcCartEmpty != nil {
+		mmCartEmpty.mock.t.Fatalf("StorerMock.CartEmpty mock is already set by Set")
+	}
+
+	if mmCartEmpty.defaultExpectation == nil {
+		mmCartEmpty.defaultExpectation = &StorerMockCartEmptyExpectation{}
+	}
+
+	mmCartEmpty.defaultExpectation.params = &StorerMockCartEmptyParams{ctx, cartID}
+	for _, e := range mmCartEmpty.expectations {
+		if minimock.Equal(e.params, mmCartEmpty.defaultExpectation.params) {
+			mmCartEmpty.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartEmpty.defaultExpectation.params)
+		}
+	}
+
+	return mmCartEmpty
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartEmpty
+func (mmCartEmpty *mStorerMockCartEmpty) Inspect(f func(ctx context.Context, cartID int64)) *mStorerMockCartEmpty {
+	if mmCartEmpty.mock.inspectFuncCartEmpty != nil {
+		mmCartEmpty.mock.t.Fatalf("Inspect function is already set for StorerMock.CartEmpty")
+	}
+
+	mmCartEmpty.mock.inspectFuncCartEmpty = f
+
+	return mmCartEmpty
+}
+
+// Return sets up results that will be returned by storer.CartEmpty
+func (mmCartEmpty *mStorerMockCartEmpty) Return(err error) *StorerMock {
+	if mmCartEmpty.mock.funcCartEmpty != nil {
+		mmCartEmpty.mock.t.Fatalf("StorerMock.CartEmpty mock is already set by Set")
+	}
+
+	if mmCartEmpty.defaultExpectation == nil {
+		mmCartEmpty.defaultExpectation = &StorerMockCartEmptyExpectation{mock: mmCartEmpty.mock}
+	}
+	mmCartEmpty.defaultExpectation.results = &StorerMockCartEmptyResults{err}
+	return mmCartEmpty.mock
+}
+
+//Set uses given function f to mock the storer.CartEmpty method
+func (mmCartEmpty *mStorerMockCartEmpty) Set(f func(ctx context.Context, cartID int64) (err error)) *StorerMock {
+	if mmCartEmpty.defaultExpectation != nil {
+		mmCartEmpty.mock.t.Fatalf("Default expectation is already set for the storer.CartEmpty method")
+	}
+
+	if len(mmCartEmpty.expectations) > 0 {
+		mmCartEmpty.mock.t.Fatalf("Some expectations are already set for the storer.CartEmpty method")
+	}
+
+	mmCartEmpty.mock.funcCartEmpty = f
+	return mmCartEmpty.mock
+}
+
+// When sets expectation for the storer.CartEmpty which will trigger the result defined by the following
+// Then helper
+func (mmCartEmpty *mStorerMockCartEmpty) When(ctx context.Context, cartID int64) *StorerMockCartEmptyExpectation {
+	if mmCartEmpty.mock.funcCartEmpty != nil {
+		mmCartEmpty.mock.t.Fatalf("StorerMock.CartEmpty mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartEmptyExpectation{
+		mock:   mmCartEmpty.mock,
+		params: &StorerMockCartEmptyParams{ctx, cartID},
+	}
+	mmCartEmpty.expectations = append(mmCartEmpty.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartEmpty return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartEmptyExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartEmptyResults{err}
+	return e.mock
+}
+
+// CartEmpty implements storer
+func (mmCartEmpty *StorerMock) CartEmpty(ctx context.Context, cartID int64) (err error) {
+	mm_atomic.AddUint64(&mmCartEmpty.beforeCartEmptyCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartEmpty.afterCartEmptyCounter, 1)
+
+	if mmCartEmpty.inspectFuncCartEmpty != nil {
+		mmCartEmpty.inspectFuncCartEmpty(ctx, cartID)
+	}
+
+	mm_params := &StorerMockCartEmptyParams{ctx, cartID}
+
+	// Record call args
+	mmCartEmpty.CartEmptyMock.mutex.Lock()
+	mmCartEmpty.CartEmptyMock.callArgs = append(mmCartEmpty.CartEmptyMock.callArgs, mm_params)
+	mmCartEmpty.CartEmptyMock.mutex.Unlock()
+
+	for _, e := range mmCartEmpty.CartEmptyMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmCartEmpty.CartEmptyMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartEmpty.CartEmptyMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartEmpty.CartEmptyMock.defaultExpectation.params
+		mm_got := StorerMockCartEmptyParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartEmpty.t.Errorf("StorerMock.CartEmpty got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartEmpty.CartEmptyMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartEmpty.t.Fatal("No results are set for the StorerMock.CartEmpty")
+		}
+		return (*mm_results).err
+	}
+	if mmCartEmpty.funcCartEmpty != nil {
+		return mmCartEmpty.funcCartEmpty(ctx, cartID)
+	}
+	mmCartEmpty.t.Fatalf("Unexpected call to StorerMock.CartEmpty. %v %v", ctx, cartID)
+	return
+}
+
+// CartEmptyAfterCounter returns a count of finished StorerMock.CartEmpty invocations
+func (mmCartEmpty *StorerMock) CartEmptyAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartEmpty.afterCartEmptyCounter)
+}
+
+// CartEmptyBeforeCounter returns a count of StorerMock.CartEmpty invocations
+func (mmCartEmpty *StorerMock) CartEmptyBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartEmpty.beforeCartEmptyCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartEmpty.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartEmpty *mStorerMockCartEmpty) Calls() []*StorerMockCartEmptyParams {
+	mmCartEmpty.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartEmptyParams, len(mmCartEmpty.callArgs))
+	copy(argCopy, mmCartEmpty.callArgs)
+
+	mmCartEmpty.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartEmptyDone returns true if the count of the CartEmpty invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartEmptyDone() bool {
+	for _, e := range m.CartEmptyMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartEmptyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartEmpty != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartEmptyInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartEmptyInspect() {
+	for _, e := range m.CartEmptyMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartEmpty with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartEmptyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
+		if m.CartEmptyMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartEmpty")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartEmpty with params: %#v", *m.CartEmptyMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartEmpty != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartEmpty")
+	}
+}
+
+type mStorerMockCartShippingAddressSet struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartShippingAddressSetExpectation
+	expectations       []*StorerMockCartShippingAddressSetExpectation
+
+	callArgs []*StorerMockCartShippingAddressSetParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartShippingAddressSetExpectation specifies expectation struct of the storer.CartShippingAddressSet
+type StorerMockCartShippingAddressSetExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartShippingAddressSetParams
+	results *StorerMockCartShippingAddressSetResults
+	Counter uint64
+}
+
+// StorerMockCartShippingAddressSetParams contains parameters of the storer.CartShippingAddressSet
+type StorerMockCartShippingAddressSetParams struct {
+	ctx    context.Context
+	cartID int64
+	a      *Address
+}
+
+// StorerMockCartShippingAddressSetResults contains results of the storer.CartShippingAddressSet
+type StorerMockCartShippingAddressSetResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.CartShippingAddressSet
+func (mmCartShippingAddressSet *mStorerMockCartShippingAddressSet) Expect(ctx context.Context, cartID int64, a *Address) *mStorerMockCartShippingAddressSet {
+	if mmCartShippingAddressSet.mock.funcCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("StorerMock.CartShippingAddressSet mock is already set by Set")
+	}
+
+	if mmCartShippingAddressSet.defaultExpectation == nil {
+		mmCartShippingAddressSet.defaultExpectation = &StorerMockCartShippingAddressSetExpectation{}
+	}
+
+	mmCartShippingAddressSet.defaultExpectation.params = &StorerMockCartShippingAddressSetParams{ctx, cartID, a}
+	for _, e := range mmCartShippingAddressSet.expectations {
+		if minimock.Equal(e.params, mmCartShippingAddressSet.defaultExpectation.params) {
+			mmCartShippingAddressSet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartShippingAddressSet.defaultExpectation.params)
+		}
+	}
+
+	return mmCartShippingAddressSet
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartShippingAddressSet
+func (mmCartShippingAddressSet *mStorerMockCartShippingAddressSet) Inspect(f func(ctx context.Context, cartID int64, a *Address)) *mStorerMockCartShippingAddressSet {
+	if mmCartShippingAddressSet.mock.inspectFuncCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("Inspect function is already set for StorerMock.CartShippingAddressSet")
+	}
+
+	mmCartShippingAddressSet.mock.inspectFuncCartShippingAddressSet = f
+
+	return mmCartShippingAddressSet
+}
+
+// Return sets up results that will be returned by storer.CartShippingAddressSet
+func (mmCartShippingAddressSet *mStorerMockCartShippingAddressSet) Return(err error) *StorerMock {
+	if mmCartShippingAddressSet.mock.funcCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("StorerMock.CartShippingAddressSet mock is already set by Set")
+	}
+
+	if mmCartShippingAddressSet.defaultExpectation == nil {
+		mmCartShippingAddressSet.defaultExpectation = &StorerMockCartShippingAddressSetExpectation{mock: mmCartShippingAddressSet.mock}
+	}
+	mmCartShippingAddressSet.defaultExpectation.results = &StorerMockCartShippingAddressSetResults{err}
+	return mmCartShippingAddressSet.mock
+}
+
+//Set uses given function f to mock the storer.CartShippingAddressSet method
+func (mmCartShippingAddressSet *mStorerMockCartShippingAddressSet) Set(f func(ctx context.Context, cartID int64, a *Address) (err error)) *StorerMock {
+	if mmCartShippingAddressSet.defaultExpectation != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("Default expectation is already set for the storer.CartShippingAddressSet method")
+	}
+
+	if len(mmCartShippingAddressSet.expectations) > 0 {
+		mmCartShippingAddressSet.mock.t.Fatalf("Some expectations are already set for the storer.CartShippingAddressSet method")
+	}
+
+	mmCartShippingAddressSet.mock.funcCartShippingAddressSet = f
+	return mmCartShippingAddressSet.mock
+}
+
+// When sets expectation for the storer.CartShippingAddressSet which will trigger the result defined by the following
+// Then helper
+func (mmCartShippingAddressSet *mStorerMockCartShippingAddressSet) When(ctx context.Context, cartID int64, a *Address) *StorerMockCartShippingAddressSetExpectation {
+	if mmCartShippingAddressSet.mock.funcCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.mock.t.Fatalf("StorerMock.CartShippingAddressSet mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartShippingAddressSetExpectation{
+		mock:   mmCartShippingAddressSet.mock,
+		params: &StorerMockCartShippingAddressSetParams{ctx, cartID, a},
+	}
+	mmCartShippingAddressSet.expectations = append(mmCartShippingAddressSet.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartShippingAddressSet return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartShippingAddressSetExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartShippingAddressSetResults{err}
+	return e.mock
+}
+
+// CartShippingAddressSet implements storer
+func (mmCartShippingAddressSet *StorerMock) CartShippingAddressSet(ctx context.Context, cartID int64, a *Address) (err error) {
+	mm_atomic.AddUint64(&mmCartShippingAddressSet.beforeCartShippingAddressSetCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartShippingAddressSet.afterCartShippingAddressSetCounter, 1)
+
+	if mmCartShippingAddressSet.inspectFuncCartShippingAddressSet != nil {
+		mmCartShippingAddressSet.inspectFuncCartShippingAddressSet(ctx, cartID, a)
+	}
+
+	mm_params := &StorerMockCartShippingAddressSetParams{ctx, cartID, a}
+
+	// Record call args
+	mmCartShippingAddressSet.CartShippingAddressSetMock.mutex.Lock()
+	mmCartShippingAddressSet.CartShippingAddressSetMock.callArgs = append(mmCartShippingAddressSet.CartShippingAddressSetMock.callArgs, mm_params)
+	mmCartShippingAddressSet.CartShippingAddressSetMock.mutex.Unlock()
+
+	for _, e := range mmCartShippingAddressSet.CartShippingAddressSetMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation.params
+		mm_got := StorerMockCartShippingAddressSetParams{ctx, cartID, a}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartShippingAddressSet.t.Errorf("StorerMock.CartShippingAddressSet got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartShippingAddressSet.CartShippingAddressSetMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartShippingAddressSet.t.Fatal("No results are set for the StorerMock.CartShippingAddressSet")
+		}
+		return (*mm_results).err
+	}
+	if mmCartShippingAddressSet.funcCartShippingAddressSet != nil {
+		return mmCartShippingAddressSet.funcCartShippingAddressSet(ctx, cartID, a)
+	}
+	mmCartShippingAddressSet.t.Fatalf("Unexpected call to StorerMock.CartShippingAddressSet. %v %v %v", ctx, cartID, a)
+	return
+}
+
+// CartShippingAddressSetAfterCounter returns a count of finished StorerMock.CartShippingAddressSet invocations
+func (mmCartShippingAddressSet *StorerMock) CartShippingAddressSetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingAddressSet.afterCartShippingAddressSetCounter)
+}
+
+// CartShippingAddressSetBeforeCounter returns a count of StorerMock.CartShippingAddressSet invocations
+func (mmCartShippingAddressSet *StorerMock) CartShippingAddressSetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingAddressSet.beforeCartShippingAddressSetCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartShippingAddressSet.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartShippingAddressSet *mStorerMockCartShippingAddressSet) Calls() []*StorerMockCartShippingAddressSetParams {
+	mmCartShippingAddressSet.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartShippingAddressSetParams, len(mmCartShippingAddressSet.callArgs))
+	copy(argCopy, mmCartShippingAddressSet.callArgs)
+
+	mmCartShippingAddressSet.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartShippingAddressSetDone returns true if the count of the CartShippingAddressSet invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartShippingAddressSetDone() bool {
+	for _, e := range m.CartShippingAddressSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingAddressSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingAddressSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartShippingAddressSetInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartShippingAddressSetInspect() {
+	for _, e := range m.CartShippingAddressSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartShippingAddressSet with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingAddressSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		if m.CartShippingAddressSetMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartShippingAddressSet")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartShippingAddressSet with params: %#v", *m.CartShippingAddressSetMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingAddressSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingAddressSetCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartShippingAddressSet")
+	}
+}
+
+type mStorerMockCartShippingMethodSet struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartShippingMethodSetExpectation
+	expectations       []*StorerMockCartShippingMethodSetExpectation
+
+	callArgs []*StorerMockCartShippingMethodSetParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartShippingMethodSetExpectation specifies expectation struct of the storer.CartShippingMethodSet
+type StorerMockCartShippingMethodSetExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartShippingMethodSetParams
+	results *StorerMockCartShippingMethodSetResults
+	Counter uint64
+}
+
+// StorerMockCartShippingMethodSetParams contains parameters of the storer.CartShippingMethodSet
+type StorerMockCartShippingMethodSetParams struct {
+	ctx    context.Context
+	cartID int64
+	code   string
+}
+
+// StorerMockCartShippingMethodSetResults contains results of the storer.CartShippingMethodSet
+type StorerMockCartShippingMethodSetResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.CartShippingMethodSet
+func (mmCartShippingMethodSet *mStorerMockCartShippingMethodSet) Expect(ctx context.Context, cartID int64, code string) *mStorerMockCartShippingMethodSet {
+	if mmCartShippingMethodSet.mock.funcCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("StorerMock.CartShippingMethodSet mock is already set by Set")
+	}
+
+	if mmCartShippingMethodSet.defaultExpectation == nil {
+		mmCartShippingMethodSet.defaultExpectation = &StorerMockCartShippingMethodSetExpectation{}
+	}
+
+	mmCartShippingMethodSet.defaultExpectation.params = &StorerMockCartShippingMethodSetParams{ctx, cartID, code}
+	for _, e := range mmCartShippingMethodSet.expectations {
+		if minimock.Equal(e.params, mmCartShippingMethodSet.defaultExpectation.params) {
+			mmCartShippingMethodSet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartShippingMethodSet.defaultExpectation.params)
+		}
+	}
+
+	return mmCartShippingMethodSet
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartShippingMethodSet
+func (mmCartShippingMethodSet *mStorerMockCartShippingMethodSet) Inspect(f func(ctx context.Context, cartID int64, code string)) *mStorerMockCartShippingMethodSet {
+	if mmCartShippingMethodSet.mock.inspectFuncCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("Inspect function is already set for StorerMock.CartShippingMethodSet")
+	}
+
+	mmCartShippingMethodSet.mock.inspectFuncCartShippingMethodSet = f
+
+	return mmCartShippingMethodSet
+}
+
+// Return sets up results that will be returned by storer.CartShippingMethodSet
+func (mmCartShippingMethodSet *mStorerMockCartShippingMethodSet) Return(err error) *StorerMock {
+	if mmCartShippingMethodSet.mock.funcCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("StorerMock.CartShippingMethodSet mock is already set by Set")
+	}
+
+	if mmCartShippingMethodSet.defaultExpectation == nil {
+		mmCartShippingMethodSet.defaultExpectation = &StorerMockCartShippingMethodSetExpectation{mock: mmCartShippingMethodSet.mock}
+	}
+	mmCartShippingMethodSet.defaultExpectation.results = &StorerMockCartShippingMethodSetResults{err}
+	return mmCartShippingMethodSet.mock
+}
+
+//Set uses given function f to mock the storer.CartShippingMethodSet method
+func (mmCartShippingMethodSet *mStorerMockCartShippingMethodSet) Set(f func(ctx context.Context, cartID int64, code string) (err error)) *StorerMock {
+	if mmCartShippingMethodSet.defaultExpectation != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("Default expectation is already set for the storer.CartShippingMethodSet method")
+	}
+
+	if len(mmCartShippingMethodSet.expectations) > 0 {
+		mmCartShippingMethodSet.mock.t.Fatalf("Some expectations are already set for the storer.CartShippingMethodSet method")
+	}
+
+	mmCartShippingMethodSet.mock.funcCartShippingMethodSet = f
+	return mmCartShippingMethodSet.mock
+}
+
+// When sets expectation for the storer.CartShippingMethodSet which will trigger the result defined by the following
+// Then helper
+func (mmCartShippingMethodSet *mStorerMockCartShippingMethodSet) When(ctx context.Context, cartID int64, code string) *StorerMockCartShippingMethodSetExpectation {
+	if mmCartShippingMethodSet.mock.funcCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.mock.t.Fatalf("StorerMock.CartShippingMethodSet mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartShippingMethodSetExpectation{
+		mock:   mmCartShippingMethodSet.mock,
+		params: &StorerMockCartShippingMethodSetParams{ctx, cartID, code},
+	}
+	mmCartShippingMethodSet.expectations = append(mmCartShippingMethodSet.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartShippingMethodSet return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartShippingMethodSetExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartShippingMethodSetResults{err}
+	return e.mock
+}
+
+// CartShippingMethodSet implements storer
+func (mmCartShippingMethodSet *StorerMock) CartShippingMethodSet(ctx context.Context, cartID int64, code string) (err error) {
+	mm_atomic.AddUint64(&mmCartShippingMethodSet.beforeCartShippingMethodSetCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartShippingMethodSet.afterCartShippingMethodSetCounter, 1)
+
+	if mmCartShippingMethodSet.inspectFuncCartShippingMethodSet != nil {
+		mmCartShippingMethodSet.inspectFuncCartShippingMethodSet(ctx, cartID, code)
+	}
+
+	mm_params := &StorerMockCartShippingMethodSetParams{ctx, cartID, code}
+
+	// Record call args
+	mmCartShippingMethodSet.CartShippingMethodSetMock.mutex.Lock()
+	mmCartShippingMethodSet.CartShippingMethodSetMock.callArgs = append(mmCartShippingMethodSet.CartShippingMethodSetMock.callArgs, mm_params)
+	mmCartShippingMethodSet.CartShippingMethodSetMock.mutex.Unlock()
+
+	for _, e := range mmCartShippingMethodSet.CartShippingMethodSetMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation.params
+		mm_got := StorerMockCartShippingMethodSetParams{ctx, cartID, code}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartShippingMethodSet.t.Errorf("StorerMock.CartShippingMethodSet got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartShippingMethodSet.CartShippingMethodSetMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartShippingMethodSet.t.Fatal("No results are set for the StorerMock.CartShippingMethodSet")
+		}
+		return (*mm_results).err
+	}
+	if mmCartShippingMethodSet.funcCartShippingMethodSet != nil {
+		return mmCartShippingMethodSet.funcCartShippingMethodSet(ctx, cartID, code)
+	}
+	mmCartShippingMethodSet.t.Fatalf("Unexpected call to StorerMock.CartShippingMethodSet. %v %v %v", ctx, cartID, code)
+	return
+}
+
+// CartShippingMethodSetAfterCounter returns a count of finished StorerMock.CartShippingMethodSet invocations
+func (mmCartShippingMethodSet *StorerMock) CartShippingMethodSetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingMethodSet.afterCartShippingMethodSetCounter)
+}
+
+// CartShippingMethodSetBeforeCounter returns a count of StorerMock.CartShippingMethodSet invocations
+func (mmCartShippingMethodSet *StorerMock) CartShippingMethodSetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartShippingMethodSet.beforeCartShippingMethodSetCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartShippingMethodSet.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartShippingMethodSet *mStorerMockCartShippingMethodSet) Calls() []*StorerMockCartShippingMethodSetParams {
+	mmCartShippingMethodSet.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartShippingMethodSetParams, len(mmCartShippingMethodSet.callArgs))
+	copy(argCopy, mmCartShippingMethodSet.callArgs)
+
+	mmCartShippingMethodSet.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartShippingMethodSetDone returns true if the count of the CartShippingMethodSet invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartShippingMethodSetDone() bool {
+	for _, e := range m.CartShippingMethodSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingMethodSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingMethodSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartShippingMethodSetInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartShippingMethodSetInspect() {
+	for _, e := range m.CartShippingMethodSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartShippingMethodSet with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartShippingMethodSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		if m.CartShippingMethodSetMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartShippingMethodSet")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartShippingMethodSet with params: %#v", *m.CartShippingMethodSetMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartShippingMethodSet != nil && mm_atomic.LoadUint64(&m.afterCartShippingMethodSetCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartShippingMethodSet")
+	}
+}
+
+type mStorerMockCartStatusSet struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartStatusSetExpectation
+	expectations       []*StorerMockCartStatusSetExpectation
+
+	callArgs []*StorerMockCartStatusSetParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartStatusSetExpectation specifies expectation struct of the storer.CartStatusSet
+type StorerMockCartStatusSetExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartStatusSetParams
+	results *StorerMockCartStatusSetResults
+	Counter uint64
+}
+
+// StorerMockCartStatusSetParams contains parameters of the storer.CartStatusSet
+type StorerMockCartStatusSetParams struct {
+	ctx    context.Context
+	cartID int64
+	status CartStatus
+}
+
+// StorerMockCartStatusSetResults contains results of the storer.CartStatusSet
+type StorerMockCartStatusSetResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.CartStatusSet
+func (mmCartStatusSet *mStorerMockCartStatusSet) Expect(ctx context.Context, cartID int64, status CartStatus) *mStorerMockCartStatusSet {
+	if mmCartStatusSet.mock.funcCartStatusSet != nil {
+		mmCartStatusSet.mock.t.Fatalf("StorerMock.CartStatusSet mock is already set by Set")
+	}
+
+	if mmCartStatusSet.defaultExpectation == nil {
+		mmCartStatusSet.defaultExpectation = &StorerMockCartStatusSetExpectation{}
+	}
+
+	mmCartStatusSet.defaultExpectation.params = &StorerMockCartStatusSetParams{ctx, cartID, status}
+	for _, e := range mmCartStatusSet.expectations {
+		if minimock.Equal(e.params, mmCartStatusSet.defaultExpectation.params) {
+			mmCartStatusSet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartStatusSet.defaultExpectation.params)
+		}
+	}
+
+	return mmCartStatusSet
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartStatusSet
+func (mmCartStatusSet *mStorerMockCartStatusSet) Inspect(f func(ctx context.Context, cartID int64, status CartStatus)) *mStorerMockCartStatusSet {
+	if mmCartStatusSet.mock.inspectFuncCartStatusSet != nil {
+		mmCartStatusSet.mock.t.Fatalf("Inspect function is already set for StorerMock.CartStatusSet")
+	}
+
+	mmCartStatusSet.mock.inspectFuncCartStatusSet = f
+
+	return mmCartStatusSet
+}
+
+// Return sets up results that will be returned by storer.CartStatusSet
+func (mmCartStatusSet *mStorerMockCartStatusSet) Return(err error) *StorerMock {
+	if mmCartStatusSet.mock.funcCartStatusSet != nil {
+		mmCartStatusSet.mock.t.Fatalf("StorerMock.CartStatusSet mock is already set by Set")
+	}
+
+	if mmCartStatusSet.defaultExpectation == nil {
+		mmCartStatusSet.defaultExpectation = &StorerMockCartStatusSetExpectation{mock: mmCartStatusSet.mock}
+	}
+	mmCartStatusSet.defaultExpectation.results = &StorerMockCartStatusSetResults{err}
+	return mmCartStatusSet.mock
+}
+
+//Set uses given function f to mock the storer.CartStatusSet method
+func (mmCartStatusSet *mStorerMockCartStatusSet) Set(f func(ctx context.Context, cartID int64, status CartStatus) (err error)) *StorerMock {
+	if mmCartStatusSet.defaultExpectation != nil {
+		mmCartStatusSet.mock.t.Fatalf("Default expectation is already set for the storer.CartStatusSet method")
+	}
+
+	if len(mmCartStatusSet.expectations) > 0 {
+		mmCartStatusSet.mock.t.Fatalf("Some expectations are already set for the storer.CartStatusSet method")
+	}
+
+	mmCartStatusSet.mock.funcCartStatusSet = f
+	return mmCartStatusSet.mock
+}
+
+// When sets expectation for the storer.CartStatusSet which will trigger the result defined by the following
+// Then helper
+func (mmCartStatusSet *mStorerMockCartStatusSet) When(ctx context.Context, cartID int64, status CartStatus) *StorerMockCartStatusSetExpectation {
+	if mmCartStatusSet.mock.funcCartStatusSet != nil {
+		mmCartStatusSet.mock.t.Fatalf("StorerMock.CartStatusSet mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartStatusSetExpectation{
+		mock:   mmCartStatusSet.mock,
+		params: &StorerMockCartStatusSetParams{ctx, cartID, status},
+	}
+	mmCartStatusSet.expectations = append(mmCartStatusSet.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartStatusSet return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartStatusSetExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockCartStatusSetResults{err}
+	return e.mock
+}
+
+// CartStatusSet implements storer
+func (mmCartStatusSet *StorerMock) CartStatusSet(ctx context.Context, cartID int64, status CartStatus) (err error) {
+	mm_atomic.AddUint64(&mmCartStatusSet.beforeCartStatusSetCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartStatusSet.afterCartStatusSetCounter, 1)
+
+	if mmCartStatusSet.inspectFuncCartStatusSet != nil {
+		mmCartStatusSet.inspectFuncCartStatusSet(ctx, cartID, status)
+	}
+
+	mm_params := &StorerMockCartStatusSetParams{ctx, cartID, status}
+
+	// Record call args
+	mmCartStatusSet.CartStatusSetMock.mutex.Lock()
+	mmCartStatusSet.CartStatusSetMock.callArgs = append(mmCartStatusSet.CartStatusSetMock.callArgs, mm_params)
+	mmCartStatusSet.CartStatusSetMock.mutex.Unlock()
+
+	for _, e := range mmCartStatusSet.CartStatusSetMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmCartStatusSet.CartStatusSetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartStatusSet.CartStatusSetMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartStatusSet.CartStatusSetMock.defaultExpectation.params
+		mm_got := StorerMockCartStatusSetParams{ctx, cartID, status}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartStatusSet.t.Errorf("StorerMock.CartStatusSet got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartStatusSet.CartStatusSetMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartStatusSet.t.Fatal("No results are set for the StorerMock.CartStatusSet")
+		}
+		return (*mm_results).err
+	}
+	if mmCartStatusSet.funcCartStatusSet != nil {
+		return mmCartStatusSet.funcCartStatusSet(ctx, cartID, status)
+	}
+	mmCartStatusSet.t.Fatalf("Unexpected call to StorerMock.CartStatusSet. %v %v %v", ctx, cartID, status)
+	return
+}
+
+// CartStatusSetAfterCounter returns a count of finished StorerMock.CartStatusSet invocations
+func (mmCartStatusSet *StorerMock) CartStatusSetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartStatusSet.afterCartStatusSetCounter)
+}
+
+// CartStatusSetBeforeCounter returns a count of StorerMock.CartStatusSet invocations
+func (mmCartStatusSet *StorerMock) CartStatusSetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartStatusSet.beforeCartStatusSetCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartStatusSet.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartStatusSet *mStorerMockCartStatusSet) Calls() []*StorerMockCartStatusSetParams {
+	mmCartStatusSet.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartStatusSetParams, len(mmCartStatusSet.callArgs))
+	copy(argCopy, mmCartStatusSet.callArgs)
+
+	mmCartStatusSet.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartStatusSetDone returns true if the count of the CartStatusSet invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartStatusSetDone() bool {
+	for _, e := range m.CartStatusSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartStatusSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartStatusSetCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartStatusSet != nil && mm_atomic.LoadUint64(&m.afterCartStatusSetCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartStatusSetInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartStatusSetInspect() {
+	for _, e := range m.CartStatusSetMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartStatusSet with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartStatusSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartStatusSetCounter) < 1 {
+		if m.CartStatusSetMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartStatusSet")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartStatusSet with params: %#v", *m.CartStatusSetMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartStatusSet != nil && mm_atomic.LoadUint64(&m.afterCartStatusSetCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartStatusSet")
+	}
+}
+
+type mStorerMockCartWithItemsByCartID struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartWithItemsByCartIDExpectation
+	expectations       []*StorerMockCartWithItemsByCartIDExpectation
+
+	callArgs []*StorerMockCartWithItemsByCartIDParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartWithItemsByCartIDExpectation specifies expectation struct of the storer.CartWithItemsByCartID
+type StorerMockCartWithItemsByCartIDExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartWithItemsByCartIDParams
+	results *StorerMockCartWithItemsByCartIDResults
+	Counter uint64
+}
+
+// StorerMockCartWithItemsByCartIDParams contains parameters of the storer.CartWithItemsByCartID
+type StorerMockCartWithItemsByCartIDParams struct {
+	ctx    context.Context
+	cartID int64
+}
+
+// StorerMockCartWithItemsByCartIDResults contains results of the storer.CartWithItemsByCartID
+type StorerMockCartWithItemsByCartIDResults struct {
+	cp1 *Cart
+	err error
+}
+
+// Expect sets up expected params for storer.CartWithItemsByCartID
+func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Expect(ctx context.Context, cartID int64) *mStorerMockCartWithItemsByCartID {
+	if mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID != nil {
+		mmCartWithItemsByCartID.mock.t.Fatalf("StorerMock.CartWithItemsByCartID mock is already set by Set")
+	}
+
+	if mmCartWithItemsByCartID.defaultExpectation == nil {
+		mmCartWithItemsByCartID.defaultExpectation = &StorerMockCartWithItemsByCartIDExpectation{}
+	}
+
+	mmCartWithItemsByCartID.defaultExpectation.params = &StorerMockCartWithItemsByCartIDParams{ctx, cartID}
+	for _, e := range mmCartWithItemsByCartID.expectations {
+		if minimock.Equal(e.params, mmCartWithItemsByCartID.defaultExpectation.params) {
+			mmCartWithItemsByCartID.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartWithItemsByCartID.defaultExpectation.params)
+		}
+	}
+
+	return mmCartWithItemsByCartID
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartWithItemsByCartID
+func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Inspect(f func(ctx context.Context, cartID int64)) *mStorerMockCartWithItemsByCartID {
+	if mmCartWithItemsByCartID.mock.inspectFuncCartWithItemsByCartID != nil {
+		mmCartWithItemsByCartID.mock.t.Fatalf("Inspect function is already set for StorerMock.CartWithItemsByCartID")
+	}
+
+	mmCartWithItemsByCartID.mock.inspectFuncCartWithItemsByCartID = f
+
+	return mmCartWithItemsByCartID
+}
+
+// Return sets up results that will be returned by storer.CartWithItemsByCartID
+func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Return(cp1 *Cart, err error) *StorerMock {
+	if mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID != nil {
+		mmCartWithItemsByCartID.mock.t.Fatalf("StorerMock.CartWithItemsByCartID mock is already set by Set")
+	}
+
+	if mmCartWithItemsByCartID.defaultExpectation == nil {
+		mmCartWithItemsByCartID.defaultExpectation = &StorerMockCartWithItemsByCartIDExpectation{mock: mmCartWithItemsByCartID.mock}
+	}
+	mmCartWithItemsByCartID.defaultExpectation.results = &StorerMockCartWithItemsByCartIDResults{cp1, err}
+	return mmCartWithItemsByCartID.mock
+}
+
+//Set uses given function f to mock the storer.CartWithItemsByCartID method
+func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Set(f func(ctx context.Context, cartID int64) (cp1 *Cart, err error)) *StorerMock {
+	if mmCartWithItemsByCartID.defaultExpectation != nil {
+		mmCartWithItemsByCartID.mock.t.Fatalf("Default expectation is already set for the storer.CartWithItemsByCartID method")
+	}
+
+	if len(mmCartWithItemsByCartID.expectations) > 0 {
+		mmCartWithItemsByCartID.mock.t.Fatalf("Some expectations are already set for the storer.CartWithItemsByCartID method")
+	}
+
+	mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID = f
+	return mmCartWithItemsByCartID.mock
+}
+
+// When sets expectation for the storer.CartWithItemsByCartID which will trigger the result defined by the following
+// Then helper
+func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) When(ctx context.Context, cartID int64) *StorerMockCartWithItemsByCartIDExpectation {
+	if mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID != nil {
+		mmCartWithItemsByCartID.mock.t.Fatalf("StorerMock.CartWithItemsByCartID mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartWithItemsByCartIDExpectation{
+		mock:   mmCartWithItemsByCartID.mock,
+		params: &StorerMockCartWithItemsByCartIDParams{ctx, cartID},
+	}
+	mmCartWithItemsByCartID.expectations = append(mmCartWithItemsByCartID.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartWithItemsByCartID return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartWithItemsByCartIDExpectation) Then(cp1 *Cart, err error) *StorerMock {
+	e.results = &StorerMockCartWithItemsByCartIDResults{cp1, err}
+	return e.mock
+}
+
+// CartWithItemsByCartID implements storer
+func (mmCartWithItemsByCartID *StorerMock) CartWithItemsByCartID(ctx context.Context, cartID int64) (cp1 *Cart, err error) {
+	mm_atomic.AddUint64(&mmCartWithItemsByCartID.beforeCartWithItemsByCartIDCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartWithItemsByCartID.afterCartWithItemsByCartIDCounter, 1)
+
+	if mmCartWithItemsByCartID.inspectFuncCartWithItemsByCartID != nil {
+		mmCartWithItemsByCartID.inspectFuncCartWithItemsByCartID(ctx, cartID)
+	}
+
+	mm_params := &StorerMockCartWithItemsByCartIDParams{ctx, cartID}
+
+	// Record call args
+	mmCartWithItemsByCartID.CartWithItemsByCartIDMock.mutex.Lock()
+	mmCartWithItemsByCartID.CartWithItemsByCartIDMock.callArgs = append(mmCartWithItemsByCartID.CartWithItemsByCartIDMock.callArgs, mm_params)
+	mmCartWithItemsByCartID.CartWithItemsByCartIDMock.mutex.Unlock()
+
+	for _, e := range mmCartWithItemsByCartID.CartWithItemsByCartIDMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cp1, e.results.err
+		}
+	}
+
+	if mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation.params
+		mm_got := StorerMockCartWithItemsByCartIDParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartWithItemsByCartID.t.Errorf("StorerMock.CartWithItemsByCartID got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartWithItemsByCartID.t.Fatal("No results are set for the StorerMock.CartWithItemsByCartID")
+		}
+		return (*mm_results).cp1, (*mm_results).err
+	}
+	if mmCartWithItemsByCartID.funcCartWithItemsByCartID != nil {
+		return mmCartWithItemsByCartID.funcCartWithItemsByCartID(ctx, cartID)
+	}
+	mmCartWithItemsByCartID.t.Fatalf("Unexpected call to StorerMock.CartWithItemsByCartID. %v %v", ctx, cartID)
+	return
+}
+
+// CartWithItemsByCartIDAfterCounter returns a count of finished StorerMock.CartWithItemsByCartID invocations
+func (mmCartWithItemsByCartID *StorerMock) CartWithItemsByCartIDAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartWithItemsByCartID.afterCartWithItemsByCartIDCounter)
+}
+
+// CartWithItemsByCartIDBeforeCounter returns a count of StorerMock.CartWithItemsByCartID invocations
+func (mmCartWithItemsByCartID *StorerMock) CartWithItemsByCartIDBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartWithItemsByCartID.beforeCartWithItemsByCartIDCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartWithItemsByCartID.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Calls() []*StorerMockCartWithItemsByCartIDParams {
+	mmCartWithItemsByCartID.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartWithItemsByCartIDParams, len(mmCartWithItemsByCartID.callArgs))
+	copy(argCopy, mmCartWithItemsByCartID.callArgs)
+
+	mmCartWithItemsByCartID.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartWithItemsByCartIDDone returns true if the count of the CartWithItemsByCartID invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartWithItemsByCartIDDone() bool {
+	for _, e := range m.CartWithItemsByCartIDMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartWithItemsByCartIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartWithItemsByCartID != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartWithItemsByCartIDInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartWithItemsByCartIDInspect() {
+	for _, e := range m.CartWithItemsByCartIDMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartWithItemsByCartID with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartWithItemsByCartIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
+		if m.CartWithItemsByCartIDMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartWithItemsByCartID")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartWithItemsByCartID with params: %#v", *m.CartWithItemsByCartIDMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartWithItemsByCartID != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartWithItemsByCartID")
+	}
+}
+
+type mStorerMockCartsByUserID struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCartsByUserIDExpectation
+	expectations       []*StorerMockCartsByUserIDExpectation
+
+	callArgs []*StorerMockCartsByUserIDParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCartsByUserIDExpectation specifies expectation struct of the storer.CartsByUserID
+type StorerMockCartsByUserIDExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCartsByUserIDParams
+	results *StorerMockCartsByUserIDResults
+	Counter uint64
+}
+
+// StorerMockCartsByUserIDParams contains parameters of the storer.CartsByUserID
+type StorerMockCartsByUserIDParams struct {
+	ctx    context.Context
+	userID int64
+	q      CartsQuery
+}
+
+// StorerMockCartsByUserIDResults contains results of the storer.CartsByUserID
+type StorerMockCartsByUserIDResults struct {
+	cpa1 []*Cart
+	err  error
+}
+
+// Expect sets up expected params for storer.CartsByUserID
+func (mmCartsByUserID *mStorerMockCartsByUserID) Expect(ctx context.Context, userID int64, q CartsQuery) *mStorerMockCartsByUserID {
+	if mmCartsByUserID.mock.funcCartsByUserID != nil {
+		mmCartsByUserID.mock.t.Fatalf("StorerMock.CartsByUserID mock is already set by Set")
+	}
+
+	if mmCartsByUserID.defaultExpectation == nil {
+		mmCartsByUserID.defaultExpectation = &StorerMockCartsByUserIDExpectation{}
+	}
+
+	mmCartsByUserID.defaultExpectation.params = &StorerMockCartsByUserIDParams{ctx, userID, q}
+	for _, e := range mmCartsByUserID.expectations {
+		if minimock.Equal(e.params, mmCartsByUserID.defaultExpectation.params) {
+			mmCartsByUserID.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartsByUserID.defaultExpectation.params)
+		}
+	}
+
+	return mmCartsByUserID
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CartsByUserID
+func (mmCartsByUserID *mStorerMockCartsByUserID) Inspect(f func(ctx context.Context, userID int64, q CartsQuery)) *mStorerMockCartsByUserID {
+	if mmCartsByUserID.mock.inspectFuncCartsByUserID != nil {
+		mmCartsByUserID.mock.t.Fatalf("Inspect function is already set for StorerMock.CartsByUserID")
+	}
+
+	mmCartsByUserID.mock.inspectFuncCartsByUserID = f
+
+	return mmCartsByUserID
+}
+
+// Return sets up results that will be returned by storer.CartsByUserID
+func (mmCartsByUserID *mStorerMockCartsByUserID) Return(cpa1 []*Cart, err error) *StorerMock {
+	if mmCartsByUserID.mock.funcCartsByUserID != nil {
+		mmCartsByUserID.mock.t.Fatalf("StorerMock.CartsByUserID mock is already set by Set")
+	}
+
+	if mmCartsByUserID.defaultExpectation == nil {
+		mmCartsByUserID.defaultExpectation = &StorerMockCartsByUserIDExpectation{mock: mmCartsByUserID.mock}
+	}
+	mmCartsByUserID.defaultExpectation.results = &StorerMockCartsByUserIDResults{cpa1, err}
+	return mmCartsByUserID.mock
+}
+
+//Set uses given function f to mock the storer.CartsByUserID method
+func (mmCartsByUserID *mStorerMockCartsByUserID) Set(f func(ctx context.Context, userID int64, q CartsQuery) (cpa1 []*Cart, err error)) *StorerMock {
+	if mmCartsByUserID.defaultExpectation != nil {
+		mmCartsByUserID.mock.t.Fatalf("Default expectation is already set for the storer.CartsByUserID method")
+	}
+
+	if len(mmCartsByUserID.expectations) > 0 {
+		mmCartsByUserID.mock.t.Fatalf("Some expectations are already set for the storer.CartsByUserID method")
+	}
+
+	mmCartsByUserID.mock.funcCartsByUserID = f
+	return mmCartsByUserID.mock
+}
+
+// When sets expectation for the storer.CartsByUserID which will trigger the result defined by the following
+// Then helper
+func (mmCartsByUserID *mStorerMockCartsByUserID) When(ctx context.Context, userID int64, q CartsQuery) *StorerMockCartsByUserIDExpectation {
+	if mmCartsByUserID.mock.funcCartsByUserID != nil {
+		mmCartsByUserID.mock.t.Fatalf("StorerMock.CartsByUserID mock is already set by Set")
+	}
+
+	expectation := &StorerMockCartsByUserIDExpectation{
+		mock:   mmCartsByUserID.mock,
+		params: &StorerMockCartsByUserIDParams{ctx, userID, q},
+	}
+	mmCartsByUserID.expectations = append(mmCartsByUserID.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CartsByUserID return parameters for the expectation previously defined by the When method
+func (e *StorerMockCartsByUserIDExpectation) Then(cpa1 []*Cart, err error) *StorerMock {
+	e.results = &StorerMockCartsByUserIDResults{cpa1, err}
+	return e.mock
+}
+
+// CartsByUserID implements storer
+func (mmCartsByUserID *StorerMock) CartsByUserID(ctx context.Context, userID int64, q CartsQuery) (cpa1 []*Cart, err error) {
+	mm_atomic.AddUint64(&mmCartsByUserID.beforeCartsByUserIDCounter, 1)
+	defer mm_atomic.AddUint64(&mmCartsByUserID.afterCartsByUserIDCounter, 1)
+
+	if mmCartsByUserID.inspectFuncCartsByUserID != nil {
+		mmCartsByUserID.inspectFuncCartsByUserID(ctx, userID, q)
+	}
+
+	mm_params := &StorerMockCartsByUserIDParams{ctx, userID, q}
+
+	// Record call args
+	mmCartsByUserID.CartsByUserIDMock.mutex.Lock()
+	mmCartsByUserID.CartsByUserIDMock.callArgs = append(mmCartsByUserID.CartsByUserIDMock.callArgs, mm_params)
+	mmCartsByUserID.CartsByUserIDMock.mutex.Unlock()
+
+	for _, e := range mmCartsByUserID.CartsByUserIDMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cpa1, e.results.err
+		}
+	}
+
+	if mmCartsByUserID.CartsByUserIDMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCartsByUserID.CartsByUserIDMock.defaultExpectation.Counter, 1)
+		mm_want := mmCartsByUserID.CartsByUserIDMock.defaultExpectation.params
+		mm_got := StorerMockCartsByUserIDParams{ctx, userID, q}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCartsByUserID.t.Errorf("StorerMock.CartsByUserID got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCartsByUserID.CartsByUserIDMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCartsByUserID.t.Fatal("No results are set for the StorerMock.CartsByUserID")
+		}
+		return (*mm_results).cpa1, (*mm_results).err
+	}
+	if mmCartsByUserID.funcCartsByUserID != nil {
+		return mmCartsByUserID.funcCartsByUserID(ctx, userID, q)
+	}
+	mmCartsByUserID.t.Fatalf("Unexpected call to StorerMock.CartsByUserID. %v %v %v", ctx, userID, q)
+	return
+}
+
+// CartsByUserIDAfterCounter returns a count of finished StorerMock.CartsByUserID invocations
+func (mmCartsByUserID *StorerMock) CartsByUserIDAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartsByUserID.afterCartsByUserIDCounter)
+}
+
+// CartsByUserIDBeforeCounter returns a count of StorerMock.CartsByUserID invocations
+func (mmCartsByUserID *StorerMock) CartsByUserIDBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCartsByUserID.beforeCartsByUserIDCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CartsByUserID.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCartsByUserID *mStorerMockCartsByUserID) Calls() []*StorerMockCartsByUserIDParams {
+	mmCartsByUserID.mutex.RLock()
+
+	argCopy := make([]*StorerMockCartsByUserIDParams, len(mmCartsByUserID.callArgs))
+	copy(argCopy, mmCartsByUserID.callArgs)
+
+	mmCartsByUserID.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCartsByUserIDDone returns true if the count of the CartsByUserID invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCartsByUserIDDone() bool {
+	for _, e := range m.CartsByUserIDMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartsByUserIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartsByUserIDCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartsByUserID != nil && mm_atomic.LoadUint64(&m.afterCartsByUserIDCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCartsByUserIDInspect logs each unmet expectation
+func (m *StorerMock) MinimockCartsByUserIDInspect() {
+	for _, e := range m.CartsByUserIDMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CartsByUserID with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CartsByUserIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartsByUserIDCounter) < 1 {
+		if m.CartsByUserIDMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CartsByUserID")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CartsByUserID with params: %#v", *m.CartsByUserIDMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCartsByUserID != nil && mm_atomic.LoadUint64(&m.afterCartsByUserIDCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CartsByUserID")
+	}
+}
+
+type mStorerMockCommit struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCommitExpectation
+	expectations       []*StorerMockCommitExpectation
+}
+
+// StorerMockCommitExpectation specifies expectation struct of the storer.Commit
+type StorerMockCommitExpectation struct {
+	mock *StorerMock
+
+	results *StorerMockCommitResults
+	Counter uint64
+}
+
+// StorerMockCommitResults contains results of the storer.Commit
+type StorerMockCommitResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.Commit
+func (mmCommit *mStorerMockCommit) Expect() *mStorerMockCommit {
+	if mmCommit.mock.funcCommit != nil {
+		mmCommit.mock.t.Fatalf("StorerMock.Commit mock is already set by Set")
+	}
+
+	if mmCommit.defaultExpectation == nil {
+		mmCommit.defaultExpectation = &StorerMockCommitExpectation{}
+	}
+
+	return mmCommit
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.Commit
+func (mmCommit *mStorerMockCommit) Inspect(f func()) *mStorerMockCommit {
+	if mmCommit.mock.inspectFuncCommit != nil {
+		mmCommit.mock.t.Fatalf("Inspect function is already set for StorerMock.Commit")
+	}
+
+	mmCommit.mock.inspectFuncCommit = f
+
+	return mmCommit
+}
+
+// Return sets up results that will be returned by storer.Commit
+func (mmCommit *mStorerMockCommit) Return(err error) *StorerMock {
+	if mmCommit.mock.funcCommit != nil {
+		mmCommit.mock.t.Fatalf("StorerMock.Commit mock is already set by Set")
+	}
+
+	if mmCommit.defaultExpectation == nil {
+		mmCommit.defaultExpectation = &StorerMockCommitExpectation{mock: mmCommit.mock}
+	}
+	mmCommit.defaultExpectation.results = &StorerMockCommitResults{err}
+	return mmCommit.mock
+}
+
+//Set uses given function f to mock the storer.Commit method
+func (mmCommit *mStorerMockCommit) Set(f func() (err error)) *StorerMock {
+	if mmCommit.defaultExpectation != nil {
+		mmCommit.mock.t.Fatalf("Default expectation is already set for the storer.Commit method")
+	}
+
+	if len(mmCommit.expectations) > 0 {
+		mmCommit.mock.t.Fatalf("Some expectations are already set for the storer.Commit method")
+	}
+
+	mmCommit.mock.funcCommit = f
+	return mmCommit.mock
+}
+
+// Commit implements storer
+func (mmCommit *StorerMock) Commit() (err error) {
+	mm_atomic.AddUint64(&mmCommit.beforeCommitCounter, 1)
+	defer mm_atomic.AddUint64(&mmCommit.afterCommitCounter, 1)
+
+	if mmCommit.inspectFuncCommit != nil {
+		mmCommit.inspectFuncCommit()
+	}
+
+	if mmCommit.CommitMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCommit.CommitMock.defaultExpectation.Counter, 1)
+
+		mm_results := mmCommit.CommitMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCommit.t.Fatal("No results are set for the StorerMock.Commit")
+		}
+		return (*mm_results).err
+	}
+	if mmCommit.funcCommit != nil {
+		return mmCommit.funcCommit()
+	}
+	mmCommit.t.Fatalf("Unexpected call to StorerMock.Commit.")
+	return
+}
+
+// CommitAfterCounter returns a count of finished StorerMock.Commit invocations
+func (mmCommit *StorerMock) CommitAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCommit.afterCommitCounter)
+}
+
+// CommitBeforeCounter returns a count of StorerMock.Commit invocations
+func (mmCommit *StorerMock) CommitBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCommit.beforeCommitCounter)
+}
+
+// MinimockCommitDone returns true if the count of the Commit invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCommitDone() bool {
+	for _, e := range m.CommitMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CommitMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCommit != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCommitInspect logs each unmet expectation
+func (m *StorerMock) MinimockCommitInspect() {
+	for _, e := range m.CommitMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Error("Expected call to StorerMock.Commit")
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CommitMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.Commit")
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCommit != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.Commit")
+	}
+}
+
+type mStorerMockCouponUses struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockCouponUsesExpectation
+	expectations       []*StorerMockCouponUsesExpectation
+
+	callArgs []*StorerMockCouponUsesParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockCouponUsesExpectation specifies expectation struct of the storer.CouponUses
+type StorerMockCouponUsesExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockCouponUsesParams
+	results *StorerMockCouponUsesResults
+	Counter uint64
+}
+
+// StorerMockCouponUsesParams contains parameters of the storer.CouponUses
+type StorerMockCouponUsesParams struct {
+	ctx    context.Context
+	userID int64
+	code   string
+}
+
+// StorerMockCouponUsesResults contains results of the storer.CouponUses
+type StorerMockCouponUsesResults struct {
+	i1  int
+	err error
+}
+
+// Expect sets up expected params for storer.CouponUses
+func (mmCouponUses *mStorerMockCouponUses) Expect(ctx context.Context, userID int64, code string) *mStorerMockCouponUses {
+	if mmCouponUses.mock.funcCouponUses != nil {
+		mmCouponUses.mock.t.Fatalf("StorerMock.CouponUses mock is already set by Set")
+	}
+
+	if mmCouponUses.defaultExpectation == nil {
+		mmCouponUses.defaultExpectation = &StorerMockCouponUsesExpectation{}
+	}
+
+	mmCouponUses.defaultExpectation.params = &StorerMockCouponUsesParams{ctx, userID, code}
+	for _, e := range mmCouponUses.expectations {
+		if minimock.Equal(e.params, mmCouponUses.defaultExpectation.params) {
+			mmCouponUses.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCouponUses.defaultExpectation.params)
+		}
+	}
+
+	return mmCouponUses
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.CouponUses
+func (mmCouponUses *mStorerMockCouponUses) Inspect(f func(ctx context.Context, userID int64, code string)) *mStorerMockCouponUses {
+	if mmCouponUses.mock.inspectFuncCouponUses != nil {
+		mmCouponUses.mock.t.Fatalf("Inspect function is already set for StorerMock.CouponUses")
+	}
+
+	mmCouponUses.mock.inspectFuncCouponUses = f
+
+	return mmCouponUses
+}
+
+// Return sets up results that will be returned by storer.CouponUses
+func (mmCouponUses *mStorerMockCouponUses) Return(i1 int, err error) *StorerMock {
+	if mmCouponUses.mock.funcCouponUses != nil {
+		mmCouponUses.mock.t.Fatalf("StorerMock.CouponUses mock is already set by Set")
+	}
+
+	if mmCouponUses.defaultExpectation == nil {
+		mmCouponUses.defaultExpectation = &StorerMockCouponUsesExpectation{mock: mmCouponUses.mock}
+	}
+	mmCouponUses.defaultExpectation.results = &StorerMockCouponUsesResults{i1, err}
+	return mmCouponUses.mock
+}
+
+//Set uses given function f to mock the storer.CouponUses method
+func (mmCouponUses *mStorerMockCouponUses) Set(f func(ctx context.Context, userID int64, code string) (i1 int, err error)) *StorerMock {
+	if mmCouponUses.defaultExpectation != nil {
+		mmCouponUses.mock.t.Fatalf("Default expectation is already set for the storer.CouponUses method")
+	}
+
+	if len(mmCouponUses.expectations) > 0 {
+		mmCouponUses.mock.t.Fatalf("Some expectations are already set for the storer.CouponUses method")
+	}
+
+	mmCouponUses.mock.funcCouponUses = f
+	return mmCouponUses.mock
+}
+
+// When sets expectation for the storer.CouponUses which will trigger the result defined by the following
+// Then helper
+func (mmCouponUses *mStorerMockCouponUses) When(ctx context.Context, userID int64, code string) *StorerMockCouponUsesExpectation {
+	if mmCouponUses.mock.funcCouponUses != nil {
+		mmCouponUses.mock.t.Fatalf("StorerMock.CouponUses mock is already set by Set")
+	}
+
+	expectation := &StorerMockCouponUsesExpectation{
+		mock:   mmCouponUses.mock,
+		params: &StorerMockCouponUsesParams{ctx, userID, code},
+	}
+	mmCouponUses.expectations = append(mmCouponUses.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.CouponUses return parameters for the expectation previously defined by the When method
+func (e *StorerMockCouponUsesExpectation) Then(i1 int, err error) *StorerMock {
+	e.results = &StorerMockCouponUsesResults{i1, err}
+	return e.mock
+}
+
+// CouponUses implements storer
+func (mmCouponUses *StorerMock) CouponUses(ctx context.Context, userID int64, code string) (i1 int, err error) {
+	mm_atomic.AddUint64(&mmCouponUses.beforeCouponUsesCounter, 1)
+	defer mm_atomic.AddUint64(&mmCouponUses.afterCouponUsesCounter, 1)
+
+	if mmCouponUses.inspectFuncCouponUses != nil {
+		mmCouponUses.inspectFuncCouponUses(ctx, userID, code)
+	}
+
+	mm_params := &StorerMockCouponUsesParams{ctx, userID, code}
+
+	// Record call args
+	mmCouponUses.CouponUsesMock.mutex.Lock()
+	mmCouponUses.CouponUsesMock.callArgs = append(mmCouponUses.CouponUsesMock.callArgs, mm_params)
+	mmCouponUses.CouponUsesMock.mutex.Unlock()
+
+	for _, e := range mmCouponUses.CouponUsesMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.i1, e.results.err
+		}
+	}
+
+	if mmCouponUses.CouponUsesMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmCouponUses.CouponUsesMock.defaultExpectation.Counter, 1)
+		mm_want := mmCouponUses.CouponUsesMock.defaultExpectation.params
+		mm_got := StorerMockCouponUsesParams{ctx, userID, code}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmCouponUses.t.Errorf("StorerMock.CouponUses got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmCouponUses.CouponUsesMock.defaultExpectation.results
+		if mm_results == nil {
+			mmCouponUses.t.Fatal("No results are set for the StorerMock.CouponUses")
+		}
+		return (*mm_results).i1, (*mm_results).err
+	}
+	if mmCouponUses.funcCouponUses != nil {
+		return mmCouponUses.funcCouponUses(ctx, userID, code)
+	}
+	mmCouponUses.t.Fatalf("Unexpected call to StorerMock.CouponUses. %v %v %v", ctx, userID, code)
+	return
+}
+
+// CouponUsesAfterCounter returns a count of finished StorerMock.CouponUses invocations
+func (mmCouponUses *StorerMock) CouponUsesAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCouponUses.afterCouponUsesCounter)
+}
+
+// CouponUsesBeforeCounter returns a count of StorerMock.CouponUses invocations
+func (mmCouponUses *StorerMock) CouponUsesBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmCouponUses.beforeCouponUsesCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.CouponUses.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmCouponUses *mStorerMockCouponUses) Calls() []*StorerMockCouponUsesParams {
+	mmCouponUses.mutex.RLock()
+
+	argCopy := make([]*StorerMockCouponUsesParams, len(mmCouponUses.callArgs))
+	copy(argCopy, mmCouponUses.callArgs)
+
+	mmCouponUses.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockCouponUsesDone returns true if the count of the CouponUses invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockCouponUsesDone() bool {
+	for _, e := range m.CouponUsesMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CouponUsesMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCouponUsesCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCouponUses != nil && mm_atomic.LoadUint64(&m.afterCouponUsesCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockCouponUsesInspect logs each unmet expectation
+func (m *StorerMock) MinimockCouponUsesInspect() {
+	for _, e := range m.CouponUsesMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.CouponUses with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.CouponUsesMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCouponUsesCounter) < 1 {
+		if m.CouponUsesMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.CouponUses")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.CouponUses with params: %#v", *m.CouponUsesMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcCouponUses != nil && mm_atomic.LoadUint64(&m.afterCouponUsesCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.CouponUses")
+	}
+}
+
+type mStorerMockIdempotencyKeyByKey struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockIdempotencyKeyByKeyExpectation
+	expectations       []*StorerMockIdempotencyKeyByKeyExpectation
+
+	callArgs []*StorerMockIdempotencyKeyByKeyParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockIdempotencyKeyByKeyExpectation specifies expectation struct of the storer.IdempotencyKeyByKey
+type StorerMockIdempotencyKeyByKeyExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockIdempotencyKeyByKeyParams
+	results *StorerMockIdempotencyKeyByKeyResults
+	Counter uint64
+}
+
+// StorerMockIdempotencyKeyByKeyParams contains parameters of the storer.IdempotencyKeyByKey
+type StorerMockIdempotencyKeyByKeyParams struct {
+	ctx    context.Context
+	userID int64
+	key    string
+}
+
+// StorerMockIdempotencyKeyByKeyResults contains results of the storer.IdempotencyKeyByKey
+type StorerMockIdempotencyKeyByKeyResults struct {
+	ip1 *IdempotencyKey
+	err error
+}
+
+// Expect sets up expected params for storer.IdempotencyKeyByKey
+func (mmIdempotencyKeyByKey *mStorerMockIdempotencyKeyByKey) Expect(ctx context.Context, userID int64, key string) *mStorerMockIdempotencyKeyByKey {
+	if mmIdempotencyKeyByKey.mock.funcIdempotencyKeyByKey != nil {
+		mmIdempotencyKeyByKey.mock.t.Fatalf("StorerMock.IdempotencyKeyByKey mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyByKey.defaultExpectation == nil {
+		mmIdempotencyKeyByKey.defaultExpectation = &StorerMockIdempotencyKeyByKeyExpectation{}
+	}
+
+	mmIdempotencyKeyByKey.defaultExpectation.params = &StorerMockIdempotencyKeyByKeyParams{ctx, userID, key}
+	for _, e := range mmIdempotencyKeyByKey.expectations {
+		if minimock.Equal(e.params, mmIdempotencyKeyByKey.defaultExpectation.params) {
+			mmIdempotencyKeyByKey.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIdempotencyKeyByKey.defaultExpectation.params)
+		}
+	}
+
+	return mmIdempotencyKeyByKey
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.IdempotencyKeyByKey
+func (mmIdempotencyKeyByKey *mStorerMockIdempotencyKeyByKey) Inspect(f func(ctx context.Context, userID int64, key string)) *mStorerMockIdempotencyKeyByKey {
+	if mmIdempotencyKeyByKey.mock.inspectFuncIdempotencyKeyByKey != nil {
+		mmIdempotencyKeyByKey.mock.t.Fatalf("Inspect function is already set for StorerMock.IdempotencyKeyByKey")
+	}
+
+	mmIdempotencyKeyByKey.mock.inspectFuncIdempotencyKeyByKey = f
+
+	return mmIdempotencyKeyByKey
+}
+
+// Return sets up results that will be returned by storer.IdempotencyKeyByKey
+func (mmIdempotencyKeyByKey *mStorerMockIdempotencyKeyByKey) Return(ip1 *IdempotencyKey, err error) *StorerMock {
+	if mmIdempotencyKeyByKey.mock.funcIdempotencyKeyByKey != nil {
+		mmIdempotencyKeyByKey.mock.t.Fatalf("StorerMock.IdempotencyKeyByKey mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyByKey.defaultExpectation == nil {
+		mmIdempotencyKeyByKey.defaultExpectation = &StorerMockIdempotencyKeyByKeyExpectation{mock: mmIdempotencyKeyByKey.mock}
+	}
+	mmIdempotencyKeyByKey.defaultExpectation.results = &StorerMockIdempotencyKeyByKeyResults{ip1, err}
+	return mmIdempotencyKeyByKey.mock
+}
+
+//Set uses given function f to mock the storer.IdempotencyKeyByKey method
+func (mmIdempotencyKeyByKey *mStorerMockIdempotencyKeyByKey) Set(f func(ctx context.Context, userID int64, key string) (ip1 *IdempotencyKey, err error)) *StorerMock {
+	if mmIdempotencyKeyByKey.defaultExpectation != nil {
+		mmIdempotencyKeyByKey.mock.t.Fatalf("Default expectation is already set for the storer.IdempotencyKeyByKey method")
+	}
+
+	if len(mmIdempotencyKeyByKey.expectations) > 0 {
+		mmIdempotencyKeyByKey.mock.t.Fatalf("Some expectations are already set for the storer.IdempotencyKeyByKey method")
+	}
+
+	mmIdempotencyKeyByKey.mock.funcIdempotencyKeyByKey = f
+	return mmIdempotencyKeyByKey.mock
+}
+
+// When sets expectation for the storer.IdempotencyKeyByKey which will trigger the result defined by the following
+// Then helper
+func (mmIdempotencyKeyByKey *mStorerMockIdempotencyKeyByKey) When(ctx context.Context, userID int64, key string) *StorerMockIdempotencyKeyByKeyExpectation {
+	if mmIdempotencyKeyByKey.mock.funcIdempotencyKeyByKey != nil {
+		mmIdempotencyKeyByKey.mock.t.Fatalf("StorerMock.IdempotencyKeyByKey mock is already set by Set")
+	}
+
+	expectation := &StorerMockIdempotencyKeyByKeyExpectation{
+		mock:   mmIdempotencyKeyByKey.mock,
+		params: &StorerMockIdempotencyKeyByKeyParams{ctx, userID, key},
+	}
+	mmIdempotencyKeyByKey.expectations = append(mmIdempotencyKeyByKey.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.IdempotencyKeyByKey return parameters for the expectation previously defined by the When method
+func (e *StorerMockIdempotencyKeyByKeyExpectation) Then(ip1 *IdempotencyKey, err error) *StorerMock {
+	e.results = &StorerMockIdempotencyKeyByKeyResults{ip1, err}
+	return e.mock
+}
+
+// IdempotencyKeyByKey implements storer
+func (mmIdempotencyKeyByKey *StorerMock) IdempotencyKeyByKey(ctx context.Context, userID int64, key string) (ip1 *IdempotencyKey, err error) {
+	mm_atomic.AddUint64(&mmIdempotencyKeyByKey.beforeIdempotencyKeyByKeyCounter, 1)
+	defer mm_atomic.AddUint64(&mmIdempotencyKeyByKey.afterIdempotencyKeyByKeyCounter, 1)
+
+	if mmIdempotencyKeyByKey.inspectFuncIdempotencyKeyByKey != nil {
+		mmIdempotencyKeyByKey.inspectFuncIdempotencyKeyByKey(ctx, userID, key)
+	}
+
+	mm_params := &StorerMockIdempotencyKeyByKeyParams{ctx, userID, key}
+
+	// Record call args
+	mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.mutex.Lock()
+	mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.callArgs = append(mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.callArgs, mm_params)
+	mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.mutex.Unlock()
+
+	for _, e := range mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.ip1, e.results.err
+		}
+	}
+
+	if mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.defaultExpectation.Counter, 1)
+		mm_want := mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.defaultExpectation.params
+		mm_got := StorerMockIdempotencyKeyByKeyParams{ctx, userID, key}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmIdempotencyKeyByKey.t.Errorf("StorerMock.IdempotencyKeyByKey got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmIdempotencyKeyByKey.IdempotencyKeyByKeyMock.defaultExpectation.results
+		if mm_results == nil {
+			mmIdempotencyKeyByKey.t.Fatal("No results are set for the StorerMock.IdempotencyKeyByKey")
+		}
+		return (*mm_results).ip1, (*mm_results).err
+	}
+	if mmIdempotencyKeyByKey.funcIdempotencyKeyByKey != nil {
+		return mmIdempotencyKeyByKey.funcIdempotencyKeyByKey(ctx, userID, key)
+	}
+	mmIdempotencyKeyByKey.t.Fatalf("Unexpected call to StorerMock.IdempotencyKeyByKey. %v %v %v", ctx, userID, key)
+	return
+}
+
+// IdempotencyKeyByKeyAfterCounter returns a count of finished StorerMock.IdempotencyKeyByKey invocations
+func (mmIdempotencyKeyByKey *StorerMock) IdempotencyKeyByKeyAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyByKey.afterIdempotencyKeyByKeyCounter)
+}
+
+// IdempotencyKeyByKeyBeforeCounter returns a count of StorerMock.IdempotencyKeyByKey invocations
+func (mmIdempotencyKeyByKey *StorerMock) IdempotencyKeyByKeyBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyByKey.beforeIdempotencyKeyByKeyCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.IdempotencyKeyByKey.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmIdempotencyKeyByKey *mStorerMockIdempotencyKeyByKey) Calls() []*StorerMockIdempotencyKeyByKeyParams {
+	mmIdempotencyKeyByKey.mutex.RLock()
+
+	argCopy := make([]*StorerMockIdempotencyKeyByKeyParams, len(mmIdempotencyKeyByKey.callArgs))
+	copy(argCopy, mmIdempotencyKeyByKey.callArgs)
+
+	mmIdempotencyKeyByKey.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockIdempotencyKeyByKeyDone returns true if the count of the IdempotencyKeyByKey invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockIdempotencyKeyByKeyDone() bool {
+	for _, e := range m.IdempotencyKeyByKeyMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyByKeyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyByKeyCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyByKey != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyByKeyCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockIdempotencyKeyByKeyInspect logs each unmet expectation
+func (m *StorerMock) MinimockIdempotencyKeyByKeyInspect() {
+	for _, e := range m.IdempotencyKeyByKeyMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyByKey with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyByKeyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyByKeyCounter) < 1 {
+		if m.IdempotencyKeyByKeyMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.IdempotencyKeyByKey")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyByKey with params: %#v", *m.IdempotencyKeyByKeyMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyByKey != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyByKeyCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.IdempotencyKeyByKey")
+	}
+}
+
+type mStorerMockIdempotencyKeyComplete struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockIdempotencyKeyCompleteExpectation
+	expectations       []*StorerMockIdempotencyKeyCompleteExpectation
+
+	callArgs []*StorerMockIdempotencyKeyCompleteParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockIdempotencyKeyCompleteExpectation specifies expectation struct of the storer.IdempotencyKeyComplete
+type StorerMockIdempotencyKeyCompleteExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockIdempotencyKeyCompleteParams
+	results *StorerMockIdempotencyKeyCompleteResults
+	Counter uint64
+}
+
+// StorerMockIdempotencyKeyCompleteParams contains parameters of the storer.IdempotencyKeyComplete
+type StorerMockIdempotencyKeyCompleteParams struct {
+	ctx context.Context
+	key *IdempotencyKey
+}
+
+// StorerMockIdempotencyKeyCompleteResults contains results of the storer.IdempotencyKeyComplete
+type StorerMockIdempotencyKeyCompleteResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.IdempotencyKeyComplete
+func (mmIdempotencyKeyComplete *mStorerMockIdempotencyKeyComplete) Expect(ctx context.Context, key *IdempotencyKey) *mStorerMockIdempotencyKeyComplete {
+	if mmIdempotencyKeyComplete.mock.funcIdempotencyKeyComplete != nil {
+		mmIdempotencyKeyComplete.mock.t.Fatalf("StorerMock.IdempotencyKeyComplete mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyComplete.defaultExpectation == nil {
+		mmIdempotencyKeyComplete.defaultExpectation = &StorerMockIdempotencyKeyCompleteExpectation{}
+	}
+
+	mmIdempotencyKeyComplete.defaultExpectation.params = &StorerMockIdempotencyKeyCompleteParams{ctx, key}
+	for _, e := range mmIdempotencyKeyComplete.expectations {
+		if minimock.Equal(e.params, mmIdempotencyKeyComplete.defaultExpectation.params) {
+			mmIdempotencyKeyComplete.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIdempotencyKeyComplete.defaultExpectation.params)
+		}
+	}
+
+	return mmIdempotencyKeyComplete
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.IdempotencyKeyComplete
+func (mmIdempotencyKeyComplete *mStorerMockIdempotencyKeyComplete) Inspect(f func(ctx context.Context, key *IdempotencyKey)) *mStorerMockIdempotencyKeyComplete {
+	if mmIdempotencyKeyComplete.mock.inspectFuncIdempotencyKeyComplete != nil {
+		mmIdempotencyKeyComplete.mock.t.Fatalf("Inspect function is already set for StorerMock.IdempotencyKeyComplete")
+	}
+
+	mmIdempotencyKeyComplete.mock.inspectFuncIdempotencyKeyComplete = f
+
+	return mmIdempotencyKeyComplete
+}
+
+// Return sets up results that will be returned by storer.IdempotencyKeyComplete
+func (mmIdempotencyKeyComplete *mStorerMockIdempotencyKeyComplete) Return(err error) *StorerMock {
+	if mmIdempotencyKeyComplete.mock.funcIdempotencyKeyComplete != nil {
+		mmIdempotencyKeyComplete.mock.t.Fatalf("StorerMock.IdempotencyKeyComplete mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyComplete.defaultExpectation == nil {
+		mmIdempotencyKeyComplete.defaultExpectation = &StorerMockIdempotencyKeyCompleteExpectation{mock: mmIdempotencyKeyComplete.mock}
+	}
+	mmIdempotencyKeyComplete.defaultExpectation.results = &StorerMockIdempotencyKeyCompleteResults{err}
+	return mmIdempotencyKeyComplete.mock
+}
+
+//Set uses given function f to mock the storer.IdempotencyKeyComplete method
+func (mmIdempotencyKeyComplete *mStorerMockIdempotencyKeyComplete) Set(f func(ctx context.Context, key *IdempotencyKey) (err error)) *StorerMock {
+	if mmIdempotencyKeyComplete.defaultExpectation != nil {
+		mmIdempotencyKeyComplete.mock.t.Fatalf("Default expectation is already set for the storer.IdempotencyKeyComplete method")
+	}
+
+	if len(mmIdempotencyKeyComplete.expectations) > 0 {
+		mmIdempotencyKeyComplete.mock.t.Fatalf("Some expectations are already set for the storer.IdempotencyKeyComplete method")
+	}
+
+	mmIdempotencyKeyComplete.mock.funcIdempotencyKeyComplete = f
+	return mmIdempotencyKeyComplete.mock
+}
+
+// When sets expectation for the storer.IdempotencyKeyComplete which will trigger the result defined by the following
+// Then helper
+func (mmIdempotencyKeyComplete *mStorerMockIdempotencyKeyComplete) When(ctx context.Context, key *IdempotencyKey) *StorerMockIdempotencyKeyCompleteExpectation {
+	if mmIdempotencyKeyComplete.mock.funcIdempotencyKeyComplete != nil {
+		mmIdempotencyKeyComplete.mock.t.Fatalf("StorerMock.IdempotencyKeyComplete mock is already set by Set")
+	}
+
+	expectation := &StorerMockIdempotencyKeyCompleteExpectation{
+		mock:   mmIdempotencyKeyComplete.mock,
+		params: &StorerMockIdempotencyKeyCompleteParams{ctx, key},
+	}
+	mmIdempotencyKeyComplete.expectations = append(mmIdempotencyKeyComplete.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.IdempotencyKeyComplete return parameters for the expectation previously defined by the When method
+func (e *StorerMockIdempotencyKeyCompleteExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockIdempotencyKeyCompleteResults{err}
+	return e.mock
+}
+
+// IdempotencyKeyComplete implements storer
+func (mmIdempotencyKeyComplete *StorerMock) IdempotencyKeyComplete(ctx context.Context, key *IdempotencyKey) (err error) {
+	mm_atomic.AddUint64(&mmIdempotencyKeyComplete.beforeIdempotencyKeyCompleteCounter, 1)
+	defer mm_atomic.AddUint64(&mmIdempotencyKeyComplete.afterIdempotencyKeyCompleteCounter, 1)
+
+	if mmIdempotencyKeyComplete.inspectFuncIdempotencyKeyComplete != nil {
+		mmIdempotencyKeyComplete.inspectFuncIdempotencyKeyComplete(ctx, key)
+	}
+
+	mm_params := &StorerMockIdempotencyKeyCompleteParams{ctx, key}
+
+	// Record call args
+	mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.mutex.Lock()
+	mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.callArgs = append(mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.callArgs, mm_params)
+	mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.mutex.Unlock()
+
+	for _, e := range mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.defaultExpectation.Counter, 1)
+		mm_want := mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.defaultExpectation.params
+		mm_got := StorerMockIdempotencyKeyCompleteParams{ctx, key}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmIdempotencyKeyComplete.t.Errorf("StorerMock.IdempotencyKeyComplete got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmIdempotencyKeyComplete.IdempotencyKeyCompleteMock.defaultExpectation.results
+		if mm_results == nil {
+			mmIdempotencyKeyComplete.t.Fatal("No results are set for the StorerMock.IdempotencyKeyComplete")
+		}
+		return (*mm_results).err
+	}
+	if mmIdempotencyKeyComplete.funcIdempotencyKeyComplete != nil {
+		return mmIdempotencyKeyComplete.funcIdempotencyKeyComplete(ctx, key)
+	}
+	mmIdempotencyKeyComplete.t.Fatalf("Unexpected call to StorerMock.IdempotencyKeyComplete. %v %v", ctx, key)
+	return
+}
+
+// IdempotencyKeyCompleteAfterCounter returns a count of finished StorerMock.IdempotencyKeyComplete invocations
+func (mmIdempotencyKeyComplete *StorerMock) IdempotencyKeyCompleteAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyComplete.afterIdempotencyKeyCompleteCounter)
+}
+
+// IdempotencyKeyCompleteBeforeCounter returns a count of StorerMock.IdempotencyKeyComplete invocations
+func (mmIdempotencyKeyComplete *StorerMock) IdempotencyKeyCompleteBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyComplete.beforeIdempotencyKeyCompleteCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.IdempotencyKeyComplete.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmIdempotencyKeyComplete *mStorerMockIdempotencyKeyComplete) Calls() []*StorerMockIdempotencyKeyCompleteParams {
+	mmIdempotencyKeyComplete.mutex.RLock()
+
+	argCopy := make([]*StorerMockIdempotencyKeyCompleteParams, len(mmIdempotencyKeyComplete.callArgs))
+	copy(argCopy, mmIdempotencyKeyComplete.callArgs)
+
+	mmIdempotencyKeyComplete.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockIdempotencyKeyCompleteDone returns true if the count of the IdempotencyKeyComplete invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockIdempotencyKeyCompleteDone() bool {
+	for _, e := range m.IdempotencyKeyCompleteMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyCompleteMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCompleteCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyComplete != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCompleteCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockIdempotencyKeyCompleteInspect logs each unmet expectation
+func (m *StorerMock) MinimockIdempotencyKeyCompleteInspect() {
+	for _, e := range m.IdempotencyKeyCompleteMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyComplete with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyCompleteMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCompleteCounter) < 1 {
+		if m.IdempotencyKeyCompleteMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.IdempotencyKeyComplete")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyComplete with params: %#v", *m.IdempotencyKeyCompleteMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyComplete != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCompleteCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.IdempotencyKeyComplete")
+	}
+}
+
+type mStorerMockIdempotencyKeyCreate struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockIdempotencyKeyCreateExpectation
+	expectations       []*StorerMockIdempotencyKeyCreateExpectation
+
+	callArgs []*StorerMockIdempotencyKeyCreateParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockIdempotencyKeyCreateExpectation specifies expectation struct of the storer.IdempotencyKeyCreate
+type StorerMockIdempotencyKeyCreateExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockIdempotencyKeyCreateParams
+	results *StorerMockIdempotencyKeyCreateResults
+	Counter uint64
+}
+
+// StorerMockIdempotencyKeyCreateParams contains parameters of the storer.IdempotencyKeyCreate
+type StorerMockIdempotencyKeyCreateParams struct {
+	ctx context.Context
+	key *IdempotencyKey
+}
+
+// StorerMockIdempotencyKeyCreateResults contains results of the storer.IdempotencyKeyCreate
+type StorerMockIdempotencyKeyCreateResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.IdempotencyKeyCreate
+func (mmIdempotencyKeyCreate *mStorerMockIdempotencyKeyCreate) Expect(ctx context.Context, key *IdempotencyKey) *mStorerMockIdempotencyKeyCreate {
+	if mmIdempotencyKeyCreate.mock.funcIdempotencyKeyCreate != nil {
+		mmIdempotencyKeyCreate.mock.t.Fatalf("StorerMock.IdempotencyKeyCreate mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyCreate.defaultExpectation == nil {
+		mmIdempotencyKeyCreate.defaultExpectation = &StorerMockIdempotencyKeyCreateExpectation{}
+	}
+
+	mmIdempotencyKeyCreate.defaultExpectation.params = &StorerMockIdempotencyKeyCreateParams{ctx, key}
+	for _, e := range mmIdempotencyKeyCreate.expectations {
+		if minimock.Equal(e.params, mmIdempotencyKeyCreate.defaultExpectation.params) {
+			mmIdempotencyKeyCreate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIdempotencyKeyCreate.defaultExpectation.params)
+		}
+	}
+
+	return mmIdempotencyKeyCreate
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.IdempotencyKeyCreate
+func (mmIdempotencyKeyCreate *mStorerMockIdempotencyKeyCreate) Inspect(f func(ctx context.Context, key *IdempotencyKey)) *mStorerMockIdempotencyKeyCreate {
+	if mmIdempotencyKeyCreate.mock.inspectFuncIdempotencyKeyCreate != nil {
+		mmIdempotencyKeyCreate.mock.t.Fatalf("Inspect function is already set for StorerMock.IdempotencyKeyCreate")
+	}
+
+	mmIdempotencyKeyCreate.mock.inspectFuncIdempotencyKeyCreate = f
+
+	return mmIdempotencyKeyCreate
+}
+
+// Return sets up results that will be returned by storer.IdempotencyKeyCreate
+func (mmIdempotencyKeyCreate *mStorerMockIdempotencyKeyCreate) Return(err error) *StorerMock {
+	if mmIdempotencyKeyCreate.mock.funcIdempotencyKeyCreate != nil {
+		mmIdempotencyKeyCreate.mock.t.Fatalf("StorerMock.IdempotencyKeyCreate mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyCreate.defaultExpectation == nil {
+		mmIdempotencyKeyCreate.defaultExpectation = &StorerMockIdempotencyKeyCreateExpectation{mock: mmIdempotencyKeyCreate.mock}
+	}
+	mmIdempotencyKeyCreate.defaultExpectation.results = &StorerMockIdempotencyKeyCreateResults{err}
+	return mmIdempotencyKeyCreate.mock
+}
+
+//Set uses given function f to mock the storer.IdempotencyKeyCreate method
+func (mmIdempotencyKeyCreate *mStorerMockIdempotencyKeyCreate) Set(f func(ctx context.Context, key *IdempotencyKey) (err error)) *StorerMock {
+	if mmIdempotencyKeyCreate.defaultExpectation != nil {
+		mmIdempotencyKeyCreate.mock.t.Fatalf("Default expectation is already set for the storer.IdempotencyKeyCreate method")
+	}
+
+	if len(mmIdempotencyKeyCreate.expectations) > 0 {
+		mmIdempotencyKeyCreate.mock.t.Fatalf("Some expectations are already set for the storer.IdempotencyKeyCreate method")
+	}
+
+	mmIdempotencyKeyCreate.mock.funcIdempotencyKeyCreate = f
+	return mmIdempotencyKeyCreate.mock
+}
+
+// When sets expectation for the storer.IdempotencyKeyCreate which will trigger the result defined by the following
+// Then helper
+func (mmIdempotencyKeyCreate *mStorerMockIdempotencyKeyCreate) When(ctx context.Context, key *IdempotencyKey) *StorerMockIdempotencyKeyCreateExpectation {
+	if mmIdempotencyKeyCreate.mock.funcIdempotencyKeyCreate != nil {
+		mmIdempotencyKeyCreate.mock.t.Fatalf("StorerMock.IdempotencyKeyCreate mock is already set by Set")
+	}
+
+	expectation := &StorerMockIdempotencyKeyCreateExpectation{
+		mock:   mmIdempotencyKeyCreate.mock,
+		params: &StorerMockIdempotencyKeyCreateParams{ctx, key},
+	}
+	mmIdempotencyKeyCreate.expectations = append(mmIdempotencyKeyCreate.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.IdempotencyKeyCreate return parameters for the expectation previously defined by the When method
+func (e *StorerMockIdempotencyKeyCreateExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockIdempotencyKeyCreateResults{err}
+	return e.mock
+}
+
+// IdempotencyKeyCreate implements storer
+func (mmIdempotencyKeyCreate *StorerMock) IdempotencyKeyCreate(ctx context.Context, key *IdempotencyKey) (err error) {
+	mm_atomic.AddUint64(&mmIdempotencyKeyCreate.beforeIdempotencyKeyCreateCounter, 1)
+	defer mm_atomic.AddUint64(&mmIdempotencyKeyCreate.afterIdempotencyKeyCreateCounter, 1)
+
+	if mmIdempotencyKeyCreate.inspectFuncIdempotencyKeyCreate != nil {
+		mmIdempotencyKeyCreate.inspectFuncIdempotencyKeyCreate(ctx, key)
+	}
+
+	mm_params := &StorerMockIdempotencyKeyCreateParams{ctx, key}
+
+	// Record call args
+	mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.mutex.Lock()
+	mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.callArgs = append(mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.callArgs, mm_params)
+	mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.mutex.Unlock()
+
+	for _, e := range mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.defaultExpectation.Counter, 1)
+		mm_want := mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.defaultExpectation.params
+		mm_got := StorerMockIdempotencyKeyCreateParams{ctx, key}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmIdempotencyKeyCreate.t.Errorf("StorerMock.IdempotencyKeyCreate got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmIdempotencyKeyCreate.IdempotencyKeyCreateMock.defaultExpectation.results
+		if mm_results == nil {
+			mmIdempotencyKeyCreate.t.Fatal("No results are set for the StorerMock.IdempotencyKeyCreate")
+		}
+		return (*mm_results).err
+	}
+	if mmIdempotencyKeyCreate.funcIdempotencyKeyCreate != nil {
+		return mmIdempotencyKeyCreate.funcIdempotencyKeyCreate(ctx, key)
+	}
+	mmIdempotencyKeyCreate.t.Fatalf("Unexpected call to StorerMock.IdempotencyKeyCreate. %v %v", ctx, key)
+	return
+}
+
+// IdempotencyKeyCreateAfterCounter returns a count of finished StorerMock.IdempotencyKeyCreate invocations
+func (mmIdempotencyKeyCreate *StorerMock) IdempotencyKeyCreateAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyCreate.afterIdempotencyKeyCreateCounter)
+}
+
+// IdempotencyKeyCreateBeforeCounter returns a count of StorerMock.IdempotencyKeyCreate invocations
+func (mmIdempotencyKeyCreate *StorerMock) IdempotencyKeyCreateBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyCreate.beforeIdempotencyKeyCreateCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.IdempotencyKeyCreate.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmIdempotencyKeyCreate *mStorerMockIdempotencyKeyCreate) Calls() []*StorerMockIdempotencyKeyCreateParams {
+	mmIdempotencyKeyCreate.mutex.RLock()
+
+	argCopy := make([]*StorerMockIdempotencyKeyCreateParams, len(mmIdempotencyKeyCreate.callArgs))
+	copy(argCopy, mmIdempotencyKeyCreate.callArgs)
+
+	mmIdempotencyKeyCreate.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockIdempotencyKeyCreateDone returns true if the count of the IdempotencyKeyCreate invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockIdempotencyKeyCreateDone() bool {
+	for _, e := range m.IdempotencyKeyCreateMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCreateCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyCreate != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCreateCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockIdempotencyKeyCreateInspect logs each unmet expectation
+func (m *StorerMock) MinimockIdempotencyKeyCreateInspect() {
+	for _, e := range m.IdempotencyKeyCreateMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyCreate with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCreateCounter) < 1 {
+		if m.IdempotencyKeyCreateMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.IdempotencyKeyCreate")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyCreate with params: %#v", *m.IdempotencyKeyCreateMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyCreate != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyCreateCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.IdempotencyKeyCreate")
+	}
+}
+
+type mStorerMockIdempotencyKeyDelete struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockIdempotencyKeyDeleteExpectation
+	expectations       []*StorerMockIdempotencyKeyDeleteExpectation
+
+	callArgs []*StorerMockIdempotencyKeyDeleteParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockIdempotencyKeyDeleteExpectation specifies expectation struct of the storer.IdempotencyKeyDelete
+type StorerMockIdempotencyKeyDeleteExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockIdempotencyKeyDeleteParams
+	results *StorerMockIdempotencyKeyDeleteResults
+	Counter uint64
+}
+
+// StorerMockIdempotencyKeyDeleteParams contains parameters of the storer.IdempotencyKeyDelete
+type StorerMockIdempotencyKeyDeleteParams struct {
+	ctx    context.Context
+	userID int64
+	key    string
+}
+
+// StorerMockIdempotencyKeyDeleteResults contains results of the storer.IdempotencyKeyDelete
+type StorerMockIdempotencyKeyDeleteResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.IdempotencyKeyDelete
+func (mmIdempotencyKeyDelete *mStorerMockIdempotencyKeyDelete) Expect(ctx context.Context, userID int64, key string) *mStorerMockIdempotencyKeyDelete {
+	if mmIdempotencyKeyDelete.mock.funcIdempotencyKeyDelete != nil {
+		mmIdempotencyKeyDelete.mock.t.Fatalf("StorerMock.IdempotencyKeyDelete mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyDelete.defaultExpectation == nil {
+		mmIdempotencyKeyDelete.defaultExpectation = &StorerMockIdempotencyKeyDeleteExpectation{}
+	}
+
+	mmIdempotencyKeyDelete.defaultExpectation.params = &StorerMockIdempotencyKeyDeleteParams{ctx, userID, key}
+	for _, e := range mmIdempotencyKeyDelete.expectations {
+		if minimock.Equal(e.params, mmIdempotencyKeyDelete.defaultExpectation.params) {
+			mmIdempotencyKeyDelete.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIdempotencyKeyDelete.defaultExpectation.params)
+		}
+	}
+
+	return mmIdempotencyKeyDelete
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.IdempotencyKeyDelete
+func (mmIdempotencyKeyDelete *mStorerMockIdempotencyKeyDelete) Inspect(f func(ctx context.Context, userID int64, key string)) *mStorerMockIdempotencyKeyDelete {
+	if mmIdempotencyKeyDelete.mock.inspectFuncIdempotencyKeyDelete != nil {
+		mmIdempotencyKeyDelete.mock.t.Fatalf("Inspect function is already set for StorerMock.IdempotencyKeyDelete")
+	}
+
+	mmIdempotencyKeyDelete.mock.inspectFuncIdempotencyKeyDelete = f
+
+	return mmIdempotencyKeyDelete
+}
+
+// Return sets up results that will be returned by storer.IdempotencyKeyDelete
+func (mmIdempotencyKeyDelete *mStorerMockIdempotencyKeyDelete) Return(err error) *StorerMock {
+	if mmIdempotencyKeyDelete.mock.funcIdempotencyKeyDelete != nil {
+		mmIdempotencyKeyDelete.mock.t.Fatalf("StorerMock.IdempotencyKeyDelete mock is already set by Set")
+	}
+
+	if mmIdempotencyKeyDelete.defaultExpectation == nil {
+		mmIdempotencyKeyDelete.defaultExpectation = &StorerMockIdempotencyKeyDeleteExpectation{mock: mmIdempotencyKeyDelete.mock}
+	}
+	mmIdempotencyKeyDelete.defaultExpectation.results = &StorerMockIdempotencyKeyDeleteResults{err}
+	return mmIdempotencyKeyDelete.mock
+}
+
+//Set uses given function f to mock the storer.IdempotencyKeyDelete method
+func (mmIdempotencyKeyDelete *mStorerMockIdempotencyKeyDelete) Set(f func(ctx context.Context, userID int64, key string) (err error)) *StorerMock {
+	if mmIdempotencyKeyDelete.defaultExpectation != nil {
+		mmIdempotencyKeyDelete.mock.t.Fatalf("Default expectation is already set for the storer.IdempotencyKeyDelete method")
+	}
+
+	if len(mmIdempotencyKeyDelete.expectations) > 0 {
+		mmIdempotencyKeyDelete.mock.t.Fatalf("Some expectations are already set for the storer.IdempotencyKeyDelete method")
+	}
+
+	mmIdempotencyKeyDelete.mock.funcIdempotencyKeyDelete = f
+	return mmIdempotencyKeyDelete.mock
+}
+
+// When sets expectation for the storer.IdempotencyKeyDelete which will trigger the result defined by the following
+// Then helper
+func (mmIdempotencyKeyDelete *mStorerMockIdempotencyKeyDelete) When(ctx context.Context, userID int64, key string) *StorerMockIdempotencyKeyDeleteExpectation {
+	if mmIdempotencyKeyDelete.mock.funcIdempotencyKeyDelete != nil {
+		mmIdempotencyKeyDelete.mock.t.Fatalf("StorerMock.IdempotencyKeyDelete mock is already set by Set")
+	}
+
+	expectation := &StorerMockIdempotencyKeyDeleteExpectation{
+		mock:   mmIdempotencyKeyDelete.mock,
+		params: &StorerMockIdempotencyKeyDeleteParams{ctx, userID, key},
+	}
+	mmIdempotencyKeyDelete.expectations = append(mmIdempotencyKeyDelete.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.IdempotencyKeyDelete return parameters for the expectation previously defined by the When method
+func (e *StorerMockIdempotencyKeyDeleteExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockIdempotencyKeyDeleteResults{err}
+	return e.mock
+}
+
+// IdempotencyKeyDelete implements storer
+func (mmIdempotencyKeyDelete *StorerMock) IdempotencyKeyDelete(ctx context.Context, userID int64, key string) (err error) {
+	mm_atomic.AddUint64(&mmIdempotencyKeyDelete.beforeIdempotencyKeyDeleteCounter, 1)
+	defer mm_atomic.AddUint64(&mmIdempotencyKeyDelete.afterIdempotencyKeyDeleteCounter, 1)
+
+	if mmIdempotencyKeyDelete.inspectFuncIdempotencyKeyDelete != nil {
+		mmIdempotencyKeyDelete.inspectFuncIdempotencyKeyDelete(ctx, userID, key)
+	}
+
+	mm_params := &StorerMockIdempotencyKeyDeleteParams{ctx, userID, key}
+
+	// Record call args
+	mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.mutex.Lock()
+	mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.callArgs = append(mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.callArgs, mm_params)
+	mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.mutex.Unlock()
+
+	for _, e := range mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.defaultExpectation.Counter, 1)
+		mm_want := mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.defaultExpectation.params
+		mm_got := StorerMockIdempotencyKeyDeleteParams{ctx, userID, key}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmIdempotencyKeyDelete.t.Errorf("StorerMock.IdempotencyKeyDelete got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmIdempotencyKeyDelete.IdempotencyKeyDeleteMock.defaultExpectation.results
+		if mm_results == nil {
+			mmIdempotencyKeyDelete.t.Fatal("No results are set for the StorerMock.IdempotencyKeyDelete")
+		}
+		return (*mm_results).err
+	}
+	if mmIdempotencyKeyDelete.funcIdempotencyKeyDelete != nil {
+		return mmIdempotencyKeyDelete.funcIdempotencyKeyDelete(ctx, userID, key)
+	}
+	mmIdempotencyKeyDelete.t.Fatalf("Unexpected call to StorerMock.IdempotencyKeyDelete. %v %v %v", ctx, userID, key)
+	return
+}
+
+// IdempotencyKeyDeleteAfterCounter returns a count of finished StorerMock.IdempotencyKeyDelete invocations
+func (mmIdempotencyKeyDelete *StorerMock) IdempotencyKeyDeleteAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyDelete.afterIdempotencyKeyDeleteCounter)
+}
+
+// IdempotencyKeyDeleteBeforeCounter returns a count of StorerMock.IdempotencyKeyDelete invocations
+func (mmIdempotencyKeyDelete *StorerMock) IdempotencyKeyDeleteBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeyDelete.beforeIdempotencyKeyDeleteCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.IdempotencyKeyDelete.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmIdempotencyKeyDelete *mStorerMockIdempotencyKeyDelete) Calls() []*StorerMockIdempotencyKeyDeleteParams {
+	mmIdempotencyKeyDelete.mutex.RLock()
+
+	argCopy := make([]*StorerMockIdempotencyKeyDeleteParams, len(mmIdempotencyKeyDelete.callArgs))
+	copy(argCopy, mmIdempotencyKeyDelete.callArgs)
+
+	mmIdempotencyKeyDelete.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockIdempotencyKeyDeleteDone returns true if the count of the IdempotencyKeyDelete invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockIdempotencyKeyDeleteDone() bool {
+	for _, e := range m.IdempotencyKeyDeleteMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyDeleteMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyDeleteCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyDelete != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyDeleteCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockIdempotencyKeyDeleteInspect logs each unmet expectation
+func (m *StorerMock) MinimockIdempotencyKeyDeleteInspect() {
+	for _, e := range m.IdempotencyKeyDeleteMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyDelete with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeyDeleteMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyDeleteCounter) < 1 {
+		if m.IdempotencyKeyDeleteMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.IdempotencyKeyDelete")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeyDelete with params: %#v", *m.IdempotencyKeyDeleteMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeyDelete != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeyDeleteCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.IdempotencyKeyDelete")
+	}
+}
+
+type mStorerMockIdempotencyKeysPurge struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockIdempotencyKeysPurgeExpectation
+	expectations       []*StorerMockIdempotencyKeysPurgeExpectation
+
+	callArgs []*StorerMockIdempotencyKeysPurgeParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockIdempotencyKeysPurgeExpectation specifies expectation struct of the storer.IdempotencyKeysPurge
+type StorerMockIdempotencyKeysPurgeExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockIdempotencyKeysPurgeParams
+	results *StorerMockIdempotencyKeysPurgeResults
+	Counter uint64
+}
+
+// StorerMockIdempotencyKeysPurgeParams contains parameters of the storer.IdempotencyKeysPurge
+type StorerMockIdempotencyKeysPurgeParams struct {
+	ctx    context.Context
+	before time.Time
+}
+
+// StorerMockIdempotencyKeysPurgeResults contains results of the storer.IdempotencyKeysPurge
+type StorerMockIdempotencyKeysPurgeResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.IdempotencyKeysPurge
+func (mmIdempotencyKeysPurge *mStorerMockIdempotencyKeysPurge) Expect(ctx context.Context, before time.Time) *mStorerMockIdempotencyKeysPurge {
+	if mmIdempotencyKeysPurge.mock.funcIdempotencyKeysPurge != nil {
+		mmIdempotencyKeysPurge.mock.t.Fatalf("StorerMock.IdempotencyKeysPurge mock is already set by Set")
+	}
+
+	if mmIdempotencyKeysPurge.defaultExpectation == nil {
+		mmIdempotencyKeysPurge.defaultExpectation = &StorerMockIdempotencyKeysPurgeExpectation{}
+	}
+
+	mmIdempotencyKeysPurge.defaultExpectation.params = &StorerMockIdempotencyKeysPurgeParams{ctx, before}
+	for _, e := range mmIdempotencyKeysPurge.expectations {
+		if minimock.Equal(e.params, mmIdempotencyKeysPurge.defaultExpectation.params) {
+			mmIdempotencyKeysPurge.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmIdempotencyKeysPurge.defaultExpectation.params)
+		}
+	}
+
+	return mmIdempotencyKeysPurge
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.IdempotencyKeysPurge
+func (mmIdempotencyKeysPurge *mStorerMockIdempotencyKeysPurge) Inspect(f func(ctx context.Context, before time.Time)) *mStorerMockIdempotencyKeysPurge {
+	if mmIdempotencyKeysPurge.mock.inspectFuncIdempotencyKeysPurge != nil {
+		mmIdempotencyKeysPurge.mock.t.Fatalf("Inspect function is already set for StorerMock.IdempotencyKeysPurge")
+	}
+
+	mmIdempotencyKeysPurge.mock.inspectFuncIdempotencyKeysPurge = f
+
+	return mmIdempotencyKeysPurge
+}
+
+// Return sets up results that will be returned by storer.IdempotencyKeysPurge
+func (mmIdempotencyKeysPurge *mStorerMockIdempotencyKeysPurge) Return(err error) *StorerMock {
+	if mmIdempotencyKeysPurge.mock.funcIdempotencyKeysPurge != nil {
+		mmIdempotencyKeysPurge.mock.t.Fatalf("StorerMock.IdempotencyKeysPurge mock is already set by Set")
+	}
+
+	if mmIdempotencyKeysPurge.defaultExpectation == nil {
+		mmIdempotencyKeysPurge.defaultExpectation = &StorerMockIdempotencyKeysPurgeExpectation{mock: mmIdempotencyKeysPurge.mock}
+	}
+	mmIdempotencyKeysPurge.defaultExpectation.results = &StorerMockIdempotencyKeysPurgeResults{err}
+	return mmIdempotencyKeysPurge.mock
+}
+
+//Set uses given function f to mock the storer.IdempotencyKeysPurge method
+func (mmIdempotencyKeysPurge *mStorerMockIdempotencyKeysPurge) Set(f func(ctx context.Context, before time.Time) (err error)) *StorerMock {
+	if mmIdempotencyKeysPurge.defaultExpectation != nil {
+		mmIdempotencyKeysPurge.mock.t.Fatalf("Default expectation is already set for the storer.IdempotencyKeysPurge method")
+	}
+
+	if len(mmIdempotencyKeysPurge.expectations) > 0 {
+		mmIdempotencyKeysPurge.mock.t.Fatalf("Some expectations are already set for the storer.IdempotencyKeysPurge method")
+	}
+
+	mmIdempotencyKeysPurge.mock.funcIdempotencyKeysPurge = f
+	return mmIdempotencyKeysPurge.mock
+}
+
+// When sets expectation for the storer.IdempotencyKeysPurge which will trigger the result defined by the following
+// Then helper
+func (mmIdempotencyKeysPurge *mStorerMockIdempotencyKeysPurge) When(ctx context.Context, before time.Time) *StorerMockIdempotencyKeysPurgeExpectation {
+	if mmIdempotencyKeysPurge.mock.funcIdempotencyKeysPurge != nil {
+		mmIdempotencyKeysPurge.mock.t.Fatalf("StorerMock.IdempotencyKeysPurge mock is already set by Set")
+	}
+
+	expectation := &StorerMockIdempotencyKeysPurgeExpectation{
+		mock:   mmIdempotencyKeysPurge.mock,
+		params: &StorerMockIdempotencyKeysPurgeParams{ctx, before},
+	}
+	mmIdempotencyKeysPurge.expectations = append(mmIdempotencyKeysPurge.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.IdempotencyKeysPurge return parameters for the expectation previously defined by the When method
+func (e *StorerMockIdempotencyKeysPurgeExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockIdempotencyKeysPurgeResults{err}
+	return e.mock
+}
+
+// IdempotencyKeysPurge implements storer
+func (mmIdempotencyKeysPurge *StorerMock) IdempotencyKeysPurge(ctx context.Context, before time.Time) (err error) {
+	mm_atomic.AddUint64(&mmIdempotencyKeysPurge.beforeIdempotencyKeysPurgeCounter, 1)
+	defer mm_atomic.AddUint64(&mmIdempotencyKeysPurge.afterIdempotencyKeysPurgeCounter, 1)
+
+	if mmIdempotencyKeysPurge.inspectFuncIdempotencyKeysPurge != nil {
+		mmIdempotencyKeysPurge.inspectFuncIdempotencyKeysPurge(ctx, before)
+	}
+
+	mm_params := &StorerMockIdempotencyKeysPurgeParams{ctx, before}
+
+	// Record call args
+	mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.mutex.Lock()
+	mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.callArgs = append(mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.callArgs, mm_params)
+	mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.mutex.Unlock()
+
+	for _, e := range mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.defaultExpectation.Counter, 1)
+		mm_want := mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.defaultExpectation.params
+		mm_got := StorerMockIdempotencyKeysPurgeParams{ctx, before}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmIdempotencyKeysPurge.t.Errorf("StorerMock.IdempotencyKeysPurge got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmIdempotencyKeysPurge.IdempotencyKeysPurgeMock.defaultExpectation.results
+		if mm_results == nil {
+			mmIdempotencyKeysPurge.t.Fatal("No results are set for the StorerMock.IdempotencyKeysPurge")
+		}
+		return (*mm_results).err
+	}
+	if mmIdempotencyKeysPurge.funcIdempotencyKeysPurge != nil {
+		return mmIdempotencyKeysPurge.funcIdempotencyKeysPurge(ctx, before)
+	}
+	mmIdempotencyKeysPurge.t.Fatalf("Unexpected call to StorerMock.IdempotencyKeysPurge. %v %v", ctx, before)
+	return
+}
+
+// IdempotencyKeysPurgeAfterCounter returns a count of finished StorerMock.IdempotencyKeysPurge invocations
+func (mmIdempotencyKeysPurge *StorerMock) IdempotencyKeysPurgeAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeysPurge.afterIdempotencyKeysPurgeCounter)
+}
+
+// IdempotencyKeysPurgeBeforeCounter returns a count of StorerMock.IdempotencyKeysPurge invocations
+func (mmIdempotencyKeysPurge *StorerMock) IdempotencyKeysPurgeBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmIdempotencyKeysPurge.beforeIdempotencyKeysPurgeCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.IdempotencyKeysPurge.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmIdempotencyKeysPurge *mStorerMockIdempotencyKeysPurge) Calls() []*StorerMockIdempotencyKeysPurgeParams {
+	mmIdempotencyKeysPurge.mutex.RLock()
+
+	argCopy := make([]*StorerMockIdempotencyKeysPurgeParams, len(mmIdempotencyKeysPurge.callArgs))
+	copy(argCopy, mmIdempotencyKeysPurge.callArgs)
+
+	mmIdempotencyKeysPurge.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockIdempotencyKeysPurgeDone returns true if the count of the IdempotencyKeysPurge invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockIdempotencyKeysPurgeDone() bool {
+	for _, e := range m.IdempotencyKeysPurgeMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeysPurgeMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeysPurgeCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeysPurge != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeysPurgeCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockIdempotencyKeysPurgeInspect logs each unmet expectation
+func (m *StorerMock) MinimockIdempotencyKeysPurgeInspect() {
+	for _, e := range m.IdempotencyKeysPurgeMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeysPurge with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.IdempotencyKeysPurgeMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeysPurgeCounter) < 1 {
+		if m.IdempotencyKeysPurgeMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.IdempotencyKeysPurge")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.IdempotencyKeysPurge with params: %#v", *m.IdempotencyKeysPurgeMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcIdempotencyKeysPurge != nil && mm_atomic.LoadUint64(&m.afterIdempotencyKeysPurgeCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.IdempotencyKeysPurge")
+	}
+}
+
+type mStorerMockLineItemRemove struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockLineItemRemoveExpectation
+	expectations       []*StorerMockLineItemRemoveExpectation
+
+	callArgs []*StorerMockLineItemRemoveParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockLineItemRemoveExpectation specifies expectation struct of the storer.LineItemRemove
+type StorerMockLineItemRemoveExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockLineItemRemoveParams
+	results *StorerMockLineItemRemoveResults
+	Counter uint64
+}
+
+// StorerMockLineItemRemoveParams contains parameters of the storer.LineItemRemove
+type StorerMockLineItemRemoveParams struct {
+	ctx    context.Context
+	cartID int64
+	itemID int64
+}
+
+// StorerMockLineItemRemoveResults contains results of the storer.LineItemRemove
+type StorerMockLineItemRemoveResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.LineItemRemove
+func (mmLineItemRemove *mStorerMockLineItemRemove) Expect(ctx context.Context, cartID int64, itemID int64) *mStorerMockLineItemRemove {
+	if mmLineItemRemove.mock.funcLineItemRemove != nil {
+		mmLineItemRemove.mock.t.Fatalf("StorerMock.LineItemRemove mock is already set by Set")
+	}
+
+	if mmLineItemRemove.defaultExpectation == nil {
+		mmLineItemRemove.defaultExpectation = &StorerMockLineItemRemoveExpectation{}
+	}
+
+	mmLineItemRemove.defaultExpectation.params = &StorerMockLineItemRemoveParams{ctx, cartID, itemID}
+	for _, e := range mmLineItemRemove.expectations {
+		if minimock.Equal(e.params, mmLineItemRemove.defaultExpectation.params) {
+			mmLineItemRemove.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmLineItemRemove.defaultExpectation.params)
+		}
+	}
+
+	return mmLineItemRemove
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.LineItemRemove
+func (mmLineItemRemove *mStorerMockLineItemRemove) Inspect(f func(ctx context.Context, cartID int64, itemID int64)) *mStorerMockLineItemRemove {
+	if mmLineItemRemove.mock.inspectFuncLineItemRemove != nil {
+		mmLineItemRemove.mock.t.Fatalf("Inspect function is already set for StorerMock.LineItemRemove")
+	}
+
+	mmLineItemRemove.mock.inspectFuncLineItemRemove = f
+
+	return mmLineItemRemove
+}
+
+// Return sets up results that will be returned by storer.LineItemRemove
+func (mmLineItemRemove *mStorerMockLineItemRemove) Return(err error) *StorerMock {
+	if mmLineItemRemove.mock.funcLineItemRemove != nil {
+		mmLineItemRemove.mock.t.Fatalf("StorerMock.LineItemRemove mock is already set by Set")
+	}
+
+	if mmLineItemRemove.defaultExpectation == nil {
+		mmLineItemRemove.defaultExpectation = &StorerMockLineItemRemoveExpectation{mock: mmLineItemRemove.mock}
+	}
+	mmLineItemRemove.defaultExpectation.results = &StorerMockLineItemRemoveResults{err}
+	return mmLineItemRemove.mock
+}
+
+//Set uses given function f to mock the storer.LineItemRemove method
+func (mmLineItemRemove *mStorerMockLineItemRemove) Set(f func(ctx context.Context, cartID int64, itemID int64) (err error)) *StorerMock {
+	if mmLineItemRemove.defaultExpectation != nil {
+		mmLineItemRemove.mock.t.Fatalf("Default expectation is already set for the storer.LineItemRemove method")
+	}
+
+	if len(mmLineItemRemove.expectations) > 0 {
+		mmLineItemRemove.mock.t.Fatalf("Some expectations are already set for the storer.LineItemRemove method")
+	}
+
+	mmLineItemRemove.mock.funcLineItemRemove = f
+	return mmLineItemRemove.mock
+}
+
+// When sets expectation for the storer.LineItemRemove which will trigger the result defined by the following
+// Then helper
+func (mmLineItemRemove *mStorerMockLineItemRemove) When(ctx context.Context, cartID int64, itemID int64) *StorerMockLineItemRemoveExpectation {
+	if mmLineItemRemove.mock.funcLineItemRemove != nil {
+		mmLineItemRemove.mock.t.Fatalf("StorerMock.LineItemRemove mock is already set by Set")
+	}
+
+	expectation := &StorerMockLineItemRemoveExpectation{
+		mock:   mmLineItemRemove.mock,
+		params: &StorerMockLineItemRemoveParams{ctx, cartID, itemID},
+	}
+	mmLineItemRemove.expectations = append(mmLineItemRemove.expectations, expectation)
+	return expectation
+}
+
+// Then sets up storer.LineItemRemove return parameters for the expectation previously defined by the When method
+func (e *StorerMockLineItemRemoveExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockLineItemRemoveResults{err}
+	return e.mock
+}
+
+// LineItemRemove implements storer
+func (mmLineItemRemove *StorerMock) LineItemRemove(ctx context.Context, cartID int64, itemID int64) (err error) {
+	mm_atomic.AddUint64(&mmLineItemRemove.beforeLineItemRemoveCounter, 1)
+	defer mm_atomic.AddUint64(&mmLineItemRemove.afterLineItemRemoveCounter, 1)
+
+	if mmLineItemRemove.inspectFuncLineItemRemove != nil {
+		mmLineItemRemove.inspectFuncLineItemRemove(ctx, cartID, itemID)
+	}
+
+	mm_params := &StorerMockLineItemRemoveParams{ctx, cartID, itemID}
+
+	// Record call args
+	mmLineItemRemove.LineItemRemoveMock.mutex.Lock()
+	mmLineItemRemove.LineItemRemoveMock.callArgs = append(mmLineItemRemove.LineItemRemoveMock.callArgs, mm_params)
+	mmLineItemRemove.LineItemRemoveMock.mutex.Unlock()
+
+	for _, e := range mmLineItemRemove.LineItemRemoveMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmLineItemRemove.LineItemRemoveMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmLineItemRemove.LineItemRemoveMock.defaultExpectation.Counter, 1)
+		mm_want := mmLineItemRemove.LineItemRemoveMock.defaultExpectation.params
+		mm_got := StorerMockLineItemRemoveParams{ctx, cartID, itemID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmLineItemRemove.t.Errorf("StorerMock.LineItemRemove got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmLineItemRemove.LineItemRemoveMock.defaultExpectation.results
+		if mm_results == nil {
+			mmLineItemRemove.t.Fatal("No results are set for the StorerMock.LineItemRemove")
+		}
+		return (*mm_results).err
+	}
+	if mmLineItemRemove.funcLineItemRemove != nil {
+		return mmLineItemRemove.funcLineItemRemove(ctx, cartID, itemID)
+	}
+	mmLineItemRemove.t.Fatalf("Unexpected call to StorerMock.LineItemRemove. %v %v %v", ctx, cartID, itemID)
+	return
+}
+
+// LineItemRemoveAfterCounter returns a count of finished StorerMock.LineItemRemove invocations
+func (mmLineItemRemove *StorerMock) LineItemRemoveAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmLineItemRemove.afterLineItemRemoveCounter)
+}
+
+// LineItemRemoveBeforeCounter returns a count of StorerMock.LineItemRemove invocations
+func (mmLineItemRemove *StorerMock) LineItemRemoveBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmLineItemRemove.beforeLineItemRemoveCounter)
+}
+
+// Calls returns a list of arguments used in each call to StorerMock.LineItemRemove.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmLineItemRemove *mStorerMockLineItemRemove) Calls() []*StorerMockLineItemRemoveParams {
+	mmLineItemRemove.mutex.RLock()
+
+	argCopy := make([]*StorerMockLineItemRemoveParams, len(mmLineItemRemove.callArgs))
+	copy(argCopy, mmLineItemRemove.callArgs)
+
+	mmLineItemRemove.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockLineItemRemoveDone returns true if the count of the LineItemRemove invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockLineItemRemoveDone() bool {
+	for _, e := range m.LineItemRemoveMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.LineItemRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcLineItemRemove != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockLineItemRemoveInspect logs each unmet expectation
+func (m *StorerMock) MinimockLineItemRemoveInspect() {
+	for _, e := range m.LineItemRemoveMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to StorerMock.LineItemRemove with params: %#v", *e.params)
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.LineItemRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
+		if m.LineItemRemoveMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.LineItemRemove")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.LineItemRemove with params: %#v", *m.LineItemRemoveMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcLineItemRemove != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.LineItemRemove")
+	}
+}
+
+type mStorerMockLineItemsUpsert struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockLineItemsUpsertExpectation
+	expectations       []*StorerMockLineItemsUpsertExpectation
+
+	callArgs []*StorerMockLineItemsUpsertParams
+	mutex    sync.RWMutex
+}
+
+// StorerMockLineItemsUpsertExpectation specifies expectation struct of the storer.LineItemsUpsert
+type StorerMockLineItemsUpsertExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockLineItemsUpsertParams
+	results *StorerMockLineItemsUpsertResults
+	Counter uint64
+}
+
+// StorerMockLineItemsUpsertParams contains parameters of the storer.LineItemsUpsert
+type StorerMockLineItemsUpsertParams struct {
+	ctx    context.Context
+	cartID int64
+	mode   UpsertMode
+	items  []*LineItem
+}
+
+// StorerMockLineItemsUpsertResults contains results of the storer.LineItemsUpsert
+type StorerMockLineItemsUpsertResults struct {
 	err error
 }
 
-// Expect sets up expected params for storer.CartEmpty
-func (mmCartEmpty *mStorerMockCartEmpty) Expect(ctx context.Context, cartID int64) *mStorerMockCartEmpty {
-	if mmCartEmpty.mock.funcCartEmpty != nil {
-		mmCartEmpty.mock.t.Fatalf("StorerMock.CartEmpty mock is already set by Set")
+// Expect sets up expected params for storer.LineItemsUpsert
+func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Expect(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) *mStorerMockLineItemsUpsert {
+	if mmLineItemsUpsert.mock.funcLineItemsUpsert != nil {
+		mmLineItemsUpsert.mock.t.Fatalf("StorerMock.LineItemsUpsert mock is already set by Set")
 	}
 
-	if mmCartEmpty.defaultExpectation == nil {
-		mmCartEmpty.defaultExpectation = &StorerMockCartEmptyExpectation{}
+	if mmLineItemsUpsert.defaultExpectation == nil {
+		mmLineItemsUpsert.defaultExpectation = &StorerMockLineItemsUpsertExpectation{}
 	}
 
-	mmCartEmpty.defaultExpectation.params = &StorerMockCartEmptyParams{ctx, cartID}
-	for _, e := range mmCartEmpty.expectations {
-		if minimock.Equal(e.params, mmCartEmpty.defaultExpectation.params) {
-			mmCartEmpty.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartEmpty.defaultExpectation.params)
+	mmLineItemsUpsert.defaultExpectation.params = &StorerMockLineItemsUpsertParams{ctx, cartID, mode, items}
+	for _, e := range mmLineItemsUpsert.expectations {
+		if minimock.Equal(e.params, mmLineItemsUpsert.defaultExpectation.params) {
+			mmLineItemsUpsert.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmLineItemsUpsert.defaultExpectation.params)
 		}
 	}
 
-	return mmCartEmpty
+	return mmLineItemsUpsert
 }
 
-// Inspect accepts an inspector function that has same arguments as the storer.CartEmpty
-func (mmCartEmpty *mStorerMockCartEmpty) Inspect(f func(ctx context.Context, cartID int64)) *mStorerMockCartEmpty {
-	if mmCartEmpty.mock.inspectFuncCartEmpty != nil {
-		mmCartEmpty.mock.t.Fatalf("Inspect function is already set for StorerMock.CartEmpty")
+// Inspect accepts an inspector function that has same arguments as the storer.LineItemsUpsert
+func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Inspect(f func(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem)) *mStorerMockLineItemsUpsert {
+	if mmLineItemsUpsert.mock.inspectFuncLineItemsUpsert != nil {
+		mmLineItemsUpsert.mock.t.Fatalf("Inspect function is already set for StorerMock.LineItemsUpsert")
 	}
 
-	mmCartEmpty.mock.inspectFuncCartEmpty = f
+	mmLineItemsUpsert.mock.inspectFuncLineItemsUpsert = f
 
-	return mmCartEmpty
+	return mmLineItemsUpsert
 }
 
-// Return sets up results that will be returned by storer.CartEmpty
-func (mmCartEmpty *mStorerMockCartEmpty) Return(err error) *StorerMock {
-	if mmCartEmpty.mock.funcCartEmpty != nil {
-		mmCartEmpty.mock.t.Fatalf("StorerMock.CartEmpty mock is already set by Set")
+// Return sets up results that will be returned by storer.LineItemsUpsert
+func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Return(err error) *StorerMock {
+	if mmLineItemsUpsert.mock.funcLineItemsUpsert != nil {
+		mmLineItemsUpsert.mock.t.Fatalf("StorerMock.LineItemsUpsert mock is already set by Set")
 	}
 
-	if mmCartEmpty.defaultExpectation == nil {
-		mmCartEmpty.defaultExpectation = &StorerMockCartEmptyExpectation{mock: mmCartEmpty.mock}
+	if mmLineItemsUpsert.defaultExpectation == nil {
+		mmLineItemsUpsert.defaultExpectation = &StorerMockLineItemsUpsertExpectation{mock: mmLineItemsUpsert.mock}
 	}
-	mmCartEmpty.defaultExpectation.results = &StorerMockCartEmptyResults{err}
-	return mmCartEmpty.mock
+	mmLineItemsUpsert.defaultExpectation.results = &StorerMockLineItemsUpsertResults{err}
+	return mmLineItemsUpsert.mock
 }
 
-//Set uses given function f to mock the storer.CartEmpty method
-func (mmCartEmpty *mStorerMockCartEmpty) Set(f func(ctx context.Context, cartID int64) (err error)) *StorerMock {
-	if mmCartEmpty.defaultExpectation != nil {
-		mmCartEmpty.mock.t.Fatalf("Default expectation is already set for the storer.CartEmpty method")
+//Set uses given function f to mock the storer.LineItemsUpsert method
+func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Set(f func(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) (err error)) *StorerMock {
+	if mmLineItemsUpsert.defaultExpectation != nil {
+		mmLineItemsUpsert.mock.t.Fatalf("Default expectation is already set for the storer.LineItemsUpsert method")
 	}
 
-	if len(mmCartEmpty.expectations) > 0 {
-		mmCartEmpty.mock.t.Fatalf("Some expectations are already set for the storer.CartEmpty method")
+	if len(mmLineItemsUpsert.expectations) > 0 {
+		mmLineItemsUpsert.mock.t.Fatalf("Some expectations are already set for the storer.LineItemsUpsert method")
 	}
 
-	mmCartEmpty.mock.funcCartEmpty = f
-	return mmCartEmpty.mock
+	mmLineItemsUpsert.mock.funcLineItemsUpsert = f
+	return mmLineItemsUpsert.mock
 }
 
-// When sets expectation for the storer.CartEmpty which will trigger the result defined by the following
+// When sets expectation for the storer.LineItemsUpsert which will trigger the result defined by the following
 // Then helper
-func (mmCartEmpty *mStorerMockCartEmpty) When(ctx context.Context, cartID int64) *StorerMockCartEmptyExpectation {
-	if mmCartEmpty.mock.funcCartEmpty != nil {
-		mmCartEmpty.mock.t.Fatalf("StorerMock.CartEmpty mock is already set by Set")
+func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) When(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) *StorerMockLineItemsUpsertExpectation {
+	if mmLineItemsUpsert.mock.funcLineItemsUpsert != nil {
+		mmLineItemsUpsert.mock.t.Fatalf("StorerMock.LineItemsUpsert mock is already set by Set")
 	}
 
-	expectation := &StorerMockCartEmptyExpectation{
-		mock:   mmCartEmpty.mock,
-		params: &StorerMockCartEmptyParams{ctx, cartID},
+	expectation := &StorerMockLineItemsUpsertExpectation{
+		mock:   mmLineItemsUpsert.mock,
+		params: &StorerMockLineItemsUpsertParams{ctx, cartID, mode, items},
 	}
-	mmCartEmpty.expectations = append(mmCartEmpty.expectations, expectation)
+	mmLineItemsUpsert.expectations = append(mmLineItemsUpsert.expectations, expectation)
 	return expectation
 }
 
-// Then sets up storer.CartEmpty return parameters for the expectation previously defined by the When method
-func (e *StorerMockCartEmptyExpectation) Then(err error) *StorerMock {
-	e.results = &StorerMockCartEmptyResults{err}
+// Then sets up storer.LineItemsUpsert return parameters for the expectation previously defined by the When method
+func (e *StorerMockLineItemsUpsertExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockLineItemsUpsertResults{err}
 	return e.mock
 }
 
-// CartEmpty implements storer
-func (mmCartEmpty *StorerMock) CartEmpty(ctx context.Context, cartID int64) (err error) {
-	mm_atomic.AddUint64(&mmCartEmpty.beforeCartEmptyCounter, 1)
-	defer mm_atomic.AddUint64(&mmCartEmpty.afterCartEmptyCounter, 1)
+// LineItemsUpsert implements storer
+func (mmLineItemsUpsert *StorerMock) LineItemsUpsert(ctx context.Context, cartID int64, mode UpsertMode, items ...*LineItem) (err error) {
+	mm_atomic.AddUint64(&mmLineItemsUpsert.beforeLineItemsUpsertCounter, 1)
+	defer mm_atomic.AddUint64(&mmLineItemsUpsert.afterLineItemsUpsertCounter, 1)
 
-	if mmCartEmpty.inspectFuncCartEmpty != nil {
-		mmCartEmpty.inspectFuncCartEmpty(ctx, cartID)
+	if mmLineItemsUpsert.inspectFuncLineItemsUpsert != nil {
+		mmLineItemsUpsert.inspectFuncLineItemsUpsert(ctx, cartID, mode, items...)
 	}
 
-	mm_params := &StorerMockCartEmptyParams{ctx, cartID}
+	mm_params := &StorerMockLineItemsUpsertParams{ctx, cartID, mode, items}
 
 	// Record call args
-	mmCartEmpty.CartEmptyMock.mutex.Lock()
-	mmCartEmpty.CartEmptyMock.callArgs = append(mmCartEmpty.CartEmptyMock.callArgs, mm_params)
-	mmCartEmpty.CartEmptyMock.mutex.Unlock()
+	mmLineItemsUpsert.LineItemsUpsertMock.mutex.Lock()
+	mmLineItemsUpsert.LineItemsUpsertMock.callArgs = append(mmLineItemsUpsert.LineItemsUpsertMock.callArgs, mm_params)
+	mmLineItemsUpsert.LineItemsUpsertMock.mutex.Unlock()
 
-	for _, e := range mmCartEmpty.CartEmptyMock.expectations {
+	for _, e := range mmLineItemsUpsert.LineItemsUpsertMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.err
 		}
 	}
 
-	if mmCartEmpty.CartEmptyMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmCartEmpty.CartEmptyMock.defaultExpectation.Counter, 1)
-		mm_want := mmCartEmpty.CartEmptyMock.defaultExpectation.params
-		mm_got := StorerMockCartEmptyParams{ctx, cartID}
+	if mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation.Counter, 1)
+		mm_want := mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation.params
+		mm_got := StorerMockLineItemsUpsertParams{ctx, cartID, mode, items}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmCartEmpty.t.Errorf("StorerMock.CartEmpty got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmLineItemsUpsert.t.Errorf("StorerMock.LineItemsUpsert got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmCartEmpty.CartEmptyMock.defaultExpectation.results
+		mm_results := mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation.results
 		if mm_results == nil {
-			mmCartEmpty.t.Fatal("No results are set for the StorerMock.CartEmpty")
+			mmLineItemsUpsert.t.Fatal("No results are set for the StorerMock.LineItemsUpsert")
 		}
 		return (*mm_results).err
 	}
-	if mmCartEmpty.funcCartEmpty != nil {
-		return mmCartEmpty.funcCartEmpty(ctx, cartID)
+	if mmLineItemsUpsert.funcLineItemsUpsert != nil {
+		return mmLineItemsUpsert.funcLineItemsUpsert(ctx, cartID, mode, items...)
 	}
-	mmCartEmpty.t.Fatalf("Unexpected call to StorerMock.CartEmpty. %v %v", ctx, cartID)
+	mmLineItemsUpsert.t.Fatalf("Unexpected call to StorerMock.LineItemsUpsert. %v %v %v %v", ctx, cartID, mode, items)
 	return
 }
 
-// CartEmptyAfterCounter returns a count of finished StorerMock.CartEmpty invocations
-func (mmCartEmpty *StorerMock) CartEmptyAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartEmpty.afterCartEmptyCounter)
+// LineItemsUpsertAfterCounter returns a count of finished StorerMock.LineItemsUpsert invocations
+func (mmLineItemsUpsert *StorerMock) LineItemsUpsertAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmLineItemsUpsert.afterLineItemsUpsertCounter)
 }
 
-// CartEmptyBeforeCounter returns a count of StorerMock.CartEmpty invocations
-func (mmCartEmpty *StorerMock) CartEmptyBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartEmpty.beforeCartEmptyCounter)
+// LineItemsUpsertBeforeCounter returns a count of StorerMock.LineItemsUpsert invocations
+func (mmLineItemsUpsert *StorerMock) LineItemsUpsertBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmLineItemsUpsert.beforeLineItemsUpsertCounter)
 }
 
-// Calls returns a list of arguments used in each call to StorerMock.CartEmpty.
+// Calls returns a list of arguments used in each call to StorerMock.LineItemsUpsert.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmCartEmpty *mStorerMockCartEmpty) Calls() []*StorerMockCartEmptyParams {
-	mmCartEmpty.mutex.RLock()
+func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Calls() []*StorerMockLineItemsUpsertParams {
+	mmLineItemsUpsert.mutex.RLock()
 
-	argCopy := make([]*StorerMockCartEmptyParams, len(mmCartEmpty.callArgs))
-	copy(argCopy, mmCartEmpty.callArgs)
+	argCopy := make([]*StorerMockLineItemsUpsertParams, len(mmLineItemsUpsert.callArgs))
+	copy(argCopy, mmLineItemsUpsert.callArgs)
 
-	mmCartEmpty.mutex.RUnlock()
+	mmLineItemsUpsert.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockCartEmptyDone returns true if the count of the CartEmpty invocations corresponds
+// MinimockLineItemsUpsertDone returns true if the count of the LineItemsUpsert invocations corresponds
 // the number of defined expectations
-func (m *StorerMock) MinimockCartEmptyDone() bool {
-	for _, e := range m.CartEmptyMock.expectations {
+func (m *StorerMock) MinimockLineItemsUpsertDone() bool {
+	for _, e := range m.LineItemsUpsertMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartEmptyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
+	if m.LineItemsUpsertMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartEmpty != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
+	if m.funcLineItemsUpsert != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockCartEmptyInspect logs each unmet expectation
-func (m *StorerMock) MinimockCartEmptyInspect() {
-	for _, e := range m.CartEmptyMock.expectations {
+// MinimockLineItemsUpsertInspect logs each unmet expectation
+func (m *StorerMock) MinimockLineItemsUpsertInspect() {
+	for _, e := range m.LineItemsUpsertMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to StorerMock.CartEmpty with params: %#v", *e.params)
+			m.t.Errorf("Expected call to StorerMock.LineItemsUpsert with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartEmptyMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
-		if m.CartEmptyMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to StorerMock.CartEmpty")
+	if m.LineItemsUpsertMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
+		if m.LineItemsUpsertMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.LineItemsUpsert")
 		} else {
-			m.t.Errorf("Expected call to StorerMock.CartEmpty with params: %#v", *m.CartEmptyMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to StorerMock.LineItemsUpsert with params: %#v", *m.LineItemsUpsertMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartEmpty != nil && mm_atomic.LoadUint64(&m.afterCartEmptyCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.CartEmpty")
+	if m.funcLineItemsUpsert != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.LineItemsUpsert")
 	}
 }
 
-type mStorerMockCartWithItemsByCartID struct {
+type mStorerMockOrderCreate struct {
 	mock               *StorerMock
-	defaultExpectation *StorerMockCartWithItemsByCartIDExpectation
-	expectations       []*StorerMockCartWithItemsByCartIDExpectation
+	defaultExpectation *StorerMockOrderCreateExpectation
+	expectations       []*StorerMockOrderCreateExpectation
 
-	callArgs []*StorerMockCartWithItemsByCartIDParams
+	callArgs []*StorerMockOrderCreateParams
 	mutex    sync.RWMutex
 }
 
-// StorerMockCartWithItemsByCartIDExpectation specifies expectation struct of the storer.CartWithItemsByCartID
-type StorerMockCartWithItemsByCartIDExpectation struct {
+// StorerMockOrderCreateExpectation specifies expectation struct of the storer.OrderCreate
+type StorerMockOrderCreateExpectation struct {
 	mock    *StorerMock
-	params  *StorerMockCartWithItemsByCartIDParams
-	results *StorerMockCartWithItemsByCartIDResults
+	params  *StorerMockOrderCreateParams
+	results *StorerMockOrderCreateResults
 	Counter uint64
 }
 
-// StorerMockCartWithItemsByCartIDParams contains parameters of the storer.CartWithItemsByCartID
-type StorerMockCartWithItemsByCartIDParams struct {
-	ctx    context.Context
-	cartID int64
+// StorerMockOrderCreateParams contains parameters of the storer.OrderCreate
+type StorerMockOrderCreateParams struct {
+	ctx context.Context
+	o   *Order
 }
 
-// StorerMockCartWithItemsByCartIDResults contains results of the storer.CartWithItemsByCartID
-type StorerMockCartWithItemsByCartIDResults struct {
-	cp1 *Cart
+// StorerMockOrderCreateResults contains results of the storer.OrderCreate
+type StorerMockOrderCreateResults struct {
 	err error
 }
 
-// Expect sets up expected params for storer.CartWithItemsByCartID
-func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Expect(ctx context.Context, cartID int64) *mStorerMockCartWithItemsByCartID {
-	if mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID != nil {
-		mmCartWithItemsByCartID.mock.t.Fatalf("StorerMock.CartWithItemsByCartID mock is already set by Set")
+// Expect sets up expected params for storer.OrderCreate
+func (mmOrderCreate *mStorerMockOrderCreate) Expect(ctx context.Context, o *Order) *mStorerMockOrderCreate {
+	if mmOrderCreate.mock.funcOrderCreate != nil {
+		mmOrderCreate.mock.t.Fatalf("StorerMock.OrderCreate mock is already set by Set")
 	}
 
-	if mmCartWithItemsByCartID.defaultExpectation == nil {
-		mmCartWithItemsByCartID.defaultExpectation = &StorerMockCartWithItemsByCartIDExpectation{}
+	if mmOrderCreate.defaultExpectation == nil {
+		mmOrderCreate.defaultExpectation = &StorerMockOrderCreateExpectation{}
 	}
 
-	mmCartWithItemsByCartID.defaultExpectation.params = &StorerMockCartWithItemsByCartIDParams{ctx, cartID}
-	for _, e := range mmCartWithItemsByCartID.expectations {
-		if minimock.Equal(e.params, mmCartWithItemsByCartID.defaultExpectation.params) {
-			mmCartWithItemsByCartID.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmCartWithItemsByCartID.defaultExpectation.params)
+	mmOrderCreate.defaultExpectation.params = &StorerMockOrderCreateParams{ctx, o}
+	for _, e := range mmOrderCreate.expectations {
+		if minimock.Equal(e.params, mmOrderCreate.defaultExpectation.params) {
+			mmOrderCreate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmOrderCreate.defaultExpectation.params)
 		}
 	}
 
-	return mmCartWithItemsByCartID
+	return mmOrderCreate
 }
 
-// Inspect accepts an inspector function that has same arguments as the storer.CartWithItemsByCartID
-func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Inspect(f func(ctx context.Context, cartID int64)) *mStorerMockCartWithItemsByCartID {
-	if mmCartWithItemsByCartID.mock.inspectFuncCartWithItemsByCartID != nil {
-		mmCartWithItemsByCartID.mock.t.Fatalf("Inspect function is already set for StorerMock.CartWithItemsByCartID")
+// Inspect accepts an inspector function that has same arguments as the storer.OrderCreate
+func (mmOrderCreate *mStorerMockOrderCreate) Inspect(f func(ctx context.Context, o *Order)) *mStorerMockOrderCreate {
+	if mmOrderCreate.mock.inspectFuncOrderCreate != nil {
+		mmOrderCreate.mock.t.Fatalf("Inspect function is already set for StorerMock.OrderCreate")
 	}
 
-	mmCartWithItemsByCartID.mock.inspectFuncCartWithItemsByCartID = f
+	mmOrderCreate.mock.inspectFuncOrderCreate = f
 
-	return mmCartWithItemsByCartID
+	return mmOrderCreate
 }
 
-// Return sets up results that will be returned by storer.CartWithItemsByCartID
-func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Return(cp1 *Cart, err error) *StorerMock {
-	if mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID != nil {
-		mmCartWithItemsByCartID.mock.t.Fatalf("StorerMock.CartWithItemsByCartID mock is already set by Set")
+// Return sets up results that will be returned by storer.OrderCreate
+func (mmOrderCreate *mStorerMockOrderCreate) Return(err error) *StorerMock {
+	if mmOrderCreate.mock.funcOrderCreate != nil {
+		mmOrderCreate.mock.t.Fatalf("StorerMock.OrderCreate mock is already set by Set")
 	}
 
-	if mmCartWithItemsByCartID.defaultExpectation == nil {
-		mmCartWithItemsByCartID.defaultExpectation = &StorerMockCartWithItemsByCartIDExpectation{mock: mmCartWithItemsByCartID.mock}
+	if mmOrderCreate.defaultExpectation == nil {
+		mmOrderCreate.defaultExpectation = &StorerMockOrderCreateExpectation{mock: mmOrderCreate.mock}
 	}
-	mmCartWithItemsByCartID.defaultExpectation.results = &StorerMockCartWithItemsByCartIDResults{cp1, err}
-	return mmCartWithItemsByCartID.mock
+	mmOrderCreate.defaultExpectation.results = &StorerMockOrderCreateResults{err}
+	return mmOrderCreate.mock
 }
 
-//Set uses given function f to mock the storer.CartWithItemsByCartID method
-func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Set(f func(ctx context.Context, cartID int64) (cp1 *Cart, err error)) *StorerMock {
-	if mmCartWithItemsByCartID.defaultExpectation != nil {
-		mmCartWithItemsByCartID.mock.t.Fatalf("Default expectation is already set for the storer.CartWithItemsByCartID method")
+//Set uses given function f to mock the storer.OrderCreate method
+func (mmOrderCreate *mStorerMockOrderCreate) Set(f func(ctx context.Context, o *Order) (err error)) *StorerMock {
+	if mmOrderCreate.defaultExpectation != nil {
+		mmOrderCreate.mock.t.Fatalf("Default expectation is already set for the storer.OrderCreate method")
 	}
 
-	if len(mmCartWithItemsByCartID.expectations) > 0 {
-		mmCartWithItemsByCartID.mock.t.Fatalf("Some expectations are already set for the storer.CartWithItemsByCartID method")
+	if len(mmOrderCreate.expectations) > 0 {
+		mmOrderCreate.mock.t.Fatalf("Some expectations are already set for the storer.OrderCreate method")
 	}
 
-	mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID = f
-	return mmCartWithItemsByCartID.mock
+	mmOrderCreate.mock.funcOrderCreate = f
+	return mmOrderCreate.mock
 }
 
-// When sets expectation for the storer.CartWithItemsByCartID which will trigger the result defined by the following
+// When sets expectation for the storer.OrderCreate which will trigger the result defined by the following
 // Then helper
-func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) When(ctx context.Context, cartID int64) *StorerMockCartWithItemsByCartIDExpectation {
-	if mmCartWithItemsByCartID.mock.funcCartWithItemsByCartID != nil {
-		mmCartWithItemsByCartID.mock.t.Fatalf("StorerMock.CartWithItemsByCartID mock is already set by Set")
+func (mmOrderCreate *mStorerMockOrderCreate) When(ctx context.Context, o *Order) *StorerMockOrderCreateExpectation {
+	if mmOrderCreate.mock.funcOrderCreate != nil {
+		mmOrderCreate.mock.t.Fatalf("StorerMock.OrderCreate mock is already set by Set")
 	}
 
-	expectation := &StorerMockCartWithItemsByCartIDExpectation{
-		mock:   mmCartWithItemsByCartID.mock,
-		params: &StorerMockCartWithItemsByCartIDParams{ctx, cartID},
+	expectation := &StorerMockOrderCreateExpectation{
+		mock:   mmOrderCreate.mock,
+		params: &StorerMockOrderCreateParams{ctx, o},
 	}
-	mmCartWithItemsByCartID.expectations = append(mmCartWithItemsByCartID.expectations, expectation)
+	mmOrderCreate.expectations = append(mmOrderCreate.expectations, expectation)
 	return expectation
 }
 
-// Then sets up storer.CartWithItemsByCartID return parameters for the expectation previously defined by the When method
-func (e *StorerMockCartWithItemsByCartIDExpectation) Then(cp1 *Cart, err error) *StorerMock {
-	e.results = &StorerMockCartWithItemsByCartIDResults{cp1, err}
+// Then sets up storer.OrderCreate return parameters for the expectation previously defined by the When method
+func (e *StorerMockOrderCreateExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockOrderCreateResults{err}
 	return e.mock
 }
 
-// CartWithItemsByCartID implements storer
-func (mmCartWithItemsByCartID *StorerMock) CartWithItemsByCartID(ctx context.Context, cartID int64) (cp1 *Cart, err error) {
-	mm_atomic.AddUint64(&mmCartWithItemsByCartID.beforeCartWithItemsByCartIDCounter, 1)
-	defer mm_atomic.AddUint64(&mmCartWithItemsByCartID.afterCartWithItemsByCartIDCounter, 1)
+// OrderCreate implements storer
+func (mmOrderCreate *StorerMock) OrderCreate(ctx context.Context, o *Order) (err error) {
+	mm_atomic.AddUint64(&mmOrderCreate.beforeOrderCreateCounter, 1)
+	defer mm_atomic.AddUint64(&mmOrderCreate.afterOrderCreateCounter, 1)
 
-	if mmCartWithItemsByCartID.inspectFuncCartWithItemsByCartID != nil {
-		mmCartWithItemsByCartID.inspectFuncCartWithItemsByCartID(ctx, cartID)
+	if mmOrderCreate.inspectFuncOrderCreate != nil {
+		mmOrderCreate.inspectFuncOrderCreate(ctx, o)
 	}
 
-	mm_params := &StorerMockCartWithItemsByCartIDParams{ctx, cartID}
+	mm_params := &StorerMockOrderCreateParams{ctx, o}
 
 	// Record call args
-	mmCartWithItemsByCartID.CartWithItemsByCartIDMock.mutex.Lock()
-	mmCartWithItemsByCartID.CartWithItemsByCartIDMock.callArgs = append(mmCartWithItemsByCartID.CartWithItemsByCartIDMock.callArgs, mm_params)
-	mmCartWithItemsByCartID.CartWithItemsByCartIDMock.mutex.Unlock()
+	mmOrderCreate.OrderCreateMock.mutex.Lock()
+	mmOrderCreate.OrderCreateMock.callArgs = append(mmOrderCreate.OrderCreateMock.callArgs, mm_params)
+	mmOrderCreate.OrderCreateMock.mutex.Unlock()
 
-	for _, e := range mmCartWithItemsByCartID.CartWithItemsByCartIDMock.expectations {
+	for _, e := range mmOrderCreate.OrderCreateMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
-			return e.results.cp1, e.results.err
+			return e.results.err
 		}
 	}
 
-	if mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation.Counter, 1)
-		mm_want := mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation.params
-		mm_got := StorerMockCartWithItemsByCartIDParams{ctx, cartID}
+	if mmOrderCreate.OrderCreateMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmOrderCreate.OrderCreateMock.defaultExpectation.Counter, 1)
+		mm_want := mmOrderCreate.OrderCreateMock.defaultExpectation.params
+		mm_got := StorerMockOrderCreateParams{ctx, o}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmCartWithItemsByCartID.t.Errorf("StorerMock.CartWithItemsByCartID got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmOrderCreate.t.Errorf("StorerMock.OrderCreate got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmCartWithItemsByCartID.CartWithItemsByCartIDMock.defaultExpectation.results
+		mm_results := mmOrderCreate.OrderCreateMock.defaultExpectation.results
 		if mm_results == nil {
-			mmCartWithItemsByCartID.t.Fatal("No results are set for the StorerMock.CartWithItemsByCartID")
+			mmOrderCreate.t.Fatal("No results are set for the StorerMock.OrderCreate")
 		}
-		return (*mm_results).cp1, (*mm_results).err
+		return (*mm_results).err
 	}
-	if mmCartWithItemsByCartID.funcCartWithItemsByCartID != nil {
-		return mmCartWithItemsByCartID.funcCartWithItemsByCartID(ctx, cartID)
+	if mmOrderCreate.funcOrderCreate != nil {
+		return mmOrderCreate.funcOrderCreate(ctx, o)
 	}
-	mmCartWithItemsByCartID.t.Fatalf("Unexpected call to StorerMock.CartWithItemsByCartID. %v %v", ctx, cartID)
+	mmOrderCreate.t.Fatalf("Unexpected call to StorerMock.OrderCreate. %v %v", ctx, o)
 	return
 }
 
-// CartWithItemsByCartIDAfterCounter returns a count of finished StorerMock.CartWithItemsByCartID invocations
-func (mmCartWithItemsByCartID *StorerMock) CartWithItemsByCartIDAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartWithItemsByCartID.afterCartWithItemsByCartIDCounter)
+// OrderCreateAfterCounter returns a count of finished StorerMock.OrderCreate invocations
+func (mmOrderCreate *StorerMock) OrderCreateAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmOrderCreate.afterOrderCreateCounter)
 }
 
-// CartWithItemsByCartIDBeforeCounter returns a count of StorerMock.CartWithItemsByCartID invocations
-func (mmCartWithItemsByCartID *StorerMock) CartWithItemsByCartIDBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCartWithItemsByCartID.beforeCartWithItemsByCartIDCounter)
+// OrderCreateBeforeCounter returns a count of StorerMock.OrderCreate invocations
+func (mmOrderCreate *StorerMock) OrderCreateBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmOrderCreate.beforeOrderCreateCounter)
 }
 
-// Calls returns a list of arguments used in each call to StorerMock.CartWithItemsByCartID.
+// Calls returns a list of arguments used in each call to StorerMock.OrderCreate.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmCartWithItemsByCartID *mStorerMockCartWithItemsByCartID) Calls() []*StorerMockCartWithItemsByCartIDParams {
-	mmCartWithItemsByCartID.mutex.RLock()
+func (mmOrderCreate *mStorerMockOrderCreate) Calls() []*StorerMockOrderCreateParams {
+	mmOrderCreate.mutex.RLock()
 
-	argCopy := make([]*StorerMockCartWithItemsByCartIDParams, len(mmCartWithItemsByCartID.callArgs))
-	copy(argCopy, mmCartWithItemsByCartID.callArgs)
+	argCopy := make([]*StorerMockOrderCreateParams, len(mmOrderCreate.callArgs))
+	copy(argCopy, mmOrderCreate.callArgs)
 
-	mmCartWithItemsByCartID.mutex.RUnlock()
+	mmOrderCreate.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockCartWithItemsByCartIDDone returns true if the count of the CartWithItemsByCartID invocations corresponds
+// MinimockOrderCreateDone returns true if the count of the OrderCreate invocations corresponds
 // the number of defined expectations
-func (m *StorerMock) MinimockCartWithItemsByCartIDDone() bool {
-	for _, e := range m.CartWithItemsByCartIDMock.expectations {
+func (m *StorerMock) MinimockOrderCreateDone() bool {
+	for _, e := range m.OrderCreateMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartWithItemsByCartIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
+	if m.OrderCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterOrderCreateCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartWithItemsByCartID != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
+	if m.funcOrderCreate != nil && mm_atomic.LoadUint64(&m.afterOrderCreateCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockCartWithItemsByCartIDInspect logs each unmet expectation
-func (m *StorerMock) MinimockCartWithItemsByCartIDInspect() {
-	for _, e := range m.CartWithItemsByCartIDMock.expectations {
+// MinimockOrderCreateInspect logs each unmet expectation
+func (m *StorerMock) MinimockOrderCreateInspect() {
+	for _, e := range m.OrderCreateMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to StorerMock.CartWithItemsByCartID with params: %#v", *e.params)
+			m.t.Errorf("Expected call to StorerMock.OrderCreate with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CartWithItemsByCartIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
-		if m.CartWithItemsByCartIDMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to StorerMock.CartWithItemsByCartID")
+	if m.OrderCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterOrderCreateCounter) < 1 {
+		if m.OrderCreateMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.OrderCreate")
 		} else {
-			m.t.Errorf("Expected call to StorerMock.CartWithItemsByCartID with params: %#v", *m.CartWithItemsByCartIDMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to StorerMock.OrderCreate with params: %#v", *m.OrderCreateMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCartWithItemsByCartID != nil && mm_atomic.LoadUint64(&m.afterCartWithItemsByCartIDCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.CartWithItemsByCartID")
+	if m.funcOrderCreate != nil && mm_atomic.LoadUint64(&m.afterOrderCreateCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.OrderCreate")
 	}
 }
 
-type mStorerMockCommit struct {
+type mStorerMockPaymentByCartID struct {
 	mock               *StorerMock
-	defaultExpectation *StorerMockCommitExpectation
-	expectations       []*StorerMockCommitExpectation
-}
+	defaultExpectation *StorerMockPaymentByCartIDExpectation
+	expectations       []*StorerMockPaymentByCartIDExpectation
 
-// StorerMockCommitExpectation specifies expectation struct of the storer.Commit
-type StorerMockCommitExpectation struct {
-	mock *StorerMock
+	callArgs []*StorerMockPaymentByCartIDParams
+	mutex    sync.RWMutex
+}
 
-	results *StorerMockCommitResults
+// StorerMockPaymentByCartIDExpectation specifies expectation struct of the storer.PaymentByCartID
+type StorerMockPaymentByCartIDExpectation struct {
+	mock    *StorerMock
+	params  *StorerMockPaymentByCartIDParams
+	results *StorerMockPaymentByCartIDResults
 	Counter uint64
 }
 
-// StorerMockCommitResults contains results of the storer.Commit
-type StorerMockCommitResults struct {
+// StorerMockPaymentByCartIDParams contains parameters of the storer.PaymentByCartID
+type StorerMockPaymentByCartIDParams struct {
+	ctx    context.Context
+	cartID int64
+}
+
+// StorerMockPaymentByCartIDResults contains results of the storer.PaymentByCartID
+type StorerMockPaymentByCartIDResults struct {
+	pp1 *Payment
 	err error
 }
 
-// Expect sets up expected params for storer.Commit
-func (mmCommit *mStorerMockCommit) Expect() *mStorerMockCommit {
-	if mmCommit.mock.funcCommit != nil {
-		mmCommit.mock.t.Fatalf("StorerMock.Commit mock is already set by Set")
+// Expect sets up expected params for storer.PaymentByCartID
+func (mmPaymentByCartID *mStorerMockPaymentByCartID) Expect(ctx context.Context, cartID int64) *mStorerMockPaymentByCartID {
+	if mmPaymentByCartID.mock.funcPaymentByCartID != nil {
+		mmPaymentByCartID.mock.t.Fatalf("StorerMock.PaymentByCartID mock is already set by Set")
 	}
 
-	if mmCommit.defaultExpectation == nil {
-		mmCommit.defaultExpectation = &StorerMockCommitExpectation{}
+	if mmPaymentByCartID.defaultExpectation == nil {
+		mmPaymentByCartID.defaultExpectation = &StorerMockPaymentByCartIDExpectation{}
 	}
 
-	return mmCommit
+	mmPaymentByCartID.defaultExpectation.params = &StorerMockPaymentByCartIDParams{ctx, cartID}
+	for _, e := range mmPaymentByCartID.expectations {
+		if minimock.Equal(e.params, mmPaymentByCartID.defaultExpectation.params) {
+			mmPaymentByCartID.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPaymentByCartID.defaultExpectation.params)
+		}
+	}
+
+	return mmPaymentByCartID
 }
 
-// Inspect accepts an inspector function that has same arguments as the storer.Commit
-func (mmCommit *mStorerMockCommit) Inspect(f func()) *mStorerMockCommit {
-	if mmCommit.mock.inspectFuncCommit != nil {
-		mmCommit.mock.t.Fatalf("Inspect function is already set for StorerMock.Commit")
+// Inspect accepts an inspector function that has same arguments as the storer.PaymentByCartID
+func (mmPaymentByCartID *mStorerMockPaymentByCartID) Inspect(f func(ctx context.Context, cartID int64)) *mStorerMockPaymentByCartID {
+	if mmPaymentByCartID.mock.inspectFuncPaymentByCartID != nil {
+		mmPaymentByCartID.mock.t.Fatalf("Inspect function is already set for StorerMock.PaymentByCartID")
 	}
 
-	mmCommit.mock.inspectFuncCommit = f
+	mmPaymentByCartID.mock.inspectFuncPaymentByCartID = f
 
-	return mmCommit
+	return mmPaymentByCartID
 }
 
-// Return sets up results that will be returned by storer.Commit
-func (mmCommit *mStorerMockCommit) Return(err error) *StorerMock {
-	if mmCommit.mock.funcCommit != nil {
-		mmCommit.mock.t.Fatalf("StorerMock.Commit mock is already set by Set")
+// Return sets up results that will be returned by storer.PaymentByCartID
+func (mmPaymentByCartID *mStorerMockPaymentByCartID) Return(pp1 *Payment, err error) *StorerMock {
+	if mmPaymentByCartID.mock.funcPaymentByCartID != nil {
+		mmPaymentByCartID.mock.t.Fatalf("StorerMock.PaymentByCartID mock is already set by Set")
 	}
 
-	if mmCommit.defaultExpectation == nil {
-		mmCommit.defaultExpectation = &StorerMockCommitExpectation{mock: mmCommit.mock}
+	if mmPaymentByCartID.defaultExpectation == nil {
+		mmPaymentByCartID.defaultExpectation = &StorerMockPaymentByCartIDExpectation{mock: mmPaymentByCartID.mock}
 	}
-	mmCommit.defaultExpectation.results = &StorerMockCommitResults{err}
-	return mmCommit.mock
+	mmPaymentByCartID.defaultExpectation.results = &StorerMockPaymentByCartIDResults{pp1, err}
+	return mmPaymentByCartID.mock
 }
 
-//Set uses given function f to mock the storer.Commit method
-func (mmCommit *mStorerMockCommit) Set(f func() (err error)) *StorerMock {
-	if mmCommit.defaultExpectation != nil {
-		mmCommit.mock.t.Fatalf("Default expectation is already set for the storer.Commit method")
+//Set uses given function f to mock the storer.PaymentByCartID method
+func (mmPaymentByCartID *mStorerMockPaymentByCartID) Set(f func(ctx context.Context, cartID int64) (pp1 *Payment, err error)) *StorerMock {
+	if mmPaymentByCartID.defaultExpectation != nil {
+		mmPaymentByCartID.mock.t.Fatalf("Default expectation is already set for the storer.PaymentByCartID method")
 	}
 
-	if len(mmCommit.expectations) > 0 {
-		mmCommit.mock.t.Fatalf("Some expectations are already set for the storer.Commit method")
+	if len(mmPaymentByCartID.expectations) > 0 {
+		mmPaymentByCartID.mock.t.Fatalf("Some expectations are already set for the storer.PaymentByCartID method")
 	}
 
-	mmCommit.mock.funcCommit = f
-	return mmCommit.mock
+	mmPaymentByCartID.mock.funcPaymentByCartID = f
+	return mmPaymentByCartID.mock
 }
 
-// Commit implements storer
-func (mmCommit *StorerMock) Commit() (err error) {
-	mm_atomic.AddUint64(&mmCommit.beforeCommitCounter, 1)
-	defer mm_atomic.AddUint64(&mmCommit.afterCommitCounter, 1)
+// When sets expectation for the storer.PaymentByCartID which will trigger the result defined by the following
+// Then helper
+func (mmPaymentByCartID *mStorerMockPaymentByCartID) When(ctx context.Context, cartID int64) *StorerMockPaymentByCartIDExpectation {
+	if mmPaymentByCartID.mock.funcPaymentByCartID != nil {
+		mmPaymentByCartID.mock.t.Fatalf("StorerMock.PaymentByCartID mock is already set by Set")
+	}
 
-	if mmCommit.inspectFuncCommit != nil {
-		mmCommit.inspectFuncCommit()
+	expectation := &StorerMockPaymentByCartIDExpectation{
+		mock:   mmPaymentByCartID.mock,
+		params: &StorerMockPaymentByCartIDParams{ctx, cartID},
 	}
+	mmPaymentByCartID.expectations = append(mmPaymentByCartID.expectations, expectation)
+	return expectation
+}
 
-	if mmCommit.CommitMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmCommit.CommitMock.defaultExpectation.Counter, 1)
+// Then sets up storer.PaymentByCartID return parameters for the expectation previously defined by the When method
+func (e *StorerMockPaymentByCartIDExpectation) Then(pp1 *Payment, err error) *StorerMock {
+	e.results = &StorerMockPaymentByCartIDResults{pp1, err}
+	return e.mock
+}
 
-		mm_results := mmCommit.CommitMock.defaultExpectation.results
+// PaymentByCartID implements storer
+func (mmPaymentByCartID *StorerMock) PaymentByCartID(ctx context.Context, cartID int64) (pp1 *Payment, err error) {
+	mm_atomic.AddUint64(&mmPaymentByCartID.beforePaymentByCartIDCounter, 1)
+	defer mm_atomic.AddUint64(&mmPaymentByCartID.afterPaymentByCartIDCounter, 1)
+
+	if mmPaymentByCartID.inspectFuncPaymentByCartID != nil {
+		mmPaymentByCartID.inspectFuncPaymentByCartID(ctx, cartID)
+	}
+
+	mm_params := &StorerMockPaymentByCartIDParams{ctx, cartID}
+
+	// Record call args
+	mmPaymentByCartID.PaymentByCartIDMock.mutex.Lock()
+	mmPaymentByCartID.PaymentByCartIDMock.callArgs = append(mmPaymentByCartID.PaymentByCartIDMock.callArgs, mm_params)
+	mmPaymentByCartID.PaymentByCartIDMock.mutex.Unlock()
+
+	for _, e := range mmPaymentByCartID.PaymentByCartIDMock.expectations {
+		if minimock.Equal(e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.pp1, e.results.err
+		}
+	}
+
+	if mmPaymentByCartID.PaymentByCartIDMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPaymentByCartID.PaymentByCartIDMock.defaultExpectation.Counter, 1)
+		mm_want := mmPaymentByCartID.PaymentByCartIDMock.defaultExpectation.params
+		mm_got := StorerMockPaymentByCartIDParams{ctx, cartID}
+		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmPaymentByCartID.t.Errorf("StorerMock.PaymentByCartID got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmPaymentByCartID.PaymentByCartIDMock.defaultExpectation.results
 		if mm_results == nil {
-			mmCommit.t.Fatal("No results are set for the StorerMock.Commit")
+			mmPaymentByCartID.t.Fatal("No results are set for the StorerMock.PaymentByCartID")
 		}
-		return (*mm_results).err
+		return (*mm_results).pp1, (*mm_results).err
 	}
-	if mmCommit.funcCommit != nil {
-		return mmCommit.funcCommit()
+	if mmPaymentByCartID.funcPaymentByCartID != nil {
+		return mmPaymentByCartID.funcPaymentByCartID(ctx, cartID)
 	}
-	mmCommit.t.Fatalf("Unexpected call to StorerMock.Commit.")
+	mmPaymentByCartID.t.Fatalf("Unexpected call to StorerMock.PaymentByCartID. %v %v", ctx, cartID)
 	return
 }
 
-// CommitAfterCounter returns a count of finished StorerMock.Commit invocations
-func (mmCommit *StorerMock) CommitAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCommit.afterCommitCounter)
+// PaymentByCartIDAfterCounter returns a count of finished StorerMock.PaymentByCartID invocations
+func (mmPaymentByCartID *StorerMock) PaymentByCartIDAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPaymentByCartID.afterPaymentByCartIDCounter)
 }
 
-// CommitBeforeCounter returns a count of StorerMock.Commit invocations
-func (mmCommit *StorerMock) CommitBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmCommit.beforeCommitCounter)
+// PaymentByCartIDBeforeCounter returns a count of StorerMock.PaymentByCartID invocations
+func (mmPaymentByCartID *StorerMock) PaymentByCartIDBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPaymentByCartID.beforePaymentByCartIDCounter)
 }
 
-// MinimockCommitDone returns true if the count of the Commit invocations corresponds
+// Calls returns a list of arguments used in each call to StorerMock.PaymentByCartID.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmPaymentByCartID *mStorerMockPaymentByCartID) Calls() []*StorerMockPaymentByCartIDParams {
+	mmPaymentByCartID.mutex.RLock()
+
+	argCopy := make([]*StorerMockPaymentByCartIDParams, len(mmPaymentByCartID.callArgs))
+	copy(argCopy, mmPaymentByCartID.callArgs)
+
+	mmPaymentByCartID.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockPaymentByCartIDDone returns true if the count of the PaymentByCartID invocations corresponds
 // the number of defined expectations
-func (m *StorerMock) MinimockCommitDone() bool {
-	for _, e := range m.CommitMock.expectations {
+func (m *StorerMock) MinimockPaymentByCartIDDone() bool {
+	for _, e := range m.PaymentByCartIDMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CommitMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
+	if m.PaymentByCartIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPaymentByCartIDCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCommit != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
+	if m.funcPaymentByCartID != nil && mm_atomic.LoadUint64(&m.afterPaymentByCartIDCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockCommitInspect logs each unmet expectation
-func (m *StorerMock) MinimockCommitInspect() {
-	for _, e := range m.CommitMock.expectations {
+// MinimockPaymentByCartIDInspect logs each unmet expectation
+func (m *StorerMock) MinimockPaymentByCartIDInspect() {
+	for _, e := range m.PaymentByCartIDMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Error("Expected call to StorerMock.Commit")
+			m.t.Errorf("Expected call to StorerMock.PaymentByCartID with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.CommitMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.Commit")
+	if m.PaymentByCartIDMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPaymentByCartIDCounter) < 1 {
+		if m.PaymentByCartIDMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.PaymentByCartID")
+		} else {
+			m.t.Errorf("Expected call to StorerMock.PaymentByCartID with params: %#v", *m.PaymentByCartIDMock.defaultExpectation.params)
+		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcCommit != nil && mm_atomic.LoadUint64(&m.afterCommitCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.Commit")
+	if m.funcPaymentByCartID != nil && mm_atomic.LoadUint64(&m.afterPaymentByCartIDCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.PaymentByCartID")
 	}
 }
 
-type mStorerMockLineItemRemove struct {
+type mStorerMockPaymentCreate struct {
 	mock               *StorerMock
-	defaultExpectation *StorerMockLineItemRemoveExpectation
-	expectations       []*StorerMockLineItemRemoveExpectation
+	defaultExpectation *StorerMockPaymentCreateExpectation
+	expectations       []*StorerMockPaymentCreateExpectation
 
-	callArgs []*StorerMockLineItemRemoveParams
+	callArgs []*StorerMockPaymentCreateParams
 	mutex    sync.RWMutex
 }
 
-// StorerMockLineItemRemoveExpectation specifies expectation struct of the storer.LineItemRemove
-type StorerMockLineItemRemoveExpectation struct {
+// StorerMockPaymentCreateExpectation specifies expectation struct of the storer.PaymentCreate
+type StorerMockPaymentCreateExpectation struct {
 	mock    *StorerMock
-	params  *StorerMockLineItemRemoveParams
-	results *StorerMockLineItemRemoveResults
+	params  *StorerMockPaymentCreateParams
+	results *StorerMockPaymentCreateResults
 	Counter uint64
 }
 
-// StorerMockLineItemRemoveParams contains parameters of the storer.LineItemRemove
-type StorerMockLineItemRemoveParams struct {
-	ctx    context.Context
-	cartID int64
-	itemID int64
+// StorerMockPaymentCreateParams contains parameters of the storer.PaymentCreate
+type StorerMockPaymentCreateParams struct {
+	ctx context.Context
+	p   *Payment
 }
 
-// StorerMockLineItemRemoveResults contains results of the storer.LineItemRemove
-type StorerMockLineItemRemoveResults struct {
+// StorerMockPaymentCreateResults contains results of the storer.PaymentCreate
+type StorerMockPaymentCreateResults struct {
 	err error
 }
 
-// Expect sets up expected params for storer.LineItemRemove
-func (mmLineItemRemove *mStorerMockLineItemRemove) Expect(ctx context.Context, cartID int64, itemID int64) *mStorerMockLineItemRemove {
-	if mmLineItemRemove.mock.funcLineItemRemove != nil {
-		mmLineItemRemove.mock.t.Fatalf("StorerMock.LineItemRemove mock is already set by Set")
+// Expect sets up expected params for storer.PaymentCreate
+func (mmPaymentCreate *mStorerMockPaymentCreate) Expect(ctx context.Context, p *Payment) *mStorerMockPaymentCreate {
+	if mmPaymentCreate.mock.funcPaymentCreate != nil {
+		mmPaymentCreate.mock.t.Fatalf("StorerMock.PaymentCreate mock is already set by Set")
 	}
 
-	if mmLineItemRemove.defaultExpectation == nil {
-		mmLineItemRemove.defaultExpectation = &StorerMockLineItemRemoveExpectation{}
+	if mmPaymentCreate.defaultExpectation == nil {
+		mmPaymentCreate.defaultExpectation = &StorerMockPaymentCreateExpectation{}
 	}
 
-	mmLineItemRemove.defaultExpectation.params = &StorerMockLineItemRemoveParams{ctx, cartID, itemID}
-	for _, e := range mmLineItemRemove.expectations {
-		if minimock.Equal(e.params, mmLineItemRemove.defaultExpectation.params) {
-			mmLineItemRemove.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmLineItemRemove.defaultExpectation.params)
+	mmPaymentCreate.defaultExpectation.params = &StorerMockPaymentCreateParams{ctx, p}
+	for _, e := range mmPaymentCreate.expectations {
+		if minimock.Equal(e.params, mmPaymentCreate.defaultExpectation.params) {
+			mmPaymentCreate.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPaymentCreate.defaultExpectation.params)
 		}
 	}
 
-	return mmLineItemRemove
+	return mmPaymentCreate
 }
 
-// Inspect accepts an inspector function that has same arguments as the storer.LineItemRemove
-func (mmLineItemRemove *mStorerMockLineItemRemove) Inspect(f func(ctx context.Context, cartID int64, itemID int64)) *mStorerMockLineItemRemove {
-	if mmLineItemRemove.mock.inspectFuncLineItemRemove != nil {
-		mmLineItemRemove.mock.t.Fatalf("Inspect function is already set for StorerMock.LineItemRemove")
+// Inspect accepts an inspector function that has same arguments as the storer.PaymentCreate
+func (mmPaymentCreate *mStorerMockPaymentCreate) Inspect(f func(ctx context.Context, p *Payment)) *mStorerMockPaymentCreate {
+	if mmPaymentCreate.mock.inspectFuncPaymentCreate != nil {
+		mmPaymentCreate.mock.t.Fatalf("Inspect function is already set for StorerMock.PaymentCreate")
 	}
 
-	mmLineItemRemove.mock.inspectFuncLineItemRemove = f
+	mmPaymentCreate.mock.inspectFuncPaymentCreate = f
 
-	return mmLineItemRemove
+	return mmPaymentCreate
 }
 
-// Return sets up results that will be returned by storer.LineItemRemove
-func (mmLineItemRemove *mStorerMockLineItemRemove) Return(err error) *StorerMock {
-	if mmLineItemRemove.mock.funcLineItemRemove != nil {
-		mmLineItemRemove.mock.t.Fatalf("StorerMock.LineItemRemove mock is already set by Set")
+// Return sets up results that will be returned by storer.PaymentCreate
+func (mmPaymentCreate *mStorerMockPaymentCreate) Return(err error) *StorerMock {
+	if mmPaymentCreate.mock.funcPaymentCreate != nil {
+		mmPaymentCreate.mock.t.Fatalf("StorerMock.PaymentCreate mock is already set by Set")
 	}
 
-	if mmLineItemRemove.defaultExpectation == nil {
-		mmLineItemRemove.defaultExpectation = &StorerMockLineItemRemoveExpectation{mock: mmLineItemRemove.mock}
+	if mmPaymentCreate.defaultExpectation == nil {
+		mmPaymentCreate.defaultExpectation = &StorerMockPaymentCreateExpectation{mock: mmPaymentCreate.mock}
 	}
-	mmLineItemRemove.defaultExpectation.results = &StorerMockLineItemRemoveResults{err}
-	return mmLineItemRemove.mock
+	mmPaymentCreate.defaultExpectation.results = &StorerMockPaymentCreateResults{err}
+	return mmPaymentCreate.mock
 }
 
-//Set uses given function f to mock the storer.LineItemRemove method
-func (mmLineItemRemove *mStorerMockLineItemRemove) Set(f func(ctx context.Context, cartID int64, itemID int64) (err error)) *StorerMock {
-	if mmLineItemRemove.defaultExpectation != nil {
-		mmLineItemRemove.mock.t.Fatalf("Default expectation is already set for the storer.LineItemRemove method")
+//Set uses given function f to mock the storer.PaymentCreate method
+func (mmPaymentCreate *mStorerMockPaymentCreate) Set(f func(ctx context.Context, p *Payment) (err error)) *StorerMock {
+	if mmPaymentCreate.defaultExpectation != nil {
+		mmPaymentCreate.mock.t.Fatalf("Default expectation is already set for the storer.PaymentCreate method")
 	}
 
-	if len(mmLineItemRemove.expectations) > 0 {
-		mmLineItemRemove.mock.t.Fatalf("Some expectations are already set for the storer.LineItemRemove method")
+	if len(mmPaymentCreate.expectations) > 0 {
+		mmPaymentCreate.mock.t.Fatalf("Some expectations are already set for the storer.PaymentCreate method")
 	}
 
-	mmLineItemRemove.mock.funcLineItemRemove = f
-	return mmLineItemRemove.mock
+	mmPaymentCreate.mock.funcPaymentCreate = f
+	return mmPaymentCreate.mock
 }
 
-// When sets expectation for the storer.LineItemRemove which will trigger the result defined by the following
+// When sets expectation for the storer.PaymentCreate which will trigger the result defined by the following
 // Then helper
-func (mmLineItemRemove *mStorerMockLineItemRemove) When(ctx context.Context, cartID int64, itemID int64) *StorerMockLineItemRemoveExpectation {
-	if mmLineItemRemove.mock.funcLineItemRemove != nil {
-		mmLineItemRemove.mock.t.Fatalf("StorerMock.LineItemRemove mock is already set by Set")
+func (mmPaymentCreate *mStorerMockPaymentCreate) When(ctx context.Context, p *Payment) *StorerMockPaymentCreateExpectation {
+	if mmPaymentCreate.mock.funcPaymentCreate != nil {
+		mmPaymentCreate.mock.t.Fatalf("StorerMock.PaymentCreate mock is already set by Set")
 	}
 
-	expectation := &StorerMockLineItemRemoveExpectation{
-		mock:   mmLineItemRemove.mock,
-		params: &StorerMockLineItemRemoveParams{ctx, cartID, itemID},
+	expectation := &StorerMockPaymentCreateExpectation{
+		mock:   mmPaymentCreate.mock,
+		params: &StorerMockPaymentCreateParams{ctx, p},
 	}
-	mmLineItemRemove.expectations = append(mmLineItemRemove.expectations, expectation)
+	mmPaymentCreate.expectations = append(mmPaymentCreate.expectations, expectation)
 	return expectation
 }
 
-// Then sets up storer.LineItemRemove return parameters for the expectation previously defined by the When method
-func (e *StorerMockLineItemRemoveExpectation) Then(err error) *StorerMock {
-	e.results = &StorerMockLineItemRemoveResults{err}
+// Then sets up storer.PaymentCreate return parameters for the expectation previously defined by the When method
+func (e *StorerMockPaymentCreateExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockPaymentCreateResults{err}
 	return e.mock
 }
 
-// LineItemRemove implements storer
-func (mmLineItemRemove *StorerMock) LineItemRemove(ctx context.Context, cartID int64, itemID int64) (err error) {
-	mm_atomic.AddUint64(&mmLineItemRemove.beforeLineItemRemoveCounter, 1)
-	defer mm_atomic.AddUint64(&mmLineItemRemove.afterLineItemRemoveCounter, 1)
+// PaymentCreate implements storer
+func (mmPaymentCreate *StorerMock) PaymentCreate(ctx context.Context, p *Payment) (err error) {
+	mm_atomic.AddUint64(&mmPaymentCreate.beforePaymentCreateCounter, 1)
+	defer mm_atomic.AddUint64(&mmPaymentCreate.afterPaymentCreateCounter, 1)
 
-	if mmLineItemRemove.inspectFuncLineItemRemove != nil {
-		mmLineItemRemove.inspectFuncLineItemRemove(ctx, cartID, itemID)
+	if mmPaymentCreate.inspectFuncPaymentCreate != nil {
+		mmPaymentCreate.inspectFuncPaymentCreate(ctx, p)
 	}
 
-	mm_params := &StorerMockLineItemRemoveParams{ctx, cartID, itemID}
+	mm_params := &StorerMockPaymentCreateParams{ctx, p}
 
 	// Record call args
-	mmLineItemRemove.LineItemRemoveMock.mutex.Lock()
-	mmLineItemRemove.LineItemRemoveMock.callArgs = append(mmLineItemRemove.LineItemRemoveMock.callArgs, mm_params)
-	mmLineItemRemove.LineItemRemoveMock.mutex.Unlock()
+	mmPaymentCreate.PaymentCreateMock.mutex.Lock()
+	mmPaymentCreate.PaymentCreateMock.callArgs = append(mmPaymentCreate.PaymentCreateMock.callArgs, mm_params)
+	mmPaymentCreate.PaymentCreateMock.mutex.Unlock()
 
-	for _, e := range mmLineItemRemove.LineItemRemoveMock.expectations {
+	for _, e := range mmPaymentCreate.PaymentCreateMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.err
 		}
 	}
 
-	if mmLineItemRemove.LineItemRemoveMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmLineItemRemove.LineItemRemoveMock.defaultExpectation.Counter, 1)
-		mm_want := mmLineItemRemove.LineItemRemoveMock.defaultExpectation.params
-		mm_got := StorerMockLineItemRemoveParams{ctx, cartID, itemID}
+	if mmPaymentCreate.PaymentCreateMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPaymentCreate.PaymentCreateMock.defaultExpectation.Counter, 1)
+		mm_want := mmPaymentCreate.PaymentCreateMock.defaultExpectation.params
+		mm_got := StorerMockPaymentCreateParams{ctx, p}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmLineItemRemove.t.Errorf("StorerMock.LineItemRemove got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmPaymentCreate.t.Errorf("StorerMock.PaymentCreate got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmLineItemRemove.LineItemRemoveMock.defaultExpectation.results
+		mm_results := mmPaymentCreate.PaymentCreateMock.defaultExpectation.results
 		if mm_results == nil {
-			mmLineItemRemove.t.Fatal("No results are set for the StorerMock.LineItemRemove")
+			mmPaymentCreate.t.Fatal("No results are set for the StorerMock.PaymentCreate")
 		}
 		return (*mm_results).err
 	}
-	if mmLineItemRemove.funcLineItemRemove != nil {
-		return mmLineItemRemove.funcLineItemRemove(ctx, cartID, itemID)
+	if mmPaymentCreate.funcPaymentCreate != nil {
+		return mmPaymentCreate.funcPaymentCreate(ctx, p)
 	}
-	mmLineItemRemove.t.Fatalf("Unexpected call to StorerMock.LineItemRemove. %v %v %v", ctx, cartID, itemID)
+	mmPaymentCreate.t.Fatalf("Unexpected call to StorerMock.PaymentCreate. %v %v", ctx, p)
 	return
 }
 
-// LineItemRemoveAfterCounter returns a count of finished StorerMock.LineItemRemove invocations
-func (mmLineItemRemove *StorerMock) LineItemRemoveAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmLineItemRemove.afterLineItemRemoveCounter)
+// PaymentCreateAfterCounter returns a count of finished StorerMock.PaymentCreate invocations
+func (mmPaymentCreate *StorerMock) PaymentCreateAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPaymentCreate.afterPaymentCreateCounter)
 }
 
-// LineItemRemoveBeforeCounter returns a count of StorerMock.LineItemRemove invocations
-func (mmLineItemRemove *StorerMock) LineItemRemoveBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmLineItemRemove.beforeLineItemRemoveCounter)
+// PaymentCreateBeforeCounter returns a count of StorerMock.PaymentCreate invocations
+func (mmPaymentCreate *StorerMock) PaymentCreateBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPaymentCreate.beforePaymentCreateCounter)
 }
 
-// Calls returns a list of arguments used in each call to StorerMock.LineItemRemove.
+// Calls returns a list of arguments used in each call to StorerMock.PaymentCreate.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmLineItemRemove *mStorerMockLineItemRemove) Calls() []*StorerMockLineItemRemoveParams {
-	mmLineItemRemove.mutex.RLock()
+func (mmPaymentCreate *mStorerMockPaymentCreate) Calls() []*StorerMockPaymentCreateParams {
+	mmPaymentCreate.mutex.RLock()
 
-	argCopy := make([]*StorerMockLineItemRemoveParams, len(mmLineItemRemove.callArgs))
-	copy(argCopy, mmLineItemRemove.callArgs)
+	argCopy := make([]*StorerMockPaymentCreateParams, len(mmPaymentCreate.callArgs))
+	copy(argCopy, mmPaymentCreate.callArgs)
 
-	mmLineItemRemove.mutex.RUnlock()
+	mmPaymentCreate.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockLineItemRemoveDone returns true if the count of the LineItemRemove invocations corresponds
+// MinimockPaymentCreateDone returns true if the count of the PaymentCreate invocations corresponds
 // the number of defined expectations
-func (m *StorerMock) MinimockLineItemRemoveDone() bool {
-	for _, e := range m.LineItemRemoveMock.expectations {
+func (m *StorerMock) MinimockPaymentCreateDone() bool {
+	for _, e := range m.PaymentCreateMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.LineItemRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
+	if m.PaymentCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPaymentCreateCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcLineItemRemove != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
+	if m.funcPaymentCreate != nil && mm_atomic.LoadUint64(&m.afterPaymentCreateCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockLineItemRemoveInspect logs each unmet expectation
-func (m *StorerMock) MinimockLineItemRemoveInspect() {
-	for _, e := range m.LineItemRemoveMock.expectations {
+// MinimockPaymentCreateInspect logs each unmet expectation
+func (m *StorerMock) MinimockPaymentCreateInspect() {
+	for _, e := range m.PaymentCreateMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to StorerMock.LineItemRemove with params: %#v", *e.params)
+			m.t.Errorf("Expected call to StorerMock.PaymentCreate with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.LineItemRemoveMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
-		if m.LineItemRemoveMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to StorerMock.LineItemRemove")
+	if m.PaymentCreateMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPaymentCreateCounter) < 1 {
+		if m.PaymentCreateMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.PaymentCreate")
 		} else {
-			m.t.Errorf("Expected call to StorerMock.LineItemRemove with params: %#v", *m.LineItemRemoveMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to StorerMock.PaymentCreate with params: %#v", *m.PaymentCreateMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcLineItemRemove != nil && mm_atomic.LoadUint64(&m.afterLineItemRemoveCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.LineItemRemove")
+	if m.funcPaymentCreate != nil && mm_atomic.LoadUint64(&m.afterPaymentCreateCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.PaymentCreate")
 	}
 }
 
-type mStorerMockLineItemsUpsert struct {
+type mStorerMockPaymentStatusSet struct {
 	mock               *StorerMock
-	defaultExpectation *StorerMockLineItemsUpsertExpectation
-	expectations       []*StorerMockLineItemsUpsertExpectation
+	defaultExpectation *StorerMockPaymentStatusSetExpectation
+	expectations       []*StorerMockPaymentStatusSetExpectation
 
-	callArgs []*StorerMockLineItemsUpsertParams
+	callArgs []*StorerMockPaymentStatusSetParams
 	mutex    sync.RWMutex
 }
 
-// StorerMockLineItemsUpsertExpectation specifies expectation struct of the storer.LineItemsUpsert
-type StorerMockLineItemsUpsertExpectation struct {
+// StorerMockPaymentStatusSetExpectation specifies expectation struct of the storer.PaymentStatusSet
+type StorerMockPaymentStatusSetExpectation struct {
 	mock    *StorerMock
-	params  *StorerMockLineItemsUpsertParams
-	results *StorerMockLineItemsUpsertResults
+	params  *StorerMockPaymentStatusSetParams
+	results *StorerMockPaymentStatusSetResults
 	Counter uint64
 }
 
-// StorerMockLineItemsUpsertParams contains parameters of the storer.LineItemsUpsert
-type StorerMockLineItemsUpsertParams struct {
-	ctx    context.Context
-	cartID int64
-	items  []*LineItem
+// StorerMockPaymentStatusSetParams contains parameters of the storer.PaymentStatusSet
+type StorerMockPaymentStatusSetParams struct {
+	ctx       context.Context
+	paymentID int64
+	status    PaymentStatus
 }
 
-// StorerMockLineItemsUpsertResults contains results of the storer.LineItemsUpsert
-type StorerMockLineItemsUpsertResults struct {
+// StorerMockPaymentStatusSetResults contains results of the storer.PaymentStatusSet
+type StorerMockPaymentStatusSetResults struct {
 	err error
 }
 
-// Expect sets up expected params for storer.LineItemsUpsert
-func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Expect(ctx context.Context, cartID int64, items ...*LineItem) *mStorerMockLineItemsUpsert {
-	if mmLineItemsUpsert.mock.funcLineItemsUpsert != nil {
-		mmLineItemsUpsert.mock.t.Fatalf("StorerMock.LineItemsUpsert mock is already set by Set")
+// Expect sets up expected params for storer.PaymentStatusSet
+func (mmPaymentStatusSet *mStorerMockPaymentStatusSet) Expect(ctx context.Context, paymentID int64, status PaymentStatus) *mStorerMockPaymentStatusSet {
+	if mmPaymentStatusSet.mock.funcPaymentStatusSet != nil {
+		mmPaymentStatusSet.mock.t.Fatalf("StorerMock.PaymentStatusSet mock is already set by Set")
 	}
 
-	if mmLineItemsUpsert.defaultExpectation == nil {
-		mmLineItemsUpsert.defaultExpectation = &StorerMockLineItemsUpsertExpectation{}
+	if mmPaymentStatusSet.defaultExpectation == nil {
+		mmPaymentStatusSet.defaultExpectation = &StorerMockPaymentStatusSetExpectation{}
 	}
 
-	mmLineItemsUpsert.defaultExpectation.params = &StorerMockLineItemsUpsertParams{ctx, cartID, items}
-	for _, e := range mmLineItemsUpsert.expectations {
-		if minimock.Equal(e.params, mmLineItemsUpsert.defaultExpectation.params) {
-			mmLineItemsUpsert.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmLineItemsUpsert.defaultExpectation.params)
+	mmPaymentStatusSet.defaultExpectation.params = &StorerMockPaymentStatusSetParams{ctx, paymentID, status}
+	for _, e := range mmPaymentStatusSet.expectations {
+		if minimock.Equal(e.params, mmPaymentStatusSet.defaultExpectation.params) {
+			mmPaymentStatusSet.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmPaymentStatusSet.defaultExpectation.params)
 		}
 	}
 
-	return mmLineItemsUpsert
+	return mmPaymentStatusSet
 }
 
-// Inspect accepts an inspector function that has same arguments as the storer.LineItemsUpsert
-func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Inspect(f func(ctx context.Context, cartID int64, items ...*LineItem)) *mStorerMockLineItemsUpsert {
-	if mmLineItemsUpsert.mock.inspectFuncLineItemsUpsert != nil {
-		mmLineItemsUpsert.mock.t.Fatalf("Inspect function is already set for StorerMock.LineItemsUpsert")
+// Inspect accepts an inspector function that has same arguments as the storer.PaymentStatusSet
+func (mmPaymentStatusSet *mStorerMockPaymentStatusSet) Inspect(f func(ctx context.Context, paymentID int64, status PaymentStatus)) *mStorerMockPaymentStatusSet {
+	if mmPaymentStatusSet.mock.inspectFuncPaymentStatusSet != nil {
+		mmPaymentStatusSet.mock.t.Fatalf("Inspect function is already set for StorerMock.PaymentStatusSet")
 	}
 
-	mmLineItemsUpsert.mock.inspectFuncLineItemsUpsert = f
+	mmPaymentStatusSet.mock.inspectFuncPaymentStatusSet = f
 
-	return mmLineItemsUpsert
+	return mmPaymentStatusSet
 }
 
-// Return sets up results that will be returned by storer.LineItemsUpsert
-func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Return(err error) *StorerMock {
-	if mmLineItemsUpsert.mock.funcLineItemsUpsert != nil {
-		mmLineItemsUpsert.mock.t.Fatalf("StorerMock.LineItemsUpsert mock is already set by Set")
+// Return sets up results that will be returned by storer.PaymentStatusSet
+func (mmPaymentStatusSet *mStorerMockPaymentStatusSet) Return(err error) *StorerMock {
+	if mmPaymentStatusSet.mock.funcPaymentStatusSet != nil {
+		mmPaymentStatusSet.mock.t.Fatalf("StorerMock.PaymentStatusSet mock is already set by Set")
 	}
 
-	if mmLineItemsUpsert.defaultExpectation == nil {
-		mmLineItemsUpsert.defaultExpectation = &StorerMockLineItemsUpsertExpectation{mock: mmLineItemsUpsert.mock}
+	if mmPaymentStatusSet.defaultExpectation == nil {
+		mmPaymentStatusSet.defaultExpectation = &StorerMockPaymentStatusSetExpectation{mock: mmPaymentStatusSet.mock}
 	}
-	mmLineItemsUpsert.defaultExpectation.results = &StorerMockLineItemsUpsertResults{err}
-	return mmLineItemsUpsert.mock
+	mmPaymentStatusSet.defaultExpectation.results = &StorerMockPaymentStatusSetResults{err}
+	return mmPaymentStatusSet.mock
 }
 
-//Set uses given function f to mock the storer.LineItemsUpsert method
-func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Set(f func(ctx context.Context, cartID int64, items ...*LineItem) (err error)) *StorerMock {
-	if mmLineItemsUpsert.defaultExpectation != nil {
-		mmLineItemsUpsert.mock.t.Fatalf("Default expectation is already set for the storer.LineItemsUpsert method")
+//Set uses given function f to mock the storer.PaymentStatusSet method
+func (mmPaymentStatusSet *mStorerMockPaymentStatusSet) Set(f func(ctx context.Context, paymentID int64, status PaymentStatus) (err error)) *StorerMock {
+	if mmPaymentStatusSet.defaultExpectation != nil {
+		mmPaymentStatusSet.mock.t.Fatalf("Default expectation is already set for the storer.PaymentStatusSet method")
 	}
 
-	if len(mmLineItemsUpsert.expectations) > 0 {
-		mmLineItemsUpsert.mock.t.Fatalf("Some expectations are already set for the storer.LineItemsUpsert method")
+	if len(mmPaymentStatusSet.expectations) > 0 {
+		mmPaymentStatusSet.mock.t.Fatalf("Some expectations are already set for the storer.PaymentStatusSet method")
 	}
 
-	mmLineItemsUpsert.mock.funcLineItemsUpsert = f
-	return mmLineItemsUpsert.mock
+	mmPaymentStatusSet.mock.funcPaymentStatusSet = f
+	return mmPaymentStatusSet.mock
 }
 
-// When sets expectation for the storer.LineItemsUpsert which will trigger the result defined by the following
+// When sets expectation for the storer.PaymentStatusSet which will trigger the result defined by the following
 // Then helper
-func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) When(ctx context.Context, cartID int64, items ...*LineItem) *StorerMockLineItemsUpsertExpectation {
-	if mmLineItemsUpsert.mock.funcLineItemsUpsert != nil {
-		mmLineItemsUpsert.mock.t.Fatalf("StorerMock.LineItemsUpsert mock is already set by Set")
+func (mmPaymentStatusSet *mStorerMockPaymentStatusSet) When(ctx context.Context, paymentID int64, status PaymentStatus) *StorerMockPaymentStatusSetExpectation {
+	if mmPaymentStatusSet.mock.funcPaymentStatusSet != nil {
+		mmPaymentStatusSet.mock.t.Fatalf("StorerMock.PaymentStatusSet mock is already set by Set")
 	}
 
-	expectation := &StorerMockLineItemsUpsertExpectation{
-		mock:   mmLineItemsUpsert.mock,
-		params: &StorerMockLineItemsUpsertParams{ctx, cartID, items},
+	expectation := &StorerMockPaymentStatusSetExpectation{
+		mock:   mmPaymentStatusSet.mock,
+		params: &StorerMockPaymentStatusSetParams{ctx, paymentID, status},
 	}
-	mmLineItemsUpsert.expectations = append(mmLineItemsUpsert.expectations, expectation)
+	mmPaymentStatusSet.expectations = append(mmPaymentStatusSet.expectations, expectation)
 	return expectation
 }
 
-// Then sets up storer.LineItemsUpsert return parameters for the expectation previously defined by the When method
-func (e *StorerMockLineItemsUpsertExpectation) Then(err error) *StorerMock {
-	e.results = &StorerMockLineItemsUpsertResults{err}
+// Then sets up storer.PaymentStatusSet return parameters for the expectation previously defined by the When method
+func (e *StorerMockPaymentStatusSetExpectation) Then(err error) *StorerMock {
+	e.results = &StorerMockPaymentStatusSetResults{err}
 	return e.mock
 }
 
-// LineItemsUpsert implements storer
-func (mmLineItemsUpsert *StorerMock) LineItemsUpsert(ctx context.Context, cartID int64, items ...*LineItem) (err error) {
-	mm_atomic.AddUint64(&mmLineItemsUpsert.beforeLineItemsUpsertCounter, 1)
-	defer mm_atomic.AddUint64(&mmLineItemsUpsert.afterLineItemsUpsertCounter, 1)
+// PaymentStatusSet implements storer
+func (mmPaymentStatusSet *StorerMock) PaymentStatusSet(ctx context.Context, paymentID int64, status PaymentStatus) (err error) {
+	mm_atomic.AddUint64(&mmPaymentStatusSet.beforePaymentStatusSetCounter, 1)
+	defer mm_atomic.AddUint64(&mmPaymentStatusSet.afterPaymentStatusSetCounter, 1)
 
-	if mmLineItemsUpsert.inspectFuncLineItemsUpsert != nil {
-		mmLineItemsUpsert.inspectFuncLineItemsUpsert(ctx, cartID, items...)
+	if mmPaymentStatusSet.inspectFuncPaymentStatusSet != nil {
+		mmPaymentStatusSet.inspectFuncPaymentStatusSet(ctx, paymentID, status)
 	}
 
-	mm_params := &StorerMockLineItemsUpsertParams{ctx, cartID, items}
+	mm_params := &StorerMockPaymentStatusSetParams{ctx, paymentID, status}
 
 	// Record call args
-	mmLineItemsUpsert.LineItemsUpsertMock.mutex.Lock()
-	mmLineItemsUpsert.LineItemsUpsertMock.callArgs = append(mmLineItemsUpsert.LineItemsUpsertMock.callArgs, mm_params)
-	mmLineItemsUpsert.LineItemsUpsertMock.mutex.Unlock()
+	mmPaymentStatusSet.PaymentStatusSetMock.mutex.Lock()
+	mmPaymentStatusSet.PaymentStatusSetMock.callArgs = append(mmPaymentStatusSet.PaymentStatusSetMock.callArgs, mm_params)
+	mmPaymentStatusSet.PaymentStatusSetMock.mutex.Unlock()
 
-	for _, e := range mmLineItemsUpsert.LineItemsUpsertMock.expectations {
+	for _, e := range mmPaymentStatusSet.PaymentStatusSetMock.expectations {
 		if minimock.Equal(e.params, mm_params) {
 			mm_atomic.AddUint64(&e.Counter, 1)
 			return e.results.err
 		}
 	}
 
-	if mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation != nil {
-		mm_atomic.AddUint64(&mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation.Counter, 1)
-		mm_want := mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation.params
-		mm_got := StorerMockLineItemsUpsertParams{ctx, cartID, items}
+	if mmPaymentStatusSet.PaymentStatusSetMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmPaymentStatusSet.PaymentStatusSetMock.defaultExpectation.Counter, 1)
+		mm_want := mmPaymentStatusSet.PaymentStatusSetMock.defaultExpectation.params
+		mm_got := StorerMockPaymentStatusSetParams{ctx, paymentID, status}
 		if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
-			mmLineItemsUpsert.t.Errorf("StorerMock.LineItemsUpsert got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+			mmPaymentStatusSet.t.Errorf("StorerMock.PaymentStatusSet got unexpected parameters, want: %#v, got: %#v%s\n", *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
 		}
 
-		mm_results := mmLineItemsUpsert.LineItemsUpsertMock.defaultExpectation.results
+		mm_results := mmPaymentStatusSet.PaymentStatusSetMock.defaultExpectation.results
 		if mm_results == nil {
-			mmLineItemsUpsert.t.Fatal("No results are set for the StorerMock.LineItemsUpsert")
+			mmPaymentStatusSet.t.Fatal("No results are set for the StorerMock.PaymentStatusSet")
 		}
 		return (*mm_results).err
 	}
-	if mmLineItemsUpsert.funcLineItemsUpsert != nil {
-		return mmLineItemsUpsert.funcLineItemsUpsert(ctx, cartID, items...)
+	if mmPaymentStatusSet.funcPaymentStatusSet != nil {
+		return mmPaymentStatusSet.funcPaymentStatusSet(ctx, paymentID, status)
 	}
-	mmLineItemsUpsert.t.Fatalf("Unexpected call to StorerMock.LineItemsUpsert. %v %v %v", ctx, cartID, items)
+	mmPaymentStatusSet.t.Fatalf("Unexpected call to StorerMock.PaymentStatusSet. %v %v %v", ctx, paymentID, status)
 	return
 }
 
-// LineItemsUpsertAfterCounter returns a count of finished StorerMock.LineItemsUpsert invocations
-func (mmLineItemsUpsert *StorerMock) LineItemsUpsertAfterCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmLineItemsUpsert.afterLineItemsUpsertCounter)
+// PaymentStatusSetAfterCounter returns a count of finished StorerMock.PaymentStatusSet invocations
+func (mmPaymentStatusSet *StorerMock) PaymentStatusSetAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPaymentStatusSet.afterPaymentStatusSetCounter)
 }
 
-// LineItemsUpsertBeforeCounter returns a count of StorerMock.LineItemsUpsert invocations
-func (mmLineItemsUpsert *StorerMock) LineItemsUpsertBeforeCounter() uint64 {
-	return mm_atomic.LoadUint64(&mmLineItemsUpsert.beforeLineItemsUpsertCounter)
+// PaymentStatusSetBeforeCounter returns a count of StorerMock.PaymentStatusSet invocations
+func (mmPaymentStatusSet *StorerMock) PaymentStatusSetBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmPaymentStatusSet.beforePaymentStatusSetCounter)
 }
 
-// Calls returns a list of arguments used in each call to StorerMock.LineItemsUpsert.
+// Calls returns a list of arguments used in each call to StorerMock.PaymentStatusSet.
 // The list is in the same order as the calls were made (i.e. recent calls have a higher index)
-func (mmLineItemsUpsert *mStorerMockLineItemsUpsert) Calls() []*StorerMockLineItemsUpsertParams {
-	mmLineItemsUpsert.mutex.RLock()
+func (mmPaymentStatusSet *mStorerMockPaymentStatusSet) Calls() []*StorerMockPaymentStatusSetParams {
+	mmPaymentStatusSet.mutex.RLock()
 
-	argCopy := make([]*StorerMockLineItemsUpsertParams, len(mmLineItemsUpsert.callArgs))
-	copy(argCopy, mmLineItemsUpsert.callArgs)
+	argCopy := make([]*StorerMockPaymentStatusSetParams, len(mmPaymentStatusSet.callArgs))
+	copy(argCopy, mmPaymentStatusSet.callArgs)
 
-	mmLineItemsUpsert.mutex.RUnlock()
+	mmPaymentStatusSet.mutex.RUnlock()
 
 	return argCopy
 }
 
-// MinimockLineItemsUpsertDone returns true if the count of the LineItemsUpsert invocations corresponds
+// MinimockPaymentStatusSetDone returns true if the count of the PaymentStatusSet invocations corresponds
 // the number of defined expectations
-func (m *StorerMock) MinimockLineItemsUpsertDone() bool {
-	for _, e := range m.LineItemsUpsertMock.expectations {
+func (m *StorerMock) MinimockPaymentStatusSetDone() bool {
+	for _, e := range m.PaymentStatusSetMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
 			return false
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.LineItemsUpsertMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
+	if m.PaymentStatusSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPaymentStatusSetCounter) < 1 {
 		return false
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcLineItemsUpsert != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
+	if m.funcPaymentStatusSet != nil && mm_atomic.LoadUint64(&m.afterPaymentStatusSetCounter) < 1 {
 		return false
 	}
 	return true
 }
 
-// MinimockLineItemsUpsertInspect logs each unmet expectation
-func (m *StorerMock) MinimockLineItemsUpsertInspect() {
-	for _, e := range m.LineItemsUpsertMock.expectations {
+// MinimockPaymentStatusSetInspect logs each unmet expectation
+func (m *StorerMock) MinimockPaymentStatusSetInspect() {
+	for _, e := range m.PaymentStatusSetMock.expectations {
 		if mm_atomic.LoadUint64(&e.Counter) < 1 {
-			m.t.Errorf("Expected call to StorerMock.LineItemsUpsert with params: %#v", *e.params)
+			m.t.Errorf("Expected call to StorerMock.PaymentStatusSet with params: %#v", *e.params)
 		}
 	}
 
 	// if default expectation was set then invocations count should be greater than zero
-	if m.LineItemsUpsertMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
-		if m.LineItemsUpsertMock.defaultExpectation.params == nil {
-			m.t.Error("Expected call to StorerMock.LineItemsUpsert")
+	if m.PaymentStatusSetMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterPaymentStatusSetCounter) < 1 {
+		if m.PaymentStatusSetMock.defaultExpectation.params == nil {
+			m.t.Error("Expected call to StorerMock.PaymentStatusSet")
 		} else {
-			m.t.Errorf("Expected call to StorerMock.LineItemsUpsert with params: %#v", *m.LineItemsUpsertMock.defaultExpectation.params)
+			m.t.Errorf("Expected call to StorerMock.PaymentStatusSet with params: %#v", *m.PaymentStatusSetMock.defaultExpectation.params)
 		}
 	}
 	// if func was set then invocations count should be greater than zero
-	if m.funcLineItemsUpsert != nil && mm_atomic.LoadUint64(&m.afterLineItemsUpsertCounter) < 1 {
-		m.t.Error("Expected call to StorerMock.LineItemsUpsert")
+	if m.funcPaymentStatusSet != nil && mm_atomic.LoadUint64(&m.afterPaymentStatusSetCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.PaymentStatusSet")
+	}
+}
+
+type mStorerMockRollback struct {
+	mock               *StorerMock
+	defaultExpectation *StorerMockRollbackExpectation
+	expectations       []*StorerMockRollbackExpectation
+}
+
+// StorerMockRollbackExpectation specifies expectation struct of the storer.Rollback
+type StorerMockRollbackExpectation struct {
+	mock *StorerMock
+
+	results *StorerMockRollbackResults
+	Counter uint64
+}
+
+// StorerMockRollbackResults contains results of the storer.Rollback
+type StorerMockRollbackResults struct {
+	err error
+}
+
+// Expect sets up expected params for storer.Rollback
+func (mmRollback *mStorerMockRollback) Expect() *mStorerMockRollback {
+	if mmRollback.mock.funcRollback != nil {
+		mmRollback.mock.t.Fatalf("StorerMock.Rollback mock is already set by Set")
+	}
+
+	if mmRollback.defaultExpectation == nil {
+		mmRollback.defaultExpectation = &StorerMockRollbackExpectation{}
+	}
+
+	return mmRollback
+}
+
+// Inspect accepts an inspector function that has same arguments as the storer.Rollback
+func (mmRollback *mStorerMockRollback) Inspect(f func()) *mStorerMockRollback {
+	if mmRollback.mock.inspectFuncRollback != nil {
+		mmRollback.mock.t.Fatalf("Inspect function is already set for StorerMock.Rollback")
+	}
+
+	mmRollback.mock.inspectFuncRollback = f
+
+	return mmRollback
+}
+
+// Return sets up results that will be returned by storer.Rollback
+func (mmRollback *mStorerMockRollback) Return(err error) *StorerMock {
+	if mmRollback.mock.funcRollback != nil {
+		mmRollback.mock.t.Fatalf("StorerMock.Rollback mock is already set by Set")
+	}
+
+	if mmRollback.defaultExpectation == nil {
+		mmRollback.defaultExpectation = &StorerMockRollbackExpectation{mock: mmRollback.mock}
+	}
+	mmRollback.defaultExpectation.results = &StorerMockRollbackResults{err}
+	return mmRollback.mock
+}
+
+//Set uses given function f to mock the storer.Rollback method
+func (mmRollback *mStorerMockRollback) Set(f func() (err error)) *StorerMock {
+	if mmRollback.defaultExpectation != nil {
+		mmRollback.mock.t.Fatalf("Default expectation is already set for the storer.Rollback method")
+	}
+
+	if len(mmRollback.expectations) > 0 {
+		mmRollback.mock.t.Fatalf("Some expectations are already set for the storer.Rollback method")
+	}
+
+	mmRollback.mock.funcRollback = f
+	return mmRollback.mock
+}
+
+// Rollback implements storer
+func (mmRollback *StorerMock) Rollback() (err error) {
+	mm_atomic.AddUint64(&mmRollback.beforeRollbackCounter, 1)
+	defer mm_atomic.AddUint64(&mmRollback.afterRollbackCounter, 1)
+
+	if mmRollback.inspectFuncRollback != nil {
+		mmRollback.inspectFuncRollback()
+	}
+
+	if mmRollback.RollbackMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmRollback.RollbackMock.defaultExpectation.Counter, 1)
+
+		mm_results := mmRollback.RollbackMock.defaultExpectation.results
+		if mm_results == nil {
+			mmRollback.t.Fatal("No results are set for the StorerMock.Rollback")
+		}
+		return (*mm_results).err
+	}
+	if mmRollback.funcRollback != nil {
+		return mmRollback.funcRollback()
+	}
+	mmRollback.t.Fatalf("Unexpected call to StorerMock.Rollback.")
+	return
+}
+
+// RollbackAfterCounter returns a count of finished StorerMock.Rollback invocations
+func (mmRollback *StorerMock) RollbackAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRollback.afterRollbackCounter)
+}
+
+// RollbackBeforeCounter returns a count of StorerMock.Rollback invocations
+func (mmRollback *StorerMock) RollbackBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRollback.beforeRollbackCounter)
+}
+
+// MinimockRollbackDone returns true if the count of the Rollback invocations corresponds
+// the number of defined expectations
+func (m *StorerMock) MinimockRollbackDone() bool {
+	for _, e := range m.RollbackMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.RollbackMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterRollbackCounter) < 1 {
+		return false
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcRollback != nil && mm_atomic.LoadUint64(&m.afterRollbackCounter) < 1 {
+		return false
+	}
+	return true
+}
+
+// MinimockRollbackInspect logs each unmet expectation
+func (m *StorerMock) MinimockRollbackInspect() {
+	for _, e := range m.RollbackMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Error("Expected call to StorerMock.Rollback")
+		}
+	}
+
+	// if default expectation was set then invocations count should be greater than zero
+	if m.RollbackMock.defaultExpectation != nil && mm_atomic.LoadUint64(&m.afterRollbackCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.Rollback")
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcRollback != nil && mm_atomic.LoadUint64(&m.afterRollbackCounter) < 1 {
+		m.t.Error("Expected call to StorerMock.Rollback")
 	}
 }
 
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *StorerMock) MinimockFinish() {
 	if !m.minimockDone() {
+		m.MinimockActiveCartByUserIDInspect()
+
 		m.MinimockBeginTxInspect()
 
+		m.MinimockCartCouponAddInspect()
+
+		m.MinimockCartCouponRemoveInspect()
+
 		m.MinimockCartCreateInspect()
 
 		m.MinimockCartEmptyInspect()
 
+		m.MinimockCartShippingAddressSetInspect()
+
+		m.MinimockCartShippingMethodSetInspect()
+
+		m.MinimockCartStatusSetInspect()
+
 		m.MinimockCartWithItemsByCartIDInspect()
 
+		m.MinimockCartsByUserIDInspect()
+
 		m.MinimockCommitInspect()
 
+		m.MinimockCouponUsesInspect()
+
+		m.MinimockIdempotencyKeyByKeyInspect()
+
+		m.MinimockIdempotencyKeyCompleteInspect()
+
+		m.MinimockIdempotencyKeyCreateInspect()
+
+		m.MinimockIdempotencyKeyDeleteInspect()
+
+		m.MinimockIdempotencyKeysPurgeInspect()
+
 		m.MinimockLineItemRemoveInspect()
 
 		m.MinimockLineItemsUpsertInspect()
+
+		m.MinimockOrderCreateInspect()
+
+		m.MinimockPaymentByCartIDInspect()
+
+		m.MinimockPaymentCreateInspect()
+
+		m.MinimockPaymentStatusSetInspect()
+
+		m.MinimockRollbackInspect()
 		m.t.FailNow()
 	}
 }
@@ -1573,11 +5602,29 @@ func (m *StorerMock) MinimockWait(timeout mm_time.Duration) {
 func (m *StorerMock) minimockDone() bool {
 	done := true
 	return done &&
+		m.MinimockActiveCartByUserIDDone() &&
 		m.MinimockBeginTxDone() &&
+		m.MinimockCartCouponAddDone() &&
+		m.MinimockCartCouponRemoveDone() &&
 		m.MinimockCartCreateDone() &&
 		m.MinimockCartEmptyDone() &&
+		m.MinimockCartShippingAddressSetDone() &&
+		m.MinimockCartShippingMethodSetDone() &&
+		m.MinimockCartStatusSetDone() &&
 		m.MinimockCartWithItemsByCartIDDone() &&
+		m.MinimockCartsByUserIDDone() &&
 		m.MinimockCommitDone() &&
+		m.MinimockCouponUsesDone() &&
+		m.MinimockIdempotencyKeyByKeyDone() &&
+		m.MinimockIdempotencyKeyCompleteDone() &&
+		m.MinimockIdempotencyKeyCreateDone() &&
+		m.MinimockIdempotencyKeyDeleteDone() &&
+		m.MinimockIdempotencyKeysPurgeDone() &&
 		m.MinimockLineItemRemoveDone() &&
-		m.MinimockLineItemsUpsertDone()
+		m.MinimockLineItemsUpsertDone() &&
+		m.MinimockOrderCreateDone() &&
+		m.MinimockPaymentByCartIDDone() &&
+		m.MinimockPaymentCreateDone() &&
+		m.MinimockPaymentStatusSetDone() &&
+		m.MinimockRollbackDone()
 }
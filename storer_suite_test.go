@@ -0,0 +1,1110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RunStorerSuite checks that a storer implementation complies with the storer contract.
+// newStorer is called for every test and must return a ready to use storer, the storer may be
+// shared with other tests as long as their carts do not interfere.
+func RunStorerSuite(t *testing.T, newStorer func(t *testing.T) storer) {
+	t.Run("BeginTx", func(t *testing.T) {
+		st := newStorer(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tx, err := st.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.BeginTx(ctx, nil); err == nil {
+			t.Error("nested transaction err exp, got none")
+		}
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		st := newStorer(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		if err := st.Commit(); err == nil {
+			t.Error("commit outside of transaction err exp, got none")
+		}
+
+		tx, err := st.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := &Cart{UserID: 1}
+		if err := tx.CartCreate(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tx.LineItemsUpsert(ctx, c.ID, UpsertSet, &LineItem{ProductID: 1, Quantity: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err := st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l := len(cart.LineItems); l != 1 {
+			t.Errorf("cart items num exp: %d, got: %d", 1, l)
+		}
+
+		if err := tx.Commit(); err == nil {
+			t.Error("second commit err exp, got none")
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		st := newStorer(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		if err := st.Rollback(); err == nil {
+			t.Error("rollback outside of transaction err exp, got none")
+		}
+
+		c := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 1})
+
+		tx, err := st.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		nc := &Cart{UserID: 1}
+		if err := tx.CartCreate(ctx, nc); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tx.CartEmpty(ctx, c.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := st.CartWithItemsByCartID(ctx, nc.ID); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("rolled back cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		cart, err := st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l := len(cart.LineItems); l != 1 {
+			t.Errorf("cart items num exp: %d, got: %d", 1, l)
+		}
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		st := newStorer(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := createSuiteCart(t, st)
+
+		tx, err := st.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+
+		if err := tx.LineItemsUpsert(ctx, c.ID, UpsertSet, &LineItem{ProductID: 1, Quantity: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err := tx.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l := len(cart.LineItems); l != 1 {
+			t.Errorf("items inside of transaction num exp: %d, got: %d", 1, l)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l := len(cart.LineItems); l != 0 {
+			t.Errorf("items outside of transaction num exp: %d, got: %d", 0, l)
+		}
+	})
+
+	t.Run("CartCreate", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := &Cart{UserID: 7}
+		if err := st.CartCreate(context.Background(), c); err != nil {
+			t.Fatal(err)
+		}
+
+		if c.ID == 0 {
+			t.Error("id not updated")
+		}
+		if c.CreatedAt.IsZero() || c.UpdatedAt.IsZero() {
+			t.Error("timestamps not updated")
+		}
+
+		nc := &Cart{UserID: 7}
+		if err := st.CartCreate(context.Background(), nc); err != nil {
+			t.Fatal(err)
+		}
+
+		if nc.ID == c.ID {
+			t.Errorf("ids are not unique: %d", c.ID)
+		}
+	})
+
+	t.Run("CartWithItemsByCartID", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := createSuiteCart(t, st,
+			&LineItem{ProductID: 2, Quantity: 3},
+			&LineItem{ProductID: 1, Quantity: 4},
+		)
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cart.ID != c.ID || cart.UserID != c.UserID {
+			t.Errorf("carts do not match\nexp: %+v\ngot: %+v", c, cart)
+		}
+		if cart.CreatedAt.IsZero() || cart.UpdatedAt.IsZero() {
+			t.Error("cart timestamps are not set")
+		}
+
+		assertSuiteLineItems(t, c.LineItems, cart.LineItems)
+
+		if _, err := st.CartWithItemsByCartID(context.Background(), -1); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("not found err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+	})
+
+	t.Run("GuestCart", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := &Cart{GuestToken: hashGuestToken("tok")}
+		if err := st.CartCreate(context.Background(), c); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.UserID != 0 || cart.GuestToken != c.GuestToken {
+			t.Errorf("guest token exp: %s, got: %+v", c.GuestToken, cart)
+		}
+
+		if cart, err := st.CartWithItemsByCartID(context.Background(), createSuiteCart(t, st).ID); err != nil || cart.GuestToken != "" {
+			t.Errorf("user cart without guest token exp, got: %+v, %v", cart, err)
+		}
+	})
+
+	t.Run("CartsByUserID", func(t *testing.T) {
+		st := newStorer(t)
+
+		// A user of its own so carts of other tests sharing the storer do not interfere.
+		userID := time.Now().UnixNano()
+
+		var carts []*Cart
+		for j := 0; j < 3; j++ {
+			c := &Cart{UserID: userID}
+			if err := st.CartCreate(context.Background(), c); err != nil {
+				t.Fatal(err)
+			}
+			carts = append(carts, c)
+		}
+		createSuiteCart(t, st)
+
+		if err := st.LineItemsUpsert(context.Background(), carts[1].ID, UpsertSet, &LineItem{ProductID: 1, Quantity: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		ids := func(carts []*Cart) []int64 {
+			var ids []int64
+			for _, c := range carts {
+				ids = append(ids, c.ID)
+			}
+			return ids
+		}
+
+		tests := []struct {
+			name string
+			q    CartsQuery
+			exp  []int64
+		}{
+			{"all", CartsQuery{Limit: 10}, ids(carts)},
+			{"first page", CartsQuery{Limit: 2}, ids(carts[:2])},
+			{"next page", CartsQuery{AfterID: carts[1].ID, Limit: 2}, ids(carts[2:])},
+			{"created from", CartsQuery{Limit: 10, CreatedFrom: carts[0].CreatedAt.Add(-time.Hour)}, ids(carts)},
+			{"created to", CartsQuery{Limit: 10, CreatedTo: carts[0].CreatedAt.Add(-time.Hour)}, nil},
+			{"updated from", CartsQuery{Limit: 10, UpdatedFrom: carts[0].UpdatedAt.Add(time.Hour)}, nil},
+			{"updated to", CartsQuery{Limit: 10, UpdatedTo: carts[0].UpdatedAt.Add(time.Hour)}, ids(carts)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := st.CartsByUserID(context.Background(), userID, tt.q)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(tt.exp, ids(got)) {
+					t.Errorf("carts do not match\nexp: %v\ngot: %v", tt.exp, ids(got))
+				}
+
+				for _, c := range got {
+					if c.UserID != userID || c.CreatedAt.IsZero() || c.UpdatedAt.IsZero() {
+						t.Errorf("cart is incomplete: %+v", c)
+					}
+					if len(c.LineItems) != 0 {
+						t.Errorf("cart %d items are not exp", c.ID)
+					}
+				}
+			})
+		}
+
+		t.Run("with items", func(t *testing.T) {
+			got, err := st.CartsByUserID(context.Background(), userID, CartsQuery{Limit: 10, WithItems: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if l := len(got); l != 3 {
+				t.Fatalf("carts num exp: %d, got: %d", 3, l)
+			}
+
+			assertSuiteLineItems(t, nil, got[0].LineItems)
+			if l := len(got[1].LineItems); l != 1 || got[1].LineItems[0].Quantity != 2 {
+				t.Errorf("cart %d items do not match: %+v", got[1].ID, got[1].LineItems)
+			}
+		})
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := createSuiteCart(t, st)
+
+		version := func() int64 {
+			t.Helper()
+
+			cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return cart.Version
+		}
+
+		v := version()
+		if v <= 0 {
+			t.Fatalf("version is not set: %d", v)
+		}
+
+		i := &LineItem{ProductID: 1, Quantity: 1}
+
+		mutations := []struct {
+			name string
+			fn   func() error
+		}{
+			{"LineItemsUpsert", func() error { return st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, i) }},
+			{"LineItemRemove", func() error { return st.LineItemRemove(context.Background(), c.ID, i.ID) }},
+			{"CartEmpty", func() error { return st.CartEmpty(context.Background(), c.ID) }},
+		}
+		for _, m := range mutations {
+			if err := m.fn(); err != nil {
+				t.Fatal(m.name, err)
+			}
+
+			if nv := version(); nv <= v {
+				t.Errorf("%s version exp above %d, got: %d", m.name, v, nv)
+			} else {
+				v = nv
+			}
+		}
+
+		if err := st.LineItemRemove(context.Background(), c.ID, i.ID); !errors.Is(err, ErrLineItemNotFound) {
+			t.Fatalf("err exp: %v, got: %v", ErrLineItemNotFound, err)
+		}
+
+		if nv := version(); nv != v {
+			t.Errorf("failed mutation version exp: %d, got: %d", v, nv)
+		}
+	})
+
+	t.Run("CartEmpty", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 1}, &LineItem{ProductID: 2, Quantity: 1})
+		other := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 1})
+
+		if err := st.CartEmpty(context.Background(), c.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal("emptied cart must be kept:", err)
+		}
+
+		if l := len(cart.LineItems); l != 0 {
+			t.Errorf("cart items num exp: %d, got: %d", 0, l)
+		}
+
+		cart, err = st.CartWithItemsByCartID(context.Background(), other.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertSuiteLineItems(t, other.LineItems, cart.LineItems)
+
+		if err := st.CartEmpty(context.Background(), -1); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+	})
+
+	t.Run("CartStatus", func(t *testing.T) {
+		st := newStorer(t)
+		ctx := context.Background()
+
+		// A user of its own so carts of other tests sharing the storer do not interfere.
+		userID := time.Now().UnixNano()
+
+		if _, err := st.ActiveCartByUserID(ctx, userID); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("no active cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		first := &Cart{UserID: userID}
+		if err := st.CartCreate(ctx, first); err != nil {
+			t.Fatal(err)
+		}
+		if first.Status != CartActive {
+			t.Errorf("status exp: %s, got: %s", CartActive, first.Status)
+		}
+		if err := st.LineItemsUpsert(ctx, first.ID, UpsertSet, &LineItem{ProductID: 1, Quantity: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err := st.ActiveCartByUserID(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.ID != first.ID || cart.Status != CartActive || len(cart.LineItems) != 1 {
+			t.Errorf("active cart exp: %d with items, got: %+v", first.ID, cart)
+		}
+
+		// Guest carts are not active carts of users.
+		if err := st.CartCreate(ctx, &Cart{GuestToken: hashGuestToken("tok")}); err != nil {
+			t.Fatal(err)
+		}
+
+		second := &Cart{UserID: userID}
+		if err := st.CartCreate(ctx, second); err != nil {
+			t.Fatal(err)
+		}
+
+		if cart, err = st.CartWithItemsByCartID(ctx, first.ID); err != nil {
+			t.Fatal(err)
+		}
+		if cart.Status != CartAbandoned || cart.Version != 3 {
+			t.Errorf("superseded cart exp: %s version 3, got: %s version %d", CartAbandoned, cart.Status, cart.Version)
+		}
+		if cart, err = st.ActiveCartByUserID(ctx, userID); err != nil || cart.ID != second.ID {
+			t.Errorf("active cart exp: %d, got: %+v, %v", second.ID, cart, err)
+		}
+
+		if err := st.CartStatusSet(ctx, second.ID, CartMerged); err != nil {
+			t.Fatal(err)
+		}
+		if cart, err = st.CartWithItemsByCartID(ctx, second.ID); err != nil {
+			t.Fatal(err)
+		}
+		if cart.Status != CartMerged || cart.Version != 2 {
+			t.Errorf("status exp: %s version 2, got: %s version %d", CartMerged, cart.Status, cart.Version)
+		}
+		if _, err := st.ActiveCartByUserID(ctx, userID); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("no active cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+		if err := st.CartStatusSet(ctx, -1, CartMerged); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		third := &Cart{UserID: userID}
+		if err := st.CartCreate(ctx, third); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.OrderCreate(ctx, &Order{CartID: third.ID, UserID: userID}); err != nil {
+			t.Fatal(err)
+		}
+		if cart, err = st.CartWithItemsByCartID(ctx, third.ID); err != nil || cart.Status != CartCheckedOut {
+			t.Errorf("status exp: %s, got: %+v, %v", CartCheckedOut, cart, err)
+		}
+
+		carts, err := st.CartsByUserID(ctx, userID, CartsQuery{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var statuses []CartStatus
+		for _, c := range carts {
+			statuses = append(statuses, c.Status)
+		}
+		if exp := []CartStatus{CartAbandoned, CartMerged, CartCheckedOut}; !reflect.DeepEqual(exp, statuses) {
+			t.Errorf("statuses exp: %v, got: %v", exp, statuses)
+		}
+	})
+
+	t.Run("LineItemsUpsert", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := createSuiteCart(t, st)
+
+		ii := []*LineItem{
+			{ProductID: 9, Quantity: 5},
+			nil,
+			{ProductID: 1, Quantity: 8},
+		}
+
+		if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, ii...); err != nil {
+			t.Fatal("insert:", err)
+		}
+
+		for _, i := range []*LineItem{ii[0], ii[2]} {
+			if i.ID == 0 {
+				t.Errorf("product %d id not updated", i.ProductID)
+			}
+			if i.UpdatedAt.IsZero() {
+				t.Errorf("product %d updated at not updated", i.ProductID)
+			}
+		}
+
+		i := &LineItem{ProductID: 9, Quantity: 3}
+		if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, i); err != nil {
+			t.Fatal("update:", err)
+		}
+
+		if i.ID != ii[0].ID {
+			t.Errorf("updated item id exp: %d, got: %d", ii[0].ID, i.ID)
+		}
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertSuiteLineItems(t, []*LineItem{
+			{ID: ii[0].ID, CartID: c.ID, ProductID: 9, Quantity: 3},
+			{ID: ii[2].ID, CartID: c.ID, ProductID: 1, Quantity: 8},
+		}, cart.LineItems)
+
+		if err := st.LineItemsUpsert(context.Background(), -1, UpsertSet, &LineItem{ProductID: 1, Quantity: 1}); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+	})
+
+	t.Run("LineItemsUpsertIncrement", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 2})
+
+		i := &LineItem{ProductID: 1, Quantity: 3}
+		if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertIncrement, i); err != nil {
+			t.Fatal(err)
+		}
+
+		if i.ID != c.LineItems[0].ID || i.Quantity != 5 {
+			t.Errorf("incremented item exp: id %d, quantity %d, got: id %d, quantity %d", c.LineItems[0].ID, 5, i.ID, i.Quantity)
+		}
+
+		const workers, adds = 10, 5
+
+		// Concurrent increments outside of transactions must not lose updates.
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for a := 0; a < adds; a++ {
+					err := st.LineItemsUpsert(context.Background(), c.ID, UpsertIncrement,
+						&LineItem{ProductID: 1, Quantity: 1},
+						&LineItem{ProductID: 2, Quantity: 2},
+					)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l := len(cart.LineItems); l != 2 {
+			t.Fatalf("items num exp: %d, got: %d", 2, l)
+		}
+		if q := cart.LineItems[0].Quantity; q != 5+workers*adds {
+			t.Errorf("product %d quantity exp: %d, got: %d", 1, 5+workers*adds, q)
+		}
+		if q := cart.LineItems[1].Quantity; q != 2*workers*adds {
+			t.Errorf("product %d quantity exp: %d, got: %d", 2, 2*workers*adds, q)
+		}
+	})
+
+	t.Run("LineItemsUpsertPrice", func(t *testing.T) {
+		st := newStorer(t)
+
+		old, cur := Money{Amount: 499, Currency: "EUR"}, Money{Amount: 549, Currency: "EUR"}
+		c := createSuiteCart(t, st,
+			&LineItem{ProductID: 1, Quantity: 1, UnitPrice: old},
+			&LineItem{ProductID: 2, Quantity: 1},
+		)
+
+		// Incremented items keep their price, unpriced ones get priced.
+		ii := []*LineItem{
+			{ProductID: 1, Quantity: 1, UnitPrice: cur},
+			{ProductID: 2, Quantity: 1, UnitPrice: cur},
+		}
+		if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertIncrement, ii...); err != nil {
+			t.Fatal("increment:", err)
+		}
+
+		if ii[0].UnitPrice != old || ii[1].UnitPrice != cur {
+			t.Errorf("incremented prices exp: %v, %v, got: %v, %v", old, cur, ii[0].UnitPrice, ii[1].UnitPrice)
+		}
+
+		// Set items get the given price.
+		i := &LineItem{ProductID: 1, Quantity: 2, UnitPrice: cur}
+		if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, i); err != nil {
+			t.Fatal("set:", err)
+		}
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertSuiteLineItems(t, []*LineItem{
+			{ID: ii[0].ID, CartID: c.ID, ProductID: 1, Quantity: 2, UnitPrice: cur},
+			{ID: ii[1].ID, CartID: c.ID, ProductID: 2, Quantity: 2, UnitPrice: cur},
+		}, cart.LineItems)
+	})
+
+	t.Run("LineItemRemove", func(t *testing.T) {
+		st := newStorer(t)
+
+		c := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 1}, &LineItem{ProductID: 2, Quantity: 1})
+		other := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 1})
+
+		if err := st.LineItemRemove(context.Background(), c.ID, c.LineItems[0].ID); err != nil {
+			t.Fatal(err)
+		}
+
+		// Items of other carts are left untouched.
+		if err := st.LineItemRemove(context.Background(), c.ID, other.LineItems[0].ID); !errors.Is(err, ErrLineItemNotFound) {
+			t.Errorf("other cart's item err exp: %v, got: %v", ErrLineItemNotFound, err)
+		}
+
+		if err := st.LineItemRemove(context.Background(), c.ID, c.LineItems[0].ID); !errors.Is(err, ErrLineItemNotFound) {
+			t.Errorf("removed item err exp: %v, got: %v", ErrLineItemNotFound, err)
+		}
+
+		cart, err := st.CartWithItemsByCartID(context.Background(), c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertSuiteLineItems(t, c.LineItems[1:], cart.LineItems)
+
+		cart, err = st.CartWithItemsByCartID(context.Background(), other.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertSuiteLineItems(t, other.LineItems, cart.LineItems)
+	})
+
+	t.Run("CartCoupons", func(t *testing.T) {
+		st := newStorer(t)
+		ctx := context.Background()
+
+		c := createSuiteCart(t, st)
+		other := createSuiteCart(t, st)
+
+		cart, err := st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version := cart.Version
+
+		for _, code := range []string{"B", "A"} {
+			if err := st.CartCouponAdd(ctx, c.ID, code); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := st.CartCouponAdd(ctx, other.ID, "A"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := st.CartCouponAdd(ctx, c.ID, "A"); !errors.Is(err, ErrConflict) {
+			t.Errorf("applied coupon err exp: %v, got: %v", ErrConflict, err)
+		}
+		if err := st.CartCouponAdd(ctx, -1, "A"); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"B", "A"}; !reflect.DeepEqual(exp, cart.Coupons) {
+			t.Errorf("coupons exp: %v, got: %v", exp, cart.Coupons)
+		}
+		if cart.Version != version+2 {
+			t.Errorf("version exp: %d, got: %d", version+2, cart.Version)
+		}
+
+		carts, err := st.CartsByUserID(ctx, c.UserID, CartsQuery{AfterID: c.ID - 1, Limit: 1, WithItems: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"B", "A"}; len(carts) != 1 || !reflect.DeepEqual(exp, carts[0].Coupons) {
+			t.Errorf("listed coupons exp: %v, got: %+v", exp, carts)
+		}
+
+		// Only checked out carts count as uses. A user of its own so carts of other tests sharing
+		// the storer do not interfere.
+		userID := time.Now().UnixNano()
+		for j, codes := range [][]string{{"A", "B"}, {"A"}} {
+			u := &Cart{UserID: userID}
+			if err := st.CartCreate(ctx, u); err != nil {
+				t.Fatal(err)
+			}
+			for _, code := range codes {
+				if err := st.CartCouponAdd(ctx, u.ID, code); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if j == 0 {
+				if err := st.OrderCreate(ctx, &Order{CartID: u.ID, UserID: userID}); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		for code, exp := range map[string]int{"A": 1, "B": 1, "C": 0} {
+			if n, err := st.CouponUses(ctx, userID, code); err != nil || n != exp {
+				t.Errorf("coupon %s uses exp: %d, got: %d, %v", code, exp, n, err)
+			}
+		}
+		if n, err := st.CouponUses(ctx, userID+1, "A"); err != nil || n != 0 {
+			t.Errorf("other user uses exp: %d, got: %d, %v", 0, n, err)
+		}
+
+		if err := st.CartCouponRemove(ctx, c.ID, "B"); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.CartCouponRemove(ctx, c.ID, "B"); !errors.Is(err, ErrCouponNotFound) {
+			t.Errorf("removed coupon err exp: %v, got: %v", ErrCouponNotFound, err)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"A"}; !reflect.DeepEqual(exp, cart.Coupons) || cart.Version != version+3 {
+			t.Errorf("coupons exp: %v, version %d, got: %v, version %d", exp, version+3, cart.Coupons, cart.Version)
+		}
+	})
+
+	t.Run("CartShippingAddressSet", func(t *testing.T) {
+		st := newStorer(t)
+		ctx := context.Background()
+
+		c := createSuiteCart(t, st)
+		other := createSuiteCart(t, st)
+
+		cart, err := st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.ShippingAddress != nil {
+			t.Errorf("no address exp, got: %+v", cart.ShippingAddress)
+		}
+		version := cart.Version
+
+		a := &Address{Name: "Jo Doe", Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Region: "CA", Country: "US"}
+		for _, a := range []*Address{{Line1: "Old", Country: "US"}, a} {
+			if err := st.CartShippingAddressSet(ctx, c.ID, a); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := st.CartShippingAddressSet(ctx, -1, a); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		// Changing the address does not change the stored one.
+		a.Line1 = "Changed"
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := &Address{Name: "Jo Doe", Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Region: "CA", Country: "US"}
+		if !reflect.DeepEqual(exp, cart.ShippingAddress) || cart.Version != version+2 {
+			t.Errorf("address exp: %+v, version %d, got: %+v, version %d", exp, version+2, cart.ShippingAddress, cart.Version)
+		}
+
+		carts, err := st.CartsByUserID(ctx, c.UserID, CartsQuery{AfterID: c.ID - 1, Limit: 2, WithItems: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(carts) != 2 || !reflect.DeepEqual(exp, carts[0].ShippingAddress) || carts[1].ShippingAddress != nil {
+			t.Errorf("listed addresses exp: %+v, nil, got: %+v", exp, carts)
+		}
+
+		if err := st.CartShippingAddressSet(ctx, c.ID, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.ShippingAddress != nil || cart.Version != version+3 {
+			t.Errorf("removed address exp, version %d, got: %+v, version %d", version+3, cart.ShippingAddress, cart.Version)
+		}
+
+		if cart, err := st.CartWithItemsByCartID(ctx, other.ID); err != nil || cart.ShippingAddress != nil {
+			t.Errorf("other cart no address exp, got: %+v, %v", cart, err)
+		}
+	})
+
+	t.Run("CartShippingMethodSet", func(t *testing.T) {
+		st := newStorer(t)
+		ctx := context.Background()
+
+		c := createSuiteCart(t, st)
+		other := createSuiteCart(t, st)
+
+		cart, err := st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version := cart.Version
+
+		for _, code := range []string{"standard", "express"} {
+			if err := st.CartShippingMethodSet(ctx, c.ID, code); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := st.CartShippingMethodSet(ctx, -1, "standard"); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.ShippingMethod != "express" || cart.Version != version+2 {
+			t.Errorf("method exp: %s, version %d, got: %s, version %d", "express", version+2, cart.ShippingMethod, cart.Version)
+		}
+
+		carts, err := st.CartsByUserID(ctx, c.UserID, CartsQuery{AfterID: c.ID - 1, Limit: 2, WithItems: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(carts) != 2 || carts[0].ShippingMethod != "express" || carts[1].ShippingMethod != "" {
+			t.Errorf("listed methods exp: express, none, got: %+v", carts)
+		}
+
+		if err := st.CartShippingMethodSet(ctx, c.ID, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.ShippingMethod != "" || cart.Version != version+3 {
+			t.Errorf("removed method exp, version %d, got: %s, version %d", version+3, cart.ShippingMethod, cart.Version)
+		}
+
+		if cart, err := st.CartWithItemsByCartID(ctx, other.ID); err != nil || cart.ShippingMethod != "" {
+			t.Errorf("other cart no method exp, got: %+v, %v", cart, err)
+		}
+	})
+
+	t.Run("OrderCreate", func(t *testing.T) {
+		st := newStorer(t)
+		ctx := context.Background()
+
+		eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+		c := createSuiteCart(t, st, &LineItem{ProductID: 1, Quantity: 2, UnitPrice: eur(500)})
+		other := createSuiteCart(t, st)
+
+		cart, err := st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version := cart.Version
+
+		o := &Order{
+			CartID:          c.ID,
+			UserID:          c.UserID,
+			LineItems:       []*OrderItem{{ProductID: 1, SKU: "SKU-1", Name: "Tea", Quantity: 2, UnitPrice: eur(500), Discount: eur(100)}},
+			ShippingAddress: &Address{Line1: "1 Main St", City: "Berlin", Country: "DE"},
+			ShippingMethod:  "standard",
+			Totals:          Totals{eur(1000), eur(100), eur(0), eur(490), eur(1390)},
+		}
+		if err := st.OrderCreate(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+		if o.ID == 0 || o.LineItems[0].ID == 0 || o.CreatedAt.IsZero() {
+			t.Errorf("IDs and created at not updated: %+v", o)
+		}
+
+		if err := st.OrderCreate(ctx, &Order{CartID: c.ID, UserID: c.UserID}); !errors.Is(err, ErrConflict) {
+			t.Errorf("checked out cart err exp: %v, got: %v", ErrConflict, err)
+		}
+		if err := st.OrderCreate(ctx, &Order{CartID: -1}); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		cart, err = st.CartWithItemsByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cart.OrderID != o.ID || cart.Version != version+1 {
+			t.Errorf("order exp: %d, version %d, got: %d, version %d", o.ID, version+1, cart.OrderID, cart.Version)
+		}
+
+		carts, err := st.CartsByUserID(ctx, c.UserID, CartsQuery{AfterID: c.ID - 1, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(carts) != 2 || carts[0].OrderID != o.ID || carts[1].OrderID != 0 {
+			t.Errorf("listed orders exp: %d, 0, got: %+v", o.ID, carts)
+		}
+
+		if cart, err := st.CartWithItemsByCartID(ctx, other.ID); err != nil || cart.OrderID != 0 {
+			t.Errorf("other cart no order exp, got: %+v, %v", cart, err)
+		}
+	})
+
+	t.Run("Payments", func(t *testing.T) {
+		st := newStorer(t)
+		ctx := context.Background()
+
+		c := createSuiteCart(t, st)
+
+		if _, err := st.PaymentByCartID(ctx, c.ID); !errors.Is(err, ErrPaymentNotFound) {
+			t.Errorf("no payment err exp: %v, got: %v", ErrPaymentNotFound, err)
+		}
+
+		first := &Payment{CartID: c.ID, CartVersion: 1, Amount: Money{Amount: 1000, Currency: "EUR"}, Status: PaymentAuthorized, AuthorizationID: "auth_1"}
+		second := &Payment{CartID: c.ID, CartVersion: 2, Amount: Money{Amount: 1500, Currency: "EUR"}, Status: PaymentAuthorized, AuthorizationID: "auth_2"}
+		for _, p := range []*Payment{first, second} {
+			if err := st.PaymentCreate(ctx, p); err != nil {
+				t.Fatal(err)
+			}
+			if p.ID == 0 || p.CreatedAt.IsZero() || p.UpdatedAt.IsZero() {
+				t.Errorf("ID and timestamps not updated: %+v", p)
+			}
+		}
+
+		if err := st.PaymentCreate(ctx, &Payment{CartID: -1, Status: PaymentAuthorized}); !errors.Is(err, ErrCartNotFound) {
+			t.Errorf("unknown cart err exp: %v, got: %v", ErrCartNotFound, err)
+		}
+
+		if err := st.PaymentStatusSet(ctx, first.ID, PaymentVoided); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.PaymentStatusSet(ctx, -1, PaymentVoided); !errors.Is(err, ErrPaymentNotFound) {
+			t.Errorf("unknown payment err exp: %v, got: %v", ErrPaymentNotFound, err)
+		}
+
+		// The latest payment of the cart.
+		p, err := st.PaymentByCartID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.ID != second.ID || p.CartVersion != 2 || p.Amount != second.Amount || p.Status != PaymentAuthorized || p.AuthorizationID != "auth_2" {
+			t.Errorf("payment exp: %+v, got: %+v", second, p)
+		}
+
+		if err := st.PaymentStatusSet(ctx, second.ID, PaymentCaptured); err != nil {
+			t.Fatal(err)
+		}
+		if p, err := st.PaymentByCartID(ctx, c.ID); err != nil || p.Status != PaymentCaptured {
+			t.Errorf("status exp: %s, got: %+v, %v", PaymentCaptured, p, err)
+		}
+	})
+
+	t.Run("IdempotencyKeys", func(t *testing.T) {
+		st := newStorer(t)
+
+		userID := time.Now().UnixNano()
+		ctx := context.Background()
+
+		k := &IdempotencyKey{UserID: userID, Key: "k1", Fingerprint: "f1", ExpiresAt: time.Now().Add(time.Hour)}
+		if err := st.IdempotencyKeyCreate(ctx, k); err != nil {
+			t.Fatal(err)
+		}
+
+		if k.CreatedAt.IsZero() {
+			t.Error("created at not updated")
+		}
+
+		if err := st.IdempotencyKeyCreate(ctx, &IdempotencyKey{UserID: userID, Key: "k1", Fingerprint: "f2", ExpiresAt: time.Now().Add(time.Hour)}); !errors.Is(err, ErrConflict) {
+			t.Errorf("duplicate err exp: %v, got: %v", ErrConflict, err)
+		}
+
+		// Keys are scoped to users.
+		if err := st.IdempotencyKeyCreate(ctx, &IdempotencyKey{UserID: userID + 1, Key: "k1", Fingerprint: "f3", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal("other user:", err)
+		}
+
+		got, err := st.IdempotencyKeyByKey(ctx, userID, "k1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Fingerprint != "f1" || got.Response != nil {
+			t.Errorf("keys do not match\nexp: %+v\ngot: %+v", k, got)
+		}
+
+		k.Response, k.ExpiresAt = []byte(`{"status":201}`), time.Now().Add(24*time.Hour)
+		if err := st.IdempotencyKeyComplete(ctx, k); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, err = st.IdempotencyKeyByKey(ctx, userID, "k1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got.Response) != string(k.Response) {
+			t.Errorf("response exp: %s, got: %s", k.Response, got.Response)
+		}
+		if got.ExpiresAt.Before(time.Now().Add(23 * time.Hour)) {
+			t.Errorf("expiry exp: %s, got: %s", k.ExpiresAt, got.ExpiresAt)
+		}
+
+		if err := st.IdempotencyKeyComplete(ctx, &IdempotencyKey{UserID: userID, Key: "k2"}); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+			t.Errorf("unknown key err exp: %v, got: %v", ErrIdempotencyKeyNotFound, err)
+		}
+
+		if err := st.IdempotencyKeyDelete(ctx, userID, "k1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := st.IdempotencyKeyByKey(ctx, userID, "k1"); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+			t.Errorf("deleted key err exp: %v, got: %v", ErrIdempotencyKeyNotFound, err)
+		}
+
+		t.Run("expired", func(t *testing.T) {
+			k := &IdempotencyKey{UserID: userID, Key: "k3", Fingerprint: "f1", ExpiresAt: time.Now().Add(-time.Minute)}
+			if err := st.IdempotencyKeyCreate(ctx, k); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := st.IdempotencyKeyByKey(ctx, userID, "k3"); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+				t.Errorf("expired key err exp: %v, got: %v", ErrIdempotencyKeyNotFound, err)
+			}
+
+			// An expired key is replaced.
+			k.ExpiresAt = time.Now().Add(time.Hour)
+			if err := st.IdempotencyKeyCreate(ctx, k); err != nil {
+				t.Fatal("replace:", err)
+			}
+
+			if err := st.IdempotencyKeysPurge(ctx, time.Now().Add(2*time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+
+			// The purged key may be created again.
+			if err := st.IdempotencyKeyCreate(ctx, k); err != nil {
+				t.Fatal("purged:", err)
+			}
+		})
+	})
+}
+
+func createSuiteCart(t *testing.T, st storer, items ...*LineItem) *Cart {
+	t.Helper()
+
+	c := &Cart{UserID: 50, LineItems: items}
+	if err := st.CartCreate(context.Background(), c); err != nil {
+		t.Fatal("cart:", err)
+	}
+
+	if err := st.LineItemsUpsert(context.Background(), c.ID, UpsertSet, items...); err != nil {
+		t.Fatal("items:", err)
+	}
+
+	for _, i := range items {
+		i.CartID = c.ID
+	}
+
+	return c
+}
+
+// assertSuiteLineItems compares items ignoring timestamps which precision depends on the storer.
+func assertSuiteLineItems(t *testing.T, exp, got []*LineItem) {
+	t.Helper()
+
+	if len(exp) != len(got) {
+		t.Fatalf("items num exp: %d, got: %d", len(exp), len(got))
+	}
+
+	byID := make(map[int64]*LineItem, len(got))
+	for j, i := range got {
+		if j > 0 && got[j-1].ID >= i.ID {
+			t.Errorf("items are not ordered by id: %d, %d", got[j-1].ID, i.ID)
+		}
+		if i.CreatedAt.IsZero() || i.UpdatedAt.IsZero() {
+			t.Errorf("item %d timestamps are not set", i.ID)
+		}
+		byID[i.ID] = i
+	}
+
+	for _, e := range exp {
+		g, ok := byID[e.ID]
+		if !ok {
+			t.Errorf("item %d is missing", e.ID)
+			continue
+		}
+
+		if g.CartID != e.CartID || g.ProductID != e.ProductID || g.Quantity != e.Quantity || g.UnitPrice != e.UnitPrice {
+			t.Errorf("items do not match\nexp: %+v\ngot: %+v", e, g)
+		}
+	}
+}
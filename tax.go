@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// TaxCalculator computes taxes of carts.
+type TaxCalculator interface {
+	// Taxes returns the tax adjustments of a priced and discounted cart, carts without
+	// a shipping address are not taxed.
+	Taxes(ctx context.Context, c *Cart) ([]Adjustment, error)
+}
+
+// TaxRate is the rate of a tax of a jurisdiction and a product tax class.
+type TaxRate struct {
+	Country   string
+	Region    string // the whole country if empty
+	TaxClass  string // products without a rate of their own tax class if empty
+	Rate      int64  // in basis points, 1900 is 19%
+	Label     string
+	Inclusive bool // unit prices include the tax
+}
+
+// tax returns the tax of an amount rounded half up, the tax is a part of the amount if the
+// rate is inclusive.
+func (r TaxRate) tax(m Money) (Money, error) {
+	tax, err := m.mul(r.Rate)
+	if err != nil {
+		return Money{}, err
+	}
+
+	div := int64(10000)
+	if r.Inclusive {
+		div += r.Rate
+	}
+
+	tax.Amount = (tax.Amount + div/2) / div
+	return tax, nil
+}
+
+// TaxTable is a tax calculator of fixed tax rates. Items are taxed at the most specific rate
+// of the shipping address and their tax class, a rate of the tax class takes precedence over
+// a rate of the region.
+type TaxTable []TaxRate
+
+// LoadTaxTable reads a tax table from a JSON file of the form
+// [{"country": "DE", "tax_class": "food", "rate": 700, "label": "VAT 7%", "inclusive": true},
+// {"country": "US", "region": "CA", "rate": 725, "label": "CA sales tax"}].
+func LoadTaxTable(path string) (TaxTable, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rr []struct {
+		Country   string `json:"country"`
+		Region    string `json:"region"`
+		TaxClass  string `json:"tax_class"`
+		Rate      int64  `json:"rate"`
+		Label     string `json:"label"`
+		Inclusive bool   `json:"inclusive"`
+	}
+	if err := json.Unmarshal(b, &rr); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+
+	t := make(TaxTable, len(rr))
+	for j, r := range rr {
+		switch {
+		case len(r.Country) != 2:
+			return nil, fmt.Errorf("rate %d: country %q is not an ISO 3166-1 alpha-2 code: %w", j, r.Country, ErrInvalidArgument)
+		case r.Rate < 0:
+			return nil, fmt.Errorf("rate %d: rate %d is negative: %w", j, r.Rate, ErrInvalidArgument)
+		case r.Label == "":
+			return nil, fmt.Errorf("rate %d: label is empty: %w", j, ErrInvalidArgument)
+		}
+
+		t[j] = TaxRate{
+			Country:   strings.ToUpper(r.Country),
+			Region:    strings.ToUpper(r.Region),
+			TaxClass:  r.TaxClass,
+			Rate:      r.Rate,
+			Label:     r.Label,
+			Inclusive: r.Inclusive,
+		}
+	}
+	return t, nil
+}
+
+func (t TaxTable) Taxes(ctx context.Context, c *Cart) ([]Adjustment, error) {
+	if c.ShippingAddress == nil {
+		return nil, nil
+	}
+
+	amounts, err := taxableAmounts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taxes are computed per rate to round once.
+	var (
+		rates []int
+		bases = map[int]Money{}
+	)
+	for j, i := range c.LineItems {
+		r, ok := t.rate(c.ShippingAddress, i.TaxClass)
+		if !ok || amounts[j].Amount == 0 {
+			continue
+		}
+
+		if _, ok := bases[r]; !ok {
+			rates = append(rates, r)
+		}
+		if bases[r], err = bases[r].add(amounts[j]); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i.ID, err)
+		}
+	}
+
+	var taxes []Adjustment
+	for _, r := range rates {
+		tax, err := t[r].tax(bases[r])
+		if err != nil {
+			return nil, fmt.Errorf("tax %q: %w", t[r].Label, err)
+		}
+		if tax.Amount == 0 {
+			continue
+		}
+
+		taxes = append(taxes, Adjustment{Kind: AdjustmentTax, Label: t[r].Label, Amount: tax, Included: t[r].Inclusive})
+	}
+	return taxes, nil
+}
+
+// rate returns the index of the most specific rate of the address and the tax class.
+func (t TaxTable) rate(a *Address, taxClass string) (int, bool) {
+	rate, score := 0, -1
+	for j, r := range t {
+		if !strings.EqualFold(r.Country, a.Country) ||
+			(r.Region != "" && !strings.EqualFold(r.Region, a.Region)) ||
+			(r.TaxClass != "" && r.TaxClass != taxClass) {
+			continue
+		}
+
+		s := 0
+		if r.TaxClass != "" {
+			s += 2
+		}
+		if r.Region != "" {
+			s++
+		}
+
+		if s > score {
+			rate, score = j, s
+		}
+	}
+	return rate, score >= 0
+}
+
+// HTTPTaxCalculator computes taxes with an external tax service. The service is POSTed
+//
+//	{"currency": "USD", "address": {"country": "US", "region": "CA", "postal_code": "94103", "city": "San Francisco"},
+//	"line_items": [{"id": 1, "product_id": 20, "tax_class": "", "quantity": 2, "amount": 2500}]}
+//
+// where amounts are in minor units of the currency after discounts, and responds with
+//
+//	{"taxes": [{"label": "CA sales tax", "amount": 181, "inclusive": false}]}.
+type HTTPTaxCalculator struct {
+	URL    string
+	Client *http.Client // http.DefaultClient if nil
+}
+
+type httpTaxRequest struct {
+	Currency  string            `json:"currency"`
+	Address   httpTaxAddress    `json:"address"`
+	LineItems []httpTaxLineItem `json:"line_items"`
+}
+
+type httpTaxAddress struct {
+	Country    string `json:"country"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	City       string `json:"city,omitempty"`
+}
+
+type httpTaxLineItem struct {
+	ID        int64  `json:"id"`
+	ProductID int64  `json:"product_id"`
+	TaxClass  string `json:"tax_class"`
+	Quantity  int64  `json:"quantity"`
+	Amount    int64  `json:"amount"`
+}
+
+type httpTaxResponse struct {
+	Taxes []struct {
+		Label     string `json:"label"`
+		Amount    int64  `json:"amount"`
+		Inclusive bool   `json:"inclusive"`
+	} `json:"taxes"`
+}
+
+func (t *HTTPTaxCalculator) Taxes(ctx context.Context, c *Cart) ([]Adjustment, error) {
+	a := c.ShippingAddress
+	if a == nil {
+		return nil, nil
+	}
+
+	amounts, err := taxableAmounts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	req := httpTaxRequest{
+		Address:   httpTaxAddress{Country: a.Country, Region: a.Region, PostalCode: a.PostalCode, City: a.City},
+		LineItems: []httpTaxLineItem{},
+	}
+	for j, i := range c.LineItems {
+		if amounts[j].Currency == "" {
+			continue
+		}
+
+		req.Currency = amounts[j].Currency
+		req.LineItems = append(req.LineItems, httpTaxLineItem{
+			ID:        i.ID,
+			ProductID: i.ProductID,
+			TaxClass:  i.TaxClass,
+			Quantity:  i.Quantity,
+			Amount:    amounts[j].Amount,
+		})
+	}
+
+	// Nothing to tax.
+	if len(req.LineItems) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(r)
+	if err != nil {
+		return nil, fmt.Errorf("tax service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tax service: %s", resp.Status)
+	}
+
+	var tr httpTaxResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, fmt.Errorf("tax service: json: %w", err)
+	}
+
+	var taxes []Adjustment
+	for _, tax := range tr.Taxes {
+		if tax.Amount < 0 {
+			return nil, fmt.Errorf("tax service: tax %q is negative", tax.Label)
+		}
+
+		taxes = append(taxes, Adjustment{
+			Kind:     AdjustmentTax,
+			Label:    tax.Label,
+			Amount:   Money{Amount: tax.Amount, Currency: req.Currency},
+			Included: tax.Inclusive,
+		})
+	}
+	return taxes, nil
+}
+
+// taxableAmounts returns the amounts of the cart items after discounts in the order of the
+// items, unpriced items have zero amounts. Cart discounts are split among the items in
+// proportion to their amounts, discounts of shipping charges do not lower the item amounts.
+func taxableAmounts(c *Cart) ([]Money, error) {
+	var (
+		amounts = make([]Money, len(c.LineItems))
+		total   Money
+		err     error
+	)
+	for j, i := range c.LineItems {
+		if i.UnitPrice.Currency == "" {
+			continue
+		}
+
+		m, err := i.UnitPrice.mul(i.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i.ID, err)
+		}
+
+		for _, a := range i.Adjustments {
+			if a.Kind == AdjustmentDiscount {
+				m.Amount -= a.Amount.Amount
+			}
+		}
+		if m.Amount < 0 {
+			m.Amount = 0
+		}
+
+		amounts[j] = m
+		if total, err = total.add(m); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i.ID, err)
+		}
+	}
+
+	var discount Money
+	for _, a := range c.Adjustments {
+		if a.Kind != AdjustmentDiscount || a.Shipping {
+			continue
+		}
+
+		if discount, err = discount.add(a.Amount); err != nil {
+			return nil, fmt.Errorf("discount %q: %w", a.Label, err)
+		}
+	}
+
+	if discount.Amount == 0 || total.Amount == 0 {
+		return amounts, nil
+	}
+	if discount.Amount > total.Amount {
+		discount.Amount = total.Amount
+	}
+
+	left := discount.Amount
+	for j := range amounts {
+		share, err := discount.mul(amounts[j].Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		share.Amount /= total.Amount
+		amounts[j].Amount -= share.Amount
+		left -= share.Amount
+	}
+
+	// Every item keeps at least a minor unit of its amount after its share unless the discount
+	// covers the total, so rounding leftovers are taken a unit per item.
+	for j := range amounts {
+		if left == 0 {
+			break
+		}
+
+		if amounts[j].Amount > 0 {
+			amounts[j].Amount--
+			left--
+		}
+	}
+
+	return amounts, nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTaxTable_Taxes(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	table := TaxTable{
+		{Country: "DE", Rate: 1900, Label: "VAT 19%", Inclusive: true},
+		{Country: "DE", TaxClass: "food", Rate: 700, Label: "VAT 7%", Inclusive: true},
+		{Country: "US", Region: "CA", Rate: 725, Label: "CA sales tax"},
+		{Country: "US", TaxClass: "food", Rate: 0, Label: "Exempt"},
+	}
+
+	tests := []struct {
+		name     string
+		address  *Address
+		discount int64 // cart discount
+		exp      []Adjustment
+	}{
+		{"no address", nil, 0, nil},
+		{
+			"inclusive",
+			&Address{Country: "DE"},
+			0,
+			[]Adjustment{
+				{Kind: AdjustmentTax, Label: "VAT 7%", Amount: eur(65), Included: true},
+				{Kind: AdjustmentTax, Label: "VAT 19%", Amount: eur(160), Included: true},
+			},
+		},
+		{
+			"discounted",
+			&Address{Country: "DE"},
+			200,
+			[]Adjustment{
+				{Kind: AdjustmentTax, Label: "VAT 7%", Amount: eur(59), Included: true},
+				{Kind: AdjustmentTax, Label: "VAT 19%", Amount: eur(144), Included: true},
+			},
+		},
+		{
+			"exclusive region",
+			&Address{Country: "us", Region: "ca"},
+			0,
+			[]Adjustment{{Kind: AdjustmentTax, Label: "CA sales tax", Amount: eur(73)}},
+		},
+		{"exempt", &Address{Country: "US", Region: "NY"}, 0, nil},
+		{"unknown country", &Address{Country: "FR"}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Cart{
+				ShippingAddress: tt.address,
+				LineItems: []*LineItem{
+					{ID: 1, ProductID: 1, TaxClass: "food", Quantity: 2, UnitPrice: eur(500)},
+					{ID: 2, ProductID: 2, Quantity: 1, UnitPrice: eur(1000)},
+					// Unpriced items are not taxed.
+					{ID: 3, ProductID: 3, Quantity: 1},
+				},
+			}
+			if tt.discount > 0 {
+				c.Adjustments = []Adjustment{{Kind: AdjustmentDiscount, Label: "D", Amount: eur(tt.discount)}}
+			}
+
+			taxes, err := table.Taxes(context.Background(), c)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tt.exp, taxes) {
+				t.Errorf("taxes do not match\nexp: %+v\ngot: %+v", tt.exp, taxes)
+			}
+		})
+	}
+}
+
+func TestLoadTaxTable(t *testing.T) {
+	path := writeTestFile(t, "taxes.json", `[
+		{"country":"de","tax_class":"food","rate":700,"label":"VAT 7%","inclusive":true},
+		{"country":"US","region":"ca","rate":725,"label":"CA sales tax"}
+	]`)
+
+	table, err := LoadTaxTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := TaxTable{
+		{Country: "DE", TaxClass: "food", Rate: 700, Label: "VAT 7%", Inclusive: true},
+		{Country: "US", Region: "CA", Rate: 725, Label: "CA sales tax"},
+	}
+	if !reflect.DeepEqual(exp, table) {
+		t.Errorf("tax tables do not match\nexp: %+v\ngot: %+v", exp, table)
+	}
+
+	for _, invalid := range []string{
+		`[{"country":"Germany","rate":1900,"label":"VAT"}]`,
+		`[{"country":"DE","rate":-1,"label":"VAT"}]`,
+		`[{"country":"DE","rate":1900}]`,
+	} {
+		if _, err := LoadTaxTable(writeTestFile(t, "taxes.json", invalid)); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s err exp: %v, got: %v", invalid, ErrInvalidArgument, err)
+		}
+	}
+}
+
+func TestHTTPTaxCalculator_Taxes(t *testing.T) {
+	usd := func(amount int64) Money { return Money{Amount: amount, Currency: "USD"} }
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req httpTaxRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+
+		switch req.Address.Region {
+		case "ERR":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "CA":
+		default:
+			t.Errorf("region exp: %s, got: %s", "CA", req.Address.Region)
+		}
+
+		exp := httpTaxRequest{
+			Currency: "USD",
+			Address:  httpTaxAddress{Country: "US", Region: "CA", PostalCode: "94103", City: "San Francisco"},
+			LineItems: []httpTaxLineItem{
+				{ID: 1, ProductID: 10, TaxClass: "food", Quantity: 2, Amount: 950},
+				{ID: 2, ProductID: 20, Quantity: 1, Amount: 1900},
+			},
+		}
+		if !reflect.DeepEqual(exp, req) {
+			t.Errorf("requests do not match\nexp: %+v\ngot: %+v", exp, req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"taxes":[{"label":"CA sales tax","amount":138}]}`))
+	}))
+	defer srv.Close()
+
+	c := &Cart{
+		ShippingAddress: &Address{Line1: "1 Market St", City: "San Francisco", PostalCode: "94103", Region: "CA", Country: "US"},
+		LineItems: []*LineItem{
+			{ID: 1, ProductID: 10, TaxClass: "food", Quantity: 2, UnitPrice: usd(500)},
+			{ID: 2, ProductID: 20, Quantity: 1, UnitPrice: usd(2000)},
+			{ID: 3, ProductID: 30, Quantity: 1},
+		},
+		Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Label: "D", Amount: usd(150)}},
+	}
+
+	calc := &HTTPTaxCalculator{URL: srv.URL}
+
+	taxes, err := calc.Taxes(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := []Adjustment{{Kind: AdjustmentTax, Label: "CA sales tax", Amount: usd(138)}}; !reflect.DeepEqual(exp, taxes) {
+		t.Errorf("taxes do not match\nexp: %+v\ngot: %+v", exp, taxes)
+	}
+
+	c.ShippingAddress.Region = "ERR"
+	if _, err := calc.Taxes(context.Background(), c); err == nil {
+		t.Error("service error exp")
+	}
+
+	// Carts without an address are not sent.
+	c.ShippingAddress = nil
+	if taxes, err := calc.Taxes(context.Background(), c); err != nil || taxes != nil {
+		t.Errorf("no taxes exp, got: %+v, %v", taxes, err)
+	}
+}
+
+func TestTaxableAmounts(t *testing.T) {
+	eur := func(amount int64) Money { return Money{Amount: amount, Currency: "EUR"} }
+
+	c := &Cart{
+		LineItems: []*LineItem{
+			{ID: 1, Quantity: 1, UnitPrice: eur(100)},
+			{ID: 2, Quantity: 2, UnitPrice: eur(50)},
+			{ID: 3, Quantity: 1, UnitPrice: eur(150), Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Amount: eur(50)}}},
+			{ID: 4, Quantity: 1, UnitPrice: eur(100), Adjustments: []Adjustment{{Kind: AdjustmentDiscount, Amount: eur(200)}}},
+			{ID: 5, Quantity: 1},
+		},
+		Adjustments: []Adjustment{
+			{Kind: AdjustmentDiscount, Amount: eur(100)},
+			{Kind: AdjustmentShipping, Amount: eur(490)},
+			// Free shipping lowers the shipping charges, not the taxable item amounts.
+			{Kind: AdjustmentDiscount, Amount: eur(490), Shipping: true},
+		},
+	}
+
+	amounts, err := taxableAmounts(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cart discount is split 33/33/33, the leftover minor unit is taken off the first item.
+	if exp := []Money{eur(66), eur(67), eur(67), eur(0), {}}; !reflect.DeepEqual(exp, amounts) {
+		t.Errorf("amounts do not match\nexp: %+v\ngot: %+v", exp, amounts)
+	}
+}